@@ -17,6 +17,7 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
@@ -120,3 +121,102 @@ func (c DirectorClient) deploymentResourceInfos(deploymentName string, resourceT
 
 	return resps, nil
 }
+
+func (c DirectorClient) DeploymentInstanceInfos(deploymentName string) ([]director.VMInfo, error) {
+	return c.deploymentResourceInfos(deploymentName, "instances")
+}
+
+func (c DirectorClient) SetUpSSH(deploymentName, jobName, indexOrID string, opts director.SSHOpts) ([]director.SSHResp, error) {
+	var resps []director.SSHResp
+
+	if len(deploymentName) == 0 {
+		return resps, fmt.Errorf("expected non-empty deployment name")
+	}
+
+	// jobName and indexOrID may be empty
+
+	path := fmt.Sprintf("/deployments/%s/ssh", deploymentName)
+
+	body := c.buildSSHBody(deploymentName, jobName, indexOrID)
+
+	body["command"] = "setup"
+	body["params"] = map[string]string{
+		"user":       opts.Username,
+		"public_key": opts.PublicKey,
+	}
+
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return resps, fmt.Errorf("error marshaling request body: %w", err)
+	}
+
+	setHeaders := func(req *http.Request) {
+		req.Header.Add("Content-Type", "application/json")
+	}
+
+	resultBytes, err := c.taskClientRequest.PostResult(path, reqBody, setHeaders)
+	if err != nil {
+		return resps, fmt.Errorf("error setting up SSH in deployment '%s': %w", deploymentName, err)
+	}
+
+	err = json.Unmarshal(resultBytes, &resps)
+	if err != nil {
+		return resps, fmt.Errorf("error unmarshaling SSH result: %w", err)
+	}
+
+	return resps, nil
+}
+
+func (c DirectorClient) CleanUpSSH(deploymentName, jobName, indexOrID string, opts director.SSHOpts) error {
+	if len(deploymentName) == 0 {
+		return fmt.Errorf("expected non-empty deployment name")
+	}
+
+	// jobName and indexOrID may be empty
+
+	path := fmt.Sprintf("/deployments/%s/ssh", deploymentName)
+
+	body := c.buildSSHBody(deploymentName, jobName, indexOrID)
+
+	body["command"] = "cleanup"
+	body["params"] = map[string]string{"user_regex": "^" + opts.Username}
+
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("error marshaling request body: %w", err)
+	}
+
+	setHeaders := func(req *http.Request) {
+		req.Header.Add("Content-Type", "application/json")
+	}
+
+	// Using clientRequest and not taskClientRequest
+	// since we don't want to wait for cleanup to finish.
+	_, _, err = c.clientRequest.RawPost(path, reqBody, setHeaders)
+	if err != nil {
+		return fmt.Errorf("error cleaning up SSH in deployment '%s': %w", deploymentName, err)
+	}
+
+	return nil
+}
+
+func (c DirectorClient) buildSSHBody(deploymentName, jobName, indexOrID string) map[string]interface{} {
+	target := map[string]interface{}{}
+
+	if len(jobName) > 0 {
+		target["job"] = jobName
+	}
+
+	if len(indexOrID) > 0 {
+		target["indexes"] = []string{indexOrID}
+		target["ids"] = []string{indexOrID}
+	} else {
+		target["indexes"] = []string{}
+		target["ids"] = []string{}
+	}
+
+	return map[string]interface{}{
+		"deployment_name": deploymentName,
+		"target":          target,
+	}
+}
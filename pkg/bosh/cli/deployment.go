@@ -15,6 +15,8 @@
 package cli
 
 import (
+	"fmt"
+
 	"github.com/cloudfoundry/bosh-cli/director"
 )
 
@@ -31,3 +33,50 @@ func (d DeploymentImpl) VMInfos() ([]director.VMInfo, error) {
 
 	return infos, nil
 }
+
+func (d DeploymentImpl) InstanceInfos() ([]director.VMInfo, error) {
+	infos, err := d.client.DeploymentInstanceInfos(d.name)
+	if err != nil {
+		return nil, err
+	}
+
+	return infos, nil
+}
+
+func (d DeploymentImpl) SetUpSSH(slug director.AllOrInstanceGroupOrInstanceSlug, opts director.SSHOpts) (director.SSHResult, error) {
+	var result director.SSHResult
+
+	resps, err := d.client.SetUpSSH(d.name, slug.Name(), slug.IndexOrID(), opts)
+	if err != nil {
+		return result, err
+	}
+
+	if len(resps) == 0 {
+		return result, fmt.Errorf("did not create any SSH sessions for the instances '%#v'", resps)
+	}
+
+	for _, resp := range resps {
+		if resp.Status != "success" {
+			return result, fmt.Errorf("failed to set up SSH session for one of the instances '%#v'", resp)
+		}
+
+		result.Hosts = append(result.Hosts, director.Host{
+			Job:       resp.Job,
+			IndexOrID: resp.IndexOrID(),
+
+			Username:      opts.Username,
+			Host:          resp.IP,
+			HostPublicKey: resp.HostPublicKey,
+		})
+	}
+
+	// Assumes that all gw are same
+	result.GatewayUsername = resps[0].GatewayUser
+	result.GatewayHost = resps[0].GatewayHost
+
+	return result, nil
+}
+
+func (d DeploymentImpl) CleanUpSSH(slug director.AllOrInstanceGroupOrInstanceSlug, opts director.SSHOpts) error {
+	return d.client.CleanUpSSH(d.name, slug.Name(), slug.IndexOrID(), opts)
+}
@@ -23,6 +23,9 @@ import (
 
 type Deployment interface {
 	VMInfos() ([]director.VMInfo, error)
+	InstanceInfos() ([]director.VMInfo, error)
+	SetUpSSH(slug director.AllOrInstanceGroupOrInstanceSlug, opts director.SSHOpts) (director.SSHResult, error)
+	CleanUpSSH(slug director.AllOrInstanceGroupOrInstanceSlug, opts director.SSHOpts) error
 }
 
 type DirectorImpl struct {
@@ -9,6 +9,44 @@ import (
 )
 
 type FakeDeployment struct {
+	CleanUpSSHStub        func(director.AllOrInstanceGroupOrInstanceSlug, director.SSHOpts) error
+	cleanUpSSHMutex       sync.RWMutex
+	cleanUpSSHArgsForCall []struct {
+		arg1 director.AllOrInstanceGroupOrInstanceSlug
+		arg2 director.SSHOpts
+	}
+	cleanUpSSHReturns struct {
+		result1 error
+	}
+	cleanUpSSHReturnsOnCall map[int]struct {
+		result1 error
+	}
+	InstanceInfosStub        func() ([]director.VMInfo, error)
+	instanceInfosMutex       sync.RWMutex
+	instanceInfosArgsForCall []struct {
+	}
+	instanceInfosReturns struct {
+		result1 []director.VMInfo
+		result2 error
+	}
+	instanceInfosReturnsOnCall map[int]struct {
+		result1 []director.VMInfo
+		result2 error
+	}
+	SetUpSSHStub        func(director.AllOrInstanceGroupOrInstanceSlug, director.SSHOpts) (director.SSHResult, error)
+	setUpSSHMutex       sync.RWMutex
+	setUpSSHArgsForCall []struct {
+		arg1 director.AllOrInstanceGroupOrInstanceSlug
+		arg2 director.SSHOpts
+	}
+	setUpSSHReturns struct {
+		result1 director.SSHResult
+		result2 error
+	}
+	setUpSSHReturnsOnCall map[int]struct {
+		result1 director.SSHResult
+		result2 error
+	}
 	VMInfosStub        func() ([]director.VMInfo, error)
 	vMInfosMutex       sync.RWMutex
 	vMInfosArgsForCall []struct {
@@ -25,6 +63,189 @@ type FakeDeployment struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeDeployment) CleanUpSSH(arg1 director.AllOrInstanceGroupOrInstanceSlug, arg2 director.SSHOpts) error {
+	fake.cleanUpSSHMutex.Lock()
+	ret, specificReturn := fake.cleanUpSSHReturnsOnCall[len(fake.cleanUpSSHArgsForCall)]
+	fake.cleanUpSSHArgsForCall = append(fake.cleanUpSSHArgsForCall, struct {
+		arg1 director.AllOrInstanceGroupOrInstanceSlug
+		arg2 director.SSHOpts
+	}{arg1, arg2})
+	stub := fake.CleanUpSSHStub
+	fakeReturns := fake.cleanUpSSHReturns
+	fake.recordInvocation("CleanUpSSH", []interface{}{arg1, arg2})
+	fake.cleanUpSSHMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeDeployment) CleanUpSSHCallCount() int {
+	fake.cleanUpSSHMutex.RLock()
+	defer fake.cleanUpSSHMutex.RUnlock()
+	return len(fake.cleanUpSSHArgsForCall)
+}
+
+func (fake *FakeDeployment) CleanUpSSHCalls(stub func(director.AllOrInstanceGroupOrInstanceSlug, director.SSHOpts) error) {
+	fake.cleanUpSSHMutex.Lock()
+	defer fake.cleanUpSSHMutex.Unlock()
+	fake.CleanUpSSHStub = stub
+}
+
+func (fake *FakeDeployment) CleanUpSSHArgsForCall(i int) (director.AllOrInstanceGroupOrInstanceSlug, director.SSHOpts) {
+	fake.cleanUpSSHMutex.RLock()
+	defer fake.cleanUpSSHMutex.RUnlock()
+	argsForCall := fake.cleanUpSSHArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeDeployment) CleanUpSSHReturns(result1 error) {
+	fake.cleanUpSSHMutex.Lock()
+	defer fake.cleanUpSSHMutex.Unlock()
+	fake.CleanUpSSHStub = nil
+	fake.cleanUpSSHReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDeployment) CleanUpSSHReturnsOnCall(i int, result1 error) {
+	fake.cleanUpSSHMutex.Lock()
+	defer fake.cleanUpSSHMutex.Unlock()
+	fake.CleanUpSSHStub = nil
+	if fake.cleanUpSSHReturnsOnCall == nil {
+		fake.cleanUpSSHReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.cleanUpSSHReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDeployment) InstanceInfos() ([]director.VMInfo, error) {
+	fake.instanceInfosMutex.Lock()
+	ret, specificReturn := fake.instanceInfosReturnsOnCall[len(fake.instanceInfosArgsForCall)]
+	fake.instanceInfosArgsForCall = append(fake.instanceInfosArgsForCall, struct {
+	}{})
+	stub := fake.InstanceInfosStub
+	fakeReturns := fake.instanceInfosReturns
+	fake.recordInvocation("InstanceInfos", []interface{}{})
+	fake.instanceInfosMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeDeployment) InstanceInfosCallCount() int {
+	fake.instanceInfosMutex.RLock()
+	defer fake.instanceInfosMutex.RUnlock()
+	return len(fake.instanceInfosArgsForCall)
+}
+
+func (fake *FakeDeployment) InstanceInfosCalls(stub func() ([]director.VMInfo, error)) {
+	fake.instanceInfosMutex.Lock()
+	defer fake.instanceInfosMutex.Unlock()
+	fake.InstanceInfosStub = stub
+}
+
+func (fake *FakeDeployment) InstanceInfosReturns(result1 []director.VMInfo, result2 error) {
+	fake.instanceInfosMutex.Lock()
+	defer fake.instanceInfosMutex.Unlock()
+	fake.InstanceInfosStub = nil
+	fake.instanceInfosReturns = struct {
+		result1 []director.VMInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDeployment) InstanceInfosReturnsOnCall(i int, result1 []director.VMInfo, result2 error) {
+	fake.instanceInfosMutex.Lock()
+	defer fake.instanceInfosMutex.Unlock()
+	fake.InstanceInfosStub = nil
+	if fake.instanceInfosReturnsOnCall == nil {
+		fake.instanceInfosReturnsOnCall = make(map[int]struct {
+			result1 []director.VMInfo
+			result2 error
+		})
+	}
+	fake.instanceInfosReturnsOnCall[i] = struct {
+		result1 []director.VMInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDeployment) SetUpSSH(arg1 director.AllOrInstanceGroupOrInstanceSlug, arg2 director.SSHOpts) (director.SSHResult, error) {
+	fake.setUpSSHMutex.Lock()
+	ret, specificReturn := fake.setUpSSHReturnsOnCall[len(fake.setUpSSHArgsForCall)]
+	fake.setUpSSHArgsForCall = append(fake.setUpSSHArgsForCall, struct {
+		arg1 director.AllOrInstanceGroupOrInstanceSlug
+		arg2 director.SSHOpts
+	}{arg1, arg2})
+	stub := fake.SetUpSSHStub
+	fakeReturns := fake.setUpSSHReturns
+	fake.recordInvocation("SetUpSSH", []interface{}{arg1, arg2})
+	fake.setUpSSHMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeDeployment) SetUpSSHCallCount() int {
+	fake.setUpSSHMutex.RLock()
+	defer fake.setUpSSHMutex.RUnlock()
+	return len(fake.setUpSSHArgsForCall)
+}
+
+func (fake *FakeDeployment) SetUpSSHCalls(stub func(director.AllOrInstanceGroupOrInstanceSlug, director.SSHOpts) (director.SSHResult, error)) {
+	fake.setUpSSHMutex.Lock()
+	defer fake.setUpSSHMutex.Unlock()
+	fake.SetUpSSHStub = stub
+}
+
+func (fake *FakeDeployment) SetUpSSHArgsForCall(i int) (director.AllOrInstanceGroupOrInstanceSlug, director.SSHOpts) {
+	fake.setUpSSHMutex.RLock()
+	defer fake.setUpSSHMutex.RUnlock()
+	argsForCall := fake.setUpSSHArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeDeployment) SetUpSSHReturns(result1 director.SSHResult, result2 error) {
+	fake.setUpSSHMutex.Lock()
+	defer fake.setUpSSHMutex.Unlock()
+	fake.SetUpSSHStub = nil
+	fake.setUpSSHReturns = struct {
+		result1 director.SSHResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDeployment) SetUpSSHReturnsOnCall(i int, result1 director.SSHResult, result2 error) {
+	fake.setUpSSHMutex.Lock()
+	defer fake.setUpSSHMutex.Unlock()
+	fake.SetUpSSHStub = nil
+	if fake.setUpSSHReturnsOnCall == nil {
+		fake.setUpSSHReturnsOnCall = make(map[int]struct {
+			result1 director.SSHResult
+			result2 error
+		})
+	}
+	fake.setUpSSHReturnsOnCall[i] = struct {
+		result1 director.SSHResult
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeDeployment) VMInfos() ([]director.VMInfo, error) {
 	fake.vMInfosMutex.Lock()
 	ret, specificReturn := fake.vMInfosReturnsOnCall[len(fake.vMInfosArgsForCall)]
@@ -84,6 +305,12 @@ func (fake *FakeDeployment) VMInfosReturnsOnCall(i int, result1 []director.VMInf
 func (fake *FakeDeployment) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.cleanUpSSHMutex.RLock()
+	defer fake.cleanUpSSHMutex.RUnlock()
+	fake.instanceInfosMutex.RLock()
+	defer fake.instanceInfosMutex.RUnlock()
+	fake.setUpSSHMutex.RLock()
+	defer fake.setUpSSHMutex.RUnlock()
 	fake.vMInfosMutex.RLock()
 	defer fake.vMInfosMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
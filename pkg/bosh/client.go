@@ -20,10 +20,12 @@ import (
 	"time"
 
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/bosh/cli"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/bosh/httpclient"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/uaa"
 
 	"github.com/cloudfoundry/bosh-cli/director"
+	boshhttp "github.com/cloudfoundry/bosh-utils/httpclient"
 	"github.com/pkg/errors"
 )
 
@@ -39,6 +41,10 @@ type Client interface {
 	VerifyAuth() error
 	FindDeployment(name string) (director.DeploymentResp, bool, error)
 	FindVM(deploymentName, processName string) (director.VMInfo, bool, error)
+	FindInstances(deploymentName string) ([]director.VMInfo, error)
+	RunCommand(deploymentName, instance, command string) (string, error)
+	UploadFile(deploymentName, instance, src, dst string) error
+	DownloadFile(deploymentName, instance, src, dst string) error
 }
 
 //counterfeiter:generate -o fakes . ClientFactory
@@ -75,6 +81,7 @@ type ClientImpl struct {
 
 	uaaFactory      UAAFactory
 	directorFactory DirectorFactory
+	dialContext     boshhttp.DialContextFunc
 }
 
 //counterfeiter:generate -o fakes . UAA
@@ -132,6 +139,7 @@ func NewClientFactory(directorFactory DirectorFactory, uaaFactory UAAFactory) Cl
 			boshAuth:        boshAuth,
 			uaaFactory:      uaaFactory,
 			directorFactory: directorFactory,
+			dialContext:     httpclient.NewDialContextFunc(allProxy),
 			PollingInterval: 5,
 			BoshInfo:        boshInfo,
 		}, nil
@@ -197,6 +205,25 @@ func (c *ClientImpl) FindVM(deploymentName, processName string) (director.VMInfo
 	return director.VMInfo{}, false, nil
 }
 
+func (c *ClientImpl) FindInstances(deploymentName string) ([]director.VMInfo, error) {
+	d, err := c.Director()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build director: %w", err)
+	}
+
+	dep, err := d.FindDeployment(deploymentName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find deployment %s: %w", deploymentName, err)
+	}
+
+	infos, err := dep.InstanceInfos()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get the list of instances: %w", err)
+	}
+
+	return infos, nil
+}
+
 func (c *ClientImpl) FindDeployment(pattern string) (director.DeploymentResp, bool, error) {
 	d, err := c.Director()
 	if err != nil {
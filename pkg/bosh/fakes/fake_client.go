@@ -9,6 +9,20 @@ import (
 )
 
 type FakeClient struct {
+	DownloadFileStub        func(string, string, string, string) error
+	downloadFileMutex       sync.RWMutex
+	downloadFileArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+	}
+	downloadFileReturns struct {
+		result1 error
+	}
+	downloadFileReturnsOnCall map[int]struct {
+		result1 error
+	}
 	FindDeploymentStub        func(string) (director.DeploymentResp, bool, error)
 	findDeploymentMutex       sync.RWMutex
 	findDeploymentArgsForCall []struct {
@@ -24,6 +38,19 @@ type FakeClient struct {
 		result2 bool
 		result3 error
 	}
+	FindInstancesStub        func(string) ([]director.VMInfo, error)
+	findInstancesMutex       sync.RWMutex
+	findInstancesArgsForCall []struct {
+		arg1 string
+	}
+	findInstancesReturns struct {
+		result1 []director.VMInfo
+		result2 error
+	}
+	findInstancesReturnsOnCall map[int]struct {
+		result1 []director.VMInfo
+		result2 error
+	}
 	FindVMStub        func(string, string) (director.VMInfo, bool, error)
 	findVMMutex       sync.RWMutex
 	findVMArgsForCall []struct {
@@ -40,6 +67,35 @@ type FakeClient struct {
 		result2 bool
 		result3 error
 	}
+	RunCommandStub        func(string, string, string) (string, error)
+	runCommandMutex       sync.RWMutex
+	runCommandArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	runCommandReturns struct {
+		result1 string
+		result2 error
+	}
+	runCommandReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	UploadFileStub        func(string, string, string, string) error
+	uploadFileMutex       sync.RWMutex
+	uploadFileArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+	}
+	uploadFileReturns struct {
+		result1 error
+	}
+	uploadFileReturnsOnCall map[int]struct {
+		result1 error
+	}
 	VerifyAuthStub        func() error
 	verifyAuthMutex       sync.RWMutex
 	verifyAuthArgsForCall []struct {
@@ -54,6 +110,70 @@ type FakeClient struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeClient) DownloadFile(arg1 string, arg2 string, arg3 string, arg4 string) error {
+	fake.downloadFileMutex.Lock()
+	ret, specificReturn := fake.downloadFileReturnsOnCall[len(fake.downloadFileArgsForCall)]
+	fake.downloadFileArgsForCall = append(fake.downloadFileArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.DownloadFileStub
+	fakeReturns := fake.downloadFileReturns
+	fake.recordInvocation("DownloadFile", []interface{}{arg1, arg2, arg3, arg4})
+	fake.downloadFileMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) DownloadFileCallCount() int {
+	fake.downloadFileMutex.RLock()
+	defer fake.downloadFileMutex.RUnlock()
+	return len(fake.downloadFileArgsForCall)
+}
+
+func (fake *FakeClient) DownloadFileCalls(stub func(string, string, string, string) error) {
+	fake.downloadFileMutex.Lock()
+	defer fake.downloadFileMutex.Unlock()
+	fake.DownloadFileStub = stub
+}
+
+func (fake *FakeClient) DownloadFileArgsForCall(i int) (string, string, string, string) {
+	fake.downloadFileMutex.RLock()
+	defer fake.downloadFileMutex.RUnlock()
+	argsForCall := fake.downloadFileArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeClient) DownloadFileReturns(result1 error) {
+	fake.downloadFileMutex.Lock()
+	defer fake.downloadFileMutex.Unlock()
+	fake.DownloadFileStub = nil
+	fake.downloadFileReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DownloadFileReturnsOnCall(i int, result1 error) {
+	fake.downloadFileMutex.Lock()
+	defer fake.downloadFileMutex.Unlock()
+	fake.DownloadFileStub = nil
+	if fake.downloadFileReturnsOnCall == nil {
+		fake.downloadFileReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.downloadFileReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeClient) FindDeployment(arg1 string) (director.DeploymentResp, bool, error) {
 	fake.findDeploymentMutex.Lock()
 	ret, specificReturn := fake.findDeploymentReturnsOnCall[len(fake.findDeploymentArgsForCall)]
@@ -121,6 +241,70 @@ func (fake *FakeClient) FindDeploymentReturnsOnCall(i int, result1 director.Depl
 	}{result1, result2, result3}
 }
 
+func (fake *FakeClient) FindInstances(arg1 string) ([]director.VMInfo, error) {
+	fake.findInstancesMutex.Lock()
+	ret, specificReturn := fake.findInstancesReturnsOnCall[len(fake.findInstancesArgsForCall)]
+	fake.findInstancesArgsForCall = append(fake.findInstancesArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.FindInstancesStub
+	fakeReturns := fake.findInstancesReturns
+	fake.recordInvocation("FindInstances", []interface{}{arg1})
+	fake.findInstancesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) FindInstancesCallCount() int {
+	fake.findInstancesMutex.RLock()
+	defer fake.findInstancesMutex.RUnlock()
+	return len(fake.findInstancesArgsForCall)
+}
+
+func (fake *FakeClient) FindInstancesCalls(stub func(string) ([]director.VMInfo, error)) {
+	fake.findInstancesMutex.Lock()
+	defer fake.findInstancesMutex.Unlock()
+	fake.FindInstancesStub = stub
+}
+
+func (fake *FakeClient) FindInstancesArgsForCall(i int) string {
+	fake.findInstancesMutex.RLock()
+	defer fake.findInstancesMutex.RUnlock()
+	argsForCall := fake.findInstancesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) FindInstancesReturns(result1 []director.VMInfo, result2 error) {
+	fake.findInstancesMutex.Lock()
+	defer fake.findInstancesMutex.Unlock()
+	fake.FindInstancesStub = nil
+	fake.findInstancesReturns = struct {
+		result1 []director.VMInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) FindInstancesReturnsOnCall(i int, result1 []director.VMInfo, result2 error) {
+	fake.findInstancesMutex.Lock()
+	defer fake.findInstancesMutex.Unlock()
+	fake.FindInstancesStub = nil
+	if fake.findInstancesReturnsOnCall == nil {
+		fake.findInstancesReturnsOnCall = make(map[int]struct {
+			result1 []director.VMInfo
+			result2 error
+		})
+	}
+	fake.findInstancesReturnsOnCall[i] = struct {
+		result1 []director.VMInfo
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) FindVM(arg1 string, arg2 string) (director.VMInfo, bool, error) {
 	fake.findVMMutex.Lock()
 	ret, specificReturn := fake.findVMReturnsOnCall[len(fake.findVMArgsForCall)]
@@ -189,6 +373,136 @@ func (fake *FakeClient) FindVMReturnsOnCall(i int, result1 director.VMInfo, resu
 	}{result1, result2, result3}
 }
 
+func (fake *FakeClient) RunCommand(arg1 string, arg2 string, arg3 string) (string, error) {
+	fake.runCommandMutex.Lock()
+	ret, specificReturn := fake.runCommandReturnsOnCall[len(fake.runCommandArgsForCall)]
+	fake.runCommandArgsForCall = append(fake.runCommandArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.RunCommandStub
+	fakeReturns := fake.runCommandReturns
+	fake.recordInvocation("RunCommand", []interface{}{arg1, arg2, arg3})
+	fake.runCommandMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) RunCommandCallCount() int {
+	fake.runCommandMutex.RLock()
+	defer fake.runCommandMutex.RUnlock()
+	return len(fake.runCommandArgsForCall)
+}
+
+func (fake *FakeClient) RunCommandCalls(stub func(string, string, string) (string, error)) {
+	fake.runCommandMutex.Lock()
+	defer fake.runCommandMutex.Unlock()
+	fake.RunCommandStub = stub
+}
+
+func (fake *FakeClient) RunCommandArgsForCall(i int) (string, string, string) {
+	fake.runCommandMutex.RLock()
+	defer fake.runCommandMutex.RUnlock()
+	argsForCall := fake.runCommandArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeClient) RunCommandReturns(result1 string, result2 error) {
+	fake.runCommandMutex.Lock()
+	defer fake.runCommandMutex.Unlock()
+	fake.RunCommandStub = nil
+	fake.runCommandReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) RunCommandReturnsOnCall(i int, result1 string, result2 error) {
+	fake.runCommandMutex.Lock()
+	defer fake.runCommandMutex.Unlock()
+	fake.RunCommandStub = nil
+	if fake.runCommandReturnsOnCall == nil {
+		fake.runCommandReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.runCommandReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) UploadFile(arg1 string, arg2 string, arg3 string, arg4 string) error {
+	fake.uploadFileMutex.Lock()
+	ret, specificReturn := fake.uploadFileReturnsOnCall[len(fake.uploadFileArgsForCall)]
+	fake.uploadFileArgsForCall = append(fake.uploadFileArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.UploadFileStub
+	fakeReturns := fake.uploadFileReturns
+	fake.recordInvocation("UploadFile", []interface{}{arg1, arg2, arg3, arg4})
+	fake.uploadFileMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) UploadFileCallCount() int {
+	fake.uploadFileMutex.RLock()
+	defer fake.uploadFileMutex.RUnlock()
+	return len(fake.uploadFileArgsForCall)
+}
+
+func (fake *FakeClient) UploadFileCalls(stub func(string, string, string, string) error) {
+	fake.uploadFileMutex.Lock()
+	defer fake.uploadFileMutex.Unlock()
+	fake.UploadFileStub = stub
+}
+
+func (fake *FakeClient) UploadFileArgsForCall(i int) (string, string, string, string) {
+	fake.uploadFileMutex.RLock()
+	defer fake.uploadFileMutex.RUnlock()
+	argsForCall := fake.uploadFileArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeClient) UploadFileReturns(result1 error) {
+	fake.uploadFileMutex.Lock()
+	defer fake.uploadFileMutex.Unlock()
+	fake.UploadFileStub = nil
+	fake.uploadFileReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) UploadFileReturnsOnCall(i int, result1 error) {
+	fake.uploadFileMutex.Lock()
+	defer fake.uploadFileMutex.Unlock()
+	fake.UploadFileStub = nil
+	if fake.uploadFileReturnsOnCall == nil {
+		fake.uploadFileReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.uploadFileReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeClient) VerifyAuth() error {
 	fake.verifyAuthMutex.Lock()
 	ret, specificReturn := fake.verifyAuthReturnsOnCall[len(fake.verifyAuthArgsForCall)]
@@ -245,10 +559,18 @@ func (fake *FakeClient) VerifyAuthReturnsOnCall(i int, result1 error) {
 func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.downloadFileMutex.RLock()
+	defer fake.downloadFileMutex.RUnlock()
 	fake.findDeploymentMutex.RLock()
 	defer fake.findDeploymentMutex.RUnlock()
+	fake.findInstancesMutex.RLock()
+	defer fake.findInstancesMutex.RUnlock()
 	fake.findVMMutex.RLock()
 	defer fake.findVMMutex.RUnlock()
+	fake.runCommandMutex.RLock()
+	defer fake.runCommandMutex.RUnlock()
+	fake.uploadFileMutex.RLock()
+	defer fake.uploadFileMutex.RUnlock()
 	fake.verifyAuthMutex.RLock()
 	defer fake.verifyAuthMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
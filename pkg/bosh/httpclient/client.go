@@ -48,15 +48,7 @@ func New(
 	disableKeepAlives bool,
 	certPool *x509.CertPool,
 ) *http.Client {
-	socks5Proxy := proxy.NewSocks5Proxy(proxy.NewHostKey(), log.New(os.Stdout, "", log.LstdFlags), 1*time.Minute)
-	dialContextFunc := boshhttp.SOCKS5DialContextFuncFromEnvironment(&net.Dialer{
-		Timeout:   30 * time.Second,
-		KeepAlive: 30 * time.Second,
-	}, socks5Proxy)
-
-	if len(allProxy) > 0 {
-		dialContextFunc = SOCKS5DialContextFuncFromAllProxy(allProxy, socks5Proxy)
-	}
+	dialContextFunc := NewDialContextFunc(allProxy)
 
 	serviceDefaults := tlsconfig.WithInternalServiceDefaults()
 	if externalClient {
@@ -85,6 +77,21 @@ func New(
 	return client
 }
 
+// NewDialContextFunc returns a dialer that tunnels connections through the given all_proxy,
+// falling back to BOSH_ALL_PROXY from the environment when it is empty
+func NewDialContextFunc(allProxy string) boshhttp.DialContextFunc {
+	socks5Proxy := proxy.NewSocks5Proxy(proxy.NewHostKey(), log.New(os.Stdout, "", log.LstdFlags), 1*time.Minute)
+
+	if len(allProxy) > 0 {
+		return SOCKS5DialContextFuncFromAllProxy(allProxy, socks5Proxy)
+	}
+
+	return boshhttp.SOCKS5DialContextFuncFromEnvironment(&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}, socks5Proxy)
+}
+
 func WithInsecureSkipVerify(insecureSkipVerify bool) tlsconfig.TLSOption {
 	return func(config *tls.Config) error {
 		config.InsecureSkipVerify = insecureSkipVerify
@@ -0,0 +1,206 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package bosh
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/bosh/httpclient"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
+
+	"github.com/cloudfoundry/bosh-cli/director"
+	boshuuid "github.com/cloudfoundry/bosh-utils/uuid"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	sshPort    = "22"
+	sshTimeout = 30 * time.Second
+)
+
+// RunCommand runs the command on the instance, e.g. mysql/0, and returns its combined stdout and stderr
+func (c *ClientImpl) RunCommand(deploymentName, instance, command string) (string, error) {
+	var output bytes.Buffer
+	err := c.withSSH(deploymentName, instance, func(client *ssh.Client) error {
+		return runSession(client, command, nil, &output, &output)
+	})
+	if err != nil {
+		return output.String(), fmt.Errorf("failed to run command on %s/%s: %w", deploymentName, instance, err)
+	}
+
+	return output.String(), nil
+}
+
+// UploadFile copies the local file src to dst on the instance
+func (c *ClientImpl) UploadFile(deploymentName, instance, src, dst string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %q: %w", src, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var stderr bytes.Buffer
+	err = c.withSSH(deploymentName, instance, func(client *ssh.Client) error {
+		return runSession(client, "cat > "+shellQuote(dst), f, io.Discard, &stderr)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload %q to %s/%s:%s: %w%s", src, deploymentName, instance, dst, err, stderrSuffix(stderr))
+	}
+
+	return nil
+}
+
+// DownloadFile copies the file src on the instance to the local file dst
+func (c *ClientImpl) DownloadFile(deploymentName, instance, src, dst string) error {
+	f, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("failed to create %q: %w", dst, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var stderr bytes.Buffer
+	err = c.withSSH(deploymentName, instance, func(client *ssh.Client) error {
+		return runSession(client, "cat "+shellQuote(src), nil, f, &stderr)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to download %s/%s:%s to %q: %w%s", deploymentName, instance, src, dst, err, stderrSuffix(stderr))
+	}
+
+	return nil
+}
+
+// withSSH asks the director to create a temporary user with a generated key on the instance, connects to it
+// through the SOCKS5 proxy and removes the user again once fn returns
+func (c *ClientImpl) withSSH(deploymentName, instance string, fn func(client *ssh.Client) error) error {
+	slug, err := director.NewAllOrInstanceGroupOrInstanceSlugFromString(instance)
+	if err != nil {
+		return err
+	}
+	if _, ok := slug.InstanceSlug(); !ok {
+		return fmt.Errorf("expected a single instance in the form 'group/index-or-id', got %q", instance)
+	}
+
+	d, err := c.Director()
+	if err != nil {
+		return fmt.Errorf("failed to build director: %w", err)
+	}
+
+	dep, err := d.FindDeployment(deploymentName)
+	if err != nil {
+		return fmt.Errorf("cannot find deployment %s: %w", deploymentName, err)
+	}
+
+	opts, privateKey, err := director.NewSSHOpts(boshuuid.NewGenerator())
+	if err != nil {
+		return fmt.Errorf("failed to generate ssh credentials: %w", err)
+	}
+
+	result, err := dep.SetUpSSH(slug, opts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := dep.CleanUpSSH(slug, opts); err != nil {
+			log.Warnf("Failed to clean up ssh user %s on %s/%s: %v", opts.Username, deploymentName, instance, err)
+		}
+	}()
+
+	if len(result.Hosts) == 0 {
+		return fmt.Errorf("no ssh hosts were set up for %s/%s", deploymentName, instance)
+	}
+
+	client, err := c.dialSSH(result.Hosts[0], privateKey)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	return fn(client)
+}
+
+func (c *ClientImpl) dialSSH(host director.Host, privateKey string) (*ssh.Client, error) {
+	signer, err := ssh.ParsePrivateKey([]byte(privateKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ssh private key: %w", err)
+	}
+
+	hostKeyCallback := ssh.InsecureIgnoreHostKey()
+	if len(host.HostPublicKey) > 0 {
+		hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(host.HostPublicKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse host key of %s: %w", host.Host, err)
+		}
+		hostKeyCallback = ssh.FixedHostKey(hostKey)
+	}
+
+	dialContext := c.dialContext
+	if dialContext == nil {
+		dialContext = httpclient.NewDialContextFunc(c.allProxy)
+	}
+
+	addr := net.JoinHostPort(host.Host, sshPort)
+	ctx, cancel := context.WithTimeout(context.Background(), sshTimeout)
+	defer cancel()
+
+	conn, err := dialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, &ssh.ClientConfig{
+		User:            host.Username,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         sshTimeout,
+	})
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to open ssh connection to %s: %w", addr, err)
+	}
+
+	return ssh.NewClient(sshConn, chans, reqs), nil
+}
+
+func runSession(client *ssh.Client, command string, stdin io.Reader, stdout, stderr io.Writer) error {
+	session, err := client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = session.Close() }()
+
+	session.Stdin = stdin
+	session.Stdout = stdout
+	session.Stderr = stderr
+
+	return session.Run(command)
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func stderrSuffix(stderr bytes.Buffer) string {
+	if stderr.Len() == 0 {
+		return ""
+	}
+	return ": " + strings.TrimSpace(stderr.String())
+}
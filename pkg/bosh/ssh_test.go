@@ -0,0 +1,234 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package bosh
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/bosh/cli"
+	clifakes "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/bosh/cli/fakes"
+
+	"github.com/cloudfoundry/bosh-cli/director"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+type directorFactoryFunc func() (cli.Director, error)
+
+func (f directorFactoryFunc) New(string, director.FactoryConfig, director.TaskReporter, director.FileReporter) (cli.Director, error) {
+	return f()
+}
+
+// startSSHServer runs an ssh server that executes every command it receives with sh
+func startSSHServer(t *testing.T) (string, string) {
+	_, hostPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hostSigner, err := ssh.NewSignerFromKey(hostPrivateKey)
+	require.NoError(t, err)
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			return nil, nil
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSSH(conn, config)
+		}
+	}()
+
+	return listener.Addr().String(), string(ssh.MarshalAuthorizedKey(hostSigner.PublicKey()))
+}
+
+func serveSSH(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer func() { _ = channel.Close() }()
+			for req := range requests {
+				if req.Type != "exec" {
+					_ = req.Reply(false, nil)
+					continue
+				}
+				_ = req.Reply(true, nil)
+
+				command := string(req.Payload[4:])
+				cmd := exec.Command("sh", "-c", command)
+				cmd.Stdin = channel
+				cmd.Stdout = channel
+				cmd.Stderr = channel.Stderr()
+
+				status := make([]byte, 4)
+				if err := cmd.Run(); err != nil {
+					var exitErr *exec.ExitError
+					if errors.As(err, &exitErr) {
+						binary.BigEndian.PutUint32(status, uint32(exitErr.ExitCode()))
+					} else {
+						binary.BigEndian.PutUint32(status, 1)
+					}
+				}
+				_, _ = channel.SendRequest("exit-status", false, status)
+				return
+			}
+		}()
+	}
+}
+
+func newSSHClient(t *testing.T) (*ClientImpl, *clifakes.FakeDeployment) {
+	addr, hostKey := startSSHServer(t)
+
+	fakeDeployment := new(clifakes.FakeDeployment)
+	fakeDeployment.SetUpSSHStub = func(slug director.AllOrInstanceGroupOrInstanceSlug, opts director.SSHOpts) (director.SSHResult, error) {
+		return director.SSHResult{
+			Hosts: []director.Host{
+				{
+					Job:           slug.Name(),
+					IndexOrID:     slug.IndexOrID(),
+					Username:      opts.Username,
+					Host:          "10.0.0.10",
+					HostPublicKey: hostKey,
+				},
+			},
+		}, nil
+	}
+
+	fakeDirector := new(clifakes.FakeDirector)
+	fakeDirector.FindDeploymentReturns(fakeDeployment, nil)
+
+	return &ClientImpl{
+		url: "https://10.0.0.6",
+		directorFactory: directorFactoryFunc(func() (cli.Director, error) {
+			return fakeDirector, nil
+		}),
+		dialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+			require.Equal(t, "10.0.0.10:22", address)
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}, fakeDeployment
+}
+
+func TestClientImpl_RunCommand(t *testing.T) {
+	client, fakeDeployment := newSSHClient(t)
+
+	out, err := client.RunCommand("service-instance_some-guid", "mysql/0", "echo hello; echo world >&2")
+	require.NoError(t, err)
+	require.Contains(t, out, "hello\n")
+	require.Contains(t, out, "world\n")
+
+	require.Equal(t, 1, fakeDeployment.SetUpSSHCallCount())
+	require.Equal(t, 1, fakeDeployment.CleanUpSSHCallCount())
+	slug, opts := fakeDeployment.SetUpSSHArgsForCall(0)
+	require.Equal(t, "mysql", slug.Name())
+	require.Equal(t, "0", slug.IndexOrID())
+	_, cleanupOpts := fakeDeployment.CleanUpSSHArgsForCall(0)
+	require.Equal(t, opts, cleanupOpts)
+}
+
+func TestClientImpl_RunCommandFails(t *testing.T) {
+	client, fakeDeployment := newSSHClient(t)
+
+	out, err := client.RunCommand("service-instance_some-guid", "mysql/0", "echo 'Restore is permitted only in a non-empty service instance' >&2; exit 3")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to run command on service-instance_some-guid/mysql/0")
+	require.Contains(t, out, "Restore is permitted only in a non-empty service instance")
+	require.Equal(t, 1, fakeDeployment.CleanUpSSHCallCount())
+}
+
+func TestClientImpl_RunCommandRequiresSingleInstance(t *testing.T) {
+	client, fakeDeployment := newSSHClient(t)
+
+	_, err := client.RunCommand("service-instance_some-guid", "mysql", "hostname")
+	require.EqualError(t, err, "failed to run command on service-instance_some-guid/mysql: expected a single instance in the form 'group/index-or-id', got \"mysql\"")
+	require.Equal(t, 0, fakeDeployment.SetUpSSHCallCount())
+}
+
+func TestClientImpl_RunCommandSetUpFails(t *testing.T) {
+	client, fakeDeployment := newSSHClient(t)
+	fakeDeployment.SetUpSSHStub = nil
+	fakeDeployment.SetUpSSHReturns(director.SSHResult{}, errors.New("director unavailable"))
+
+	_, err := client.RunCommand("service-instance_some-guid", "mysql/0", "hostname")
+	require.EqualError(t, err, "failed to run command on service-instance_some-guid/mysql/0: director unavailable")
+	require.Equal(t, 0, fakeDeployment.CleanUpSSHCallCount())
+}
+
+func TestClientImpl_RunCommandWithoutHosts(t *testing.T) {
+	client, fakeDeployment := newSSHClient(t)
+	fakeDeployment.SetUpSSHStub = nil
+	fakeDeployment.SetUpSSHReturns(director.SSHResult{}, nil)
+
+	_, err := client.RunCommand("service-instance_some-guid", "mysql/0", "hostname")
+	require.EqualError(t, err, "failed to run command on service-instance_some-guid/mysql/0: no ssh hosts were set up for service-instance_some-guid/mysql/0")
+	require.Equal(t, 1, fakeDeployment.CleanUpSSHCallCount())
+}
+
+func TestClientImpl_UploadAndDownloadFile(t *testing.T) {
+	client, fakeDeployment := newSSHClient(t)
+	dir := t.TempDir()
+
+	src := filepath.Join(dir, "backup.tar")
+	require.NoError(t, os.WriteFile(src, []byte("some backup"), 0600))
+	remote := filepath.Join(dir, "remote file.tar")
+
+	require.NoError(t, client.UploadFile("service-instance_some-guid", "mysql/0", src, remote))
+	b, err := os.ReadFile(remote)
+	require.NoError(t, err)
+	require.Equal(t, "some backup", string(b))
+
+	dst := filepath.Join(dir, "downloaded.tar")
+	require.NoError(t, client.DownloadFile("service-instance_some-guid", "mysql/0", remote, dst))
+	b, err = os.ReadFile(dst)
+	require.NoError(t, err)
+	require.Equal(t, "some backup", string(b))
+
+	require.Equal(t, 2, fakeDeployment.CleanUpSSHCallCount())
+}
+
+func TestClientImpl_DownloadFileMissing(t *testing.T) {
+	client, _ := newSSHClient(t)
+	dir := t.TempDir()
+
+	err := client.DownloadFile("service-instance_some-guid", "mysql/0", filepath.Join(dir, "missing"), filepath.Join(dir, "dst"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "No such file or directory")
+}
@@ -19,14 +19,14 @@ import (
 )
 
 type CachingClient struct {
-	*cfclient.Client
+	*v3Client
 	cache *Cache
 }
 
 func NewCachingClient(client *cfclient.Client) *CachingClient {
 	return &CachingClient{
-		cache:  NewCache(),
-		Client: client,
+		cache:    NewCache(),
+		v3Client: &v3Client{Client: client},
 	}
 }
 
@@ -55,13 +55,25 @@ func (c *CachingClient) ListSpaces() ([]cfclient.Space, error) {
 	if err == nil {
 		c.cache.Store("spaces", spaces)
 		for _, space := range spaces {
-			c.cache.Store("space:"+space.Name, space)
+			c.cache.Store("space:"+space.OrganizationGuid+"/"+space.Name, space)
 			c.cache.Store("space_id:"+space.Guid, space)
 		}
 	}
 	return spaces, err
 }
 
+func (c *CachingClient) ListOrgQuotas() ([]cfclient.OrgQuota, error) {
+	cachedQuotas, ok := c.cache.Load("org_quotas")
+	if ok {
+		return cachedQuotas.([]cfclient.OrgQuota), nil
+	}
+	quotas, err := c.Client.ListOrgQuotas()
+	if err == nil {
+		c.cache.Store("org_quotas", quotas)
+	}
+	return quotas, err
+}
+
 func (c *CachingClient) GetOrgByName(name string) (cfclient.Org, error) {
 	cachedOrg, ok := c.cache.Load("org:" + name)
 	if ok {
@@ -89,13 +101,13 @@ func (c *CachingClient) GetOrgByGuid(orgGUID string) (cfclient.Org, error) {
 }
 
 func (c *CachingClient) GetSpaceByName(name string, orgGUID string) (cfclient.Space, error) {
-	cachedSpace, ok := c.cache.Load("space:" + name)
+	cachedSpace, ok := c.cache.Load("space:" + orgGUID + "/" + name)
 	if ok {
 		return cachedSpace.(cfclient.Space), nil
 	}
 	space, err := c.Client.GetSpaceByName(name, orgGUID)
 	if err == nil {
-		c.cache.Store("space:"+space.Name, space)
+		c.cache.Store("space:"+space.OrganizationGuid+"/"+space.Name, space)
 		c.cache.Store("space_id:"+space.Guid, space)
 	}
 	return space, err
@@ -108,7 +120,7 @@ func (c *CachingClient) GetSpaceByGuid(spaceGUID string) (cfclient.Space, error)
 	}
 	space, err := c.Client.GetSpaceByGuid(spaceGUID)
 	if err == nil {
-		c.cache.Store("space:"+space.Name, space)
+		c.cache.Store("space:"+space.OrganizationGuid+"/"+space.Name, space)
 		c.cache.Store("space_id:"+space.Guid, space)
 	}
 	return space, err
@@ -119,7 +131,7 @@ func (c *CachingClient) GetServiceByGuid(serviceGUID string) (cfclient.Service,
 	if ok {
 		return cachedService.(cfclient.Service), nil
 	}
-	service, err := c.Client.GetServiceByGuid(serviceGUID)
+	service, err := c.v3Client.GetServiceByGuid(serviceGUID)
 	if err == nil {
 		c.cache.Store("service_id:"+service.Guid, service)
 	}
@@ -131,7 +143,7 @@ func (c *CachingClient) GetServicePlanByGUID(servicePlanGUID string) (*cfclient.
 	if ok {
 		return cachedServicePlan.(*cfclient.ServicePlan), nil
 	}
-	servicePlan, err := c.Client.GetServicePlanByGUID(servicePlanGUID)
+	servicePlan, err := c.v3Client.GetServicePlanByGUID(servicePlanGUID)
 	if err == nil {
 		c.cache.Store("serviceplan_id:"+servicePlan.Guid, servicePlan)
 	}
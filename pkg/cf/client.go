@@ -39,12 +39,20 @@ var ErrRetry = errors.New("retry")
 
 //counterfeiter:generate -o fakes . Client
 
+// Client is the subset of the Cloud Controller API used by the migrator. Service instance, credential
+// binding, service key, plan and offering operations use the v3 API, and their ByQuery methods take
+// v3 list filters such as names, space_guids or service_instance_guids.
 type Client interface {
+	AddIsolationSegmentToOrg(isolationSegmentGUID, orgGUID string) error
 	AppByName(appName, spaceGuid, orgGuid string) (cfclient.App, error)
+	AssociateOrgUserByUsername(orgGUID, name string) (cfclient.Org, error)
+	AssociateSpaceAuditorByUsername(spaceGUID, name string) (cfclient.Space, error)
+	AssociateSpaceDeveloperByUsername(spaceGUID, name string) (cfclient.Space, error)
+	AssociateSpaceManagerByUsername(spaceGUID, name string) (cfclient.Space, error)
 	CreateApp(req cfclient.AppCreateRequest) (cfclient.App, error)
 	DeleteApp(guid string) error
 	CreateOrg(req cfclient.OrgRequest) (cfclient.Org, error)
-	CreateServiceBinding(appGUID, serviceInstanceGUID string) (*cfclient.ServiceBinding, error)
+	CreateServiceBinding(req CreateServiceBindingRequest) (*cfclient.ServiceBinding, error)
 	CreateServiceInstance(req cfclient.ServiceInstanceRequest) (cfclient.ServiceInstance, error)
 	CreateServiceKey(req cfclient.CreateServiceKeyRequest) (cfclient.ServiceKey, error)
 	CreateSpace(req cfclient.SpaceRequest) (cfclient.Space, error)
@@ -54,6 +62,8 @@ type Client interface {
 	DeleteServiceInstance(guid string, recursive, async bool) error
 	GetAppByGuidNoInlineCall(guid string) (cfclient.App, error)
 	GetClientConfig() *cfclient.Config
+	GetIsolationSegmentByGUID(guid string) (*cfclient.IsolationSegment, error)
+	GetOrgQuotaByName(name string) (cfclient.OrgQuota, error)
 	GetOrgByGuid(guid string) (cfclient.Org, error)
 	GetOrgByName(name string) (cfclient.Org, error)
 	GetSpaceByGuid(spaceGUID string) (cfclient.Space, error)
@@ -62,8 +72,16 @@ type Client interface {
 	GetServicePlanByGUID(guid string) (*cfclient.ServicePlan, error)
 	GetServiceInstanceByGuid(guid string) (cfclient.ServiceInstance, error)
 	GetServiceInstanceParams(guid string) (map[string]interface{}, error)
+	GetServiceInstanceMetadata(guid string) (Metadata, error)
+	GetServiceBindingParams(guid string) (map[string]interface{}, error)
+	GetServiceCredentialBindingMetadata(guid string) (Metadata, error)
 	GetUserProvidedServiceInstanceByGuid(guid string) (cfclient.UserProvidedServiceInstance, error)
+	IsolationSegmentForSpace(spaceGUID, isolationSegmentGUID string) error
+	ListDomains() ([]cfclient.Domain, error)
+	ListIsolationSegmentsByQuery(query url.Values) ([]cfclient.IsolationSegment, error)
+	ListOrgQuotas() ([]cfclient.OrgQuota, error)
 	ListOrgs() ([]cfclient.Org, error)
+	ListSharedDomains() ([]cfclient.SharedDomain, error)
 	ListUserProvidedServiceInstancesByQuery(query url.Values) ([]cfclient.UserProvidedServiceInstance, error)
 	ListServices() ([]cfclient.Service, error)
 	ListServiceBindingsByQuery(query url.Values) ([]cfclient.ServiceBinding, error)
@@ -73,10 +91,20 @@ type Client interface {
 	ListServicePlans() ([]cfclient.ServicePlan, error)
 	ListSpaces() ([]cfclient.Space, error)
 	ListSpacesByQuery(query url.Values) ([]cfclient.Space, error)
+	ListSpaceAuditors(spaceGUID string) ([]cfclient.User, error)
+	ListSpaceDevelopers(spaceGUID string) ([]cfclient.User, error)
+	ListSpaceManagers(spaceGUID string) ([]cfclient.User, error)
 	ListServiceBrokers() ([]cfclient.ServiceBroker, error)
 	ListServicePlansByQuery(values url.Values) ([]cfclient.ServicePlan, error)
 	UpdateUserProvidedServiceInstance(guid string, req cfclient.UserProvidedServiceInstanceRequest) (*cfclient.UserProvidedServiceInstance, error)
 	UpdateSI(serviceInstanceGuid string, req cfclient.ServiceInstanceUpdateRequest, async bool) error
+	UpdateServiceInstanceMetadata(guid string, metadata Metadata) error
+	UpdateServiceCredentialBindingMetadata(guid string, metadata Metadata) error
+	ListSharedSpaces(serviceInstanceGUID string) ([]SharedSpace, error)
+	ShareServiceInstance(serviceInstanceGUID string, spaceGUIDs []string) error
+	ListRouteBindings(serviceInstanceGUID string) ([]RouteBinding, error)
+	FindRoute(host, domain, path string) (string, error)
+	CreateRouteBinding(routeGUID, serviceInstanceGUID string) error
 	NewRequest(method, path string) *cfclient.Request
 	DoRequest(req *cfclient.Request) (*http.Response, error)
 	DoWithRetry(f func() error) error
@@ -181,6 +209,62 @@ func (c *ClientImpl) CreateOrg(request cfclient.OrgRequest) (cfclient.Org, error
 	return c.lazyLoadCacheClientOrDie().CreateOrg(request)
 }
 
+func (c *ClientImpl) CreateSpace(request cfclient.SpaceRequest) (cfclient.Space, error) {
+	return c.lazyLoadCacheClientOrDie().CreateSpace(request)
+}
+
+func (c *ClientImpl) ListOrgQuotas() ([]cfclient.OrgQuota, error) {
+	return c.lazyLoadCacheClientOrDie().ListOrgQuotas()
+}
+
+func (c *ClientImpl) GetOrgQuotaByName(name string) (cfclient.OrgQuota, error) {
+	return c.lazyLoadCacheClientOrDie().GetOrgQuotaByName(name)
+}
+
+func (c *ClientImpl) AssociateOrgUserByUsername(orgGUID, name string) (cfclient.Org, error) {
+	return c.lazyLoadCacheClientOrDie().AssociateOrgUserByUsername(orgGUID, name)
+}
+
+func (c *ClientImpl) ListSpaceManagers(spaceGUID string) ([]cfclient.User, error) {
+	return c.lazyLoadCacheClientOrDie().ListSpaceManagers(spaceGUID)
+}
+
+func (c *ClientImpl) ListSpaceDevelopers(spaceGUID string) ([]cfclient.User, error) {
+	return c.lazyLoadCacheClientOrDie().ListSpaceDevelopers(spaceGUID)
+}
+
+func (c *ClientImpl) ListSpaceAuditors(spaceGUID string) ([]cfclient.User, error) {
+	return c.lazyLoadCacheClientOrDie().ListSpaceAuditors(spaceGUID)
+}
+
+func (c *ClientImpl) AssociateSpaceManagerByUsername(spaceGUID, name string) (cfclient.Space, error) {
+	return c.lazyLoadCacheClientOrDie().AssociateSpaceManagerByUsername(spaceGUID, name)
+}
+
+func (c *ClientImpl) AssociateSpaceDeveloperByUsername(spaceGUID, name string) (cfclient.Space, error) {
+	return c.lazyLoadCacheClientOrDie().AssociateSpaceDeveloperByUsername(spaceGUID, name)
+}
+
+func (c *ClientImpl) AssociateSpaceAuditorByUsername(spaceGUID, name string) (cfclient.Space, error) {
+	return c.lazyLoadCacheClientOrDie().AssociateSpaceAuditorByUsername(spaceGUID, name)
+}
+
+func (c *ClientImpl) GetIsolationSegmentByGUID(guid string) (*cfclient.IsolationSegment, error) {
+	return c.lazyLoadCacheClientOrDie().GetIsolationSegmentByGUID(guid)
+}
+
+func (c *ClientImpl) ListIsolationSegmentsByQuery(query url.Values) ([]cfclient.IsolationSegment, error) {
+	return c.lazyLoadCacheClientOrDie().ListIsolationSegmentsByQuery(query)
+}
+
+func (c *ClientImpl) AddIsolationSegmentToOrg(isolationSegmentGUID, orgGUID string) error {
+	return c.lazyLoadCacheClientOrDie().AddIsolationSegmentToOrg(isolationSegmentGUID, orgGUID)
+}
+
+func (c *ClientImpl) IsolationSegmentForSpace(spaceGUID, isolationSegmentGUID string) error {
+	return c.lazyLoadCacheClientOrDie().IsolationSegmentForSpace(spaceGUID, isolationSegmentGUID)
+}
+
 func (c *ClientImpl) GetOrgByGuid(guid string) (cfclient.Org, error) {
 	return c.lazyLoadCacheClientOrDie().GetOrgByGuid(guid)
 }
@@ -213,6 +297,82 @@ func (c *ClientImpl) GetServiceInstanceParams(guid string) (map[string]interface
 	return c.lazyLoadCacheClientOrDie().GetServiceInstanceParams(guid)
 }
 
+func (c *ClientImpl) GetServiceInstanceMetadata(guid string) (Metadata, error) {
+	return c.lazyLoadCacheClientOrDie().GetServiceInstanceMetadata(guid)
+}
+
+func (c *ClientImpl) UpdateServiceInstanceMetadata(guid string, metadata Metadata) error {
+	return c.lazyLoadCacheClientOrDie().UpdateServiceInstanceMetadata(guid, metadata)
+}
+
+func (c *ClientImpl) CreateServiceInstance(req cfclient.ServiceInstanceRequest) (cfclient.ServiceInstance, error) {
+	return c.lazyLoadCacheClientOrDie().CreateServiceInstance(req)
+}
+
+func (c *ClientImpl) UpdateSI(serviceInstanceGuid string, req cfclient.ServiceInstanceUpdateRequest, async bool) error {
+	return c.lazyLoadCacheClientOrDie().UpdateSI(serviceInstanceGuid, req, async)
+}
+
+func (c *ClientImpl) ListSharedSpaces(serviceInstanceGUID string) ([]SharedSpace, error) {
+	return c.lazyLoadCacheClientOrDie().ListSharedSpaces(serviceInstanceGUID)
+}
+
+func (c *ClientImpl) ShareServiceInstance(serviceInstanceGUID string, spaceGUIDs []string) error {
+	return c.lazyLoadCacheClientOrDie().ShareServiceInstance(serviceInstanceGUID, spaceGUIDs)
+}
+
+func (c *ClientImpl) ListRouteBindings(serviceInstanceGUID string) ([]RouteBinding, error) {
+	return c.lazyLoadCacheClientOrDie().ListRouteBindings(serviceInstanceGUID)
+}
+
+func (c *ClientImpl) FindRoute(host, domain, path string) (string, error) {
+	return c.lazyLoadCacheClientOrDie().FindRoute(host, domain, path)
+}
+
+func (c *ClientImpl) CreateRouteBinding(routeGUID, serviceInstanceGUID string) error {
+	return c.lazyLoadCacheClientOrDie().CreateRouteBinding(routeGUID, serviceInstanceGUID)
+}
+
+func (c *ClientImpl) DeleteServiceInstance(guid string, recursive, async bool) error {
+	return c.lazyLoadCacheClientOrDie().DeleteServiceInstance(guid, recursive, async)
+}
+
+func (c *ClientImpl) GetUserProvidedServiceInstanceByGuid(guid string) (cfclient.UserProvidedServiceInstance, error) {
+	return c.lazyLoadCacheClientOrDie().GetUserProvidedServiceInstanceByGuid(guid)
+}
+
+func (c *ClientImpl) CreateUserProvidedServiceInstance(req cfclient.UserProvidedServiceInstanceRequest) (*cfclient.UserProvidedServiceInstance, error) {
+	return c.lazyLoadCacheClientOrDie().CreateUserProvidedServiceInstance(req)
+}
+
+func (c *ClientImpl) UpdateUserProvidedServiceInstance(guid string, req cfclient.UserProvidedServiceInstanceRequest) (*cfclient.UserProvidedServiceInstance, error) {
+	return c.lazyLoadCacheClientOrDie().UpdateUserProvidedServiceInstance(guid, req)
+}
+
+func (c *ClientImpl) CreateServiceBinding(req CreateServiceBindingRequest) (*cfclient.ServiceBinding, error) {
+	return c.lazyLoadCacheClientOrDie().CreateServiceBinding(req)
+}
+
+func (c *ClientImpl) GetServiceBindingParams(guid string) (map[string]interface{}, error) {
+	return c.lazyLoadCacheClientOrDie().GetServiceBindingParams(guid)
+}
+
+func (c *ClientImpl) GetServiceCredentialBindingMetadata(guid string) (Metadata, error) {
+	return c.lazyLoadCacheClientOrDie().GetServiceCredentialBindingMetadata(guid)
+}
+
+func (c *ClientImpl) UpdateServiceCredentialBindingMetadata(guid string, metadata Metadata) error {
+	return c.lazyLoadCacheClientOrDie().UpdateServiceCredentialBindingMetadata(guid, metadata)
+}
+
+func (c *ClientImpl) DeleteServiceBinding(guid string) error {
+	return c.lazyLoadCacheClientOrDie().DeleteServiceBinding(guid)
+}
+
+func (c *ClientImpl) CreateServiceKey(req cfclient.CreateServiceKeyRequest) (cfclient.ServiceKey, error) {
+	return c.lazyLoadCacheClientOrDie().CreateServiceKey(req)
+}
+
 func (c *ClientImpl) ListOrgs() ([]cfclient.Org, error) {
 	return c.lazyLoadCacheClientOrDie().ListOrgs()
 }
@@ -221,6 +381,14 @@ func (c *ClientImpl) ListUserProvidedServiceInstancesByQuery(query url.Values) (
 	return c.lazyLoadCacheClientOrDie().ListUserProvidedServiceInstancesByQuery(query)
 }
 
+func (c *ClientImpl) ListDomains() ([]cfclient.Domain, error) {
+	return c.lazyLoadCacheClientOrDie().ListDomains()
+}
+
+func (c *ClientImpl) ListSharedDomains() ([]cfclient.SharedDomain, error) {
+	return c.lazyLoadCacheClientOrDie().ListSharedDomains()
+}
+
 func (c *ClientImpl) ListServices() ([]cfclient.Service, error) {
 	return c.lazyLoadCacheClientOrDie().ListServices()
 }
@@ -43,14 +43,14 @@ func CreateServiceInstance(e exec.Executor, cfHome string, instance ServiceInsta
 
 func createServiceInstance(ctx context.Context, e exec.Executor, cfHome string, instance ServiceInstance) error {
 	log.Infof("Creating service instance")
-	createCommand := fmt.Sprintf("CF_HOME='%s' cf create-service '%s' '%s' '%s'", cfHome, instance.Service, instance.Plan, instance.Name)
+	createCommand := fmt.Sprintf("CF_HOME='%s' cf create-service '%s' '%s' '%s'", cfHome, instance.ServiceToCreate(), instance.PlanToCreate(), instance.Name)
 	if len(instance.Credentials) > 0 {
 		var json = jsoniter.ConfigCompatibleWithStandardLibrary
 		credentialsBytes, err := json.Marshal(instance.Credentials)
 		if err != nil {
 			return fmt.Errorf("error encoding credentials: %w", err)
 		}
-		createCommand = fmt.Sprintf("CF_HOME='%s' cf create-service '%s' '%s' '%s' -c '%s'", cfHome, instance.Service, instance.Plan, instance.Name, strings.Trim(string(credentialsBytes), "\n"))
+		createCommand = fmt.Sprintf("CF_HOME='%s' cf create-service '%s' '%s' '%s' -c '%s'", cfHome, instance.ServiceToCreate(), instance.PlanToCreate(), instance.Name, strings.Trim(string(credentialsBytes), "\n"))
 	}
 	log.Debugf("Create service command: %q", createCommand)
 	_, err := e.Execute(ctx, strings.NewReader(createCommand))
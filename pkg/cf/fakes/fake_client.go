@@ -11,6 +11,18 @@ import (
 )
 
 type FakeClient struct {
+	AddIsolationSegmentToOrgStub        func(string, string) error
+	addIsolationSegmentToOrgMutex       sync.RWMutex
+	addIsolationSegmentToOrgArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	addIsolationSegmentToOrgReturns struct {
+		result1 error
+	}
+	addIsolationSegmentToOrgReturnsOnCall map[int]struct {
+		result1 error
+	}
 	AppByNameStub        func(string, string, string) (cfclient.App, error)
 	appByNameMutex       sync.RWMutex
 	appByNameArgsForCall []struct {
@@ -26,6 +38,62 @@ type FakeClient struct {
 		result1 cfclient.App
 		result2 error
 	}
+	AssociateOrgUserByUsernameStub        func(string, string) (cfclient.Org, error)
+	associateOrgUserByUsernameMutex       sync.RWMutex
+	associateOrgUserByUsernameArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	associateOrgUserByUsernameReturns struct {
+		result1 cfclient.Org
+		result2 error
+	}
+	associateOrgUserByUsernameReturnsOnCall map[int]struct {
+		result1 cfclient.Org
+		result2 error
+	}
+	AssociateSpaceAuditorByUsernameStub        func(string, string) (cfclient.Space, error)
+	associateSpaceAuditorByUsernameMutex       sync.RWMutex
+	associateSpaceAuditorByUsernameArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	associateSpaceAuditorByUsernameReturns struct {
+		result1 cfclient.Space
+		result2 error
+	}
+	associateSpaceAuditorByUsernameReturnsOnCall map[int]struct {
+		result1 cfclient.Space
+		result2 error
+	}
+	AssociateSpaceDeveloperByUsernameStub        func(string, string) (cfclient.Space, error)
+	associateSpaceDeveloperByUsernameMutex       sync.RWMutex
+	associateSpaceDeveloperByUsernameArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	associateSpaceDeveloperByUsernameReturns struct {
+		result1 cfclient.Space
+		result2 error
+	}
+	associateSpaceDeveloperByUsernameReturnsOnCall map[int]struct {
+		result1 cfclient.Space
+		result2 error
+	}
+	AssociateSpaceManagerByUsernameStub        func(string, string) (cfclient.Space, error)
+	associateSpaceManagerByUsernameMutex       sync.RWMutex
+	associateSpaceManagerByUsernameArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	associateSpaceManagerByUsernameReturns struct {
+		result1 cfclient.Space
+		result2 error
+	}
+	associateSpaceManagerByUsernameReturnsOnCall map[int]struct {
+		result1 cfclient.Space
+		result2 error
+	}
 	CreateAppStub        func(cfclient.AppCreateRequest) (cfclient.App, error)
 	createAppMutex       sync.RWMutex
 	createAppArgsForCall []struct {
@@ -52,12 +120,23 @@ type FakeClient struct {
 		result1 cfclient.Org
 		result2 error
 	}
-	CreateServiceBindingStub        func(string, string) (*cfclient.ServiceBinding, error)
-	createServiceBindingMutex       sync.RWMutex
-	createServiceBindingArgsForCall []struct {
+	CreateRouteBindingStub        func(string, string) error
+	createRouteBindingMutex       sync.RWMutex
+	createRouteBindingArgsForCall []struct {
 		arg1 string
 		arg2 string
 	}
+	createRouteBindingReturns struct {
+		result1 error
+	}
+	createRouteBindingReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateServiceBindingStub        func(cf.CreateServiceBindingRequest) (*cfclient.ServiceBinding, error)
+	createServiceBindingMutex       sync.RWMutex
+	createServiceBindingArgsForCall []struct {
+		arg1 cf.CreateServiceBindingRequest
+	}
 	createServiceBindingReturns struct {
 		result1 *cfclient.ServiceBinding
 		result2 error
@@ -190,6 +269,21 @@ type FakeClient struct {
 	doWithRetryReturnsOnCall map[int]struct {
 		result1 error
 	}
+	FindRouteStub        func(string, string, string) (string, error)
+	findRouteMutex       sync.RWMutex
+	findRouteArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	findRouteReturns struct {
+		result1 string
+		result2 error
+	}
+	findRouteReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	GetAppByGuidNoInlineCallStub        func(string) (cfclient.App, error)
 	getAppByGuidNoInlineCallMutex       sync.RWMutex
 	getAppByGuidNoInlineCallArgsForCall []struct {
@@ -213,6 +307,19 @@ type FakeClient struct {
 	getClientConfigReturnsOnCall map[int]struct {
 		result1 *cfclient.Config
 	}
+	GetIsolationSegmentByGUIDStub        func(string) (*cfclient.IsolationSegment, error)
+	getIsolationSegmentByGUIDMutex       sync.RWMutex
+	getIsolationSegmentByGUIDArgsForCall []struct {
+		arg1 string
+	}
+	getIsolationSegmentByGUIDReturns struct {
+		result1 *cfclient.IsolationSegment
+		result2 error
+	}
+	getIsolationSegmentByGUIDReturnsOnCall map[int]struct {
+		result1 *cfclient.IsolationSegment
+		result2 error
+	}
 	GetOrgByGuidStub        func(string) (cfclient.Org, error)
 	getOrgByGuidMutex       sync.RWMutex
 	getOrgByGuidArgsForCall []struct {
@@ -239,6 +346,32 @@ type FakeClient struct {
 		result1 cfclient.Org
 		result2 error
 	}
+	GetOrgQuotaByNameStub        func(string) (cfclient.OrgQuota, error)
+	getOrgQuotaByNameMutex       sync.RWMutex
+	getOrgQuotaByNameArgsForCall []struct {
+		arg1 string
+	}
+	getOrgQuotaByNameReturns struct {
+		result1 cfclient.OrgQuota
+		result2 error
+	}
+	getOrgQuotaByNameReturnsOnCall map[int]struct {
+		result1 cfclient.OrgQuota
+		result2 error
+	}
+	GetServiceBindingParamsStub        func(string) (map[string]interface{}, error)
+	getServiceBindingParamsMutex       sync.RWMutex
+	getServiceBindingParamsArgsForCall []struct {
+		arg1 string
+	}
+	getServiceBindingParamsReturns struct {
+		result1 map[string]interface{}
+		result2 error
+	}
+	getServiceBindingParamsReturnsOnCall map[int]struct {
+		result1 map[string]interface{}
+		result2 error
+	}
 	GetServiceByGuidStub        func(string) (cfclient.Service, error)
 	getServiceByGuidMutex       sync.RWMutex
 	getServiceByGuidArgsForCall []struct {
@@ -252,6 +385,19 @@ type FakeClient struct {
 		result1 cfclient.Service
 		result2 error
 	}
+	GetServiceCredentialBindingMetadataStub        func(string) (cf.Metadata, error)
+	getServiceCredentialBindingMetadataMutex       sync.RWMutex
+	getServiceCredentialBindingMetadataArgsForCall []struct {
+		arg1 string
+	}
+	getServiceCredentialBindingMetadataReturns struct {
+		result1 cf.Metadata
+		result2 error
+	}
+	getServiceCredentialBindingMetadataReturnsOnCall map[int]struct {
+		result1 cf.Metadata
+		result2 error
+	}
 	GetServiceInstanceByGuidStub        func(string) (cfclient.ServiceInstance, error)
 	getServiceInstanceByGuidMutex       sync.RWMutex
 	getServiceInstanceByGuidArgsForCall []struct {
@@ -265,6 +411,19 @@ type FakeClient struct {
 		result1 cfclient.ServiceInstance
 		result2 error
 	}
+	GetServiceInstanceMetadataStub        func(string) (cf.Metadata, error)
+	getServiceInstanceMetadataMutex       sync.RWMutex
+	getServiceInstanceMetadataArgsForCall []struct {
+		arg1 string
+	}
+	getServiceInstanceMetadataReturns struct {
+		result1 cf.Metadata
+		result2 error
+	}
+	getServiceInstanceMetadataReturnsOnCall map[int]struct {
+		result1 cf.Metadata
+		result2 error
+	}
 	GetServiceInstanceParamsStub        func(string) (map[string]interface{}, error)
 	getServiceInstanceParamsMutex       sync.RWMutex
 	getServiceInstanceParamsArgsForCall []struct {
@@ -331,6 +490,55 @@ type FakeClient struct {
 		result1 cfclient.UserProvidedServiceInstance
 		result2 error
 	}
+	IsolationSegmentForSpaceStub        func(string, string) error
+	isolationSegmentForSpaceMutex       sync.RWMutex
+	isolationSegmentForSpaceArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	isolationSegmentForSpaceReturns struct {
+		result1 error
+	}
+	isolationSegmentForSpaceReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ListDomainsStub        func() ([]cfclient.Domain, error)
+	listDomainsMutex       sync.RWMutex
+	listDomainsArgsForCall []struct {
+	}
+	listDomainsReturns struct {
+		result1 []cfclient.Domain
+		result2 error
+	}
+	listDomainsReturnsOnCall map[int]struct {
+		result1 []cfclient.Domain
+		result2 error
+	}
+	ListIsolationSegmentsByQueryStub        func(url.Values) ([]cfclient.IsolationSegment, error)
+	listIsolationSegmentsByQueryMutex       sync.RWMutex
+	listIsolationSegmentsByQueryArgsForCall []struct {
+		arg1 url.Values
+	}
+	listIsolationSegmentsByQueryReturns struct {
+		result1 []cfclient.IsolationSegment
+		result2 error
+	}
+	listIsolationSegmentsByQueryReturnsOnCall map[int]struct {
+		result1 []cfclient.IsolationSegment
+		result2 error
+	}
+	ListOrgQuotasStub        func() ([]cfclient.OrgQuota, error)
+	listOrgQuotasMutex       sync.RWMutex
+	listOrgQuotasArgsForCall []struct {
+	}
+	listOrgQuotasReturns struct {
+		result1 []cfclient.OrgQuota
+		result2 error
+	}
+	listOrgQuotasReturnsOnCall map[int]struct {
+		result1 []cfclient.OrgQuota
+		result2 error
+	}
 	ListOrgsStub        func() ([]cfclient.Org, error)
 	listOrgsMutex       sync.RWMutex
 	listOrgsArgsForCall []struct {
@@ -343,6 +551,19 @@ type FakeClient struct {
 		result1 []cfclient.Org
 		result2 error
 	}
+	ListRouteBindingsStub        func(string) ([]cf.RouteBinding, error)
+	listRouteBindingsMutex       sync.RWMutex
+	listRouteBindingsArgsForCall []struct {
+		arg1 string
+	}
+	listRouteBindingsReturns struct {
+		result1 []cf.RouteBinding
+		result2 error
+	}
+	listRouteBindingsReturnsOnCall map[int]struct {
+		result1 []cf.RouteBinding
+		result2 error
+	}
 	ListServiceBindingsByQueryStub        func(url.Values) ([]cfclient.ServiceBinding, error)
 	listServiceBindingsByQueryMutex       sync.RWMutex
 	listServiceBindingsByQueryArgsForCall []struct {
@@ -431,6 +652,70 @@ type FakeClient struct {
 		result1 []cfclient.Service
 		result2 error
 	}
+	ListSharedDomainsStub        func() ([]cfclient.SharedDomain, error)
+	listSharedDomainsMutex       sync.RWMutex
+	listSharedDomainsArgsForCall []struct {
+	}
+	listSharedDomainsReturns struct {
+		result1 []cfclient.SharedDomain
+		result2 error
+	}
+	listSharedDomainsReturnsOnCall map[int]struct {
+		result1 []cfclient.SharedDomain
+		result2 error
+	}
+	ListSharedSpacesStub        func(string) ([]cf.SharedSpace, error)
+	listSharedSpacesMutex       sync.RWMutex
+	listSharedSpacesArgsForCall []struct {
+		arg1 string
+	}
+	listSharedSpacesReturns struct {
+		result1 []cf.SharedSpace
+		result2 error
+	}
+	listSharedSpacesReturnsOnCall map[int]struct {
+		result1 []cf.SharedSpace
+		result2 error
+	}
+	ListSpaceAuditorsStub        func(string) ([]cfclient.User, error)
+	listSpaceAuditorsMutex       sync.RWMutex
+	listSpaceAuditorsArgsForCall []struct {
+		arg1 string
+	}
+	listSpaceAuditorsReturns struct {
+		result1 []cfclient.User
+		result2 error
+	}
+	listSpaceAuditorsReturnsOnCall map[int]struct {
+		result1 []cfclient.User
+		result2 error
+	}
+	ListSpaceDevelopersStub        func(string) ([]cfclient.User, error)
+	listSpaceDevelopersMutex       sync.RWMutex
+	listSpaceDevelopersArgsForCall []struct {
+		arg1 string
+	}
+	listSpaceDevelopersReturns struct {
+		result1 []cfclient.User
+		result2 error
+	}
+	listSpaceDevelopersReturnsOnCall map[int]struct {
+		result1 []cfclient.User
+		result2 error
+	}
+	ListSpaceManagersStub        func(string) ([]cfclient.User, error)
+	listSpaceManagersMutex       sync.RWMutex
+	listSpaceManagersArgsForCall []struct {
+		arg1 string
+	}
+	listSpaceManagersReturns struct {
+		result1 []cfclient.User
+		result2 error
+	}
+	listSpaceManagersReturnsOnCall map[int]struct {
+		result1 []cfclient.User
+		result2 error
+	}
 	ListSpaceServiceInstancesStub        func(string) ([]cfclient.ServiceInstance, error)
 	listSpaceServiceInstancesMutex       sync.RWMutex
 	listSpaceServiceInstancesArgsForCall []struct {
@@ -494,6 +779,18 @@ type FakeClient struct {
 	newRequestReturnsOnCall map[int]struct {
 		result1 *cfclient.Request
 	}
+	ShareServiceInstanceStub        func(string, []string) error
+	shareServiceInstanceMutex       sync.RWMutex
+	shareServiceInstanceArgsForCall []struct {
+		arg1 string
+		arg2 []string
+	}
+	shareServiceInstanceReturns struct {
+		result1 error
+	}
+	shareServiceInstanceReturnsOnCall map[int]struct {
+		result1 error
+	}
 	UpdateSIStub        func(string, cfclient.ServiceInstanceUpdateRequest, bool) error
 	updateSIMutex       sync.RWMutex
 	updateSIArgsForCall []struct {
@@ -507,6 +804,30 @@ type FakeClient struct {
 	updateSIReturnsOnCall map[int]struct {
 		result1 error
 	}
+	UpdateServiceCredentialBindingMetadataStub        func(string, cf.Metadata) error
+	updateServiceCredentialBindingMetadataMutex       sync.RWMutex
+	updateServiceCredentialBindingMetadataArgsForCall []struct {
+		arg1 string
+		arg2 cf.Metadata
+	}
+	updateServiceCredentialBindingMetadataReturns struct {
+		result1 error
+	}
+	updateServiceCredentialBindingMetadataReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateServiceInstanceMetadataStub        func(string, cf.Metadata) error
+	updateServiceInstanceMetadataMutex       sync.RWMutex
+	updateServiceInstanceMetadataArgsForCall []struct {
+		arg1 string
+		arg2 cf.Metadata
+	}
+	updateServiceInstanceMetadataReturns struct {
+		result1 error
+	}
+	updateServiceInstanceMetadataReturnsOnCall map[int]struct {
+		result1 error
+	}
 	UpdateUserProvidedServiceInstanceStub        func(string, cfclient.UserProvidedServiceInstanceRequest) (*cfclient.UserProvidedServiceInstance, error)
 	updateUserProvidedServiceInstanceMutex       sync.RWMutex
 	updateUserProvidedServiceInstanceArgsForCall []struct {
@@ -525,6 +846,68 @@ type FakeClient struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeClient) AddIsolationSegmentToOrg(arg1 string, arg2 string) error {
+	fake.addIsolationSegmentToOrgMutex.Lock()
+	ret, specificReturn := fake.addIsolationSegmentToOrgReturnsOnCall[len(fake.addIsolationSegmentToOrgArgsForCall)]
+	fake.addIsolationSegmentToOrgArgsForCall = append(fake.addIsolationSegmentToOrgArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AddIsolationSegmentToOrgStub
+	fakeReturns := fake.addIsolationSegmentToOrgReturns
+	fake.recordInvocation("AddIsolationSegmentToOrg", []interface{}{arg1, arg2})
+	fake.addIsolationSegmentToOrgMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) AddIsolationSegmentToOrgCallCount() int {
+	fake.addIsolationSegmentToOrgMutex.RLock()
+	defer fake.addIsolationSegmentToOrgMutex.RUnlock()
+	return len(fake.addIsolationSegmentToOrgArgsForCall)
+}
+
+func (fake *FakeClient) AddIsolationSegmentToOrgCalls(stub func(string, string) error) {
+	fake.addIsolationSegmentToOrgMutex.Lock()
+	defer fake.addIsolationSegmentToOrgMutex.Unlock()
+	fake.AddIsolationSegmentToOrgStub = stub
+}
+
+func (fake *FakeClient) AddIsolationSegmentToOrgArgsForCall(i int) (string, string) {
+	fake.addIsolationSegmentToOrgMutex.RLock()
+	defer fake.addIsolationSegmentToOrgMutex.RUnlock()
+	argsForCall := fake.addIsolationSegmentToOrgArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) AddIsolationSegmentToOrgReturns(result1 error) {
+	fake.addIsolationSegmentToOrgMutex.Lock()
+	defer fake.addIsolationSegmentToOrgMutex.Unlock()
+	fake.AddIsolationSegmentToOrgStub = nil
+	fake.addIsolationSegmentToOrgReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) AddIsolationSegmentToOrgReturnsOnCall(i int, result1 error) {
+	fake.addIsolationSegmentToOrgMutex.Lock()
+	defer fake.addIsolationSegmentToOrgMutex.Unlock()
+	fake.AddIsolationSegmentToOrgStub = nil
+	if fake.addIsolationSegmentToOrgReturnsOnCall == nil {
+		fake.addIsolationSegmentToOrgReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.addIsolationSegmentToOrgReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeClient) AppByName(arg1 string, arg2 string, arg3 string) (cfclient.App, error) {
 	fake.appByNameMutex.Lock()
 	ret, specificReturn := fake.appByNameReturnsOnCall[len(fake.appByNameArgsForCall)]
@@ -591,18 +974,19 @@ func (fake *FakeClient) AppByNameReturnsOnCall(i int, result1 cfclient.App, resu
 	}{result1, result2}
 }
 
-func (fake *FakeClient) CreateApp(arg1 cfclient.AppCreateRequest) (cfclient.App, error) {
-	fake.createAppMutex.Lock()
-	ret, specificReturn := fake.createAppReturnsOnCall[len(fake.createAppArgsForCall)]
-	fake.createAppArgsForCall = append(fake.createAppArgsForCall, struct {
-		arg1 cfclient.AppCreateRequest
-	}{arg1})
-	stub := fake.CreateAppStub
-	fakeReturns := fake.createAppReturns
-	fake.recordInvocation("CreateApp", []interface{}{arg1})
-	fake.createAppMutex.Unlock()
+func (fake *FakeClient) AssociateOrgUserByUsername(arg1 string, arg2 string) (cfclient.Org, error) {
+	fake.associateOrgUserByUsernameMutex.Lock()
+	ret, specificReturn := fake.associateOrgUserByUsernameReturnsOnCall[len(fake.associateOrgUserByUsernameArgsForCall)]
+	fake.associateOrgUserByUsernameArgsForCall = append(fake.associateOrgUserByUsernameArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AssociateOrgUserByUsernameStub
+	fakeReturns := fake.associateOrgUserByUsernameReturns
+	fake.recordInvocation("AssociateOrgUserByUsername", []interface{}{arg1, arg2})
+	fake.associateOrgUserByUsernameMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -610,62 +994,321 @@ func (fake *FakeClient) CreateApp(arg1 cfclient.AppCreateRequest) (cfclient.App,
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeClient) CreateAppCallCount() int {
-	fake.createAppMutex.RLock()
-	defer fake.createAppMutex.RUnlock()
-	return len(fake.createAppArgsForCall)
+func (fake *FakeClient) AssociateOrgUserByUsernameCallCount() int {
+	fake.associateOrgUserByUsernameMutex.RLock()
+	defer fake.associateOrgUserByUsernameMutex.RUnlock()
+	return len(fake.associateOrgUserByUsernameArgsForCall)
 }
 
-func (fake *FakeClient) CreateAppCalls(stub func(cfclient.AppCreateRequest) (cfclient.App, error)) {
-	fake.createAppMutex.Lock()
-	defer fake.createAppMutex.Unlock()
-	fake.CreateAppStub = stub
+func (fake *FakeClient) AssociateOrgUserByUsernameCalls(stub func(string, string) (cfclient.Org, error)) {
+	fake.associateOrgUserByUsernameMutex.Lock()
+	defer fake.associateOrgUserByUsernameMutex.Unlock()
+	fake.AssociateOrgUserByUsernameStub = stub
 }
 
-func (fake *FakeClient) CreateAppArgsForCall(i int) cfclient.AppCreateRequest {
-	fake.createAppMutex.RLock()
-	defer fake.createAppMutex.RUnlock()
-	argsForCall := fake.createAppArgsForCall[i]
-	return argsForCall.arg1
+func (fake *FakeClient) AssociateOrgUserByUsernameArgsForCall(i int) (string, string) {
+	fake.associateOrgUserByUsernameMutex.RLock()
+	defer fake.associateOrgUserByUsernameMutex.RUnlock()
+	argsForCall := fake.associateOrgUserByUsernameArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeClient) CreateAppReturns(result1 cfclient.App, result2 error) {
-	fake.createAppMutex.Lock()
-	defer fake.createAppMutex.Unlock()
-	fake.CreateAppStub = nil
-	fake.createAppReturns = struct {
-		result1 cfclient.App
+func (fake *FakeClient) AssociateOrgUserByUsernameReturns(result1 cfclient.Org, result2 error) {
+	fake.associateOrgUserByUsernameMutex.Lock()
+	defer fake.associateOrgUserByUsernameMutex.Unlock()
+	fake.AssociateOrgUserByUsernameStub = nil
+	fake.associateOrgUserByUsernameReturns = struct {
+		result1 cfclient.Org
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeClient) CreateAppReturnsOnCall(i int, result1 cfclient.App, result2 error) {
-	fake.createAppMutex.Lock()
-	defer fake.createAppMutex.Unlock()
-	fake.CreateAppStub = nil
-	if fake.createAppReturnsOnCall == nil {
-		fake.createAppReturnsOnCall = make(map[int]struct {
-			result1 cfclient.App
+func (fake *FakeClient) AssociateOrgUserByUsernameReturnsOnCall(i int, result1 cfclient.Org, result2 error) {
+	fake.associateOrgUserByUsernameMutex.Lock()
+	defer fake.associateOrgUserByUsernameMutex.Unlock()
+	fake.AssociateOrgUserByUsernameStub = nil
+	if fake.associateOrgUserByUsernameReturnsOnCall == nil {
+		fake.associateOrgUserByUsernameReturnsOnCall = make(map[int]struct {
+			result1 cfclient.Org
 			result2 error
 		})
 	}
-	fake.createAppReturnsOnCall[i] = struct {
-		result1 cfclient.App
+	fake.associateOrgUserByUsernameReturnsOnCall[i] = struct {
+		result1 cfclient.Org
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeClient) CreateOrg(arg1 cfclient.OrgRequest) (cfclient.Org, error) {
-	fake.createOrgMutex.Lock()
-	ret, specificReturn := fake.createOrgReturnsOnCall[len(fake.createOrgArgsForCall)]
-	fake.createOrgArgsForCall = append(fake.createOrgArgsForCall, struct {
-		arg1 cfclient.OrgRequest
-	}{arg1})
-	stub := fake.CreateOrgStub
-	fakeReturns := fake.createOrgReturns
-	fake.recordInvocation("CreateOrg", []interface{}{arg1})
-	fake.createOrgMutex.Unlock()
-	if stub != nil {
+func (fake *FakeClient) AssociateSpaceAuditorByUsername(arg1 string, arg2 string) (cfclient.Space, error) {
+	fake.associateSpaceAuditorByUsernameMutex.Lock()
+	ret, specificReturn := fake.associateSpaceAuditorByUsernameReturnsOnCall[len(fake.associateSpaceAuditorByUsernameArgsForCall)]
+	fake.associateSpaceAuditorByUsernameArgsForCall = append(fake.associateSpaceAuditorByUsernameArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AssociateSpaceAuditorByUsernameStub
+	fakeReturns := fake.associateSpaceAuditorByUsernameReturns
+	fake.recordInvocation("AssociateSpaceAuditorByUsername", []interface{}{arg1, arg2})
+	fake.associateSpaceAuditorByUsernameMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) AssociateSpaceAuditorByUsernameCallCount() int {
+	fake.associateSpaceAuditorByUsernameMutex.RLock()
+	defer fake.associateSpaceAuditorByUsernameMutex.RUnlock()
+	return len(fake.associateSpaceAuditorByUsernameArgsForCall)
+}
+
+func (fake *FakeClient) AssociateSpaceAuditorByUsernameCalls(stub func(string, string) (cfclient.Space, error)) {
+	fake.associateSpaceAuditorByUsernameMutex.Lock()
+	defer fake.associateSpaceAuditorByUsernameMutex.Unlock()
+	fake.AssociateSpaceAuditorByUsernameStub = stub
+}
+
+func (fake *FakeClient) AssociateSpaceAuditorByUsernameArgsForCall(i int) (string, string) {
+	fake.associateSpaceAuditorByUsernameMutex.RLock()
+	defer fake.associateSpaceAuditorByUsernameMutex.RUnlock()
+	argsForCall := fake.associateSpaceAuditorByUsernameArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) AssociateSpaceAuditorByUsernameReturns(result1 cfclient.Space, result2 error) {
+	fake.associateSpaceAuditorByUsernameMutex.Lock()
+	defer fake.associateSpaceAuditorByUsernameMutex.Unlock()
+	fake.AssociateSpaceAuditorByUsernameStub = nil
+	fake.associateSpaceAuditorByUsernameReturns = struct {
+		result1 cfclient.Space
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) AssociateSpaceAuditorByUsernameReturnsOnCall(i int, result1 cfclient.Space, result2 error) {
+	fake.associateSpaceAuditorByUsernameMutex.Lock()
+	defer fake.associateSpaceAuditorByUsernameMutex.Unlock()
+	fake.AssociateSpaceAuditorByUsernameStub = nil
+	if fake.associateSpaceAuditorByUsernameReturnsOnCall == nil {
+		fake.associateSpaceAuditorByUsernameReturnsOnCall = make(map[int]struct {
+			result1 cfclient.Space
+			result2 error
+		})
+	}
+	fake.associateSpaceAuditorByUsernameReturnsOnCall[i] = struct {
+		result1 cfclient.Space
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) AssociateSpaceDeveloperByUsername(arg1 string, arg2 string) (cfclient.Space, error) {
+	fake.associateSpaceDeveloperByUsernameMutex.Lock()
+	ret, specificReturn := fake.associateSpaceDeveloperByUsernameReturnsOnCall[len(fake.associateSpaceDeveloperByUsernameArgsForCall)]
+	fake.associateSpaceDeveloperByUsernameArgsForCall = append(fake.associateSpaceDeveloperByUsernameArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AssociateSpaceDeveloperByUsernameStub
+	fakeReturns := fake.associateSpaceDeveloperByUsernameReturns
+	fake.recordInvocation("AssociateSpaceDeveloperByUsername", []interface{}{arg1, arg2})
+	fake.associateSpaceDeveloperByUsernameMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) AssociateSpaceDeveloperByUsernameCallCount() int {
+	fake.associateSpaceDeveloperByUsernameMutex.RLock()
+	defer fake.associateSpaceDeveloperByUsernameMutex.RUnlock()
+	return len(fake.associateSpaceDeveloperByUsernameArgsForCall)
+}
+
+func (fake *FakeClient) AssociateSpaceDeveloperByUsernameCalls(stub func(string, string) (cfclient.Space, error)) {
+	fake.associateSpaceDeveloperByUsernameMutex.Lock()
+	defer fake.associateSpaceDeveloperByUsernameMutex.Unlock()
+	fake.AssociateSpaceDeveloperByUsernameStub = stub
+}
+
+func (fake *FakeClient) AssociateSpaceDeveloperByUsernameArgsForCall(i int) (string, string) {
+	fake.associateSpaceDeveloperByUsernameMutex.RLock()
+	defer fake.associateSpaceDeveloperByUsernameMutex.RUnlock()
+	argsForCall := fake.associateSpaceDeveloperByUsernameArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) AssociateSpaceDeveloperByUsernameReturns(result1 cfclient.Space, result2 error) {
+	fake.associateSpaceDeveloperByUsernameMutex.Lock()
+	defer fake.associateSpaceDeveloperByUsernameMutex.Unlock()
+	fake.AssociateSpaceDeveloperByUsernameStub = nil
+	fake.associateSpaceDeveloperByUsernameReturns = struct {
+		result1 cfclient.Space
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) AssociateSpaceDeveloperByUsernameReturnsOnCall(i int, result1 cfclient.Space, result2 error) {
+	fake.associateSpaceDeveloperByUsernameMutex.Lock()
+	defer fake.associateSpaceDeveloperByUsernameMutex.Unlock()
+	fake.AssociateSpaceDeveloperByUsernameStub = nil
+	if fake.associateSpaceDeveloperByUsernameReturnsOnCall == nil {
+		fake.associateSpaceDeveloperByUsernameReturnsOnCall = make(map[int]struct {
+			result1 cfclient.Space
+			result2 error
+		})
+	}
+	fake.associateSpaceDeveloperByUsernameReturnsOnCall[i] = struct {
+		result1 cfclient.Space
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) AssociateSpaceManagerByUsername(arg1 string, arg2 string) (cfclient.Space, error) {
+	fake.associateSpaceManagerByUsernameMutex.Lock()
+	ret, specificReturn := fake.associateSpaceManagerByUsernameReturnsOnCall[len(fake.associateSpaceManagerByUsernameArgsForCall)]
+	fake.associateSpaceManagerByUsernameArgsForCall = append(fake.associateSpaceManagerByUsernameArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AssociateSpaceManagerByUsernameStub
+	fakeReturns := fake.associateSpaceManagerByUsernameReturns
+	fake.recordInvocation("AssociateSpaceManagerByUsername", []interface{}{arg1, arg2})
+	fake.associateSpaceManagerByUsernameMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) AssociateSpaceManagerByUsernameCallCount() int {
+	fake.associateSpaceManagerByUsernameMutex.RLock()
+	defer fake.associateSpaceManagerByUsernameMutex.RUnlock()
+	return len(fake.associateSpaceManagerByUsernameArgsForCall)
+}
+
+func (fake *FakeClient) AssociateSpaceManagerByUsernameCalls(stub func(string, string) (cfclient.Space, error)) {
+	fake.associateSpaceManagerByUsernameMutex.Lock()
+	defer fake.associateSpaceManagerByUsernameMutex.Unlock()
+	fake.AssociateSpaceManagerByUsernameStub = stub
+}
+
+func (fake *FakeClient) AssociateSpaceManagerByUsernameArgsForCall(i int) (string, string) {
+	fake.associateSpaceManagerByUsernameMutex.RLock()
+	defer fake.associateSpaceManagerByUsernameMutex.RUnlock()
+	argsForCall := fake.associateSpaceManagerByUsernameArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) AssociateSpaceManagerByUsernameReturns(result1 cfclient.Space, result2 error) {
+	fake.associateSpaceManagerByUsernameMutex.Lock()
+	defer fake.associateSpaceManagerByUsernameMutex.Unlock()
+	fake.AssociateSpaceManagerByUsernameStub = nil
+	fake.associateSpaceManagerByUsernameReturns = struct {
+		result1 cfclient.Space
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) AssociateSpaceManagerByUsernameReturnsOnCall(i int, result1 cfclient.Space, result2 error) {
+	fake.associateSpaceManagerByUsernameMutex.Lock()
+	defer fake.associateSpaceManagerByUsernameMutex.Unlock()
+	fake.AssociateSpaceManagerByUsernameStub = nil
+	if fake.associateSpaceManagerByUsernameReturnsOnCall == nil {
+		fake.associateSpaceManagerByUsernameReturnsOnCall = make(map[int]struct {
+			result1 cfclient.Space
+			result2 error
+		})
+	}
+	fake.associateSpaceManagerByUsernameReturnsOnCall[i] = struct {
+		result1 cfclient.Space
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) CreateApp(arg1 cfclient.AppCreateRequest) (cfclient.App, error) {
+	fake.createAppMutex.Lock()
+	ret, specificReturn := fake.createAppReturnsOnCall[len(fake.createAppArgsForCall)]
+	fake.createAppArgsForCall = append(fake.createAppArgsForCall, struct {
+		arg1 cfclient.AppCreateRequest
+	}{arg1})
+	stub := fake.CreateAppStub
+	fakeReturns := fake.createAppReturns
+	fake.recordInvocation("CreateApp", []interface{}{arg1})
+	fake.createAppMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) CreateAppCallCount() int {
+	fake.createAppMutex.RLock()
+	defer fake.createAppMutex.RUnlock()
+	return len(fake.createAppArgsForCall)
+}
+
+func (fake *FakeClient) CreateAppCalls(stub func(cfclient.AppCreateRequest) (cfclient.App, error)) {
+	fake.createAppMutex.Lock()
+	defer fake.createAppMutex.Unlock()
+	fake.CreateAppStub = stub
+}
+
+func (fake *FakeClient) CreateAppArgsForCall(i int) cfclient.AppCreateRequest {
+	fake.createAppMutex.RLock()
+	defer fake.createAppMutex.RUnlock()
+	argsForCall := fake.createAppArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) CreateAppReturns(result1 cfclient.App, result2 error) {
+	fake.createAppMutex.Lock()
+	defer fake.createAppMutex.Unlock()
+	fake.CreateAppStub = nil
+	fake.createAppReturns = struct {
+		result1 cfclient.App
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) CreateAppReturnsOnCall(i int, result1 cfclient.App, result2 error) {
+	fake.createAppMutex.Lock()
+	defer fake.createAppMutex.Unlock()
+	fake.CreateAppStub = nil
+	if fake.createAppReturnsOnCall == nil {
+		fake.createAppReturnsOnCall = make(map[int]struct {
+			result1 cfclient.App
+			result2 error
+		})
+	}
+	fake.createAppReturnsOnCall[i] = struct {
+		result1 cfclient.App
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) CreateOrg(arg1 cfclient.OrgRequest) (cfclient.Org, error) {
+	fake.createOrgMutex.Lock()
+	ret, specificReturn := fake.createOrgReturnsOnCall[len(fake.createOrgArgsForCall)]
+	fake.createOrgArgsForCall = append(fake.createOrgArgsForCall, struct {
+		arg1 cfclient.OrgRequest
+	}{arg1})
+	stub := fake.CreateOrgStub
+	fakeReturns := fake.createOrgReturns
+	fake.recordInvocation("CreateOrg", []interface{}{arg1})
+	fake.createOrgMutex.Unlock()
+	if stub != nil {
 		return stub(arg1)
 	}
 	if specificReturn {
@@ -719,19 +1362,80 @@ func (fake *FakeClient) CreateOrgReturnsOnCall(i int, result1 cfclient.Org, resu
 	}{result1, result2}
 }
 
-func (fake *FakeClient) CreateServiceBinding(arg1 string, arg2 string) (*cfclient.ServiceBinding, error) {
-	fake.createServiceBindingMutex.Lock()
-	ret, specificReturn := fake.createServiceBindingReturnsOnCall[len(fake.createServiceBindingArgsForCall)]
-	fake.createServiceBindingArgsForCall = append(fake.createServiceBindingArgsForCall, struct {
+func (fake *FakeClient) CreateRouteBinding(arg1 string, arg2 string) error {
+	fake.createRouteBindingMutex.Lock()
+	ret, specificReturn := fake.createRouteBindingReturnsOnCall[len(fake.createRouteBindingArgsForCall)]
+	fake.createRouteBindingArgsForCall = append(fake.createRouteBindingArgsForCall, struct {
 		arg1 string
 		arg2 string
 	}{arg1, arg2})
+	stub := fake.CreateRouteBindingStub
+	fakeReturns := fake.createRouteBindingReturns
+	fake.recordInvocation("CreateRouteBinding", []interface{}{arg1, arg2})
+	fake.createRouteBindingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) CreateRouteBindingCallCount() int {
+	fake.createRouteBindingMutex.RLock()
+	defer fake.createRouteBindingMutex.RUnlock()
+	return len(fake.createRouteBindingArgsForCall)
+}
+
+func (fake *FakeClient) CreateRouteBindingCalls(stub func(string, string) error) {
+	fake.createRouteBindingMutex.Lock()
+	defer fake.createRouteBindingMutex.Unlock()
+	fake.CreateRouteBindingStub = stub
+}
+
+func (fake *FakeClient) CreateRouteBindingArgsForCall(i int) (string, string) {
+	fake.createRouteBindingMutex.RLock()
+	defer fake.createRouteBindingMutex.RUnlock()
+	argsForCall := fake.createRouteBindingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) CreateRouteBindingReturns(result1 error) {
+	fake.createRouteBindingMutex.Lock()
+	defer fake.createRouteBindingMutex.Unlock()
+	fake.CreateRouteBindingStub = nil
+	fake.createRouteBindingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) CreateRouteBindingReturnsOnCall(i int, result1 error) {
+	fake.createRouteBindingMutex.Lock()
+	defer fake.createRouteBindingMutex.Unlock()
+	fake.CreateRouteBindingStub = nil
+	if fake.createRouteBindingReturnsOnCall == nil {
+		fake.createRouteBindingReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createRouteBindingReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) CreateServiceBinding(arg1 cf.CreateServiceBindingRequest) (*cfclient.ServiceBinding, error) {
+	fake.createServiceBindingMutex.Lock()
+	ret, specificReturn := fake.createServiceBindingReturnsOnCall[len(fake.createServiceBindingArgsForCall)]
+	fake.createServiceBindingArgsForCall = append(fake.createServiceBindingArgsForCall, struct {
+		arg1 cf.CreateServiceBindingRequest
+	}{arg1})
 	stub := fake.CreateServiceBindingStub
 	fakeReturns := fake.createServiceBindingReturns
-	fake.recordInvocation("CreateServiceBinding", []interface{}{arg1, arg2})
+	fake.recordInvocation("CreateServiceBinding", []interface{}{arg1})
 	fake.createServiceBindingMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -745,17 +1449,17 @@ func (fake *FakeClient) CreateServiceBindingCallCount() int {
 	return len(fake.createServiceBindingArgsForCall)
 }
 
-func (fake *FakeClient) CreateServiceBindingCalls(stub func(string, string) (*cfclient.ServiceBinding, error)) {
+func (fake *FakeClient) CreateServiceBindingCalls(stub func(cf.CreateServiceBindingRequest) (*cfclient.ServiceBinding, error)) {
 	fake.createServiceBindingMutex.Lock()
 	defer fake.createServiceBindingMutex.Unlock()
 	fake.CreateServiceBindingStub = stub
 }
 
-func (fake *FakeClient) CreateServiceBindingArgsForCall(i int) (string, string) {
+func (fake *FakeClient) CreateServiceBindingArgsForCall(i int) cf.CreateServiceBindingRequest {
 	fake.createServiceBindingMutex.RLock()
 	defer fake.createServiceBindingMutex.RUnlock()
 	argsForCall := fake.createServiceBindingArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1
 }
 
 func (fake *FakeClient) CreateServiceBindingReturns(result1 *cfclient.ServiceBinding, result2 error) {
@@ -1413,6 +2117,72 @@ func (fake *FakeClient) DoWithRetryReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeClient) FindRoute(arg1 string, arg2 string, arg3 string) (string, error) {
+	fake.findRouteMutex.Lock()
+	ret, specificReturn := fake.findRouteReturnsOnCall[len(fake.findRouteArgsForCall)]
+	fake.findRouteArgsForCall = append(fake.findRouteArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.FindRouteStub
+	fakeReturns := fake.findRouteReturns
+	fake.recordInvocation("FindRoute", []interface{}{arg1, arg2, arg3})
+	fake.findRouteMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) FindRouteCallCount() int {
+	fake.findRouteMutex.RLock()
+	defer fake.findRouteMutex.RUnlock()
+	return len(fake.findRouteArgsForCall)
+}
+
+func (fake *FakeClient) FindRouteCalls(stub func(string, string, string) (string, error)) {
+	fake.findRouteMutex.Lock()
+	defer fake.findRouteMutex.Unlock()
+	fake.FindRouteStub = stub
+}
+
+func (fake *FakeClient) FindRouteArgsForCall(i int) (string, string, string) {
+	fake.findRouteMutex.RLock()
+	defer fake.findRouteMutex.RUnlock()
+	argsForCall := fake.findRouteArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeClient) FindRouteReturns(result1 string, result2 error) {
+	fake.findRouteMutex.Lock()
+	defer fake.findRouteMutex.Unlock()
+	fake.FindRouteStub = nil
+	fake.findRouteReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) FindRouteReturnsOnCall(i int, result1 string, result2 error) {
+	fake.findRouteMutex.Lock()
+	defer fake.findRouteMutex.Unlock()
+	fake.FindRouteStub = nil
+	if fake.findRouteReturnsOnCall == nil {
+		fake.findRouteReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.findRouteReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) GetAppByGuidNoInlineCall(arg1 string) (cfclient.App, error) {
 	fake.getAppByGuidNoInlineCallMutex.Lock()
 	ret, specificReturn := fake.getAppByGuidNoInlineCallReturnsOnCall[len(fake.getAppByGuidNoInlineCallArgsForCall)]
@@ -1530,16 +2300,16 @@ func (fake *FakeClient) GetClientConfigReturnsOnCall(i int, result1 *cfclient.Co
 	}{result1}
 }
 
-func (fake *FakeClient) GetOrgByGuid(arg1 string) (cfclient.Org, error) {
-	fake.getOrgByGuidMutex.Lock()
-	ret, specificReturn := fake.getOrgByGuidReturnsOnCall[len(fake.getOrgByGuidArgsForCall)]
-	fake.getOrgByGuidArgsForCall = append(fake.getOrgByGuidArgsForCall, struct {
+func (fake *FakeClient) GetIsolationSegmentByGUID(arg1 string) (*cfclient.IsolationSegment, error) {
+	fake.getIsolationSegmentByGUIDMutex.Lock()
+	ret, specificReturn := fake.getIsolationSegmentByGUIDReturnsOnCall[len(fake.getIsolationSegmentByGUIDArgsForCall)]
+	fake.getIsolationSegmentByGUIDArgsForCall = append(fake.getIsolationSegmentByGUIDArgsForCall, struct {
 		arg1 string
 	}{arg1})
-	stub := fake.GetOrgByGuidStub
-	fakeReturns := fake.getOrgByGuidReturns
-	fake.recordInvocation("GetOrgByGuid", []interface{}{arg1})
-	fake.getOrgByGuidMutex.Unlock()
+	stub := fake.GetIsolationSegmentByGUIDStub
+	fakeReturns := fake.getIsolationSegmentByGUIDReturns
+	fake.recordInvocation("GetIsolationSegmentByGUID", []interface{}{arg1})
+	fake.getIsolationSegmentByGUIDMutex.Unlock()
 	if stub != nil {
 		return stub(arg1)
 	}
@@ -1549,13 +2319,77 @@ func (fake *FakeClient) GetOrgByGuid(arg1 string) (cfclient.Org, error) {
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeClient) GetOrgByGuidCallCount() int {
-	fake.getOrgByGuidMutex.RLock()
-	defer fake.getOrgByGuidMutex.RUnlock()
-	return len(fake.getOrgByGuidArgsForCall)
+func (fake *FakeClient) GetIsolationSegmentByGUIDCallCount() int {
+	fake.getIsolationSegmentByGUIDMutex.RLock()
+	defer fake.getIsolationSegmentByGUIDMutex.RUnlock()
+	return len(fake.getIsolationSegmentByGUIDArgsForCall)
 }
 
-func (fake *FakeClient) GetOrgByGuidCalls(stub func(string) (cfclient.Org, error)) {
+func (fake *FakeClient) GetIsolationSegmentByGUIDCalls(stub func(string) (*cfclient.IsolationSegment, error)) {
+	fake.getIsolationSegmentByGUIDMutex.Lock()
+	defer fake.getIsolationSegmentByGUIDMutex.Unlock()
+	fake.GetIsolationSegmentByGUIDStub = stub
+}
+
+func (fake *FakeClient) GetIsolationSegmentByGUIDArgsForCall(i int) string {
+	fake.getIsolationSegmentByGUIDMutex.RLock()
+	defer fake.getIsolationSegmentByGUIDMutex.RUnlock()
+	argsForCall := fake.getIsolationSegmentByGUIDArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) GetIsolationSegmentByGUIDReturns(result1 *cfclient.IsolationSegment, result2 error) {
+	fake.getIsolationSegmentByGUIDMutex.Lock()
+	defer fake.getIsolationSegmentByGUIDMutex.Unlock()
+	fake.GetIsolationSegmentByGUIDStub = nil
+	fake.getIsolationSegmentByGUIDReturns = struct {
+		result1 *cfclient.IsolationSegment
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetIsolationSegmentByGUIDReturnsOnCall(i int, result1 *cfclient.IsolationSegment, result2 error) {
+	fake.getIsolationSegmentByGUIDMutex.Lock()
+	defer fake.getIsolationSegmentByGUIDMutex.Unlock()
+	fake.GetIsolationSegmentByGUIDStub = nil
+	if fake.getIsolationSegmentByGUIDReturnsOnCall == nil {
+		fake.getIsolationSegmentByGUIDReturnsOnCall = make(map[int]struct {
+			result1 *cfclient.IsolationSegment
+			result2 error
+		})
+	}
+	fake.getIsolationSegmentByGUIDReturnsOnCall[i] = struct {
+		result1 *cfclient.IsolationSegment
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetOrgByGuid(arg1 string) (cfclient.Org, error) {
+	fake.getOrgByGuidMutex.Lock()
+	ret, specificReturn := fake.getOrgByGuidReturnsOnCall[len(fake.getOrgByGuidArgsForCall)]
+	fake.getOrgByGuidArgsForCall = append(fake.getOrgByGuidArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetOrgByGuidStub
+	fakeReturns := fake.getOrgByGuidReturns
+	fake.recordInvocation("GetOrgByGuid", []interface{}{arg1})
+	fake.getOrgByGuidMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) GetOrgByGuidCallCount() int {
+	fake.getOrgByGuidMutex.RLock()
+	defer fake.getOrgByGuidMutex.RUnlock()
+	return len(fake.getOrgByGuidArgsForCall)
+}
+
+func (fake *FakeClient) GetOrgByGuidCalls(stub func(string) (cfclient.Org, error)) {
 	fake.getOrgByGuidMutex.Lock()
 	defer fake.getOrgByGuidMutex.Unlock()
 	fake.GetOrgByGuidStub = stub
@@ -1658,6 +2492,134 @@ func (fake *FakeClient) GetOrgByNameReturnsOnCall(i int, result1 cfclient.Org, r
 	}{result1, result2}
 }
 
+func (fake *FakeClient) GetOrgQuotaByName(arg1 string) (cfclient.OrgQuota, error) {
+	fake.getOrgQuotaByNameMutex.Lock()
+	ret, specificReturn := fake.getOrgQuotaByNameReturnsOnCall[len(fake.getOrgQuotaByNameArgsForCall)]
+	fake.getOrgQuotaByNameArgsForCall = append(fake.getOrgQuotaByNameArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetOrgQuotaByNameStub
+	fakeReturns := fake.getOrgQuotaByNameReturns
+	fake.recordInvocation("GetOrgQuotaByName", []interface{}{arg1})
+	fake.getOrgQuotaByNameMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) GetOrgQuotaByNameCallCount() int {
+	fake.getOrgQuotaByNameMutex.RLock()
+	defer fake.getOrgQuotaByNameMutex.RUnlock()
+	return len(fake.getOrgQuotaByNameArgsForCall)
+}
+
+func (fake *FakeClient) GetOrgQuotaByNameCalls(stub func(string) (cfclient.OrgQuota, error)) {
+	fake.getOrgQuotaByNameMutex.Lock()
+	defer fake.getOrgQuotaByNameMutex.Unlock()
+	fake.GetOrgQuotaByNameStub = stub
+}
+
+func (fake *FakeClient) GetOrgQuotaByNameArgsForCall(i int) string {
+	fake.getOrgQuotaByNameMutex.RLock()
+	defer fake.getOrgQuotaByNameMutex.RUnlock()
+	argsForCall := fake.getOrgQuotaByNameArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) GetOrgQuotaByNameReturns(result1 cfclient.OrgQuota, result2 error) {
+	fake.getOrgQuotaByNameMutex.Lock()
+	defer fake.getOrgQuotaByNameMutex.Unlock()
+	fake.GetOrgQuotaByNameStub = nil
+	fake.getOrgQuotaByNameReturns = struct {
+		result1 cfclient.OrgQuota
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetOrgQuotaByNameReturnsOnCall(i int, result1 cfclient.OrgQuota, result2 error) {
+	fake.getOrgQuotaByNameMutex.Lock()
+	defer fake.getOrgQuotaByNameMutex.Unlock()
+	fake.GetOrgQuotaByNameStub = nil
+	if fake.getOrgQuotaByNameReturnsOnCall == nil {
+		fake.getOrgQuotaByNameReturnsOnCall = make(map[int]struct {
+			result1 cfclient.OrgQuota
+			result2 error
+		})
+	}
+	fake.getOrgQuotaByNameReturnsOnCall[i] = struct {
+		result1 cfclient.OrgQuota
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServiceBindingParams(arg1 string) (map[string]interface{}, error) {
+	fake.getServiceBindingParamsMutex.Lock()
+	ret, specificReturn := fake.getServiceBindingParamsReturnsOnCall[len(fake.getServiceBindingParamsArgsForCall)]
+	fake.getServiceBindingParamsArgsForCall = append(fake.getServiceBindingParamsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetServiceBindingParamsStub
+	fakeReturns := fake.getServiceBindingParamsReturns
+	fake.recordInvocation("GetServiceBindingParams", []interface{}{arg1})
+	fake.getServiceBindingParamsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) GetServiceBindingParamsCallCount() int {
+	fake.getServiceBindingParamsMutex.RLock()
+	defer fake.getServiceBindingParamsMutex.RUnlock()
+	return len(fake.getServiceBindingParamsArgsForCall)
+}
+
+func (fake *FakeClient) GetServiceBindingParamsCalls(stub func(string) (map[string]interface{}, error)) {
+	fake.getServiceBindingParamsMutex.Lock()
+	defer fake.getServiceBindingParamsMutex.Unlock()
+	fake.GetServiceBindingParamsStub = stub
+}
+
+func (fake *FakeClient) GetServiceBindingParamsArgsForCall(i int) string {
+	fake.getServiceBindingParamsMutex.RLock()
+	defer fake.getServiceBindingParamsMutex.RUnlock()
+	argsForCall := fake.getServiceBindingParamsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) GetServiceBindingParamsReturns(result1 map[string]interface{}, result2 error) {
+	fake.getServiceBindingParamsMutex.Lock()
+	defer fake.getServiceBindingParamsMutex.Unlock()
+	fake.GetServiceBindingParamsStub = nil
+	fake.getServiceBindingParamsReturns = struct {
+		result1 map[string]interface{}
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServiceBindingParamsReturnsOnCall(i int, result1 map[string]interface{}, result2 error) {
+	fake.getServiceBindingParamsMutex.Lock()
+	defer fake.getServiceBindingParamsMutex.Unlock()
+	fake.GetServiceBindingParamsStub = nil
+	if fake.getServiceBindingParamsReturnsOnCall == nil {
+		fake.getServiceBindingParamsReturnsOnCall = make(map[int]struct {
+			result1 map[string]interface{}
+			result2 error
+		})
+	}
+	fake.getServiceBindingParamsReturnsOnCall[i] = struct {
+		result1 map[string]interface{}
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) GetServiceByGuid(arg1 string) (cfclient.Service, error) {
 	fake.getServiceByGuidMutex.Lock()
 	ret, specificReturn := fake.getServiceByGuidReturnsOnCall[len(fake.getServiceByGuidArgsForCall)]
@@ -1722,6 +2684,70 @@ func (fake *FakeClient) GetServiceByGuidReturnsOnCall(i int, result1 cfclient.Se
 	}{result1, result2}
 }
 
+func (fake *FakeClient) GetServiceCredentialBindingMetadata(arg1 string) (cf.Metadata, error) {
+	fake.getServiceCredentialBindingMetadataMutex.Lock()
+	ret, specificReturn := fake.getServiceCredentialBindingMetadataReturnsOnCall[len(fake.getServiceCredentialBindingMetadataArgsForCall)]
+	fake.getServiceCredentialBindingMetadataArgsForCall = append(fake.getServiceCredentialBindingMetadataArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetServiceCredentialBindingMetadataStub
+	fakeReturns := fake.getServiceCredentialBindingMetadataReturns
+	fake.recordInvocation("GetServiceCredentialBindingMetadata", []interface{}{arg1})
+	fake.getServiceCredentialBindingMetadataMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) GetServiceCredentialBindingMetadataCallCount() int {
+	fake.getServiceCredentialBindingMetadataMutex.RLock()
+	defer fake.getServiceCredentialBindingMetadataMutex.RUnlock()
+	return len(fake.getServiceCredentialBindingMetadataArgsForCall)
+}
+
+func (fake *FakeClient) GetServiceCredentialBindingMetadataCalls(stub func(string) (cf.Metadata, error)) {
+	fake.getServiceCredentialBindingMetadataMutex.Lock()
+	defer fake.getServiceCredentialBindingMetadataMutex.Unlock()
+	fake.GetServiceCredentialBindingMetadataStub = stub
+}
+
+func (fake *FakeClient) GetServiceCredentialBindingMetadataArgsForCall(i int) string {
+	fake.getServiceCredentialBindingMetadataMutex.RLock()
+	defer fake.getServiceCredentialBindingMetadataMutex.RUnlock()
+	argsForCall := fake.getServiceCredentialBindingMetadataArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) GetServiceCredentialBindingMetadataReturns(result1 cf.Metadata, result2 error) {
+	fake.getServiceCredentialBindingMetadataMutex.Lock()
+	defer fake.getServiceCredentialBindingMetadataMutex.Unlock()
+	fake.GetServiceCredentialBindingMetadataStub = nil
+	fake.getServiceCredentialBindingMetadataReturns = struct {
+		result1 cf.Metadata
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServiceCredentialBindingMetadataReturnsOnCall(i int, result1 cf.Metadata, result2 error) {
+	fake.getServiceCredentialBindingMetadataMutex.Lock()
+	defer fake.getServiceCredentialBindingMetadataMutex.Unlock()
+	fake.GetServiceCredentialBindingMetadataStub = nil
+	if fake.getServiceCredentialBindingMetadataReturnsOnCall == nil {
+		fake.getServiceCredentialBindingMetadataReturnsOnCall = make(map[int]struct {
+			result1 cf.Metadata
+			result2 error
+		})
+	}
+	fake.getServiceCredentialBindingMetadataReturnsOnCall[i] = struct {
+		result1 cf.Metadata
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) GetServiceInstanceByGuid(arg1 string) (cfclient.ServiceInstance, error) {
 	fake.getServiceInstanceByGuidMutex.Lock()
 	ret, specificReturn := fake.getServiceInstanceByGuidReturnsOnCall[len(fake.getServiceInstanceByGuidArgsForCall)]
@@ -1786,6 +2812,70 @@ func (fake *FakeClient) GetServiceInstanceByGuidReturnsOnCall(i int, result1 cfc
 	}{result1, result2}
 }
 
+func (fake *FakeClient) GetServiceInstanceMetadata(arg1 string) (cf.Metadata, error) {
+	fake.getServiceInstanceMetadataMutex.Lock()
+	ret, specificReturn := fake.getServiceInstanceMetadataReturnsOnCall[len(fake.getServiceInstanceMetadataArgsForCall)]
+	fake.getServiceInstanceMetadataArgsForCall = append(fake.getServiceInstanceMetadataArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetServiceInstanceMetadataStub
+	fakeReturns := fake.getServiceInstanceMetadataReturns
+	fake.recordInvocation("GetServiceInstanceMetadata", []interface{}{arg1})
+	fake.getServiceInstanceMetadataMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) GetServiceInstanceMetadataCallCount() int {
+	fake.getServiceInstanceMetadataMutex.RLock()
+	defer fake.getServiceInstanceMetadataMutex.RUnlock()
+	return len(fake.getServiceInstanceMetadataArgsForCall)
+}
+
+func (fake *FakeClient) GetServiceInstanceMetadataCalls(stub func(string) (cf.Metadata, error)) {
+	fake.getServiceInstanceMetadataMutex.Lock()
+	defer fake.getServiceInstanceMetadataMutex.Unlock()
+	fake.GetServiceInstanceMetadataStub = stub
+}
+
+func (fake *FakeClient) GetServiceInstanceMetadataArgsForCall(i int) string {
+	fake.getServiceInstanceMetadataMutex.RLock()
+	defer fake.getServiceInstanceMetadataMutex.RUnlock()
+	argsForCall := fake.getServiceInstanceMetadataArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) GetServiceInstanceMetadataReturns(result1 cf.Metadata, result2 error) {
+	fake.getServiceInstanceMetadataMutex.Lock()
+	defer fake.getServiceInstanceMetadataMutex.Unlock()
+	fake.GetServiceInstanceMetadataStub = nil
+	fake.getServiceInstanceMetadataReturns = struct {
+		result1 cf.Metadata
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServiceInstanceMetadataReturnsOnCall(i int, result1 cf.Metadata, result2 error) {
+	fake.getServiceInstanceMetadataMutex.Lock()
+	defer fake.getServiceInstanceMetadataMutex.Unlock()
+	fake.GetServiceInstanceMetadataStub = nil
+	if fake.getServiceInstanceMetadataReturnsOnCall == nil {
+		fake.getServiceInstanceMetadataReturnsOnCall = make(map[int]struct {
+			result1 cf.Metadata
+			result2 error
+		})
+	}
+	fake.getServiceInstanceMetadataReturnsOnCall[i] = struct {
+		result1 cf.Metadata
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) GetServiceInstanceParams(arg1 string) (map[string]interface{}, error) {
 	fake.getServiceInstanceParamsMutex.Lock()
 	ret, specificReturn := fake.getServiceInstanceParamsReturnsOnCall[len(fake.getServiceInstanceParamsArgsForCall)]
@@ -2107,6 +3197,244 @@ func (fake *FakeClient) GetUserProvidedServiceInstanceByGuidReturnsOnCall(i int,
 	}{result1, result2}
 }
 
+func (fake *FakeClient) IsolationSegmentForSpace(arg1 string, arg2 string) error {
+	fake.isolationSegmentForSpaceMutex.Lock()
+	ret, specificReturn := fake.isolationSegmentForSpaceReturnsOnCall[len(fake.isolationSegmentForSpaceArgsForCall)]
+	fake.isolationSegmentForSpaceArgsForCall = append(fake.isolationSegmentForSpaceArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.IsolationSegmentForSpaceStub
+	fakeReturns := fake.isolationSegmentForSpaceReturns
+	fake.recordInvocation("IsolationSegmentForSpace", []interface{}{arg1, arg2})
+	fake.isolationSegmentForSpaceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) IsolationSegmentForSpaceCallCount() int {
+	fake.isolationSegmentForSpaceMutex.RLock()
+	defer fake.isolationSegmentForSpaceMutex.RUnlock()
+	return len(fake.isolationSegmentForSpaceArgsForCall)
+}
+
+func (fake *FakeClient) IsolationSegmentForSpaceCalls(stub func(string, string) error) {
+	fake.isolationSegmentForSpaceMutex.Lock()
+	defer fake.isolationSegmentForSpaceMutex.Unlock()
+	fake.IsolationSegmentForSpaceStub = stub
+}
+
+func (fake *FakeClient) IsolationSegmentForSpaceArgsForCall(i int) (string, string) {
+	fake.isolationSegmentForSpaceMutex.RLock()
+	defer fake.isolationSegmentForSpaceMutex.RUnlock()
+	argsForCall := fake.isolationSegmentForSpaceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) IsolationSegmentForSpaceReturns(result1 error) {
+	fake.isolationSegmentForSpaceMutex.Lock()
+	defer fake.isolationSegmentForSpaceMutex.Unlock()
+	fake.IsolationSegmentForSpaceStub = nil
+	fake.isolationSegmentForSpaceReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) IsolationSegmentForSpaceReturnsOnCall(i int, result1 error) {
+	fake.isolationSegmentForSpaceMutex.Lock()
+	defer fake.isolationSegmentForSpaceMutex.Unlock()
+	fake.IsolationSegmentForSpaceStub = nil
+	if fake.isolationSegmentForSpaceReturnsOnCall == nil {
+		fake.isolationSegmentForSpaceReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.isolationSegmentForSpaceReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) ListDomains() ([]cfclient.Domain, error) {
+	fake.listDomainsMutex.Lock()
+	ret, specificReturn := fake.listDomainsReturnsOnCall[len(fake.listDomainsArgsForCall)]
+	fake.listDomainsArgsForCall = append(fake.listDomainsArgsForCall, struct {
+	}{})
+	stub := fake.ListDomainsStub
+	fakeReturns := fake.listDomainsReturns
+	fake.recordInvocation("ListDomains", []interface{}{})
+	fake.listDomainsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListDomainsCallCount() int {
+	fake.listDomainsMutex.RLock()
+	defer fake.listDomainsMutex.RUnlock()
+	return len(fake.listDomainsArgsForCall)
+}
+
+func (fake *FakeClient) ListDomainsCalls(stub func() ([]cfclient.Domain, error)) {
+	fake.listDomainsMutex.Lock()
+	defer fake.listDomainsMutex.Unlock()
+	fake.ListDomainsStub = stub
+}
+
+func (fake *FakeClient) ListDomainsReturns(result1 []cfclient.Domain, result2 error) {
+	fake.listDomainsMutex.Lock()
+	defer fake.listDomainsMutex.Unlock()
+	fake.ListDomainsStub = nil
+	fake.listDomainsReturns = struct {
+		result1 []cfclient.Domain
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListDomainsReturnsOnCall(i int, result1 []cfclient.Domain, result2 error) {
+	fake.listDomainsMutex.Lock()
+	defer fake.listDomainsMutex.Unlock()
+	fake.ListDomainsStub = nil
+	if fake.listDomainsReturnsOnCall == nil {
+		fake.listDomainsReturnsOnCall = make(map[int]struct {
+			result1 []cfclient.Domain
+			result2 error
+		})
+	}
+	fake.listDomainsReturnsOnCall[i] = struct {
+		result1 []cfclient.Domain
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListIsolationSegmentsByQuery(arg1 url.Values) ([]cfclient.IsolationSegment, error) {
+	fake.listIsolationSegmentsByQueryMutex.Lock()
+	ret, specificReturn := fake.listIsolationSegmentsByQueryReturnsOnCall[len(fake.listIsolationSegmentsByQueryArgsForCall)]
+	fake.listIsolationSegmentsByQueryArgsForCall = append(fake.listIsolationSegmentsByQueryArgsForCall, struct {
+		arg1 url.Values
+	}{arg1})
+	stub := fake.ListIsolationSegmentsByQueryStub
+	fakeReturns := fake.listIsolationSegmentsByQueryReturns
+	fake.recordInvocation("ListIsolationSegmentsByQuery", []interface{}{arg1})
+	fake.listIsolationSegmentsByQueryMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListIsolationSegmentsByQueryCallCount() int {
+	fake.listIsolationSegmentsByQueryMutex.RLock()
+	defer fake.listIsolationSegmentsByQueryMutex.RUnlock()
+	return len(fake.listIsolationSegmentsByQueryArgsForCall)
+}
+
+func (fake *FakeClient) ListIsolationSegmentsByQueryCalls(stub func(url.Values) ([]cfclient.IsolationSegment, error)) {
+	fake.listIsolationSegmentsByQueryMutex.Lock()
+	defer fake.listIsolationSegmentsByQueryMutex.Unlock()
+	fake.ListIsolationSegmentsByQueryStub = stub
+}
+
+func (fake *FakeClient) ListIsolationSegmentsByQueryArgsForCall(i int) url.Values {
+	fake.listIsolationSegmentsByQueryMutex.RLock()
+	defer fake.listIsolationSegmentsByQueryMutex.RUnlock()
+	argsForCall := fake.listIsolationSegmentsByQueryArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) ListIsolationSegmentsByQueryReturns(result1 []cfclient.IsolationSegment, result2 error) {
+	fake.listIsolationSegmentsByQueryMutex.Lock()
+	defer fake.listIsolationSegmentsByQueryMutex.Unlock()
+	fake.ListIsolationSegmentsByQueryStub = nil
+	fake.listIsolationSegmentsByQueryReturns = struct {
+		result1 []cfclient.IsolationSegment
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListIsolationSegmentsByQueryReturnsOnCall(i int, result1 []cfclient.IsolationSegment, result2 error) {
+	fake.listIsolationSegmentsByQueryMutex.Lock()
+	defer fake.listIsolationSegmentsByQueryMutex.Unlock()
+	fake.ListIsolationSegmentsByQueryStub = nil
+	if fake.listIsolationSegmentsByQueryReturnsOnCall == nil {
+		fake.listIsolationSegmentsByQueryReturnsOnCall = make(map[int]struct {
+			result1 []cfclient.IsolationSegment
+			result2 error
+		})
+	}
+	fake.listIsolationSegmentsByQueryReturnsOnCall[i] = struct {
+		result1 []cfclient.IsolationSegment
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListOrgQuotas() ([]cfclient.OrgQuota, error) {
+	fake.listOrgQuotasMutex.Lock()
+	ret, specificReturn := fake.listOrgQuotasReturnsOnCall[len(fake.listOrgQuotasArgsForCall)]
+	fake.listOrgQuotasArgsForCall = append(fake.listOrgQuotasArgsForCall, struct {
+	}{})
+	stub := fake.ListOrgQuotasStub
+	fakeReturns := fake.listOrgQuotasReturns
+	fake.recordInvocation("ListOrgQuotas", []interface{}{})
+	fake.listOrgQuotasMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListOrgQuotasCallCount() int {
+	fake.listOrgQuotasMutex.RLock()
+	defer fake.listOrgQuotasMutex.RUnlock()
+	return len(fake.listOrgQuotasArgsForCall)
+}
+
+func (fake *FakeClient) ListOrgQuotasCalls(stub func() ([]cfclient.OrgQuota, error)) {
+	fake.listOrgQuotasMutex.Lock()
+	defer fake.listOrgQuotasMutex.Unlock()
+	fake.ListOrgQuotasStub = stub
+}
+
+func (fake *FakeClient) ListOrgQuotasReturns(result1 []cfclient.OrgQuota, result2 error) {
+	fake.listOrgQuotasMutex.Lock()
+	defer fake.listOrgQuotasMutex.Unlock()
+	fake.ListOrgQuotasStub = nil
+	fake.listOrgQuotasReturns = struct {
+		result1 []cfclient.OrgQuota
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListOrgQuotasReturnsOnCall(i int, result1 []cfclient.OrgQuota, result2 error) {
+	fake.listOrgQuotasMutex.Lock()
+	defer fake.listOrgQuotasMutex.Unlock()
+	fake.ListOrgQuotasStub = nil
+	if fake.listOrgQuotasReturnsOnCall == nil {
+		fake.listOrgQuotasReturnsOnCall = make(map[int]struct {
+			result1 []cfclient.OrgQuota
+			result2 error
+		})
+	}
+	fake.listOrgQuotasReturnsOnCall[i] = struct {
+		result1 []cfclient.OrgQuota
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) ListOrgs() ([]cfclient.Org, error) {
 	fake.listOrgsMutex.Lock()
 	ret, specificReturn := fake.listOrgsReturnsOnCall[len(fake.listOrgsArgsForCall)]
@@ -2117,7 +3445,64 @@ func (fake *FakeClient) ListOrgs() ([]cfclient.Org, error) {
 	fake.recordInvocation("ListOrgs", []interface{}{})
 	fake.listOrgsMutex.Unlock()
 	if stub != nil {
-		return stub()
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListOrgsCallCount() int {
+	fake.listOrgsMutex.RLock()
+	defer fake.listOrgsMutex.RUnlock()
+	return len(fake.listOrgsArgsForCall)
+}
+
+func (fake *FakeClient) ListOrgsCalls(stub func() ([]cfclient.Org, error)) {
+	fake.listOrgsMutex.Lock()
+	defer fake.listOrgsMutex.Unlock()
+	fake.ListOrgsStub = stub
+}
+
+func (fake *FakeClient) ListOrgsReturns(result1 []cfclient.Org, result2 error) {
+	fake.listOrgsMutex.Lock()
+	defer fake.listOrgsMutex.Unlock()
+	fake.ListOrgsStub = nil
+	fake.listOrgsReturns = struct {
+		result1 []cfclient.Org
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListOrgsReturnsOnCall(i int, result1 []cfclient.Org, result2 error) {
+	fake.listOrgsMutex.Lock()
+	defer fake.listOrgsMutex.Unlock()
+	fake.ListOrgsStub = nil
+	if fake.listOrgsReturnsOnCall == nil {
+		fake.listOrgsReturnsOnCall = make(map[int]struct {
+			result1 []cfclient.Org
+			result2 error
+		})
+	}
+	fake.listOrgsReturnsOnCall[i] = struct {
+		result1 []cfclient.Org
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListRouteBindings(arg1 string) ([]cf.RouteBinding, error) {
+	fake.listRouteBindingsMutex.Lock()
+	ret, specificReturn := fake.listRouteBindingsReturnsOnCall[len(fake.listRouteBindingsArgsForCall)]
+	fake.listRouteBindingsArgsForCall = append(fake.listRouteBindingsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ListRouteBindingsStub
+	fakeReturns := fake.listRouteBindingsReturns
+	fake.recordInvocation("ListRouteBindings", []interface{}{arg1})
+	fake.listRouteBindingsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -2125,40 +3510,47 @@ func (fake *FakeClient) ListOrgs() ([]cfclient.Org, error) {
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeClient) ListOrgsCallCount() int {
-	fake.listOrgsMutex.RLock()
-	defer fake.listOrgsMutex.RUnlock()
-	return len(fake.listOrgsArgsForCall)
+func (fake *FakeClient) ListRouteBindingsCallCount() int {
+	fake.listRouteBindingsMutex.RLock()
+	defer fake.listRouteBindingsMutex.RUnlock()
+	return len(fake.listRouteBindingsArgsForCall)
 }
 
-func (fake *FakeClient) ListOrgsCalls(stub func() ([]cfclient.Org, error)) {
-	fake.listOrgsMutex.Lock()
-	defer fake.listOrgsMutex.Unlock()
-	fake.ListOrgsStub = stub
+func (fake *FakeClient) ListRouteBindingsCalls(stub func(string) ([]cf.RouteBinding, error)) {
+	fake.listRouteBindingsMutex.Lock()
+	defer fake.listRouteBindingsMutex.Unlock()
+	fake.ListRouteBindingsStub = stub
 }
 
-func (fake *FakeClient) ListOrgsReturns(result1 []cfclient.Org, result2 error) {
-	fake.listOrgsMutex.Lock()
-	defer fake.listOrgsMutex.Unlock()
-	fake.ListOrgsStub = nil
-	fake.listOrgsReturns = struct {
-		result1 []cfclient.Org
+func (fake *FakeClient) ListRouteBindingsArgsForCall(i int) string {
+	fake.listRouteBindingsMutex.RLock()
+	defer fake.listRouteBindingsMutex.RUnlock()
+	argsForCall := fake.listRouteBindingsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) ListRouteBindingsReturns(result1 []cf.RouteBinding, result2 error) {
+	fake.listRouteBindingsMutex.Lock()
+	defer fake.listRouteBindingsMutex.Unlock()
+	fake.ListRouteBindingsStub = nil
+	fake.listRouteBindingsReturns = struct {
+		result1 []cf.RouteBinding
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeClient) ListOrgsReturnsOnCall(i int, result1 []cfclient.Org, result2 error) {
-	fake.listOrgsMutex.Lock()
-	defer fake.listOrgsMutex.Unlock()
-	fake.ListOrgsStub = nil
-	if fake.listOrgsReturnsOnCall == nil {
-		fake.listOrgsReturnsOnCall = make(map[int]struct {
-			result1 []cfclient.Org
+func (fake *FakeClient) ListRouteBindingsReturnsOnCall(i int, result1 []cf.RouteBinding, result2 error) {
+	fake.listRouteBindingsMutex.Lock()
+	defer fake.listRouteBindingsMutex.Unlock()
+	fake.ListRouteBindingsStub = nil
+	if fake.listRouteBindingsReturnsOnCall == nil {
+		fake.listRouteBindingsReturnsOnCall = make(map[int]struct {
+			result1 []cf.RouteBinding
 			result2 error
 		})
 	}
-	fake.listOrgsReturnsOnCall[i] = struct {
-		result1 []cfclient.Org
+	fake.listRouteBindingsReturnsOnCall[i] = struct {
+		result1 []cf.RouteBinding
 		result2 error
 	}{result1, result2}
 }
@@ -2587,6 +3979,318 @@ func (fake *FakeClient) ListServicesReturnsOnCall(i int, result1 []cfclient.Serv
 	}{result1, result2}
 }
 
+func (fake *FakeClient) ListSharedDomains() ([]cfclient.SharedDomain, error) {
+	fake.listSharedDomainsMutex.Lock()
+	ret, specificReturn := fake.listSharedDomainsReturnsOnCall[len(fake.listSharedDomainsArgsForCall)]
+	fake.listSharedDomainsArgsForCall = append(fake.listSharedDomainsArgsForCall, struct {
+	}{})
+	stub := fake.ListSharedDomainsStub
+	fakeReturns := fake.listSharedDomainsReturns
+	fake.recordInvocation("ListSharedDomains", []interface{}{})
+	fake.listSharedDomainsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListSharedDomainsCallCount() int {
+	fake.listSharedDomainsMutex.RLock()
+	defer fake.listSharedDomainsMutex.RUnlock()
+	return len(fake.listSharedDomainsArgsForCall)
+}
+
+func (fake *FakeClient) ListSharedDomainsCalls(stub func() ([]cfclient.SharedDomain, error)) {
+	fake.listSharedDomainsMutex.Lock()
+	defer fake.listSharedDomainsMutex.Unlock()
+	fake.ListSharedDomainsStub = stub
+}
+
+func (fake *FakeClient) ListSharedDomainsReturns(result1 []cfclient.SharedDomain, result2 error) {
+	fake.listSharedDomainsMutex.Lock()
+	defer fake.listSharedDomainsMutex.Unlock()
+	fake.ListSharedDomainsStub = nil
+	fake.listSharedDomainsReturns = struct {
+		result1 []cfclient.SharedDomain
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListSharedDomainsReturnsOnCall(i int, result1 []cfclient.SharedDomain, result2 error) {
+	fake.listSharedDomainsMutex.Lock()
+	defer fake.listSharedDomainsMutex.Unlock()
+	fake.ListSharedDomainsStub = nil
+	if fake.listSharedDomainsReturnsOnCall == nil {
+		fake.listSharedDomainsReturnsOnCall = make(map[int]struct {
+			result1 []cfclient.SharedDomain
+			result2 error
+		})
+	}
+	fake.listSharedDomainsReturnsOnCall[i] = struct {
+		result1 []cfclient.SharedDomain
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListSharedSpaces(arg1 string) ([]cf.SharedSpace, error) {
+	fake.listSharedSpacesMutex.Lock()
+	ret, specificReturn := fake.listSharedSpacesReturnsOnCall[len(fake.listSharedSpacesArgsForCall)]
+	fake.listSharedSpacesArgsForCall = append(fake.listSharedSpacesArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ListSharedSpacesStub
+	fakeReturns := fake.listSharedSpacesReturns
+	fake.recordInvocation("ListSharedSpaces", []interface{}{arg1})
+	fake.listSharedSpacesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListSharedSpacesCallCount() int {
+	fake.listSharedSpacesMutex.RLock()
+	defer fake.listSharedSpacesMutex.RUnlock()
+	return len(fake.listSharedSpacesArgsForCall)
+}
+
+func (fake *FakeClient) ListSharedSpacesCalls(stub func(string) ([]cf.SharedSpace, error)) {
+	fake.listSharedSpacesMutex.Lock()
+	defer fake.listSharedSpacesMutex.Unlock()
+	fake.ListSharedSpacesStub = stub
+}
+
+func (fake *FakeClient) ListSharedSpacesArgsForCall(i int) string {
+	fake.listSharedSpacesMutex.RLock()
+	defer fake.listSharedSpacesMutex.RUnlock()
+	argsForCall := fake.listSharedSpacesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) ListSharedSpacesReturns(result1 []cf.SharedSpace, result2 error) {
+	fake.listSharedSpacesMutex.Lock()
+	defer fake.listSharedSpacesMutex.Unlock()
+	fake.ListSharedSpacesStub = nil
+	fake.listSharedSpacesReturns = struct {
+		result1 []cf.SharedSpace
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListSharedSpacesReturnsOnCall(i int, result1 []cf.SharedSpace, result2 error) {
+	fake.listSharedSpacesMutex.Lock()
+	defer fake.listSharedSpacesMutex.Unlock()
+	fake.ListSharedSpacesStub = nil
+	if fake.listSharedSpacesReturnsOnCall == nil {
+		fake.listSharedSpacesReturnsOnCall = make(map[int]struct {
+			result1 []cf.SharedSpace
+			result2 error
+		})
+	}
+	fake.listSharedSpacesReturnsOnCall[i] = struct {
+		result1 []cf.SharedSpace
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListSpaceAuditors(arg1 string) ([]cfclient.User, error) {
+	fake.listSpaceAuditorsMutex.Lock()
+	ret, specificReturn := fake.listSpaceAuditorsReturnsOnCall[len(fake.listSpaceAuditorsArgsForCall)]
+	fake.listSpaceAuditorsArgsForCall = append(fake.listSpaceAuditorsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ListSpaceAuditorsStub
+	fakeReturns := fake.listSpaceAuditorsReturns
+	fake.recordInvocation("ListSpaceAuditors", []interface{}{arg1})
+	fake.listSpaceAuditorsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListSpaceAuditorsCallCount() int {
+	fake.listSpaceAuditorsMutex.RLock()
+	defer fake.listSpaceAuditorsMutex.RUnlock()
+	return len(fake.listSpaceAuditorsArgsForCall)
+}
+
+func (fake *FakeClient) ListSpaceAuditorsCalls(stub func(string) ([]cfclient.User, error)) {
+	fake.listSpaceAuditorsMutex.Lock()
+	defer fake.listSpaceAuditorsMutex.Unlock()
+	fake.ListSpaceAuditorsStub = stub
+}
+
+func (fake *FakeClient) ListSpaceAuditorsArgsForCall(i int) string {
+	fake.listSpaceAuditorsMutex.RLock()
+	defer fake.listSpaceAuditorsMutex.RUnlock()
+	argsForCall := fake.listSpaceAuditorsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) ListSpaceAuditorsReturns(result1 []cfclient.User, result2 error) {
+	fake.listSpaceAuditorsMutex.Lock()
+	defer fake.listSpaceAuditorsMutex.Unlock()
+	fake.ListSpaceAuditorsStub = nil
+	fake.listSpaceAuditorsReturns = struct {
+		result1 []cfclient.User
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListSpaceAuditorsReturnsOnCall(i int, result1 []cfclient.User, result2 error) {
+	fake.listSpaceAuditorsMutex.Lock()
+	defer fake.listSpaceAuditorsMutex.Unlock()
+	fake.ListSpaceAuditorsStub = nil
+	if fake.listSpaceAuditorsReturnsOnCall == nil {
+		fake.listSpaceAuditorsReturnsOnCall = make(map[int]struct {
+			result1 []cfclient.User
+			result2 error
+		})
+	}
+	fake.listSpaceAuditorsReturnsOnCall[i] = struct {
+		result1 []cfclient.User
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListSpaceDevelopers(arg1 string) ([]cfclient.User, error) {
+	fake.listSpaceDevelopersMutex.Lock()
+	ret, specificReturn := fake.listSpaceDevelopersReturnsOnCall[len(fake.listSpaceDevelopersArgsForCall)]
+	fake.listSpaceDevelopersArgsForCall = append(fake.listSpaceDevelopersArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ListSpaceDevelopersStub
+	fakeReturns := fake.listSpaceDevelopersReturns
+	fake.recordInvocation("ListSpaceDevelopers", []interface{}{arg1})
+	fake.listSpaceDevelopersMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListSpaceDevelopersCallCount() int {
+	fake.listSpaceDevelopersMutex.RLock()
+	defer fake.listSpaceDevelopersMutex.RUnlock()
+	return len(fake.listSpaceDevelopersArgsForCall)
+}
+
+func (fake *FakeClient) ListSpaceDevelopersCalls(stub func(string) ([]cfclient.User, error)) {
+	fake.listSpaceDevelopersMutex.Lock()
+	defer fake.listSpaceDevelopersMutex.Unlock()
+	fake.ListSpaceDevelopersStub = stub
+}
+
+func (fake *FakeClient) ListSpaceDevelopersArgsForCall(i int) string {
+	fake.listSpaceDevelopersMutex.RLock()
+	defer fake.listSpaceDevelopersMutex.RUnlock()
+	argsForCall := fake.listSpaceDevelopersArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) ListSpaceDevelopersReturns(result1 []cfclient.User, result2 error) {
+	fake.listSpaceDevelopersMutex.Lock()
+	defer fake.listSpaceDevelopersMutex.Unlock()
+	fake.ListSpaceDevelopersStub = nil
+	fake.listSpaceDevelopersReturns = struct {
+		result1 []cfclient.User
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListSpaceDevelopersReturnsOnCall(i int, result1 []cfclient.User, result2 error) {
+	fake.listSpaceDevelopersMutex.Lock()
+	defer fake.listSpaceDevelopersMutex.Unlock()
+	fake.ListSpaceDevelopersStub = nil
+	if fake.listSpaceDevelopersReturnsOnCall == nil {
+		fake.listSpaceDevelopersReturnsOnCall = make(map[int]struct {
+			result1 []cfclient.User
+			result2 error
+		})
+	}
+	fake.listSpaceDevelopersReturnsOnCall[i] = struct {
+		result1 []cfclient.User
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListSpaceManagers(arg1 string) ([]cfclient.User, error) {
+	fake.listSpaceManagersMutex.Lock()
+	ret, specificReturn := fake.listSpaceManagersReturnsOnCall[len(fake.listSpaceManagersArgsForCall)]
+	fake.listSpaceManagersArgsForCall = append(fake.listSpaceManagersArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ListSpaceManagersStub
+	fakeReturns := fake.listSpaceManagersReturns
+	fake.recordInvocation("ListSpaceManagers", []interface{}{arg1})
+	fake.listSpaceManagersMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListSpaceManagersCallCount() int {
+	fake.listSpaceManagersMutex.RLock()
+	defer fake.listSpaceManagersMutex.RUnlock()
+	return len(fake.listSpaceManagersArgsForCall)
+}
+
+func (fake *FakeClient) ListSpaceManagersCalls(stub func(string) ([]cfclient.User, error)) {
+	fake.listSpaceManagersMutex.Lock()
+	defer fake.listSpaceManagersMutex.Unlock()
+	fake.ListSpaceManagersStub = stub
+}
+
+func (fake *FakeClient) ListSpaceManagersArgsForCall(i int) string {
+	fake.listSpaceManagersMutex.RLock()
+	defer fake.listSpaceManagersMutex.RUnlock()
+	argsForCall := fake.listSpaceManagersArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) ListSpaceManagersReturns(result1 []cfclient.User, result2 error) {
+	fake.listSpaceManagersMutex.Lock()
+	defer fake.listSpaceManagersMutex.Unlock()
+	fake.ListSpaceManagersStub = nil
+	fake.listSpaceManagersReturns = struct {
+		result1 []cfclient.User
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListSpaceManagersReturnsOnCall(i int, result1 []cfclient.User, result2 error) {
+	fake.listSpaceManagersMutex.Lock()
+	defer fake.listSpaceManagersMutex.Unlock()
+	fake.ListSpaceManagersStub = nil
+	if fake.listSpaceManagersReturnsOnCall == nil {
+		fake.listSpaceManagersReturnsOnCall = make(map[int]struct {
+			result1 []cfclient.User
+			result2 error
+		})
+	}
+	fake.listSpaceManagersReturnsOnCall[i] = struct {
+		result1 []cfclient.User
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) ListSpaceServiceInstances(arg1 string) ([]cfclient.ServiceInstance, error) {
 	fake.listSpaceServiceInstancesMutex.Lock()
 	ret, specificReturn := fake.listSpaceServiceInstancesReturnsOnCall[len(fake.listSpaceServiceInstancesArgsForCall)]
@@ -2897,6 +4601,73 @@ func (fake *FakeClient) NewRequestReturnsOnCall(i int, result1 *cfclient.Request
 	}{result1}
 }
 
+func (fake *FakeClient) ShareServiceInstance(arg1 string, arg2 []string) error {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.shareServiceInstanceMutex.Lock()
+	ret, specificReturn := fake.shareServiceInstanceReturnsOnCall[len(fake.shareServiceInstanceArgsForCall)]
+	fake.shareServiceInstanceArgsForCall = append(fake.shareServiceInstanceArgsForCall, struct {
+		arg1 string
+		arg2 []string
+	}{arg1, arg2Copy})
+	stub := fake.ShareServiceInstanceStub
+	fakeReturns := fake.shareServiceInstanceReturns
+	fake.recordInvocation("ShareServiceInstance", []interface{}{arg1, arg2Copy})
+	fake.shareServiceInstanceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) ShareServiceInstanceCallCount() int {
+	fake.shareServiceInstanceMutex.RLock()
+	defer fake.shareServiceInstanceMutex.RUnlock()
+	return len(fake.shareServiceInstanceArgsForCall)
+}
+
+func (fake *FakeClient) ShareServiceInstanceCalls(stub func(string, []string) error) {
+	fake.shareServiceInstanceMutex.Lock()
+	defer fake.shareServiceInstanceMutex.Unlock()
+	fake.ShareServiceInstanceStub = stub
+}
+
+func (fake *FakeClient) ShareServiceInstanceArgsForCall(i int) (string, []string) {
+	fake.shareServiceInstanceMutex.RLock()
+	defer fake.shareServiceInstanceMutex.RUnlock()
+	argsForCall := fake.shareServiceInstanceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) ShareServiceInstanceReturns(result1 error) {
+	fake.shareServiceInstanceMutex.Lock()
+	defer fake.shareServiceInstanceMutex.Unlock()
+	fake.ShareServiceInstanceStub = nil
+	fake.shareServiceInstanceReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) ShareServiceInstanceReturnsOnCall(i int, result1 error) {
+	fake.shareServiceInstanceMutex.Lock()
+	defer fake.shareServiceInstanceMutex.Unlock()
+	fake.ShareServiceInstanceStub = nil
+	if fake.shareServiceInstanceReturnsOnCall == nil {
+		fake.shareServiceInstanceReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.shareServiceInstanceReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeClient) UpdateSI(arg1 string, arg2 cfclient.ServiceInstanceUpdateRequest, arg3 bool) error {
 	fake.updateSIMutex.Lock()
 	ret, specificReturn := fake.updateSIReturnsOnCall[len(fake.updateSIArgsForCall)]
@@ -2960,6 +4731,130 @@ func (fake *FakeClient) UpdateSIReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeClient) UpdateServiceCredentialBindingMetadata(arg1 string, arg2 cf.Metadata) error {
+	fake.updateServiceCredentialBindingMetadataMutex.Lock()
+	ret, specificReturn := fake.updateServiceCredentialBindingMetadataReturnsOnCall[len(fake.updateServiceCredentialBindingMetadataArgsForCall)]
+	fake.updateServiceCredentialBindingMetadataArgsForCall = append(fake.updateServiceCredentialBindingMetadataArgsForCall, struct {
+		arg1 string
+		arg2 cf.Metadata
+	}{arg1, arg2})
+	stub := fake.UpdateServiceCredentialBindingMetadataStub
+	fakeReturns := fake.updateServiceCredentialBindingMetadataReturns
+	fake.recordInvocation("UpdateServiceCredentialBindingMetadata", []interface{}{arg1, arg2})
+	fake.updateServiceCredentialBindingMetadataMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) UpdateServiceCredentialBindingMetadataCallCount() int {
+	fake.updateServiceCredentialBindingMetadataMutex.RLock()
+	defer fake.updateServiceCredentialBindingMetadataMutex.RUnlock()
+	return len(fake.updateServiceCredentialBindingMetadataArgsForCall)
+}
+
+func (fake *FakeClient) UpdateServiceCredentialBindingMetadataCalls(stub func(string, cf.Metadata) error) {
+	fake.updateServiceCredentialBindingMetadataMutex.Lock()
+	defer fake.updateServiceCredentialBindingMetadataMutex.Unlock()
+	fake.UpdateServiceCredentialBindingMetadataStub = stub
+}
+
+func (fake *FakeClient) UpdateServiceCredentialBindingMetadataArgsForCall(i int) (string, cf.Metadata) {
+	fake.updateServiceCredentialBindingMetadataMutex.RLock()
+	defer fake.updateServiceCredentialBindingMetadataMutex.RUnlock()
+	argsForCall := fake.updateServiceCredentialBindingMetadataArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) UpdateServiceCredentialBindingMetadataReturns(result1 error) {
+	fake.updateServiceCredentialBindingMetadataMutex.Lock()
+	defer fake.updateServiceCredentialBindingMetadataMutex.Unlock()
+	fake.UpdateServiceCredentialBindingMetadataStub = nil
+	fake.updateServiceCredentialBindingMetadataReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) UpdateServiceCredentialBindingMetadataReturnsOnCall(i int, result1 error) {
+	fake.updateServiceCredentialBindingMetadataMutex.Lock()
+	defer fake.updateServiceCredentialBindingMetadataMutex.Unlock()
+	fake.UpdateServiceCredentialBindingMetadataStub = nil
+	if fake.updateServiceCredentialBindingMetadataReturnsOnCall == nil {
+		fake.updateServiceCredentialBindingMetadataReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateServiceCredentialBindingMetadataReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) UpdateServiceInstanceMetadata(arg1 string, arg2 cf.Metadata) error {
+	fake.updateServiceInstanceMetadataMutex.Lock()
+	ret, specificReturn := fake.updateServiceInstanceMetadataReturnsOnCall[len(fake.updateServiceInstanceMetadataArgsForCall)]
+	fake.updateServiceInstanceMetadataArgsForCall = append(fake.updateServiceInstanceMetadataArgsForCall, struct {
+		arg1 string
+		arg2 cf.Metadata
+	}{arg1, arg2})
+	stub := fake.UpdateServiceInstanceMetadataStub
+	fakeReturns := fake.updateServiceInstanceMetadataReturns
+	fake.recordInvocation("UpdateServiceInstanceMetadata", []interface{}{arg1, arg2})
+	fake.updateServiceInstanceMetadataMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) UpdateServiceInstanceMetadataCallCount() int {
+	fake.updateServiceInstanceMetadataMutex.RLock()
+	defer fake.updateServiceInstanceMetadataMutex.RUnlock()
+	return len(fake.updateServiceInstanceMetadataArgsForCall)
+}
+
+func (fake *FakeClient) UpdateServiceInstanceMetadataCalls(stub func(string, cf.Metadata) error) {
+	fake.updateServiceInstanceMetadataMutex.Lock()
+	defer fake.updateServiceInstanceMetadataMutex.Unlock()
+	fake.UpdateServiceInstanceMetadataStub = stub
+}
+
+func (fake *FakeClient) UpdateServiceInstanceMetadataArgsForCall(i int) (string, cf.Metadata) {
+	fake.updateServiceInstanceMetadataMutex.RLock()
+	defer fake.updateServiceInstanceMetadataMutex.RUnlock()
+	argsForCall := fake.updateServiceInstanceMetadataArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) UpdateServiceInstanceMetadataReturns(result1 error) {
+	fake.updateServiceInstanceMetadataMutex.Lock()
+	defer fake.updateServiceInstanceMetadataMutex.Unlock()
+	fake.UpdateServiceInstanceMetadataStub = nil
+	fake.updateServiceInstanceMetadataReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) UpdateServiceInstanceMetadataReturnsOnCall(i int, result1 error) {
+	fake.updateServiceInstanceMetadataMutex.Lock()
+	defer fake.updateServiceInstanceMetadataMutex.Unlock()
+	fake.UpdateServiceInstanceMetadataStub = nil
+	if fake.updateServiceInstanceMetadataReturnsOnCall == nil {
+		fake.updateServiceInstanceMetadataReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateServiceInstanceMetadataReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeClient) UpdateUserProvidedServiceInstance(arg1 string, arg2 cfclient.UserProvidedServiceInstanceRequest) (*cfclient.UserProvidedServiceInstance, error) {
 	fake.updateUserProvidedServiceInstanceMutex.Lock()
 	ret, specificReturn := fake.updateUserProvidedServiceInstanceReturnsOnCall[len(fake.updateUserProvidedServiceInstanceArgsForCall)]
@@ -3028,12 +4923,24 @@ func (fake *FakeClient) UpdateUserProvidedServiceInstanceReturnsOnCall(i int, re
 func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.addIsolationSegmentToOrgMutex.RLock()
+	defer fake.addIsolationSegmentToOrgMutex.RUnlock()
 	fake.appByNameMutex.RLock()
 	defer fake.appByNameMutex.RUnlock()
+	fake.associateOrgUserByUsernameMutex.RLock()
+	defer fake.associateOrgUserByUsernameMutex.RUnlock()
+	fake.associateSpaceAuditorByUsernameMutex.RLock()
+	defer fake.associateSpaceAuditorByUsernameMutex.RUnlock()
+	fake.associateSpaceDeveloperByUsernameMutex.RLock()
+	defer fake.associateSpaceDeveloperByUsernameMutex.RUnlock()
+	fake.associateSpaceManagerByUsernameMutex.RLock()
+	defer fake.associateSpaceManagerByUsernameMutex.RUnlock()
 	fake.createAppMutex.RLock()
 	defer fake.createAppMutex.RUnlock()
 	fake.createOrgMutex.RLock()
 	defer fake.createOrgMutex.RUnlock()
+	fake.createRouteBindingMutex.RLock()
+	defer fake.createRouteBindingMutex.RUnlock()
 	fake.createServiceBindingMutex.RLock()
 	defer fake.createServiceBindingMutex.RUnlock()
 	fake.createServiceInstanceMutex.RLock()
@@ -3056,18 +4963,30 @@ func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	defer fake.doRequestMutex.RUnlock()
 	fake.doWithRetryMutex.RLock()
 	defer fake.doWithRetryMutex.RUnlock()
+	fake.findRouteMutex.RLock()
+	defer fake.findRouteMutex.RUnlock()
 	fake.getAppByGuidNoInlineCallMutex.RLock()
 	defer fake.getAppByGuidNoInlineCallMutex.RUnlock()
 	fake.getClientConfigMutex.RLock()
 	defer fake.getClientConfigMutex.RUnlock()
+	fake.getIsolationSegmentByGUIDMutex.RLock()
+	defer fake.getIsolationSegmentByGUIDMutex.RUnlock()
 	fake.getOrgByGuidMutex.RLock()
 	defer fake.getOrgByGuidMutex.RUnlock()
 	fake.getOrgByNameMutex.RLock()
 	defer fake.getOrgByNameMutex.RUnlock()
+	fake.getOrgQuotaByNameMutex.RLock()
+	defer fake.getOrgQuotaByNameMutex.RUnlock()
+	fake.getServiceBindingParamsMutex.RLock()
+	defer fake.getServiceBindingParamsMutex.RUnlock()
 	fake.getServiceByGuidMutex.RLock()
 	defer fake.getServiceByGuidMutex.RUnlock()
+	fake.getServiceCredentialBindingMetadataMutex.RLock()
+	defer fake.getServiceCredentialBindingMetadataMutex.RUnlock()
 	fake.getServiceInstanceByGuidMutex.RLock()
 	defer fake.getServiceInstanceByGuidMutex.RUnlock()
+	fake.getServiceInstanceMetadataMutex.RLock()
+	defer fake.getServiceInstanceMetadataMutex.RUnlock()
 	fake.getServiceInstanceParamsMutex.RLock()
 	defer fake.getServiceInstanceParamsMutex.RUnlock()
 	fake.getServicePlanByGUIDMutex.RLock()
@@ -3078,8 +4997,18 @@ func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	defer fake.getSpaceByNameMutex.RUnlock()
 	fake.getUserProvidedServiceInstanceByGuidMutex.RLock()
 	defer fake.getUserProvidedServiceInstanceByGuidMutex.RUnlock()
+	fake.isolationSegmentForSpaceMutex.RLock()
+	defer fake.isolationSegmentForSpaceMutex.RUnlock()
+	fake.listDomainsMutex.RLock()
+	defer fake.listDomainsMutex.RUnlock()
+	fake.listIsolationSegmentsByQueryMutex.RLock()
+	defer fake.listIsolationSegmentsByQueryMutex.RUnlock()
+	fake.listOrgQuotasMutex.RLock()
+	defer fake.listOrgQuotasMutex.RUnlock()
 	fake.listOrgsMutex.RLock()
 	defer fake.listOrgsMutex.RUnlock()
+	fake.listRouteBindingsMutex.RLock()
+	defer fake.listRouteBindingsMutex.RUnlock()
 	fake.listServiceBindingsByQueryMutex.RLock()
 	defer fake.listServiceBindingsByQueryMutex.RUnlock()
 	fake.listServiceBrokersMutex.RLock()
@@ -3094,6 +5023,16 @@ func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	defer fake.listServicePlansByQueryMutex.RUnlock()
 	fake.listServicesMutex.RLock()
 	defer fake.listServicesMutex.RUnlock()
+	fake.listSharedDomainsMutex.RLock()
+	defer fake.listSharedDomainsMutex.RUnlock()
+	fake.listSharedSpacesMutex.RLock()
+	defer fake.listSharedSpacesMutex.RUnlock()
+	fake.listSpaceAuditorsMutex.RLock()
+	defer fake.listSpaceAuditorsMutex.RUnlock()
+	fake.listSpaceDevelopersMutex.RLock()
+	defer fake.listSpaceDevelopersMutex.RUnlock()
+	fake.listSpaceManagersMutex.RLock()
+	defer fake.listSpaceManagersMutex.RUnlock()
 	fake.listSpaceServiceInstancesMutex.RLock()
 	defer fake.listSpaceServiceInstancesMutex.RUnlock()
 	fake.listSpacesMutex.RLock()
@@ -3104,8 +5043,14 @@ func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	defer fake.listUserProvidedServiceInstancesByQueryMutex.RUnlock()
 	fake.newRequestMutex.RLock()
 	defer fake.newRequestMutex.RUnlock()
+	fake.shareServiceInstanceMutex.RLock()
+	defer fake.shareServiceInstanceMutex.RUnlock()
 	fake.updateSIMutex.RLock()
 	defer fake.updateSIMutex.RUnlock()
+	fake.updateServiceCredentialBindingMetadataMutex.RLock()
+	defer fake.updateServiceCredentialBindingMetadataMutex.RUnlock()
+	fake.updateServiceInstanceMetadataMutex.RLock()
+	defer fake.updateServiceInstanceMetadataMutex.RUnlock()
 	fake.updateUserProvidedServiceInstanceMutex.RLock()
 	defer fake.updateUserProvidedServiceInstanceMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -58,22 +58,15 @@ func LoginTargetFoundation(e exec.Executor, om config.OpsManager, api, org, spac
 }
 
 func loginFoundation(ctx context.Context, api, org, space, cfHome string, e exec.Executor, om config.OpsManager) error {
+	omCmd := fmt.Sprintf(`OM_CLIENT_ID='%s' OM_CLIENT_SECRET='%s' OM_USERNAME='%s' OM_PASSWORD='%s' om -t %s -k`,
+		om.ClientID,
+		om.ClientSecret,
+		om.Username,
+		om.Password,
+		om.URL)
 	lines := []string{
-		fmt.Sprintf(`products="$(OM_CLIENT_ID='%s' OM_CLIENT_SECRET='%s' OM_USERNAME='%s' OM_PASSWORD='%s' om -t %s -k curl -s -p /api/v0/staged/products)"`,
-			om.ClientID,
-			om.ClientSecret,
-			om.Username,
-			om.Password,
-			om.URL),
-		`product_guid="$(echo "$products" | jq -r '.[] | select(.type == "cf") | .guid')"`,
-		fmt.Sprintf(`admin_credentials="$(OM_CLIENT_ID='%s' OM_CLIENT_SECRET='%s' OM_USERNAME='%s' OM_PASSWORD='%s' om -t %s -k curl -s -p /api/v0/deployed/products/"$product_guid"/credentials/.uaa.admin_credentials)"`,
-			om.ClientID,
-			om.ClientSecret,
-			om.Username,
-			om.Password,
-			om.URL),
-		`username="$(echo "$admin_credentials" | jq -r .credential.value.identity)"`,
-		`password="$(echo "$admin_credentials" | jq -r .credential.value.password)"`,
+		fmt.Sprintf(`username="$(%s credentials -p cf -c .uaa.admin_credentials -f identity)"`, omCmd),
+		fmt.Sprintf(`password="$(%s credentials -p cf -c .uaa.admin_credentials -f password)"`, omCmd),
 		fmt.Sprintf(`CF_HOME='%s' cf login -a "%s" -u "$username" -p "$password" -o %s -s %s --skip-ssl-validation`, cfHome, api, org, space),
 	}
 
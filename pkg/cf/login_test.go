@@ -58,11 +58,8 @@ func TestLoginFoundation(t *testing.T) {
 				api:      "https://api.sys.source.example.com",
 			},
 			want: strings.NewReader(strings.Join([]string{
-				`products="$(OM_CLIENT_ID='fake-client-id' OM_CLIENT_SECRET='fake-client-secret' OM_USERNAME='fake-user' OM_PASSWORD='fake-password' om -t https://opsman.source.example.com -k curl -s -p /api/v0/staged/products)"`,
-				`product_guid="$(echo "$products" | jq -r '.[] | select(.type == "cf") | .guid')"`,
-				`admin_credentials="$(OM_CLIENT_ID='fake-client-id' OM_CLIENT_SECRET='fake-client-secret' OM_USERNAME='fake-user' OM_PASSWORD='fake-password' om -t https://opsman.source.example.com -k curl -s -p /api/v0/deployed/products/"$product_guid"/credentials/.uaa.admin_credentials)"`,
-				`username="$(echo "$admin_credentials" | jq -r .credential.value.identity)"`,
-				`password="$(echo "$admin_credentials" | jq -r .credential.value.password)"`,
+				`username="$(OM_CLIENT_ID='fake-client-id' OM_CLIENT_SECRET='fake-client-secret' OM_USERNAME='fake-user' OM_PASSWORD='fake-password' om -t https://opsman.source.example.com -k credentials -p cf -c .uaa.admin_credentials -f identity)"`,
+				`password="$(OM_CLIENT_ID='fake-client-id' OM_CLIENT_SECRET='fake-client-secret' OM_USERNAME='fake-user' OM_PASSWORD='fake-password' om -t https://opsman.source.example.com -k credentials -p cf -c .uaa.admin_credentials -f password)"`,
 				`CF_HOME='.cf' cf login -a "https://api.sys.source.example.com" -u "$username" -p "$password" -o my-org -s my-space --skip-ssl-validation`,
 			}, "\n")),
 		},
@@ -81,11 +78,8 @@ func TestLoginFoundation(t *testing.T) {
 				api:      "https://api.sys.target.example.com",
 			},
 			want: strings.NewReader(strings.Join([]string{
-				`products="$(OM_CLIENT_ID='fake-client-id' OM_CLIENT_SECRET='fake-client-secret' OM_USERNAME='fake-user' OM_PASSWORD='fake-password' om -t https://opsman.target.example.com -k curl -s -p /api/v0/staged/products)"`,
-				`product_guid="$(echo "$products" | jq -r '.[] | select(.type == "cf") | .guid')"`,
-				`admin_credentials="$(OM_CLIENT_ID='fake-client-id' OM_CLIENT_SECRET='fake-client-secret' OM_USERNAME='fake-user' OM_PASSWORD='fake-password' om -t https://opsman.target.example.com -k curl -s -p /api/v0/deployed/products/"$product_guid"/credentials/.uaa.admin_credentials)"`,
-				`username="$(echo "$admin_credentials" | jq -r .credential.value.identity)"`,
-				`password="$(echo "$admin_credentials" | jq -r .credential.value.password)"`,
+				`username="$(OM_CLIENT_ID='fake-client-id' OM_CLIENT_SECRET='fake-client-secret' OM_USERNAME='fake-user' OM_PASSWORD='fake-password' om -t https://opsman.target.example.com -k credentials -p cf -c .uaa.admin_credentials -f identity)"`,
+				`password="$(OM_CLIENT_ID='fake-client-id' OM_CLIENT_SECRET='fake-client-secret' OM_USERNAME='fake-user' OM_PASSWORD='fake-password' om -t https://opsman.target.example.com -k credentials -p cf -c .uaa.admin_credentials -f password)"`,
 				`CF_HOME='.cf' cf login -a "https://api.sys.target.example.com" -u "$username" -p "$password" -o my-org -s my-space --skip-ssl-validation`,
 			}, "\n")),
 		},
@@ -0,0 +1,56 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package cf
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/cloudfoundry-community/go-cfclient"
+)
+
+// FindServicePlan returns the service offering with the given label and its plan with the given name. Plans of
+// other offerings with the same name are never returned.
+func FindServicePlan(client Client, label, plan string) (cfclient.Service, cfclient.ServicePlan, error) {
+	services, err := client.ListServices()
+	if err != nil {
+		return cfclient.Service{}, cfclient.ServicePlan{}, fmt.Errorf("failed to list service offerings: %w", err)
+	}
+
+	var service cfclient.Service
+	var found bool
+	for _, s := range services {
+		if s.Label == label {
+			service = s
+			found = true
+			break
+		}
+	}
+	if !found {
+		return cfclient.Service{}, cfclient.ServicePlan{}, fmt.Errorf("failed to find service offering %q", label)
+	}
+
+	plans, err := client.ListServicePlansByQuery(url.Values{
+		"service_offering_guids": []string{service.Guid},
+		"names":                  []string{plan},
+	})
+	if err != nil {
+		return cfclient.Service{}, cfclient.ServicePlan{}, fmt.Errorf("failed to list plans of service offering %q: %w", label, err)
+	}
+	if len(plans) == 0 {
+		return cfclient.Service{}, cfclient.ServicePlan{}, fmt.Errorf("failed to find plan %q of service offering %q", plan, label)
+	}
+
+	return service, plans[0], nil
+}
@@ -15,74 +15,136 @@
 package cf
 
 type ServiceInstance struct {
-	Name                string                 `yaml:"name,omitempty"`
-	GUID                string                 `yaml:"guid,omitempty"`
-	Type                string                 `yaml:"type,omitempty"`
-	Tags                string                 `yaml:"tags,omitempty"`
-	Params              map[string]interface{} `yaml:"params,omitempty"`
-	RouteServiceUrl     string                 `yaml:"route_service_url,omitempty"`
-	SyslogDrainUrl      string                 `yaml:"syslog_drain_url,omitempty"`
-	DashboardURL        string                 `yaml:"dashboard_url,omitempty"`
-	Service             string                 `yaml:"service,omitempty"`
-	Plan                string                 `yaml:"plan,omitempty"`
-	Credentials         map[string]interface{} `json:"credentials,omitempty"`
-	ServiceBindings     []ServiceBinding       `yaml:"service_bindings,omitempty"`
-	ServiceKeys         []ServiceKey           `yaml:"service_keys,omitempty"`
-	BackupID            string                 `yaml:"backup_id,omitempty"`
-	BackupDate          string                 `yaml:"backup_date,omitempty"`
-	BackupTime          string                 `yaml:"backup_time,omitempty"`
-	BackupFile          string                 `yaml:"backup_file,omitempty"`
-	BackupEncryptionKey string                 `yaml:"backup_encryption_key,omitempty"`
-	Apps                map[string]string      `yaml:"apps,omitempty"`
-	AppManifest         Manifest               `yaml:"app_manifest"`
+	Name                string                 `yaml:"name,omitempty" json:"name,omitempty"`
+	GUID                string                 `yaml:"guid,omitempty" json:"guid,omitempty"`
+	Type                string                 `yaml:"type,omitempty" json:"type,omitempty"`
+	Tags                string                 `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Params              map[string]interface{} `yaml:"params,omitempty" json:"params,omitempty"`
+	RouteServiceUrl     string                 `yaml:"route_service_url,omitempty" json:"route_service_url,omitempty"`
+	SyslogDrainUrl      string                 `yaml:"syslog_drain_url,omitempty" json:"syslog_drain_url,omitempty"`
+	DashboardURL        string                 `yaml:"dashboard_url,omitempty" json:"dashboard_url,omitempty"`
+	Service             string                 `yaml:"service,omitempty" json:"service,omitempty"`
+	Plan                string                 `yaml:"plan,omitempty" json:"plan,omitempty"`
+	Credentials         map[string]interface{} `yaml:"credentials,omitempty" json:"credentials,omitempty"`
+	ServiceBindings     []ServiceBinding       `yaml:"service_bindings,omitempty" json:"service_bindings,omitempty"`
+	ServiceKeys         []ServiceKey           `yaml:"service_keys,omitempty" json:"service_keys,omitempty"`
+	BackupID            string                 `yaml:"backup_id,omitempty" json:"backup_id,omitempty"`
+	BackupDate          string                 `yaml:"backup_date,omitempty" json:"backup_date,omitempty"`
+	BackupTime          string                 `yaml:"backup_time,omitempty" json:"backup_time,omitempty"`
+	BackupFile          string                 `yaml:"backup_file,omitempty" json:"backup_file,omitempty"`
+	BackupEncryptionKey string                 `yaml:"backup_encryption_key,omitempty" json:"backup_encryption_key,omitempty"`
+	Outputs             map[string]interface{} `yaml:"outputs,omitempty" json:"outputs,omitempty"`
+	Apps                map[string]string      `yaml:"apps,omitempty" json:"apps,omitempty"`
+	SharedSpaces        []SharedSpace          `yaml:"shared_spaces,omitempty" json:"shared_spaces,omitempty"`
+	RouteBindings       []RouteBinding         `yaml:"route_bindings,omitempty" json:"route_bindings,omitempty"`
+	Labels              map[string]string      `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Annotations         map[string]string      `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+	AppManifest         Manifest               `yaml:"app_manifest" json:"app_manifest"`
+	// TargetService and TargetPlan are the service label and plan the config maps the instance to on import,
+	// they are never exported
+	TargetService string `yaml:"-" json:"-"`
+	TargetPlan    string `yaml:"-" json:"-"`
+}
+
+// ServiceToCreate returns the service label to create the instance with on the target
+func (si ServiceInstance) ServiceToCreate() string {
+	if si.TargetService != "" {
+		return si.TargetService
+	}
+	return si.Service
+}
+
+// PlanToCreate returns the plan to create the instance with on the target
+func (si ServiceInstance) PlanToCreate() string {
+	if si.TargetPlan != "" {
+		return si.TargetPlan
+	}
+	return si.Plan
 }
 
 type ServiceBinding struct {
-	Guid                string                 `yaml:"guid,omitempty"`
-	Name                string                 `yaml:"name,omitempty"`
-	AppGuid             string                 `yaml:"app_guid,omitempty"`
-	ServiceInstanceGuid string                 `yaml:"service_instance_guid,omitempty"`
-	Credentials         map[string]interface{} `yaml:"credentials,omitempty"`
-	BindingOptions      interface{}            `yaml:"binding_options,omitempty"`
-	GatewayData         interface{}            `yaml:"gateway_data,omitempty"`
-	GatewayName         string                 `yaml:"gateway_name,omitempty"`
-	SyslogDrainUrl      string                 `yaml:"syslog_drain_url,omitempty"`
-	VolumeMounts        interface{}            `yaml:"volume_mounts,omitempty"`
-	AppUrl              string                 `yaml:"app_url,omitempty"`
-	ServiceInstanceUrl  string                 `yaml:"service_instance_url,omitempty"`
+	Guid                string                 `yaml:"guid,omitempty" json:"guid,omitempty"`
+	Name                string                 `yaml:"name,omitempty" json:"name,omitempty"`
+	AppGuid             string                 `yaml:"app_guid,omitempty" json:"app_guid,omitempty"`
+	ServiceInstanceGuid string                 `yaml:"service_instance_guid,omitempty" json:"service_instance_guid,omitempty"`
+	Credentials         map[string]interface{} `yaml:"credentials,omitempty" json:"credentials,omitempty"`
+	BindingOptions      interface{}            `yaml:"binding_options,omitempty" json:"binding_options,omitempty"`
+	GatewayData         interface{}            `yaml:"gateway_data,omitempty" json:"gateway_data,omitempty"`
+	GatewayName         string                 `yaml:"gateway_name,omitempty" json:"gateway_name,omitempty"`
+	SyslogDrainUrl      string                 `yaml:"syslog_drain_url,omitempty" json:"syslog_drain_url,omitempty"`
+	VolumeMounts        interface{}            `yaml:"volume_mounts,omitempty" json:"volume_mounts,omitempty"`
+	AppUrl              string                 `yaml:"app_url,omitempty" json:"app_url,omitempty"`
+	ServiceInstanceUrl  string                 `yaml:"service_instance_url,omitempty" json:"service_instance_url,omitempty"`
+	Parameters          map[string]interface{} `yaml:"parameters,omitempty" json:"parameters,omitempty"`
+	Labels              map[string]string      `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Annotations         map[string]string      `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+}
+
+// SharedSpace is a space, other than its own, that a service instance is shared into
+type SharedSpace struct {
+	Org   string `yaml:"org" json:"org"`
+	Space string `yaml:"space" json:"space"`
+}
+
+// RouteBinding is a route whose requests are sent through a route service instance
+type RouteBinding struct {
+	Host   string `yaml:"host,omitempty" json:"host,omitempty"`
+	Domain string `yaml:"domain" json:"domain"`
+	Path   string `yaml:"path,omitempty" json:"path,omitempty"`
+}
+
+// URL returns the route as host.domain/path
+func (r RouteBinding) URL() string {
+	if r.Host == "" {
+		return r.Domain + r.Path
+	}
+	return r.Host + "." + r.Domain + r.Path
 }
 
 type ServiceKey struct {
-	Name                string                 `yaml:"name"`
-	Guid                string                 `yaml:"guid"`
-	ServiceInstanceGuid string                 `yaml:"service_instance_guid"`
-	Credentials         map[string]interface{} `yaml:"credentials"`
-	ServiceInstanceUrl  string                 `yaml:"service_instance_url"`
+	Name                string                 `yaml:"name" json:"name"`
+	Guid                string                 `yaml:"guid" json:"guid"`
+	ServiceInstanceGuid string                 `yaml:"service_instance_guid" json:"service_instance_guid"`
+	Credentials         map[string]interface{} `yaml:"credentials" json:"credentials"`
+	ServiceInstanceUrl  string                 `yaml:"service_instance_url" json:"service_instance_url"`
+	Labels              map[string]string      `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Annotations         map[string]string      `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+}
+
+// Metadata is the labels and annotations of a v3 resource
+type Metadata struct {
+	Labels      map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+}
+
+// IsEmpty returns true if there are no labels and no annotations
+func (m Metadata) IsEmpty() bool {
+	return len(m.Labels) == 0 && len(m.Annotations) == 0
 }
 
 type Manifest struct {
-	Applications []Application `yaml:"applications"`
+	Applications []Application `yaml:"applications" json:"applications"`
 }
 
 type Application struct {
-	Name       string   `yaml:"name"`
-	Buildpacks []string `yaml:"buildpacks"`
-	Command    string   `yaml:"command"`
-	DiskQuota  string   `yaml:"disk_quota"`
+	Name       string   `yaml:"name" json:"name"`
+	Buildpacks []string `yaml:"buildpacks" json:"buildpacks"`
+	Command    string   `yaml:"command" json:"command"`
+	DiskQuota  string   `yaml:"disk_quota" json:"disk_quota"`
 	Docker     struct {
-		Image    string `yaml:"image,omitempty"`
-		Username string `yaml:"username,omitempty"`
-	} `yaml:"docker,omitempty"`
-	Env                     map[string]interface{} `yaml:"env"`
-	HealthCheckType         string                 `yaml:"health-check-type"`
-	HealthCheckHTTPEndpoint string                 `yaml:"health-check-http-endpoint,omitempty"`
-	Instances               int64                  `yaml:"instances"`
-	Memory                  string                 `yaml:"memory"`
-	NoRoute                 bool                   `yaml:"no-route,omitempty"`
+		Image    string `yaml:"image,omitempty" json:"image,omitempty"`
+		Username string `yaml:"username,omitempty" json:"username,omitempty"`
+	} `yaml:"docker,omitempty" json:"docker,omitempty"`
+	Env                     map[string]interface{} `yaml:"env" json:"env"`
+	HealthCheckType         string                 `yaml:"health-check-type" json:"health-check-type"`
+	HealthCheckHTTPEndpoint string                 `yaml:"health-check-http-endpoint,omitempty" json:"health-check-http-endpoint,omitempty"`
+	Instances               int64                  `yaml:"instances" json:"instances"`
+	Memory                  string                 `yaml:"memory" json:"memory"`
+	NoRoute                 bool                   `yaml:"no-route,omitempty" json:"no-route,omitempty"`
 	Routes                  []struct {
-		Route string `yaml:"route,omitempty"`
-	} `yaml:"routes,omitempty"`
-	Services []string `yaml:"services"`
-	Stack    string   `yaml:"stack"`
-	Timeout  int64    `yaml:"timeout,omitempty"`
+		Route string `yaml:"route,omitempty" json:"route,omitempty"`
+	} `yaml:"routes,omitempty" json:"routes,omitempty"`
+	Services []string `yaml:"services" json:"services"`
+	Stack    string   `yaml:"stack" json:"stack"`
+	Timeout  int64    `yaml:"timeout,omitempty" json:"timeout,omitempty"`
 }
@@ -0,0 +1,1022 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/cloudfoundry-community/go-cfclient"
+)
+
+const (
+	v3ManagedType      = "managed"
+	v3UserProvidedType = "user-provided"
+	v3AppBindingType   = "app"
+	v3KeyBindingType   = "key"
+)
+
+// v3Client implements the service instance, credential binding, service key, plan and offering
+// operations of Client on top of the Cloud Controller v3 API, so they keep working on foundations
+// where the v2 API is disabled. Results are converted to the go-cfclient types so callers are unaffected.
+type v3Client struct {
+	*cfclient.Client
+}
+
+// CreateServiceBindingRequest describes an app binding to create
+type CreateServiceBindingRequest struct {
+	Name                string
+	AppGuid             string
+	ServiceInstanceGuid string
+	Parameters          map[string]interface{}
+}
+
+type v3Relationship struct {
+	Data *struct {
+		GUID string `json:"guid"`
+	} `json:"data"`
+}
+
+func (r v3Relationship) guid() string {
+	if r.Data == nil {
+		return ""
+	}
+	return r.Data.GUID
+}
+
+func toOne(guid string) map[string]interface{} {
+	return map[string]interface{}{"data": map[string]string{"guid": guid}}
+}
+
+type v3LastOperation struct {
+	Type        string `json:"type"`
+	State       string `json:"state"`
+	Description string `json:"description"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+type v3ServiceInstance struct {
+	GUID            string          `json:"guid"`
+	Name            string          `json:"name"`
+	Type            string          `json:"type"`
+	CreatedAt       string          `json:"created_at"`
+	UpdatedAt       string          `json:"updated_at"`
+	Tags            []string        `json:"tags"`
+	DashboardURL    string          `json:"dashboard_url"`
+	SyslogDrainURL  string          `json:"syslog_drain_url"`
+	RouteServiceURL string          `json:"route_service_url"`
+	LastOperation   v3LastOperation `json:"last_operation"`
+	Relationships   struct {
+		Space       v3Relationship `json:"space"`
+		ServicePlan v3Relationship `json:"service_plan"`
+	} `json:"relationships"`
+}
+
+type v3CredentialBinding struct {
+	GUID          string `json:"guid"`
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+	Relationships struct {
+		App             v3Relationship `json:"app"`
+		ServiceInstance v3Relationship `json:"service_instance"`
+	} `json:"relationships"`
+}
+
+type v3CredentialBindingDetails struct {
+	Credentials    map[string]interface{} `json:"credentials"`
+	SyslogDrainURL string                 `json:"syslog_drain_url"`
+	VolumeMounts   interface{}            `json:"volume_mounts"`
+}
+
+type v3ServicePlan struct {
+	GUID           string `json:"guid"`
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	CreatedAt      string `json:"created_at"`
+	UpdatedAt      string `json:"updated_at"`
+	Free           bool   `json:"free"`
+	Available      bool   `json:"available"`
+	VisibilityType string `json:"visibility_type"`
+	BrokerCatalog  struct {
+		ID       string      `json:"id"`
+		Metadata interface{} `json:"metadata"`
+		Features struct {
+			PlanUpdateable bool `json:"plan_updateable"`
+			Bindable       bool `json:"bindable"`
+		} `json:"features"`
+	} `json:"broker_catalog"`
+	Relationships struct {
+		ServiceOffering v3Relationship `json:"service_offering"`
+	} `json:"relationships"`
+}
+
+type v3ServiceOffering struct {
+	GUID          string   `json:"guid"`
+	Name          string   `json:"name"`
+	Description   string   `json:"description"`
+	CreatedAt     string   `json:"created_at"`
+	UpdatedAt     string   `json:"updated_at"`
+	Available     bool     `json:"available"`
+	Tags          []string `json:"tags"`
+	Requires      []string `json:"requires"`
+	BrokerCatalog struct {
+		ID       string      `json:"id"`
+		Metadata interface{} `json:"metadata"`
+		Features struct {
+			PlanUpdateable       bool `json:"plan_updateable"`
+			Bindable             bool `json:"bindable"`
+			InstancesRetrievable bool `json:"instances_retrievable"`
+			BindingsRetrievable  bool `json:"bindings_retrievable"`
+		} `json:"features"`
+	} `json:"broker_catalog"`
+	Relationships struct {
+		ServiceBroker v3Relationship `json:"service_broker"`
+	} `json:"relationships"`
+}
+
+type v3Route struct {
+	GUID          string `json:"guid"`
+	Host          string `json:"host"`
+	Path          string `json:"path"`
+	Relationships struct {
+		Domain v3Relationship `json:"domain"`
+	} `json:"relationships"`
+}
+
+type v3ServiceBroker struct {
+	GUID          string `json:"guid"`
+	Name          string `json:"name"`
+	URL           string `json:"url"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+	Relationships struct {
+		Space v3Relationship `json:"space"`
+	} `json:"relationships"`
+}
+
+type v3Page struct {
+	Pagination struct {
+		Next *struct {
+			Href string `json:"href"`
+		} `json:"next"`
+	} `json:"pagination"`
+	Resources json.RawMessage `json:"resources"`
+}
+
+// ListSpaceServiceInstances returns the managed service instances in the given space
+func (c *v3Client) ListSpaceServiceInstances(spaceGUID string) ([]cfclient.ServiceInstance, error) {
+	return c.ListServiceInstancesByQuery(url.Values{"space_guids": []string{spaceGUID}})
+}
+
+// ListServiceInstancesByQuery returns the managed service instances matching the v3 filters in query,
+// e.g. names, space_guids or organization_guids
+func (c *v3Client) ListServiceInstancesByQuery(query url.Values) ([]cfclient.ServiceInstance, error) {
+	instances, err := c.listServiceInstances(v3ManagedType, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]cfclient.ServiceInstance, 0, len(instances))
+	for _, si := range instances {
+		result = append(result, convertV3ServiceInstance(si))
+	}
+	return result, nil
+}
+
+// GetServiceInstanceByGuid returns the service instance with the given guid
+func (c *v3Client) GetServiceInstanceByGuid(guid string) (cfclient.ServiceInstance, error) {
+	var si v3ServiceInstance
+	if err := c.do(http.MethodGet, "/v3/service_instances/"+guid, nil, &si); err != nil {
+		return cfclient.ServiceInstance{}, fmt.Errorf("error getting service instance %s: %w", guid, err)
+	}
+	return convertV3ServiceInstance(si), nil
+}
+
+// GetServiceInstanceParams returns the parameters the broker reports for the given managed service instance
+func (c *v3Client) GetServiceInstanceParams(guid string) (map[string]interface{}, error) {
+	params := make(map[string]interface{})
+	if err := c.do(http.MethodGet, "/v3/service_instances/"+guid+"/parameters", nil, &params); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// CreateServiceInstance asynchronously provisions a managed service instance
+func (c *v3Client) CreateServiceInstance(req cfclient.ServiceInstanceRequest) (cfclient.ServiceInstance, error) {
+	body := map[string]interface{}{
+		"type": v3ManagedType,
+		"name": req.Name,
+		"relationships": map[string]interface{}{
+			"space":        toOne(req.SpaceGuid),
+			"service_plan": toOne(req.ServicePlanGuid),
+		},
+	}
+	if len(req.Parameters) > 0 {
+		body["parameters"] = req.Parameters
+	}
+	if len(req.Tags) > 0 {
+		body["tags"] = req.Tags
+	}
+
+	if err := c.do(http.MethodPost, "/v3/service_instances", body, nil); err != nil {
+		return cfclient.ServiceInstance{}, fmt.Errorf("error creating service instance %s: %w", req.Name, err)
+	}
+
+	// provisioning is asynchronous, the instance exists as soon as the request is accepted
+	instances, err := c.ListServiceInstancesByQuery(url.Values{
+		"names":       []string{req.Name},
+		"space_guids": []string{req.SpaceGuid},
+	})
+	if err != nil {
+		return cfclient.ServiceInstance{}, err
+	}
+	if len(instances) == 0 {
+		return cfclient.ServiceInstance{}, fmt.Errorf("service instance %s was not found after it was created", req.Name)
+	}
+
+	return instances[0], nil
+}
+
+// UpdateSI updates a managed service instance, v3 updates are always asynchronous so async is ignored
+func (c *v3Client) UpdateSI(serviceInstanceGuid string, req cfclient.ServiceInstanceUpdateRequest, async bool) error {
+	body := map[string]interface{}{}
+	if req.Name != "" {
+		body["name"] = req.Name
+	}
+	if req.ServicePlanGuid != "" {
+		body["relationships"] = map[string]interface{}{"service_plan": toOne(req.ServicePlanGuid)}
+	}
+	if len(req.Parameters) > 0 {
+		body["parameters"] = req.Parameters
+	}
+	if len(req.Tags) > 0 {
+		body["tags"] = req.Tags
+	}
+
+	if err := c.do(http.MethodPatch, "/v3/service_instances/"+serviceInstanceGuid, body, nil); err != nil {
+		return fmt.Errorf("error updating service instance %s: %w", serviceInstanceGuid, err)
+	}
+	return nil
+}
+
+// ListSharedSpaces returns the org and space names of the spaces the service instance is shared into
+func (c *v3Client) ListSharedSpaces(serviceInstanceGUID string) ([]SharedSpace, error) {
+	var shares struct {
+		Data     []v3Relationship `json:"data"`
+		Included struct {
+			Spaces []struct {
+				GUID          string `json:"guid"`
+				Name          string `json:"name"`
+				Relationships struct {
+					Organization v3Relationship `json:"organization"`
+				} `json:"relationships"`
+			} `json:"spaces"`
+			Organizations []struct {
+				GUID string `json:"guid"`
+				Name string `json:"name"`
+			} `json:"organizations"`
+		} `json:"included"`
+	}
+
+	query := url.Values{
+		"fields[space]":              []string{"name,relationships.organization"},
+		"fields[space.organization]": []string{"name"},
+	}
+	path := "/v3/service_instances/" + serviceInstanceGUID + "/relationships/shared_spaces?" + query.Encode()
+	if err := c.do(http.MethodGet, path, nil, &shares); err != nil {
+		return nil, fmt.Errorf("error listing shared spaces of service instance %s: %w", serviceInstanceGUID, err)
+	}
+
+	orgs := make(map[string]string, len(shares.Included.Organizations))
+	for _, o := range shares.Included.Organizations {
+		orgs[o.GUID] = o.Name
+	}
+
+	result := make([]SharedSpace, 0, len(shares.Included.Spaces))
+	for _, s := range shares.Included.Spaces {
+		result = append(result, SharedSpace{
+			Org:   orgs[s.Relationships.Organization.guid()],
+			Space: s.Name,
+		})
+	}
+	return result, nil
+}
+
+// ShareServiceInstance shares the service instance into the given spaces
+func (c *v3Client) ShareServiceInstance(serviceInstanceGUID string, spaceGUIDs []string) error {
+	data := make([]map[string]string, 0, len(spaceGUIDs))
+	for _, guid := range spaceGUIDs {
+		data = append(data, map[string]string{"guid": guid})
+	}
+
+	path := "/v3/service_instances/" + serviceInstanceGUID + "/relationships/shared_spaces"
+	if err := c.do(http.MethodPost, path, map[string]interface{}{"data": data}, nil); err != nil {
+		return fmt.Errorf("error sharing service instance %s: %w", serviceInstanceGUID, err)
+	}
+	return nil
+}
+
+// DeleteServiceInstance deletes a service instance. The v3 API always deletes the bindings and keys of
+// the instance and runs asynchronously, so recursive and async are ignored.
+func (c *v3Client) DeleteServiceInstance(guid string, recursive, async bool) error {
+	if err := c.do(http.MethodDelete, "/v3/service_instances/"+guid, nil, nil); err != nil {
+		return fmt.Errorf("error deleting service instance %s: %w", guid, err)
+	}
+	return nil
+}
+
+// GetServiceInstanceMetadata returns the labels and annotations of the service instance with the given guid
+func (c *v3Client) GetServiceInstanceMetadata(guid string) (Metadata, error) {
+	var si struct {
+		Metadata Metadata `json:"metadata"`
+	}
+	if err := c.do(http.MethodGet, "/v3/service_instances/"+guid, nil, &si); err != nil {
+		return Metadata{}, fmt.Errorf("error getting metadata of service instance %s: %w", guid, err)
+	}
+	return si.Metadata, nil
+}
+
+// UpdateServiceInstanceMetadata adds the labels and annotations to the service instance. Metadata only
+// updates never reach the broker, so they are applied right away for managed service instances too.
+func (c *v3Client) UpdateServiceInstanceMetadata(guid string, metadata Metadata) error {
+	if err := c.do(http.MethodPatch, "/v3/service_instances/"+guid, map[string]interface{}{"metadata": metadata}, nil); err != nil {
+		return fmt.Errorf("error updating metadata of service instance %s: %w", guid, err)
+	}
+	return nil
+}
+
+// ListUserProvidedServiceInstancesByQuery returns the user provided service instances matching the
+// v3 filters in query, along with their credentials
+func (c *v3Client) ListUserProvidedServiceInstancesByQuery(query url.Values) ([]cfclient.UserProvidedServiceInstance, error) {
+	instances, err := c.listServiceInstances(v3UserProvidedType, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]cfclient.UserProvidedServiceInstance, 0, len(instances))
+	for _, si := range instances {
+		ups, err := c.withCredentials(si)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, ups)
+	}
+	return result, nil
+}
+
+// GetUserProvidedServiceInstanceByGuid returns the user provided service instance with the given guid
+func (c *v3Client) GetUserProvidedServiceInstanceByGuid(guid string) (cfclient.UserProvidedServiceInstance, error) {
+	var si v3ServiceInstance
+	if err := c.do(http.MethodGet, "/v3/service_instances/"+guid, nil, &si); err != nil {
+		return cfclient.UserProvidedServiceInstance{}, fmt.Errorf("error getting user provided service instance %s: %w", guid, err)
+	}
+	return c.withCredentials(si)
+}
+
+// CreateUserProvidedServiceInstance creates a user provided service instance
+func (c *v3Client) CreateUserProvidedServiceInstance(req cfclient.UserProvidedServiceInstanceRequest) (*cfclient.UserProvidedServiceInstance, error) {
+	body := userProvidedServiceInstanceBody(req)
+	body["type"] = v3UserProvidedType
+	body["name"] = req.Name
+	body["relationships"] = map[string]interface{}{"space": toOne(req.SpaceGuid)}
+
+	var si v3ServiceInstance
+	if err := c.do(http.MethodPost, "/v3/service_instances", body, &si); err != nil {
+		return nil, fmt.Errorf("error creating user provided service instance %s: %w", req.Name, err)
+	}
+
+	ups := convertV3UserProvidedServiceInstance(si, req.Credentials)
+	return &ups, nil
+}
+
+// UpdateUserProvidedServiceInstance updates a user provided service instance
+func (c *v3Client) UpdateUserProvidedServiceInstance(guid string, req cfclient.UserProvidedServiceInstanceRequest) (*cfclient.UserProvidedServiceInstance, error) {
+	body := userProvidedServiceInstanceBody(req)
+	if req.Name != "" {
+		body["name"] = req.Name
+	}
+
+	var si v3ServiceInstance
+	if err := c.do(http.MethodPatch, "/v3/service_instances/"+guid, body, &si); err != nil {
+		return nil, fmt.Errorf("error updating user provided service instance %s: %w", guid, err)
+	}
+
+	ups := convertV3UserProvidedServiceInstance(si, req.Credentials)
+	return &ups, nil
+}
+
+// ListServiceBindingsByQuery returns the app bindings matching the v3 filters in query,
+// e.g. service_instance_guids or app_guids, along with their credentials
+func (c *v3Client) ListServiceBindingsByQuery(query url.Values) ([]cfclient.ServiceBinding, error) {
+	bindings, err := c.listCredentialBindings(v3AppBindingType, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]cfclient.ServiceBinding, 0, len(bindings))
+	for _, b := range bindings {
+		details, err := c.credentialBindingDetails(b.GUID)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, cfclient.ServiceBinding{
+			Guid:                b.GUID,
+			Name:                b.Name,
+			CreatedAt:           b.CreatedAt,
+			UpdatedAt:           b.UpdatedAt,
+			AppGuid:             b.Relationships.App.guid(),
+			ServiceInstanceGuid: b.Relationships.ServiceInstance.guid(),
+			Credentials:         details.Credentials,
+			SyslogDrainUrl:      details.SyslogDrainURL,
+			VolumeMounts:        details.VolumeMounts,
+		})
+	}
+	return result, nil
+}
+
+// CreateServiceBinding binds the service instance to the app
+func (c *v3Client) CreateServiceBinding(req CreateServiceBindingRequest) (*cfclient.ServiceBinding, error) {
+	body := map[string]interface{}{
+		"type": v3AppBindingType,
+		"relationships": map[string]interface{}{
+			"app":              toOne(req.AppGuid),
+			"service_instance": toOne(req.ServiceInstanceGuid),
+		},
+	}
+	if req.Name != "" {
+		body["name"] = req.Name
+	}
+	if len(req.Parameters) > 0 {
+		body["parameters"] = req.Parameters
+	}
+
+	b, err := c.createCredentialBinding(body, url.Values{
+		"app_guids":              []string{req.AppGuid},
+		"service_instance_guids": []string{req.ServiceInstanceGuid},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error binding service instance %s to app %s: %w", req.ServiceInstanceGuid, req.AppGuid, err)
+	}
+
+	return &cfclient.ServiceBinding{
+		Guid:                b.GUID,
+		Name:                b.Name,
+		CreatedAt:           b.CreatedAt,
+		UpdatedAt:           b.UpdatedAt,
+		AppGuid:             req.AppGuid,
+		ServiceInstanceGuid: req.ServiceInstanceGuid,
+	}, nil
+}
+
+// GetServiceBindingParams returns the parameters the broker reports for the given app binding
+func (c *v3Client) GetServiceBindingParams(guid string) (map[string]interface{}, error) {
+	params := make(map[string]interface{})
+	if err := c.do(http.MethodGet, "/v3/service_credential_bindings/"+guid+"/parameters", nil, &params); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// DeleteServiceBinding deletes the app binding with the given guid
+func (c *v3Client) DeleteServiceBinding(guid string) error {
+	if err := c.do(http.MethodDelete, "/v3/service_credential_bindings/"+guid, nil, nil); err != nil {
+		return fmt.Errorf("error deleting service binding %s: %w", guid, err)
+	}
+	return nil
+}
+
+// GetServiceCredentialBindingMetadata returns the labels and annotations of the app binding or service key
+// with the given guid
+func (c *v3Client) GetServiceCredentialBindingMetadata(guid string) (Metadata, error) {
+	var b struct {
+		Metadata Metadata `json:"metadata"`
+	}
+	if err := c.do(http.MethodGet, "/v3/service_credential_bindings/"+guid, nil, &b); err != nil {
+		return Metadata{}, fmt.Errorf("error getting metadata of service credential binding %s: %w", guid, err)
+	}
+	return b.Metadata, nil
+}
+
+// UpdateServiceCredentialBindingMetadata adds the labels and annotations to the app binding or service key
+func (c *v3Client) UpdateServiceCredentialBindingMetadata(guid string, metadata Metadata) error {
+	if err := c.do(http.MethodPatch, "/v3/service_credential_bindings/"+guid, map[string]interface{}{"metadata": metadata}, nil); err != nil {
+		return fmt.Errorf("error updating metadata of service credential binding %s: %w", guid, err)
+	}
+	return nil
+}
+
+// ListServiceKeysByQuery returns the service keys matching the v3 filters in query,
+// e.g. service_instance_guids or names, along with their credentials
+func (c *v3Client) ListServiceKeysByQuery(query url.Values) ([]cfclient.ServiceKey, error) {
+	keys, err := c.listCredentialBindings(v3KeyBindingType, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]cfclient.ServiceKey, 0, len(keys))
+	for _, k := range keys {
+		details, err := c.credentialBindingDetails(k.GUID)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, cfclient.ServiceKey{
+			Guid:                k.GUID,
+			Name:                k.Name,
+			CreatedAt:           k.CreatedAt,
+			UpdatedAt:           k.UpdatedAt,
+			ServiceInstanceGuid: k.Relationships.ServiceInstance.guid(),
+			Credentials:         details.Credentials,
+		})
+	}
+	return result, nil
+}
+
+// CreateServiceKey creates a service key for the service instance
+func (c *v3Client) CreateServiceKey(req cfclient.CreateServiceKeyRequest) (cfclient.ServiceKey, error) {
+	body := map[string]interface{}{
+		"type": v3KeyBindingType,
+		"name": req.Name,
+		"relationships": map[string]interface{}{
+			"service_instance": toOne(req.ServiceInstanceGuid),
+		},
+	}
+	if req.Parameters != nil {
+		body["parameters"] = req.Parameters
+	}
+
+	k, err := c.createCredentialBinding(body, url.Values{
+		"names":                  []string{req.Name},
+		"service_instance_guids": []string{req.ServiceInstanceGuid},
+	})
+	if err != nil {
+		return cfclient.ServiceKey{}, fmt.Errorf("error creating service key %s: %w", req.Name, err)
+	}
+
+	return cfclient.ServiceKey{
+		Guid:                k.GUID,
+		Name:                k.Name,
+		CreatedAt:           k.CreatedAt,
+		UpdatedAt:           k.UpdatedAt,
+		ServiceInstanceGuid: req.ServiceInstanceGuid,
+	}, nil
+}
+
+// ListRouteBindings returns the routes whose requests are sent through the given route service instance
+func (c *v3Client) ListRouteBindings(serviceInstanceGUID string) ([]RouteBinding, error) {
+	type v3RouteBinding struct {
+		Relationships struct {
+			Route v3Relationship `json:"route"`
+		} `json:"relationships"`
+	}
+	var bindings []v3RouteBinding
+	query := url.Values{"service_instance_guids": []string{serviceInstanceGUID}}
+	err := c.list("/v3/service_route_bindings", query, func(resources json.RawMessage) error {
+		var page []v3RouteBinding
+		if err := json.Unmarshal(resources, &page); err != nil {
+			return err
+		}
+		bindings = append(bindings, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing route bindings of service instance %s: %w", serviceInstanceGUID, err)
+	}
+
+	result := make([]RouteBinding, 0, len(bindings))
+	for _, b := range bindings {
+		var route struct {
+			v3Route
+			Included struct {
+				Domains []struct {
+					Name string `json:"name"`
+				} `json:"domains"`
+			} `json:"included"`
+		}
+		if err = c.do(http.MethodGet, "/v3/routes/"+b.Relationships.Route.guid()+"?include=domain", nil, &route); err != nil {
+			return nil, fmt.Errorf("error getting route %s: %w", b.Relationships.Route.guid(), err)
+		}
+		if len(route.Included.Domains) == 0 {
+			return nil, fmt.Errorf("domain of route %s was not found", route.GUID)
+		}
+		result = append(result, RouteBinding{
+			Host:   route.Host,
+			Domain: route.Included.Domains[0].Name,
+			Path:   route.Path,
+		})
+	}
+	return result, nil
+}
+
+// FindRoute returns the guid of the route with the given host, domain and path, or an empty guid when
+// the domain or the route does not exist
+func (c *v3Client) FindRoute(host, domain, path string) (string, error) {
+	var domains []struct {
+		GUID string `json:"guid"`
+	}
+	err := c.list("/v3/domains", url.Values{"names": []string{domain}}, func(resources json.RawMessage) error {
+		return json.Unmarshal(resources, &domains)
+	})
+	if err != nil {
+		return "", fmt.Errorf("error looking up domain %s: %w", domain, err)
+	}
+	if len(domains) == 0 {
+		return "", nil
+	}
+
+	// routes without a host or path can't be filtered on them, so the matching route is picked here
+	query := url.Values{"domain_guids": []string{domains[0].GUID}}
+	if host != "" {
+		query.Set("hosts", host)
+	}
+	var routes []v3Route
+	err = c.list("/v3/routes", query, func(resources json.RawMessage) error {
+		var page []v3Route
+		if err := json.Unmarshal(resources, &page); err != nil {
+			return err
+		}
+		routes = append(routes, page...)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("error looking up route %s: %w", RouteBinding{Host: host, Domain: domain, Path: path}.URL(), err)
+	}
+
+	for _, r := range routes {
+		if r.Host == host && r.Path == path {
+			return r.GUID, nil
+		}
+	}
+	return "", nil
+}
+
+// CreateRouteBinding sends the requests of the route through the route service instance
+func (c *v3Client) CreateRouteBinding(routeGUID, serviceInstanceGUID string) error {
+	body := map[string]interface{}{
+		"relationships": map[string]interface{}{
+			"route":            toOne(routeGUID),
+			"service_instance": toOne(serviceInstanceGUID),
+		},
+	}
+	if err := c.do(http.MethodPost, "/v3/service_route_bindings", body, nil); err != nil {
+		return fmt.Errorf("error binding route %s to service instance %s: %w", routeGUID, serviceInstanceGUID, err)
+	}
+	return nil
+}
+
+// ListServicePlans returns all the service plans visible to the user
+func (c *v3Client) ListServicePlans() ([]cfclient.ServicePlan, error) {
+	return c.ListServicePlansByQuery(nil)
+}
+
+// ListServicePlansByQuery returns the service plans matching the v3 filters in query,
+// e.g. names or service_offering_guids
+func (c *v3Client) ListServicePlansByQuery(query url.Values) ([]cfclient.ServicePlan, error) {
+	var plans []v3ServicePlan
+	err := c.list("/v3/service_plans", query, func(resources json.RawMessage) error {
+		var page []v3ServicePlan
+		if err := json.Unmarshal(resources, &page); err != nil {
+			return err
+		}
+		plans = append(plans, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing service plans: %w", err)
+	}
+
+	result := make([]cfclient.ServicePlan, 0, len(plans))
+	for _, p := range plans {
+		result = append(result, convertV3ServicePlan(p))
+	}
+	return result, nil
+}
+
+// GetServicePlanByGUID returns the service plan with the given guid
+func (c *v3Client) GetServicePlanByGUID(guid string) (*cfclient.ServicePlan, error) {
+	var p v3ServicePlan
+	if err := c.do(http.MethodGet, "/v3/service_plans/"+guid, nil, &p); err != nil {
+		return nil, fmt.Errorf("error getting service plan %s: %w", guid, err)
+	}
+	plan := convertV3ServicePlan(p)
+	return &plan, nil
+}
+
+// ListServices returns all the service offerings visible to the user
+func (c *v3Client) ListServices() ([]cfclient.Service, error) {
+	var offerings []v3ServiceOffering
+	err := c.list("/v3/service_offerings", nil, func(resources json.RawMessage) error {
+		var page []v3ServiceOffering
+		if err := json.Unmarshal(resources, &page); err != nil {
+			return err
+		}
+		offerings = append(offerings, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing service offerings: %w", err)
+	}
+
+	result := make([]cfclient.Service, 0, len(offerings))
+	for _, o := range offerings {
+		result = append(result, convertV3ServiceOffering(o))
+	}
+	return result, nil
+}
+
+// GetServiceByGuid returns the service offering with the given guid
+func (c *v3Client) GetServiceByGuid(guid string) (cfclient.Service, error) {
+	var o v3ServiceOffering
+	if err := c.do(http.MethodGet, "/v3/service_offerings/"+guid, nil, &o); err != nil {
+		return cfclient.Service{}, fmt.Errorf("error getting service offering %s: %w", guid, err)
+	}
+	return convertV3ServiceOffering(o), nil
+}
+
+// ListServiceBrokers returns all the service brokers visible to the user
+func (c *v3Client) ListServiceBrokers() ([]cfclient.ServiceBroker, error) {
+	var brokers []v3ServiceBroker
+	err := c.list("/v3/service_brokers", nil, func(resources json.RawMessage) error {
+		var page []v3ServiceBroker
+		if err := json.Unmarshal(resources, &page); err != nil {
+			return err
+		}
+		brokers = append(brokers, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing service brokers: %w", err)
+	}
+
+	result := make([]cfclient.ServiceBroker, 0, len(brokers))
+	for _, b := range brokers {
+		result = append(result, cfclient.ServiceBroker{
+			Guid:      b.GUID,
+			Name:      b.Name,
+			CreatedAt: b.CreatedAt,
+			UpdatedAt: b.UpdatedAt,
+			BrokerURL: b.URL,
+			SpaceGUID: b.Relationships.Space.guid(),
+		})
+	}
+	return result, nil
+}
+
+func (c *v3Client) listServiceInstances(instanceType string, query url.Values) ([]v3ServiceInstance, error) {
+	var instances []v3ServiceInstance
+	err := c.list("/v3/service_instances", withType(query, instanceType), func(resources json.RawMessage) error {
+		var page []v3ServiceInstance
+		if err := json.Unmarshal(resources, &page); err != nil {
+			return err
+		}
+		instances = append(instances, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing %s service instances: %w", instanceType, err)
+	}
+	return instances, nil
+}
+
+func (c *v3Client) withCredentials(si v3ServiceInstance) (cfclient.UserProvidedServiceInstance, error) {
+	credentials := make(map[string]interface{})
+	if err := c.do(http.MethodGet, "/v3/service_instances/"+si.GUID+"/credentials", nil, &credentials); err != nil {
+		return cfclient.UserProvidedServiceInstance{}, fmt.Errorf("error getting credentials of user provided service instance %s: %w", si.Name, err)
+	}
+	return convertV3UserProvidedServiceInstance(si, credentials), nil
+}
+
+func (c *v3Client) listCredentialBindings(bindingType string, query url.Values) ([]v3CredentialBinding, error) {
+	var bindings []v3CredentialBinding
+	err := c.list("/v3/service_credential_bindings", withType(query, bindingType), func(resources json.RawMessage) error {
+		var page []v3CredentialBinding
+		if err := json.Unmarshal(resources, &page); err != nil {
+			return err
+		}
+		bindings = append(bindings, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing %s credential bindings: %w", bindingType, err)
+	}
+	return bindings, nil
+}
+
+func (c *v3Client) credentialBindingDetails(guid string) (v3CredentialBindingDetails, error) {
+	var details v3CredentialBindingDetails
+	if err := c.do(http.MethodGet, "/v3/service_credential_bindings/"+guid+"/details", nil, &details); err != nil {
+		return details, fmt.Errorf("error getting details of credential binding %s: %w", guid, err)
+	}
+	if details.Credentials == nil {
+		details.Credentials = make(map[string]interface{})
+	}
+	return details, nil
+}
+
+// createCredentialBinding creates an app binding or service key. Bindings to managed service instances
+// are created asynchronously, in which case the new binding is looked up using query.
+func (c *v3Client) createCredentialBinding(body map[string]interface{}, query url.Values) (v3CredentialBinding, error) {
+	var b v3CredentialBinding
+	if err := c.do(http.MethodPost, "/v3/service_credential_bindings", body, &b); err != nil {
+		return b, err
+	}
+	if b.GUID != "" {
+		return b, nil
+	}
+
+	bindings, err := c.listCredentialBindings(body["type"].(string), query)
+	if err != nil {
+		return b, err
+	}
+	if len(bindings) == 0 {
+		return b, fmt.Errorf("credential binding was not found after it was created")
+	}
+	return bindings[0], nil
+}
+
+// list requests every page of the v3 collection at path, passing the resources of each page to appendPage
+func (c *v3Client) list(path string, query url.Values, appendPage func(resources json.RawMessage) error) error {
+	requestURL := path
+	if e := query.Encode(); e != "" {
+		requestURL += "?" + e
+	}
+
+	for requestURL != "" {
+		var page v3Page
+		if err := c.do(http.MethodGet, requestURL, nil, &page); err != nil {
+			return err
+		}
+		if len(page.Resources) > 0 {
+			if err := appendPage(page.Resources); err != nil {
+				return fmt.Errorf("error parsing %s: %w", path, err)
+			}
+		}
+
+		requestURL = ""
+		if page.Pagination.Next != nil && page.Pagination.Next.Href != "" {
+			next, err := url.Parse(page.Pagination.Next.Href)
+			if err != nil {
+				return fmt.Errorf("error parsing the next page of %s: %w", path, err)
+			}
+			requestURL = next.RequestURI()
+		}
+	}
+
+	return nil
+}
+
+// do sends a request with an optional JSON body and decodes the JSON response into result when
+// one is given and the response has a body. Error responses are returned as cfclient errors.
+func (c *v3Client) do(method, path string, body interface{}, result interface{}) error {
+	var req *cfclient.Request
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		req = c.NewRequestWithBody(method, path, bytes.NewReader(b))
+	} else {
+		req = c.NewRequest(method, path)
+	}
+
+	res, err := c.DoRequest(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if result == nil || res.StatusCode == http.StatusAccepted || res.StatusCode == http.StatusNoContent {
+		_, _ = io.Copy(io.Discard, res.Body)
+		return nil
+	}
+
+	if err = json.NewDecoder(res.Body).Decode(result); err != nil && err != io.EOF {
+		return fmt.Errorf("error parsing response from %s: %w", path, err)
+	}
+	return nil
+}
+
+func withType(query url.Values, t string) url.Values {
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set("type", t)
+	return q
+}
+
+func userProvidedServiceInstanceBody(req cfclient.UserProvidedServiceInstanceRequest) map[string]interface{} {
+	body := map[string]interface{}{
+		"syslog_drain_url":  req.SyslogDrainUrl,
+		"route_service_url": req.RouteServiceUrl,
+	}
+	if req.Credentials != nil {
+		body["credentials"] = req.Credentials
+	}
+	if req.Tags != nil {
+		body["tags"] = req.Tags
+	}
+	return body
+}
+
+func convertV3ServiceInstance(si v3ServiceInstance) cfclient.ServiceInstance {
+	return cfclient.ServiceInstance{
+		Guid:            si.GUID,
+		Name:            si.Name,
+		CreatedAt:       si.CreatedAt,
+		UpdatedAt:       si.UpdatedAt,
+		Type:            convertV3ServiceInstanceType(si.Type),
+		Tags:            si.Tags,
+		DashboardUrl:    si.DashboardURL,
+		SpaceGuid:       si.Relationships.Space.guid(),
+		ServicePlanGuid: si.Relationships.ServicePlan.guid(),
+		LastOperation: cfclient.LastOperation{
+			Type:        si.LastOperation.Type,
+			State:       si.LastOperation.State,
+			Description: si.LastOperation.Description,
+			CreatedAt:   si.LastOperation.CreatedAt,
+			UpdatedAt:   si.LastOperation.UpdatedAt,
+		},
+	}
+}
+
+func convertV3UserProvidedServiceInstance(si v3ServiceInstance, credentials map[string]interface{}) cfclient.UserProvidedServiceInstance {
+	return cfclient.UserProvidedServiceInstance{
+		Guid:            si.GUID,
+		Name:            si.Name,
+		CreatedAt:       si.CreatedAt,
+		UpdatedAt:       si.UpdatedAt,
+		Credentials:     credentials,
+		SpaceGuid:       si.Relationships.Space.guid(),
+		Type:            convertV3ServiceInstanceType(si.Type),
+		Tags:            si.Tags,
+		RouteServiceUrl: si.RouteServiceURL,
+		SyslogDrainUrl:  si.SyslogDrainURL,
+	}
+}
+
+// convertV3ServiceInstanceType maps v3 instance types to the v2 names written to exported files
+func convertV3ServiceInstanceType(t string) string {
+	switch t {
+	case v3ManagedType:
+		return "managed_service_instance"
+	case v3UserProvidedType:
+		return "user_provided_service_instance"
+	}
+	return t
+}
+
+func convertV3ServicePlan(p v3ServicePlan) cfclient.ServicePlan {
+	return cfclient.ServicePlan{
+		Guid:           p.GUID,
+		Name:           p.Name,
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
+		Free:           p.Free,
+		Description:    p.Description,
+		ServiceGuid:    p.Relationships.ServiceOffering.guid(),
+		Extra:          p.BrokerCatalog.Metadata,
+		UniqueId:       p.BrokerCatalog.ID,
+		Public:         p.VisibilityType == "public",
+		Active:         p.Available,
+		Bindable:       p.BrokerCatalog.Features.Bindable,
+		PlanUpdateable: p.BrokerCatalog.Features.PlanUpdateable,
+	}
+}
+
+func convertV3ServiceOffering(o v3ServiceOffering) cfclient.Service {
+	return cfclient.Service{
+		Guid:                 o.GUID,
+		Label:                o.Name,
+		CreatedAt:            o.CreatedAt,
+		UpdatedAt:            o.UpdatedAt,
+		Description:          o.Description,
+		Active:               o.Available,
+		Bindable:             o.BrokerCatalog.Features.Bindable,
+		ServiceBrokerGuid:    o.Relationships.ServiceBroker.guid(),
+		PlanUpdateable:       o.BrokerCatalog.Features.PlanUpdateable,
+		Tags:                 o.Tags,
+		UniqueID:             o.BrokerCatalog.ID,
+		Requires:             o.Requires,
+		InstancesRetrievable: o.BrokerCatalog.Features.InstancesRetrievable,
+		BindingsRetrievable:  o.BrokerCatalog.Features.BindingsRetrievable,
+	}
+}
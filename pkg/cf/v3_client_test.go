@@ -0,0 +1,330 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/cloudfoundry-community/go-cfclient"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestV3Client(t *testing.T, mux *http.ServeMux) *v3Client {
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return &v3Client{
+		Client: &cfclient.Client{
+			Config: cfclient.Config{
+				ApiAddress: server.URL,
+				HttpClient: server.Client(),
+			},
+		},
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, body)
+}
+
+func TestV3Client_ListSpaceServiceInstances(t *testing.T) {
+	mux := http.NewServeMux()
+	var server string
+	mux.HandleFunc("/v3/service_instances", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "managed", r.URL.Query().Get("type"))
+		require.Equal(t, "space-guid", r.URL.Query().Get("space_guids"))
+		if r.URL.Query().Get("page") == "2" {
+			writeJSON(w, http.StatusOK, `{"pagination":{"next":null},"resources":[{"guid":"si-2","name":"db-2","type":"managed","relationships":{"space":{"data":{"guid":"space-guid"}},"service_plan":{"data":{"guid":"plan-2"}}}}]}`)
+			return
+		}
+		writeJSON(w, http.StatusOK, fmt.Sprintf(`{"pagination":{"next":{"href":"%s/v3/service_instances?page=2&space_guids=space-guid&type=managed"}},"resources":[{"guid":"si-1","name":"db-1","type":"managed","tags":["mysql"],"dashboard_url":null,"last_operation":{"type":"create","state":"succeeded"},"relationships":{"space":{"data":{"guid":"space-guid"}},"service_plan":{"data":{"guid":"plan-1"}}}}]}`, server))
+	})
+	c := newTestV3Client(t, mux)
+	server = c.Config.ApiAddress
+
+	got, err := c.ListSpaceServiceInstances("space-guid")
+	require.NoError(t, err)
+	require.Equal(t, []cfclient.ServiceInstance{
+		{
+			Guid:            "si-1",
+			Name:            "db-1",
+			Type:            "managed_service_instance",
+			Tags:            []string{"mysql"},
+			SpaceGuid:       "space-guid",
+			ServicePlanGuid: "plan-1",
+			LastOperation:   cfclient.LastOperation{Type: "create", State: "succeeded"},
+		},
+		{
+			Guid:            "si-2",
+			Name:            "db-2",
+			Type:            "managed_service_instance",
+			SpaceGuid:       "space-guid",
+			ServicePlanGuid: "plan-2",
+		},
+	}, got)
+}
+
+func TestV3Client_ListServiceBindingsByQuery(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v3/service_credential_bindings", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "app", r.URL.Query().Get("type"))
+		require.Equal(t, "si-1", r.URL.Query().Get("service_instance_guids"))
+		writeJSON(w, http.StatusOK, `{"pagination":{"next":null},"resources":[{"guid":"binding-1","name":"prod-db","type":"app","relationships":{"app":{"data":{"guid":"app-1"}},"service_instance":{"data":{"guid":"si-1"}}}}]}`)
+	})
+	mux.HandleFunc("/v3/service_credential_bindings/binding-1/details", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, `{"credentials":{"password":"secret"},"syslog_drain_url":"syslog://logs.example.com"}`)
+	})
+	c := newTestV3Client(t, mux)
+
+	got, err := c.ListServiceBindingsByQuery(url.Values{"service_instance_guids": []string{"si-1"}})
+	require.NoError(t, err)
+	require.Equal(t, []cfclient.ServiceBinding{
+		{
+			Guid:                "binding-1",
+			Name:                "prod-db",
+			AppGuid:             "app-1",
+			ServiceInstanceGuid: "si-1",
+			Credentials:         map[string]interface{}{"password": "secret"},
+			SyslogDrainUrl:      "syslog://logs.example.com",
+		},
+	}, got)
+}
+
+func TestV3Client_ListUserProvidedServiceInstancesByQuery(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v3/service_instances", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "user-provided", r.URL.Query().Get("type"))
+		writeJSON(w, http.StatusOK, `{"pagination":{"next":null},"resources":[{"guid":"ups-1","name":"my-ups","type":"user-provided","syslog_drain_url":"syslog://logs.example.com","relationships":{"space":{"data":{"guid":"space-guid"}}}}]}`)
+	})
+	mux.HandleFunc("/v3/service_instances/ups-1/credentials", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, `{"uri":"https://example.com"}`)
+	})
+	c := newTestV3Client(t, mux)
+
+	got, err := c.ListUserProvidedServiceInstancesByQuery(url.Values{"space_guids": []string{"space-guid"}})
+	require.NoError(t, err)
+	require.Equal(t, []cfclient.UserProvidedServiceInstance{
+		{
+			Guid:           "ups-1",
+			Name:           "my-ups",
+			Type:           "user_provided_service_instance",
+			SpaceGuid:      "space-guid",
+			SyslogDrainUrl: "syslog://logs.example.com",
+			Credentials:    map[string]interface{}{"uri": "https://example.com"},
+		},
+	}, got)
+}
+
+func TestV3Client_GetServicePlanAndOffering(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v3/service_plans/plan-1", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, `{"guid":"plan-1","name":"db-small","free":true,"available":true,"visibility_type":"public","broker_catalog":{"id":"catalog-plan-1","features":{"bindable":true,"plan_updateable":true}},"relationships":{"service_offering":{"data":{"guid":"offering-1"}}}}`)
+	})
+	mux.HandleFunc("/v3/service_offerings/offering-1", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, `{"guid":"offering-1","name":"p.mysql","available":true,"tags":["mysql"],"broker_catalog":{"id":"catalog-offering-1","features":{"bindable":true,"instances_retrievable":true}},"relationships":{"service_broker":{"data":{"guid":"broker-1"}}}}`)
+	})
+	c := newTestV3Client(t, mux)
+
+	plan, err := c.GetServicePlanByGUID("plan-1")
+	require.NoError(t, err)
+	require.Equal(t, &cfclient.ServicePlan{
+		Guid:           "plan-1",
+		Name:           "db-small",
+		Free:           true,
+		ServiceGuid:    "offering-1",
+		UniqueId:       "catalog-plan-1",
+		Public:         true,
+		Active:         true,
+		Bindable:       true,
+		PlanUpdateable: true,
+	}, plan)
+
+	svc, err := c.GetServiceByGuid(plan.ServiceGuid)
+	require.NoError(t, err)
+	require.Equal(t, cfclient.Service{
+		Guid:                 "offering-1",
+		Label:                "p.mysql",
+		Active:               true,
+		Bindable:             true,
+		ServiceBrokerGuid:    "broker-1",
+		Tags:                 []string{"mysql"},
+		UniqueID:             "catalog-offering-1",
+		InstancesRetrievable: true,
+	}, svc)
+}
+
+func TestV3Client_GetServiceInstanceParams(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v3/service_instances/si-1/parameters", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, `{"backup_enabled":true}`)
+	})
+	mux.HandleFunc("/v3/service_instances/si-2/parameters", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusBadGateway, `{"errors":[{"code":120004,"title":"CF-ServiceFetchInstanceParametersNotSupported","detail":"This service does not support fetching service instance parameters."}]}`)
+	})
+	c := newTestV3Client(t, mux)
+
+	params, err := c.GetServiceInstanceParams("si-1")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"backup_enabled": true}, params)
+
+	_, err = c.GetServiceInstanceParams("si-2")
+	require.Error(t, err)
+	require.True(t, cfclient.IsServiceFetchInstanceParametersNotSupportedError(err))
+}
+
+func TestV3Client_CreateServiceKey(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v3/service_credential_bindings", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, "key", body["type"])
+			require.Equal(t, "my-key", body["name"])
+			w.Header().Set("Location", "/v3/jobs/job-1")
+			w.WriteHeader(http.StatusAccepted)
+		case http.MethodGet:
+			require.Equal(t, "key", r.URL.Query().Get("type"))
+			require.Equal(t, "my-key", r.URL.Query().Get("names"))
+			writeJSON(w, http.StatusOK, `{"pagination":{"next":null},"resources":[{"guid":"key-1","name":"my-key","type":"key","relationships":{"service_instance":{"data":{"guid":"si-1"}}}}]}`)
+		}
+	})
+	c := newTestV3Client(t, mux)
+
+	key, err := c.CreateServiceKey(cfclient.CreateServiceKeyRequest{Name: "my-key", ServiceInstanceGuid: "si-1"})
+	require.NoError(t, err)
+	require.Equal(t, cfclient.ServiceKey{Guid: "key-1", Name: "my-key", ServiceInstanceGuid: "si-1"}, key)
+}
+
+func TestV3Client_SharedSpaces(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v3/service_instances/si-1/relationships/shared_spaces", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			require.Equal(t, "name,relationships.organization", r.URL.Query().Get("fields[space]"))
+			require.Equal(t, "name", r.URL.Query().Get("fields[space.organization]"))
+			writeJSON(w, http.StatusOK, `{"data":[{"guid":"space-1"}],"included":{"spaces":[{"guid":"space-1","name":"dev","relationships":{"organization":{"data":{"guid":"org-1"}}}}],"organizations":[{"guid":"org-1","name":"blue"}]}}`)
+		case http.MethodPost:
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, []interface{}{map[string]interface{}{"guid": "space-2"}}, body["data"])
+			writeJSON(w, http.StatusOK, `{"data":[{"guid":"space-1"},{"guid":"space-2"}]}`)
+		}
+	})
+	c := newTestV3Client(t, mux)
+
+	shares, err := c.ListSharedSpaces("si-1")
+	require.NoError(t, err)
+	require.Equal(t, []SharedSpace{{Org: "blue", Space: "dev"}}, shares)
+
+	require.NoError(t, c.ShareServiceInstance("si-1", []string{"space-2"}))
+}
+
+func TestV3Client_Metadata(t *testing.T) {
+	metadata := Metadata{
+		Labels:      map[string]string{"team": "payments"},
+		Annotations: map[string]string{"owner": "payments@example.com"},
+	}
+
+	mux := http.NewServeMux()
+	for _, path := range []string{"/v3/service_instances/si-1", "/v3/service_credential_bindings/binding-1"} {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				writeJSON(w, http.StatusOK, `{"guid":"some-guid","metadata":{"labels":{"team":"payments"},"annotations":{"owner":"payments@example.com"}}}`)
+			case http.MethodPatch:
+				var body struct {
+					Metadata Metadata `json:"metadata"`
+				}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				require.Equal(t, metadata, body.Metadata)
+				writeJSON(w, http.StatusOK, `{}`)
+			}
+		})
+	}
+	c := newTestV3Client(t, mux)
+
+	got, err := c.GetServiceInstanceMetadata("si-1")
+	require.NoError(t, err)
+	require.Equal(t, metadata, got)
+	require.NoError(t, c.UpdateServiceInstanceMetadata("si-1", metadata))
+
+	got, err = c.GetServiceCredentialBindingMetadata("binding-1")
+	require.NoError(t, err)
+	require.Equal(t, metadata, got)
+	require.NoError(t, c.UpdateServiceCredentialBindingMetadata("binding-1", metadata))
+}
+
+func TestV3Client_RouteBindings(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v3/service_route_bindings", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			require.Equal(t, "si-1", r.URL.Query().Get("service_instance_guids"))
+			writeJSON(w, http.StatusOK, `{"pagination":{"next":null},"resources":[{"guid":"rb-1","relationships":{"route":{"data":{"guid":"route-1"}},"service_instance":{"data":{"guid":"si-1"}}}}]}`)
+		case http.MethodPost:
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, map[string]interface{}{
+				"route":            map[string]interface{}{"data": map[string]interface{}{"guid": "route-2"}},
+				"service_instance": map[string]interface{}{"data": map[string]interface{}{"guid": "si-1"}},
+			}, body["relationships"])
+			w.WriteHeader(http.StatusCreated)
+		}
+	})
+	mux.HandleFunc("/v3/routes/route-1", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "domain", r.URL.Query().Get("include"))
+		writeJSON(w, http.StatusOK, `{"guid":"route-1","host":"auth","path":"/admin","relationships":{"domain":{"data":{"guid":"domain-1"}}},"included":{"domains":[{"guid":"domain-1","name":"apps.example.com"}]}}`)
+	})
+	mux.HandleFunc("/v3/domains", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("names") != "apps.example.com" {
+			writeJSON(w, http.StatusOK, `{"pagination":{"next":null},"resources":[]}`)
+			return
+		}
+		writeJSON(w, http.StatusOK, `{"pagination":{"next":null},"resources":[{"guid":"domain-1","name":"apps.example.com"}]}`)
+	})
+	mux.HandleFunc("/v3/routes", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "domain-1", r.URL.Query().Get("domain_guids"))
+		require.Equal(t, "auth", r.URL.Query().Get("hosts"))
+		writeJSON(w, http.StatusOK, `{"pagination":{"next":null},"resources":[{"guid":"route-1","host":"auth","path":"/admin"},{"guid":"route-2","host":"auth","path":""}]}`)
+	})
+	c := newTestV3Client(t, mux)
+
+	bindings, err := c.ListRouteBindings("si-1")
+	require.NoError(t, err)
+	require.Equal(t, []RouteBinding{{Host: "auth", Domain: "apps.example.com", Path: "/admin"}}, bindings)
+
+	guid, err := c.FindRoute("auth", "apps.example.com", "")
+	require.NoError(t, err)
+	require.Equal(t, "route-2", guid)
+
+	guid, err = c.FindRoute("auth", "apps.example.com", "/missing")
+	require.NoError(t, err)
+	require.Empty(t, guid)
+
+	guid, err = c.FindRoute("auth", "missing.example.com", "")
+	require.NoError(t, err)
+	require.Empty(t, guid)
+
+	require.NoError(t, c.CreateRouteBinding("route-2", "si-1"))
+}
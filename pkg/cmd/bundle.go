@@ -0,0 +1,118 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cli"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/io"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/bundle"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/report"
+)
+
+// checkBundleExportDir refuses to export with --bundle into a directory holding files of an earlier export, which
+// would end up in the bundle. A resumed export continues the export that wrote them.
+func checkBundleExportDir(cfg *config.Config, fso io.FileSystemOperations) error {
+	if cfg.Bundle == "" || cfg.Resume {
+		return nil
+	}
+
+	empty, err := fso.IsEmpty(cfg.ExportDir)
+	if err != nil {
+		return err
+	}
+	if !empty {
+		return fmt.Errorf("cannot write bundle %q, the export directory %q is not empty", cfg.Bundle, cfg.ExportDir)
+	}
+
+	return nil
+}
+
+// writeBundle packs the export directory into the file given with --bundle. The manifest lists the service
+// instances exported successfully by this run.
+func writeBundle(cfg *config.Config, s *report.Summary) error {
+	if cfg.Bundle == "" {
+		return nil
+	}
+
+	if cfg.DryRun {
+		log.Infof("Would write bundle %s", cfg.Bundle)
+		return nil
+	}
+
+	var instances []bundle.Instance
+	for _, r := range s.Results() {
+		if r.Status != report.Successful {
+			continue
+		}
+		instances = append(instances, bundle.Instance{
+			Org:      r.OrgName,
+			Space:    r.SpaceName,
+			Name:     r.ServiceName,
+			Service:  r.Service,
+			Migrator: r.Migrator,
+		})
+	}
+
+	m, err := bundle.Create(cfg.Bundle, cfg.ExportDir, cli.Env.Version, instances)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Bundle with %d service instances and %d files written to %s", len(m.Instances), len(m.Files), cfg.Bundle)
+	return nil
+}
+
+// extractBundle unpacks the file given with --bundle into the import directory, failing before anything is
+// imported if any file does not match the checksum in the manifest. A resumed import verifies the files extracted
+// by the interrupted one instead.
+func extractBundle(cfg *config.Config) error {
+	if cfg.Bundle == "" {
+		return nil
+	}
+
+	var m *bundle.Manifest
+	var err error
+	if cfg.Resume {
+		m, err = verifyExtractedBundle(cfg.ExportDir)
+	} else {
+		m, err = bundle.Extract(cfg.Bundle, cfg.ExportDir)
+	}
+	if err != nil {
+		return err
+	}
+
+	if m.Version != cli.Env.Version {
+		log.Warnf("Bundle %s was created by version %s, importing with version %s", cfg.Bundle, m.Version, cli.Env.Version)
+	}
+
+	log.Infof("Verified bundle %s with %d service instances and %d files", cfg.Bundle, len(m.Instances), len(m.Files))
+	return nil
+}
+
+func verifyExtractedBundle(dir string) (*bundle.Manifest, error) {
+	m, err := bundle.ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = m.Verify(dir); err != nil {
+		return nil, fmt.Errorf("bundle extracted to %q is corrupt: %w", dir, err)
+	}
+
+	return m, nil
+}
@@ -0,0 +1,130 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package cmd_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cmd"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cmd/fakes"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/io"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/bundle"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/report"
+)
+
+func TestBundle(t *testing.T) {
+	exportDir := t.TempDir()
+	importDir := filepath.Join(t.TempDir(), "import")
+	bundlePath := filepath.Join(t.TempDir(), "export.tgz")
+
+	orgExporter := &fakes.FakeOrgExporter{
+		ExportAllStub: func(ctx context.Context, om config.OpsManager, dir string) error {
+			path := filepath.Join(dir, "org1", "space1")
+			require.NoError(t, os.MkdirAll(path, 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(path, "db.yml"), []byte("name: db\n"), 0644))
+			summary, _ := config.SummaryFromContext(ctx)
+			summary.AddSuccessfulService("org1", "space1", "db", "p.mysql", report.WithMigrator("mysql"))
+			return nil
+		},
+	}
+	exporterFactory := &fakes.FakeExporterFactory{
+		NewOrgExporterStub: func(migrate.ServiceInstanceExporter) cmd.OrgExporter {
+			return orgExporter
+		},
+	}
+
+	exportCfg := &config.Config{ExportDir: exportDir}
+	exportCmd := cmd.CreateExportCommand(context.TODO(), exportCfg, exporterFactory, nil, io.NewFileSystemHelper(), report.NewSummary(&bytes.Buffer{}))
+	exportCmd.PersistentFlags().BoolP("non-interactive", "n", false, "Don't ask for user input")
+	exportCmd.PersistentFlags().StringVar(&exportCfg.Bundle, "bundle", exportCfg.Bundle, "")
+	exportCmd.SetArgs([]string{"--non-interactive", "--bundle", bundlePath})
+	require.NoError(t, exportCmd.Execute())
+	require.FileExists(t, bundlePath)
+
+	orgImporter := &fakes.FakeOrgImporter{
+		ImportAllStub: func(ctx context.Context, om config.OpsManager, dir string) error {
+			b, err := os.ReadFile(filepath.Join(dir, "org1", "space1", "db.yml"))
+			require.NoError(t, err)
+			require.Equal(t, "name: db\n", string(b))
+			return nil
+		},
+	}
+	importerFactory := &fakes.FakeImporterFactory{
+		NewOrgImporterStub: func(migrate.ServiceInstanceImporter) cmd.OrgImporter {
+			return orgImporter
+		},
+	}
+
+	importCfg := &config.Config{ExportDir: importDir, Bundle: bundlePath}
+	importCmd := cmd.CreateImportCommand(context.TODO(), importCfg, importerFactory, nil, io.NewFileSystemHelper(), report.NewSummary(&bytes.Buffer{}))
+	require.NoError(t, importCmd.Execute())
+	require.Equal(t, 1, orgImporter.ImportAllCallCount())
+
+	m, err := bundle.ReadManifest(importDir)
+	require.NoError(t, err)
+	require.Equal(t, []bundle.Instance{{Org: "org1", Space: "space1", Name: "db", Service: "p.mysql", Migrator: "mysql"}}, m.Instances)
+
+	t.Run("does not write a bundle from an export directory that is not empty", func(t *testing.T) {
+		cfg := &config.Config{ExportDir: exportDir}
+		exportCmd := cmd.CreateExportCommand(context.TODO(), cfg, exporterFactory, nil, io.NewFileSystemHelper(), report.NewSummary(&bytes.Buffer{}))
+		exportCmd.PersistentFlags().BoolP("non-interactive", "n", false, "Don't ask for user input")
+		exportCmd.PersistentFlags().StringVar(&cfg.Bundle, "bundle", cfg.Bundle, "")
+		exportCmd.SetArgs([]string{"--non-interactive", "--bundle", filepath.Join(t.TempDir(), "export.tgz")})
+		exportCmd.SetErr(&bytes.Buffer{})
+		exportCmd.SetOut(&bytes.Buffer{})
+		require.ErrorContains(t, exportCmd.Execute(), "is not empty")
+		require.Equal(t, 1, orgExporter.ExportAllCallCount())
+	})
+
+	t.Run("does not extract a bundle into a directory that is not empty", func(t *testing.T) {
+		cfg := &config.Config{ExportDir: importDir, Bundle: bundlePath}
+		importCmd := cmd.CreateImportCommand(context.TODO(), cfg, importerFactory, nil, io.NewFileSystemHelper(), report.NewSummary(&bytes.Buffer{}))
+		importCmd.SetErr(&bytes.Buffer{})
+		importCmd.SetOut(&bytes.Buffer{})
+		require.ErrorContains(t, importCmd.Execute(), "the directory is not empty")
+		require.Equal(t, 1, orgImporter.ImportAllCallCount())
+	})
+
+	t.Run("resumes an import from the files extracted before", func(t *testing.T) {
+		cfg := &config.Config{ExportDir: importDir, Bundle: bundlePath, Resume: true}
+		importCmd := cmd.CreateImportCommand(context.TODO(), cfg, importerFactory, nil, io.NewFileSystemHelper(), report.NewSummary(&bytes.Buffer{}))
+		require.NoError(t, importCmd.Execute())
+		require.Equal(t, 2, orgImporter.ImportAllCallCount())
+	})
+
+	t.Run("does not import a bundle whose files do not match the manifest", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(exportDir, "org1", "space1", "db.yml"), []byte("name: changed\n"), 0644))
+		f, err := os.Create(bundlePath)
+		require.NoError(t, err)
+		gw := gzip.NewWriter(f)
+		require.NoError(t, io.Tar(exportDir, gw))
+		require.NoError(t, gw.Close())
+		require.NoError(t, f.Close())
+
+		cfg := &config.Config{ExportDir: filepath.Join(t.TempDir(), "import"), Bundle: bundlePath}
+		importCmd := cmd.CreateImportCommand(context.TODO(), cfg, importerFactory, nil, io.NewFileSystemHelper(), report.NewSummary(&bytes.Buffer{}))
+		importCmd.SetErr(&bytes.Buffer{})
+		importCmd.SetOut(&bytes.Buffer{})
+		require.ErrorContains(t, importCmd.Execute(), "org1/space1/db.yml does not match its checksum")
+		require.Equal(t, 2, orgImporter.ImportAllCallCount())
+	})
+}
@@ -0,0 +1,90 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"filippo.io/age"
+	"github.com/spf13/cobra"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/encryption"
+)
+
+// validateEncryption fails the command before anything is exported if the encryption keys can't be loaded
+func validateEncryption(cfg *config.Config) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if cfg.EncryptionRecipient != "" && !cfg.Encrypt {
+			return errors.New("--encryption-recipient requires --encrypt")
+		}
+		return encryption.NewCipher(cfg).Validate()
+	}
+}
+
+// validateAll runs each validation in order and returns the first error
+func validateAll(validations ...func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		for _, validate := range validations {
+			if err := validate(cmd, args); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func CreateGenerateEncryptionKeyCommand() *cobra.Command {
+	var output string
+	keygen := &cobra.Command{
+		Use:   "generate-encryption-key",
+		Short: "Generate a key pair for encrypting exports.",
+		Long: `Generate a key pair for encrypting exports.
+
+The private key is written to the output file, which must not exist yet, and the public key is printed.
+Pass the public key to export with --encrypt --encryption-recipient, and the private key file to import
+with --encryption-identity.`,
+		Example: `service-instance-migrator generate-encryption-key --output=si-migrator.key`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output == "" {
+				return errors.New("--output is required")
+			}
+
+			id, err := age.GenerateX25519Identity()
+			if err != nil {
+				return fmt.Errorf("failed to generate key: %w", err)
+			}
+
+			f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+			if err != nil {
+				return fmt.Errorf("failed to create key file: %w", err)
+			}
+			if _, err = fmt.Fprintf(f, "# public key: %s\n%s\n", id.Recipient(), id); err != nil {
+				_ = f.Close()
+				return fmt.Errorf("failed to write key file: %w", err)
+			}
+			if err = f.Close(); err != nil {
+				return fmt.Errorf("failed to write key file: %w", err)
+			}
+
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "Public key: %s\n", id.Recipient())
+			return err
+		},
+	}
+	keygen.Flags().StringVarP(&output, "output", "o", "", "File to write the private key to")
+
+	return keygen
+}
@@ -0,0 +1,92 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cmd"
+)
+
+func TestGenerateEncryptionKey(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.key")
+	require.NoError(t, os.WriteFile(existing, []byte("keep me"), 0600))
+
+	tests := []struct {
+		name        string
+		commandArgs []string
+		wantErr     string
+		afterFunc   func(out string)
+	}{
+		{
+			name:        "writes the private key and prints the public key",
+			commandArgs: []string{"--output", filepath.Join(dir, "si-migrator.key")},
+			afterFunc: func(out string) {
+				info, err := os.Stat(filepath.Join(dir, "si-migrator.key"))
+				require.NoError(t, err)
+				require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+				f, err := os.Open(filepath.Join(dir, "si-migrator.key"))
+				require.NoError(t, err)
+				defer f.Close()
+				ids, err := age.ParseIdentities(f)
+				require.NoError(t, err)
+				require.Len(t, ids, 1)
+
+				publicKey := strings.TrimSpace(strings.TrimPrefix(out, "Public key: "))
+				require.Equal(t, ids[0].(*age.X25519Identity).Recipient().String(), publicKey)
+			},
+		},
+		{
+			name:        "refuses to overwrite an existing file",
+			commandArgs: []string{"--output", existing},
+			wantErr:     "failed to create key file",
+			afterFunc: func(out string) {
+				b, err := os.ReadFile(existing)
+				require.NoError(t, err)
+				require.Equal(t, "keep me", string(b))
+			},
+		},
+		{
+			name:        "requires an output file",
+			commandArgs: []string{},
+			wantErr:     "--output is required",
+			afterFunc:   func(out string) {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			c := cmd.CreateGenerateEncryptionKeyCommand()
+			c.SetOut(&out)
+			c.SetErr(&out)
+			c.SetArgs(tt.commandArgs)
+			err := c.Execute()
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			tt.afterFunc(out.String())
+		})
+	}
+}
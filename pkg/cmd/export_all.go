@@ -24,6 +24,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/journal"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/report"
 )
 
@@ -48,7 +49,11 @@ func exportAll(ctx context.Context, cfg *config.Config, f ExporterFactory, e mig
 			return err
 		}
 
-		if !nonInteractive {
+		if err := checkBundleExportDir(cfg, fso); err != nil {
+			return err
+		}
+
+		if !nonInteractive && !cfg.Resume {
 			if empty, err := fso.IsEmpty(cfg.ExportDir); !empty {
 				if err != nil {
 					return err
@@ -63,7 +68,14 @@ func exportAll(ctx context.Context, cfg *config.Config, f ExporterFactory, e mig
 			return fmt.Errorf("failed to create export dir: %v: %w", cfg.ExportDir, err)
 		}
 
-		defer s.Display()
+		defer displayReport(cfg, s)
+
+		ctx, err = contextWithJournal(ctx, cfg, journal.ExportFile)
+		if err != nil {
+			return err
+		}
+
+		ctx = contextWithLimiter(ctx, cfg)
 
 		p := mpb.New(mpb.WithWidth(64))
 		ctx = config.ContextWithProgress(ctx, p)
@@ -73,6 +85,6 @@ func exportAll(ctx context.Context, cfg *config.Config, f ExporterFactory, e mig
 			return err
 		}
 
-		return nil
+		return writeBundle(cfg, s)
 	}
 }
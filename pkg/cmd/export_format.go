@@ -0,0 +1,29 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/io"
+)
+
+// validateExportFormat fails the export before any service instance is exported if the file format is unknown
+func validateExportFormat(cfg *config.Config) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		_, err := io.FormatExtension(cfg.ExportFormat)
+		return err
+	}
+}
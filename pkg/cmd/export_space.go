@@ -24,6 +24,7 @@ import (
 	. "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/io"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/journal"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/report"
 	"os"
 )
@@ -64,7 +65,11 @@ func exportSpace(ctx context.Context, cfg *Config, f ExporterFactory, e migrate.
 			return err
 		}
 
-		if !nonInteractive {
+		if err := checkBundleExportDir(cfg, d); err != nil {
+			return err
+		}
+
+		if !nonInteractive && !cfg.Resume {
 			if empty, err := d.IsEmpty(cfg.ExportDir); !empty {
 				if err != nil {
 					return err
@@ -79,9 +84,16 @@ func exportSpace(ctx context.Context, cfg *Config, f ExporterFactory, e migrate.
 			return fmt.Errorf("failed to create export dir: %v, %w", cfg.ExportDir, err)
 		}
 
-		defer s.Display()
+		defer displayReport(cfg, s)
 		space := args[0]
 
+		ctx, err = contextWithJournal(ctx, cfg, journal.ExportFile)
+		if err != nil {
+			return err
+		}
+
+		ctx = contextWithLimiter(ctx, cfg)
+
 		p := mpb.New(mpb.WithWidth(64))
 		ctx = ContextWithProgress(ctx, p)
 
@@ -95,6 +107,6 @@ func exportSpace(ctx context.Context, cfg *Config, f ExporterFactory, e migrate.
 			return err
 		}
 
-		return nil
+		return writeBundle(cfg, s)
 	}
 }
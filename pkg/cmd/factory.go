@@ -61,12 +61,12 @@ func (f ExportMigratorFactory) NewSpaceExporter(exporter migrate.ServiceInstance
 
 func (f ImportMigratorFactory) NewOrgImporter(importer migrate.ServiceInstanceImporter) OrgImporter {
 	return migrate.NewOrgImporter(
-		migrate.NewSpaceImporter(importer),
+		migrate.NewSpaceImporter(importer, f.ClientHolder),
 		f.Config.IncludedOrgs,
 		f.Config.ExcludedOrgs,
 	)
 }
 
 func (f ImportMigratorFactory) NewSpaceImporter(importer migrate.ServiceInstanceImporter) SpaceImporter {
-	return migrate.NewSpaceImporter(importer)
+	return migrate.NewSpaceImporter(importer, f.ClientHolder)
 }
@@ -138,6 +138,7 @@ func TestFactory_NewOrgImporter(t *testing.T) {
 			want: &migrate.OrgImporter{
 				SpaceImporter: &migrate.SpaceImporter{
 					ServiceInstanceImporter: &fakes.FakeServiceInstanceImporter{},
+					ClientHolder:            new(fakes.FakeClientHolder),
 				},
 				ExcludedOrgs: []string{},
 			},
@@ -181,6 +182,7 @@ func TestFactory_NewSpaceImporter(t *testing.T) {
 			},
 			want: &migrate.SpaceImporter{
 				ServiceInstanceImporter: &fakes.FakeServiceInstanceImporter{},
+				ClientHolder:            new(fakes.FakeClientHolder),
 			},
 		},
 	}
@@ -0,0 +1,38 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+)
+
+// resolveFailureMode decides whether a failed service instance stops the run. Imports keep migrating the other
+// service instances unless --fail-fast is set, exports stop at the first failure unless --continue-on-error is set.
+func resolveFailureMode(cfg *config.Config, continueByDefault bool) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if cfg.ContinueOnError && cfg.FailFast {
+			return errors.New("--continue-on-error and --fail-fast can't be used together")
+		}
+		if cfg.FailFast {
+			cfg.ContinueOnError = false
+		} else if continueByDefault {
+			cfg.ContinueOnError = true
+		}
+		return nil
+	}
+}
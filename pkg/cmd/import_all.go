@@ -23,6 +23,7 @@ import (
 	"github.com/spf13/cobra"
 	. "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/journal"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/report"
 )
 
@@ -43,11 +44,23 @@ service-instance-migrator import --include-orgs='org1,org2' --import-dir=/tmp`,
 
 func importAll(ctx context.Context, cfg *Config, f ImporterFactory, i migrate.ServiceInstanceImporter, fso io.FileSystemOperations, s *report.Summary) func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, args []string) error {
+		if err := extractBundle(cfg); err != nil {
+			return err
+		}
+
 		if exists, _ := fso.Exists(cfg.ExportDir); !exists {
 			return fmt.Errorf("import directory %q does not exist", cfg.ExportDir)
 		}
 
-		defer s.Display()
+		defer displayReport(cfg, s)
+
+		var err error
+		ctx, err = contextWithJournal(ctx, cfg, journal.ImportFile)
+		if err != nil {
+			return err
+		}
+
+		ctx = contextWithLimiter(ctx, cfg)
 
 		p := mpb.New(mpb.WithWidth(64))
 		ctx = ContextWithProgress(ctx, p)
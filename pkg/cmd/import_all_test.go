@@ -17,6 +17,10 @@ package cmd_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -29,6 +33,7 @@ import (
 )
 
 func TestImport(t *testing.T) {
+	reportFile := filepath.Join(t.TempDir(), "report.json")
 	type args struct {
 		config                  *config.Config
 		commandArgs             []string
@@ -90,6 +95,59 @@ func TestImport(t *testing.T) {
 				require.Equal(t, 1, args.orgImporter.ImportAllCallCount())
 			},
 		},
+		{
+			name: "writes the report file in the requested format",
+			args: args{
+				orgImporter:           new(fakes.FakeOrgImporter),
+				importMigratorFactory: new(fakes.FakeImporterFactory),
+				fsOperations:          new(iofakes.FakeFileSystemOperations),
+				config: &config.Config{
+					ReportFormat: "json",
+					ReportFile:   reportFile,
+				},
+				commandArgs:   []string{"--import-dir", "/path/to/import-dir"},
+				importDir:     "/path/to/import-dir",
+				reportSummary: report.NewSummary(&bytes.Buffer{}),
+			},
+			beforeFunc: func(args args) {
+				args.fsOperations.ExistsStub = func(s string) (bool, error) {
+					return true, nil
+				}
+				args.importMigratorFactory.NewOrgImporterStub = func(importer migrate.ServiceInstanceImporter) cmd.OrgImporter {
+					return args.orgImporter
+				}
+				args.orgImporter.ImportAllCalls(func(ctx context.Context, om config.OpsManager, dir string) error {
+					summary, ok := config.SummaryFromContext(ctx)
+					require.True(t, ok)
+					summary.AddSuccessfulService("org1", "space1", "db", "p.mysql", report.WithMigrator("mysql"))
+					summary.AddFailedService("org1", "space1", "cache", "p.redis", errors.New("failed to restore: connection refused"))
+					return nil
+				})
+			},
+			afterFunc: func(args args) {
+				b, err := os.ReadFile(reportFile)
+				require.NoError(t, err)
+
+				var r struct {
+					Successful int `json:"successful"`
+					Failed     int `json:"failed"`
+					Results    []struct {
+						Instance string `json:"instance"`
+						Migrator string `json:"migrator"`
+						Status   string `json:"status"`
+						Error    string `json:"error"`
+					} `json:"results"`
+				}
+				require.NoError(t, json.Unmarshal(b, &r))
+				require.Equal(t, 1, r.Successful)
+				require.Equal(t, 1, r.Failed)
+				require.Len(t, r.Results, 2)
+				require.Equal(t, "cache", r.Results[0].Instance)
+				require.Equal(t, "failed", r.Results[0].Status)
+				require.Equal(t, "failed to restore: connection refused", r.Results[0].Error)
+				require.Equal(t, "mysql", r.Results[1].Migrator)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -24,6 +24,7 @@ import (
 	. "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/io"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/journal"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/report"
 )
 
@@ -54,11 +55,23 @@ func importOrg(ctx context.Context, cfg *Config, f ImporterFactory, i migrate.Se
 	return func(cmd *cobra.Command, args []string) error {
 		org := args[0]
 
+		if err := extractBundle(cfg); err != nil {
+			return err
+		}
+
 		if exists, _ := fso.Exists(cfg.ExportDir); !exists {
 			return fmt.Errorf("import directory %q does not exist", cfg.ExportDir)
 		}
 
-		defer s.Display()
+		defer displayReport(cfg, s)
+
+		var err error
+		ctx, err = contextWithJournal(ctx, cfg, journal.ImportFile)
+		if err != nil {
+			return err
+		}
+
+		ctx = contextWithLimiter(ctx, cfg)
 
 		p := mpb.New(mpb.WithWidth(64))
 		ctx = ContextWithProgress(ctx, p)
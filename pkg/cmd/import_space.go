@@ -24,6 +24,7 @@ import (
 	. "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/io"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/journal"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/report"
 )
 
@@ -59,11 +60,22 @@ func importSpace(ctx context.Context, cfg *Config, f ImporterFactory, i migrate.
 			return err
 		}
 
+		if err := extractBundle(cfg); err != nil {
+			return err
+		}
+
 		if exists, _ := fso.Exists(cfg.ExportDir); !exists {
 			return fmt.Errorf("import directory %q does not exist", cfg.ExportDir)
 		}
 
-		defer summary.Display()
+		defer displayReport(cfg, summary)
+
+		ctx, err = contextWithJournal(ctx, cfg, journal.ImportFile)
+		if err != nil {
+			return err
+		}
+
+		ctx = contextWithLimiter(ctx, cfg)
 
 		p := mpb.New(mpb.WithWidth(64))
 		ctx = ContextWithProgress(ctx, p)
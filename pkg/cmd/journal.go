@@ -0,0 +1,38 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/journal"
+)
+
+// contextWithJournal opens the journal kept in the export directory, so an interrupted
+// migration can be picked up again with --resume. Dry runs don't keep a journal.
+func contextWithJournal(ctx context.Context, cfg *config.Config, name string) (context.Context, error) {
+	if cfg.DryRun {
+		return ctx, nil
+	}
+
+	j, err := journal.Open(filepath.Join(cfg.ExportDir, name), cfg.Resume)
+	if err != nil {
+		return ctx, err
+	}
+
+	return config.ContextWithJournal(ctx, j), nil
+}
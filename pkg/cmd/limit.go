@@ -0,0 +1,49 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/limit"
+)
+
+// contextWithLimiter bounds how many service instances are migrated at once for the rest of the run, using
+// --max-parallel and the max_parallel of each migrator in the config
+func contextWithLimiter(ctx context.Context, cfg *config.Config) context.Context {
+	migrators := make(map[string]int)
+	for _, m := range cfg.Migration.Migrators {
+		migrators[m.Name] = m.MaxParallel
+	}
+	return config.ContextWithLimiter(ctx, limit.New(cfg.MaxParallel, migrators))
+}
+
+// validateMaxParallel fails the command if a concurrency limit is negative
+func validateMaxParallel(cfg *config.Config) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if cfg.MaxParallel < 0 {
+			return fmt.Errorf("--max-parallel must not be negative, got %d", cfg.MaxParallel)
+		}
+		for _, m := range cfg.Migration.Migrators {
+			if m.MaxParallel < 0 {
+				return fmt.Errorf("max_parallel of migrator %q must not be negative, got %d", m.Name, m.MaxParallel)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,65 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/io"
+)
+
+func CreatePreflightCommand(ctx context.Context, cfg *config.Config, c PreflightChecker, fso io.FileSystemOperations) *cobra.Command {
+	preflight := &cobra.Command{
+		Use:   "preflight",
+		Short: "Check that exported service instances can be imported into the target foundation.",
+		Long: `Check that exported service instances can be imported into the target foundation.
+
+For every service instance in the import directory, preflight verifies that the target foundation has the
+service offering and plan, the org and space, the bound apps and the domains of their routes (after applying
+domains-to-replace), and that a migrator is configured for the service. Nothing is changed on either foundation.
+The command exits with a nonzero status when any check fails.`,
+		Example: `service-instance-migrator preflight
+service-instance-migrator preflight --import-dir=/tmp
+service-instance-migrator preflight --import-dir=/tmp --domains-to-replace='apps.cf1.example.com=apps.cf2.example.com'
+service-instance-migrator preflight --instances='mysql-db,redis-cache'`,
+		RunE: preflightAll(ctx, cfg, c, fso),
+	}
+	return preflight
+}
+
+func preflightAll(ctx context.Context, cfg *config.Config, c PreflightChecker, fso io.FileSystemOperations) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if exists, _ := fso.Exists(cfg.ExportDir); !exists {
+			return fmt.Errorf("import directory %q does not exist", cfg.ExportDir)
+		}
+
+		report, err := c.Check(ctx, cfg.ExportDir)
+		if err != nil {
+			return fmt.Errorf("failed to run preflight checks: %w", err)
+		}
+
+		report.Display(cmd.OutOrStdout())
+
+		if report.Failed() {
+			return errors.New("preflight checks failed, the import would not succeed")
+		}
+
+		return nil
+	}
+}
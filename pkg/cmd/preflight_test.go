@@ -0,0 +1,165 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cmd"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cmd/fakes"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	iofakes "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/io/fakes"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate"
+)
+
+func TestPreflight(t *testing.T) {
+	passed := migrate.PreflightReport{{
+		Org:     "org1",
+		Space:   "space1",
+		Name:    "db",
+		Service: "p.mysql",
+		Checks:  []migrate.PreflightCheck{{Name: migrate.ServicePlanCheck, Passed: true}},
+	}}
+	failed := migrate.PreflightReport{{
+		Org:     "org1",
+		Space:   "space1",
+		Name:    "db",
+		Service: "p.mysql",
+		Checks:  []migrate.PreflightCheck{{Name: migrate.ServicePlanCheck, Message: `plan "db-large" not found`}},
+	}}
+	dirExists := func() *iofakes.FakeFileSystemOperations {
+		return &iofakes.FakeFileSystemOperations{
+			ExistsStub: func(s string) (bool, error) {
+				return true, nil
+			},
+		}
+	}
+
+	type args struct {
+		config       *config.Config
+		commandArgs  []string
+		checker      *fakes.FakePreflightChecker
+		fsOperations *iofakes.FakeFileSystemOperations
+	}
+	tests := []struct {
+		name      string
+		args      args
+		setup     func(args args)
+		wantErr   bool
+		afterFunc func(args args, out string)
+	}{
+		{
+			name: "checks the import dir",
+			args: args{
+				config:       &config.Config{ExportDir: "/path/to/export-dir"},
+				commandArgs:  []string{},
+				checker:      new(fakes.FakePreflightChecker),
+				fsOperations: dirExists(),
+			},
+			setup: func(args args) {
+				args.checker.CheckReturns(passed, nil)
+			},
+			afterFunc: func(args args, out string) {
+				require.Equal(t, 1, args.checker.CheckCallCount())
+				_, dir := args.checker.CheckArgsForCall(0)
+				require.Equal(t, "/path/to/export-dir", dir)
+				require.Contains(t, out, "ok")
+			},
+		},
+		{
+			name: "import-dir flag overrides export_dir from config",
+			args: args{
+				config:       &config.Config{ExportDir: "/path/to/export-dir"},
+				commandArgs:  []string{"--import-dir", "/overridden/path"},
+				checker:      new(fakes.FakePreflightChecker),
+				fsOperations: dirExists(),
+			},
+			setup: func(args args) {
+				args.checker.CheckReturns(passed, nil)
+			},
+			afterFunc: func(args args, out string) {
+				_, dir := args.checker.CheckArgsForCall(0)
+				require.Equal(t, "/overridden/path", dir)
+			},
+		},
+		{
+			name: "fails when a check fails",
+			args: args{
+				config:       &config.Config{ExportDir: "/path/to/export-dir"},
+				commandArgs:  []string{},
+				checker:      new(fakes.FakePreflightChecker),
+				fsOperations: dirExists(),
+			},
+			setup: func(args args) {
+				args.checker.CheckReturns(failed, nil)
+			},
+			wantErr: true,
+			afterFunc: func(args args, out string) {
+				require.Contains(t, out, `failed: plan "db-large" not found`)
+			},
+		},
+		{
+			name: "fails when the target cannot be checked",
+			args: args{
+				config:       &config.Config{ExportDir: "/path/to/export-dir"},
+				commandArgs:  []string{},
+				checker:      new(fakes.FakePreflightChecker),
+				fsOperations: dirExists(),
+			},
+			setup: func(args args) {
+				args.checker.CheckReturns(nil, errors.New("unauthorized"))
+			},
+			wantErr: true,
+			afterFunc: func(args args, out string) {
+				require.Equal(t, 1, args.checker.CheckCallCount())
+			},
+		},
+		{
+			name: "fails when the import dir does not exist",
+			args: args{
+				config:       &config.Config{ExportDir: "/path/to/export-dir"},
+				commandArgs:  []string{},
+				checker:      new(fakes.FakePreflightChecker),
+				fsOperations: new(iofakes.FakeFileSystemOperations),
+			},
+			setup:   func(args args) {},
+			wantErr: true,
+			afterFunc: func(args args, out string) {
+				require.Equal(t, 0, args.checker.CheckCallCount())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(tt.args)
+			var out bytes.Buffer
+			preflightCmd := cmd.CreatePreflightCommand(context.TODO(), tt.args.config, tt.args.checker, tt.args.fsOperations)
+			preflightCmd.Flags().StringVar(&tt.args.config.ExportDir, "import-dir", tt.args.config.ExportDir, "Directory where service instances will be placed or read")
+			preflightCmd.SetArgs(tt.args.commandArgs)
+			preflightCmd.SetOut(&out)
+			preflightCmd.SetErr(&bytes.Buffer{})
+
+			err := preflightCmd.Execute()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("preflight() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			tt.afterFunc(tt.args, out.String())
+		})
+	}
+}
@@ -0,0 +1,68 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/report"
+)
+
+// validateReportFormat fails the command before any service instance is migrated if the report format is unknown
+func validateReportFormat(cfg *config.Config) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		_, err := report.ParseFormat(cfg.ReportFormat)
+		return err
+	}
+}
+
+// displayReport prints the migration summary. The table is always shown on stdout unless another format is
+// requested without a report file, in which case that format is printed instead.
+func displayReport(cfg *config.Config, s *report.Summary) {
+	format, err := report.ParseFormat(cfg.ReportFormat)
+	if err != nil {
+		log.Errorln(err)
+		format = report.TableFormat
+	}
+
+	if cfg.ReportFile == "" {
+		if format == report.TableFormat {
+			s.Display()
+			return
+		}
+		if err = s.Write(s.TableWriter, format); err != nil {
+			log.Errorf("Failed to write %s report: %v", format, err)
+		}
+		return
+	}
+
+	s.Display()
+
+	f, err := os.Create(cfg.ReportFile)
+	if err != nil {
+		log.Errorf("Failed to create report file %q: %v", cfg.ReportFile, err)
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	if err = s.Write(f, format); err != nil {
+		log.Errorf("Failed to write %s report to %q: %v", format, cfg.ReportFile, err)
+		return
+	}
+	log.Infof("Migration report written to %s", cfg.ReportFile)
+}
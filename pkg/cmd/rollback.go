@@ -0,0 +1,66 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/io"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/report"
+)
+
+func CreateRollbackCommand(ctx context.Context, cfg *config.Config, r Rollbacker, fso io.FileSystemOperations, s *report.Summary) *cobra.Command {
+	rollback := &cobra.Command{
+		Use:   "rollback",
+		Short: "Roll back the cloud controller database changes made by export and import.",
+		Long: `Roll back the cloud controller database changes made by export and import.
+
+The ecs and sqlserver migrations delete service instances from the source cloud controller database on export
+and insert them into the target cloud controller database on import. Both record how to undo those changes in
+the export directory, and rollback replays them: imported service instances are removed from the target
+foundation and exported service instances are restored on the source foundation.`,
+		Example: `service-instance-migrator rollback
+service-instance-migrator rollback --export-dir=/tmp`,
+		RunE: rollbackAll(ctx, cfg, r, fso, s),
+	}
+	return rollback
+}
+
+func rollbackAll(ctx context.Context, cfg *config.Config, r Rollbacker, fso io.FileSystemOperations, s *report.Summary) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		nonInteractive, err := cmd.Flags().GetBool("non-interactive")
+		if err != nil {
+			return err
+		}
+
+		if exists, _ := fso.Exists(cfg.ExportDir); !exists {
+			return fmt.Errorf("export directory %q does not exist", cfg.ExportDir)
+		}
+
+		if !nonInteractive && !cfg.DryRun {
+			if c, err := ConfirmYesOrNo("Rollback will modify the cloud controller databases. Do you wish to continue?", os.Stdin); !c {
+				return err
+			}
+		}
+
+		defer s.Display()
+
+		return r.Rollback(config.ContextWithSummary(ctx, s), cfg.ExportDir)
+	}
+}
@@ -0,0 +1,113 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cmd"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cmd/fakes"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	iofakes "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/io/fakes"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/report"
+)
+
+func TestRollback(t *testing.T) {
+	type args struct {
+		config        *config.Config
+		commandArgs   []string
+		rollbacker    *fakes.FakeRollbacker
+		fsOperations  *iofakes.FakeFileSystemOperations
+		reportSummary *report.Summary
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantErr   bool
+		afterFunc func(args args)
+	}{
+		{
+			name: "rolls back the export dir",
+			args: args{
+				config:      &config.Config{ExportDir: "/path/to/export-dir"},
+				commandArgs: []string{"--non-interactive"},
+				rollbacker:  new(fakes.FakeRollbacker),
+				fsOperations: &iofakes.FakeFileSystemOperations{
+					ExistsStub: func(s string) (bool, error) {
+						return true, nil
+					},
+				},
+				reportSummary: report.NewSummary(&bytes.Buffer{}),
+			},
+			afterFunc: func(args args) {
+				require.Equal(t, 1, args.rollbacker.RollbackCallCount())
+				ctx, dir := args.rollbacker.RollbackArgsForCall(0)
+				require.Equal(t, "/path/to/export-dir", dir)
+				_, ok := config.SummaryFromContext(ctx)
+				require.True(t, ok)
+			},
+		},
+		{
+			name: "export-dir flag overrides export_dir from config",
+			args: args{
+				config:      &config.Config{ExportDir: "/path/to/export-dir"},
+				commandArgs: []string{"--non-interactive", "--export-dir", "/overridden/path"},
+				rollbacker:  new(fakes.FakeRollbacker),
+				fsOperations: &iofakes.FakeFileSystemOperations{
+					ExistsStub: func(s string) (bool, error) {
+						return true, nil
+					},
+				},
+				reportSummary: report.NewSummary(&bytes.Buffer{}),
+			},
+			afterFunc: func(args args) {
+				require.Equal(t, 1, args.rollbacker.RollbackCallCount())
+				_, dir := args.rollbacker.RollbackArgsForCall(0)
+				require.Equal(t, "/overridden/path", dir)
+			},
+		},
+		{
+			name: "fails when the export dir does not exist",
+			args: args{
+				config:        &config.Config{ExportDir: "/path/to/export-dir"},
+				commandArgs:   []string{"--non-interactive"},
+				rollbacker:    new(fakes.FakeRollbacker),
+				fsOperations:  new(iofakes.FakeFileSystemOperations),
+				reportSummary: report.NewSummary(&bytes.Buffer{}),
+			},
+			wantErr: true,
+			afterFunc: func(args args) {
+				require.Equal(t, 0, args.rollbacker.RollbackCallCount())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rollbackCmd := cmd.CreateRollbackCommand(context.TODO(), tt.args.config, tt.args.rollbacker, tt.args.fsOperations, tt.args.reportSummary)
+			rollbackCmd.Flags().BoolP("non-interactive", "n", false, "Don't ask for user input")
+			rollbackCmd.Flags().StringVar(&tt.args.config.ExportDir, "export-dir", tt.args.config.ExportDir, "Directory where service instances were exported to and imported from")
+			rollbackCmd.SetArgs(tt.args.commandArgs)
+
+			err := rollbackCmd.Execute()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("rollback() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			tt.afterFunc(tt.args)
+		})
+	}
+}
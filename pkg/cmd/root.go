@@ -25,6 +25,8 @@ import (
 	boshcli "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/bosh/cli"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cli"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/credhub"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/encryption"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/exec"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/io"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
@@ -69,6 +71,10 @@ func CreateRootCommand(
 
 	addExportCommands(config.ContextWithConfig(context.Background(), cfg), rootCmd, cfg, mr, sourceConfigLoader)
 	addImportCommands(config.ContextWithConfig(context.Background(), cfg), rootCmd, cfg, mr, targetConfigLoader)
+	addRollbackCommand(config.ContextWithConfig(context.Background(), cfg), rootCmd, cfg, sourceConfigLoader, targetConfigLoader)
+	addPreflightCommand(config.ContextWithConfig(context.Background(), cfg), rootCmd, cfg, mr, targetConfigLoader)
+	addVerifyCommand(config.ContextWithConfig(context.Background(), cfg), rootCmd, cfg, mr, targetConfigLoader)
+	rootCmd.AddCommand(CreateGenerateEncryptionKeyCommand())
 
 	return rootCmd
 }
@@ -78,7 +84,7 @@ func addExportCommands(ctx context.Context, rootCmd *cobra.Command, cfg *config.
 	uaaFactory := uaa.NewFactory()
 	omFactory := om.NewFactory()
 	dirFactory := boshcli.NewFactory()
-	clientFactory := migrate.NewClientFactory(configLoader, bosh.NewClientFactory(dirFactory, uaaFactory), om.NewClientFactory(omFactory, uaaFactory), cfg.Foundations.Source)
+	clientFactory := migrate.NewClientFactory(configLoader, bosh.NewClientFactory(dirFactory, uaaFactory), credhub.NewClientFactory(), om.NewClientFactory(omFactory, uaaFactory), cfg.Foundations.Source)
 	me := cc.NewManifestExporter(cfg, clientFactory)
 	factory := NewExportMigratorFactory(cfg, clientFactory)
 	sf := cc.NewCloudControllerServiceFactory(clientFactory, me)
@@ -88,13 +94,23 @@ func addExportCommands(ctx context.Context, rootCmd *cobra.Command, cfg *config.
 		exec.WithDebug(cfg.Debug),
 	)
 	registry := migrate.NewMigratorRegistry(migrate.NewMigratorFactory(configLoader, clientFactory, mh, e, sf), mh, cfg, configLoader, clientFactory)
-	sie := migrate.NewServiceInstanceExporter(cfg, clientFactory, registry, io.NewParser())
+	sie := migrate.NewServiceInstanceExporter(cfg, clientFactory, registry, io.NewParser(io.WithCipher(encryption.NewCipher(cfg))))
 	fs := io.NewFileSystemHelper()
 
 	exportCmd := CreateExportCommand(ctx, cfg, factory, sie, fs, reportSummary)
 	exportCmd.Flags().StringSliceVar(&cfg.IncludedOrgs, "include-orgs", cfg.IncludedOrgs, "Only orgs matching the regex(es) specified will be included")
 	exportCmd.Flags().StringSliceVar(&cfg.ExcludedOrgs, "exclude-orgs", cfg.ExcludedOrgs, "Any orgs matching the regex(es) specified will be excluded")
 	exportCmd.PersistentFlags().StringVar(&cfg.ExportDir, "export-dir", cfg.ExportDir, "Directory where service instances will be placed or read")
+	exportCmd.PersistentFlags().BoolVar(&cfg.Resume, "resume", cfg.Resume, "Resume a previous export, skipping service instances that were already exported")
+	exportCmd.PersistentFlags().StringVar(&cfg.ExportFormat, "export-format", cfg.ExportFormat, "Format of the exported service instance files: yaml or json [default: yaml]")
+	exportCmd.PersistentFlags().StringVar(&cfg.Bundle, "bundle", cfg.Bundle, "Also write the export to a single gzipped tar file with a manifest of the service instances and file checksums, the export directory must be empty")
+	exportCmd.PersistentFlags().BoolVar(&cfg.Encrypt, "encrypt", cfg.Encrypt, "Encrypt exported service instances and backups with the recipient public key or the "+encryption.PassphraseEnvVar+" passphrase")
+	exportCmd.PersistentFlags().StringVar(&cfg.EncryptionRecipient, "encryption-recipient", cfg.EncryptionRecipient, "Public key to encrypt the export to, see generate-encryption-key")
+	addReportFlags(exportCmd, cfg)
+	exportCmd.PersistentFlags().IntVar(&cfg.MaxParallel, "max-parallel", cfg.MaxParallel, "Maximum number of service instances to export at the same time, 0 for no limit")
+	exportCmd.PersistentFlags().BoolVar(&cfg.ContinueOnError, "continue-on-error", cfg.ContinueOnError, "Keep exporting the other service instances when one fails, exit nonzero at the end")
+	exportCmd.PersistentFlags().BoolVar(&cfg.FailFast, "fail-fast", cfg.FailFast, "Stop exporting at the first service instance that fails [default]")
+	exportCmd.PersistentPreRunE = validateAll(validateReportFormat(cfg), validateExportFormat(cfg), validateEncryption(cfg), validateMaxParallel(cfg), resolveFailureMode(cfg, false))
 
 	exportOrgCmd := CreateExportOrgCommand(ctx, cfg, factory, sie, fs, reportSummary)
 	exportCmd.AddCommand(exportOrgCmd)
@@ -115,7 +131,7 @@ func addImportCommands(ctx context.Context, rootCmd *cobra.Command, cfg *config.
 	uaaFactory := uaa.NewFactory()
 	omFactory := om.NewFactory()
 	dirFactory := boshcli.NewFactory()
-	clientFactory := migrate.NewClientFactory(configLoader, bosh.NewClientFactory(dirFactory, uaaFactory), om.NewClientFactory(omFactory, uaaFactory), cfg.Foundations.Target)
+	clientFactory := migrate.NewClientFactory(configLoader, bosh.NewClientFactory(dirFactory, uaaFactory), credhub.NewClientFactory(), om.NewClientFactory(omFactory, uaaFactory), cfg.Foundations.Target)
 	factory := NewImportMigratorFactory(cfg, clientFactory)
 	sf := cc.NewCloudControllerServiceFactory(clientFactory, nil)
 	mh := migrate.NewMigratorHelper(mr)
@@ -125,7 +141,7 @@ func addImportCommands(ctx context.Context, rootCmd *cobra.Command, cfg *config.
 		exec.WithTimeout(20*time.Minute),
 	)
 	registry := migrate.NewMigratorRegistry(migrate.NewMigratorFactory(configLoader, clientFactory, mh, e, sf), mh, cfg, configLoader, clientFactory)
-	sii := migrate.NewServiceInstanceImporter(registry)
+	sii := migrate.NewServiceInstanceImporter(registry, clientFactory)
 	fs := io.NewFileSystemHelper()
 
 	importCmd := CreateImportCommand(ctx, cfg, factory, sii, fs, reportSummary)
@@ -134,6 +150,15 @@ func addImportCommands(ctx context.Context, rootCmd *cobra.Command, cfg *config.
 	importCmd.PersistentFlags().BoolVar(&cfg.IgnoreServiceKeys, "ignore-service-keys", cfg.IgnoreServiceKeys, "Don't create any service keys on import")
 	importCmd.PersistentFlags().StringVar(&cfg.ExportDir, "import-dir", cfg.ExportDir, "Directory where service instances will be placed or read")
 	importCmd.PersistentFlags().StringToStringVar(&cfg.DomainsToReplace, "domains-to-replace", cfg.DomainsToReplace, "Domains to replace in any found application routes")
+	importCmd.PersistentFlags().BoolVar(&cfg.Resume, "resume", cfg.Resume, "Resume a previous import, skipping service instances that were already imported")
+	importCmd.PersistentFlags().StringVar(&cfg.Bundle, "bundle", cfg.Bundle, "Extract a bundle written by export --bundle into the empty import directory and verify its checksums before importing")
+	importCmd.PersistentFlags().BoolVar(&cfg.CreateMissingOrgsSpaces, "create-missing-orgs-spaces", cfg.CreateMissingOrgsSpaces, "Create the orgs and spaces in the import directory that don't exist on the target")
+	importCmd.PersistentFlags().StringVar(&cfg.EncryptionIdentity, "encryption-identity", cfg.EncryptionIdentity, "Private key file to decrypt an encrypted export with")
+	addReportFlags(importCmd, cfg)
+	importCmd.PersistentFlags().IntVar(&cfg.MaxParallel, "max-parallel", cfg.MaxParallel, "Maximum number of service instances to import at the same time, 0 for no limit")
+	importCmd.PersistentFlags().BoolVar(&cfg.ContinueOnError, "continue-on-error", cfg.ContinueOnError, "Keep importing the other service instances when one fails, exit nonzero at the end [default]")
+	importCmd.PersistentFlags().BoolVar(&cfg.FailFast, "fail-fast", cfg.FailFast, "Stop importing at the first service instance that fails")
+	importCmd.PersistentPreRunE = validateAll(validateReportFormat(cfg), validateEncryption(cfg), validateMaxParallel(cfg), validateMappings(cfg), resolveFailureMode(cfg, true))
 
 	importOrgCmd := CreateImportOrgCommand(ctx, cfg, factory, sii, fs, reportSummary)
 	importCmd.AddCommand(importOrgCmd)
@@ -148,3 +173,61 @@ func addImportCommands(ctx context.Context, rootCmd *cobra.Command, cfg *config.
 
 	rootCmd.AddCommand(importCmd)
 }
+
+func addReportFlags(cmd *cobra.Command, cfg *config.Config) {
+	cmd.PersistentFlags().StringVar(&cfg.ReportFormat, "report-format", cfg.ReportFormat, "Format of the migration report: table, json or junit [default: table]")
+	cmd.PersistentFlags().StringVar(&cfg.ReportFile, "report-file", cfg.ReportFile, "File to write the migration report to, the table is still printed")
+}
+
+func addRollbackCommand(ctx context.Context, rootCmd *cobra.Command, cfg *config.Config, sourceConfigLoader config.Loader, targetConfigLoader config.Loader) {
+	reportSummary := report.NewSummary(os.Stdout)
+	uaaFactory := uaa.NewFactory()
+	omFactory := om.NewFactory()
+	dirFactory := boshcli.NewFactory()
+	sourceClientFactory := migrate.NewClientFactory(sourceConfigLoader, bosh.NewClientFactory(dirFactory, uaaFactory), credhub.NewClientFactory(), om.NewClientFactory(omFactory, uaaFactory), cfg.Foundations.Source)
+	targetClientFactory := migrate.NewClientFactory(targetConfigLoader, bosh.NewClientFactory(dirFactory, uaaFactory), credhub.NewClientFactory(), om.NewClientFactory(omFactory, uaaFactory), cfg.Foundations.Target)
+	r := migrate.NewCloudControllerRollback(
+		sourceConfigLoader,
+		targetConfigLoader,
+		cc.NewCloudControllerServiceFactory(sourceClientFactory, nil),
+		cc.NewCloudControllerServiceFactory(targetClientFactory, nil),
+	)
+
+	rollbackCmd := CreateRollbackCommand(ctx, cfg, r, io.NewFileSystemHelper(), reportSummary)
+	rollbackCmd.Flags().StringVar(&cfg.ExportDir, "export-dir", cfg.ExportDir, "Directory where service instances were exported to and imported from")
+
+	rootCmd.AddCommand(rollbackCmd)
+}
+
+func addPreflightCommand(ctx context.Context, rootCmd *cobra.Command, cfg *config.Config, mr config.MigrationReader, configLoader config.Loader) {
+	uaaFactory := uaa.NewFactory()
+	omFactory := om.NewFactory()
+	dirFactory := boshcli.NewFactory()
+	clientFactory := migrate.NewClientFactory(configLoader, bosh.NewClientFactory(dirFactory, uaaFactory), credhub.NewClientFactory(), om.NewClientFactory(omFactory, uaaFactory), cfg.Foundations.Target)
+	p := migrate.NewPreflight(clientFactory, migrate.NewMigratorHelper(mr))
+
+	preflightCmd := CreatePreflightCommand(ctx, cfg, p, io.NewFileSystemHelper())
+	preflightCmd.Flags().StringVar(&cfg.ExportDir, "import-dir", cfg.ExportDir, "Directory where service instances will be placed or read")
+	preflightCmd.Flags().StringToStringVar(&cfg.DomainsToReplace, "domains-to-replace", cfg.DomainsToReplace, "Domains to replace in any found application routes")
+	preflightCmd.Flags().StringVar(&cfg.EncryptionIdentity, "encryption-identity", cfg.EncryptionIdentity, "Private key file to decrypt an encrypted export with")
+	preflightCmd.PreRunE = validateAll(validateEncryption(cfg), validateMappings(cfg))
+
+	rootCmd.AddCommand(preflightCmd)
+}
+
+func addVerifyCommand(ctx context.Context, rootCmd *cobra.Command, cfg *config.Config, mr config.MigrationReader, configLoader config.Loader) {
+	uaaFactory := uaa.NewFactory()
+	omFactory := om.NewFactory()
+	dirFactory := boshcli.NewFactory()
+	clientFactory := migrate.NewClientFactory(configLoader, bosh.NewClientFactory(dirFactory, uaaFactory), credhub.NewClientFactory(), om.NewClientFactory(omFactory, uaaFactory), cfg.Foundations.Target)
+	v := migrate.NewVerifier(clientFactory, migrate.NewMigratorHelper(mr))
+
+	verifyCmd := CreateVerifyCommand(ctx, cfg, v, io.NewFileSystemHelper())
+	verifyCmd.Flags().StringVar(&cfg.ExportDir, "import-dir", cfg.ExportDir, "Directory where service instances will be placed or read")
+	verifyCmd.Flags().StringToStringVar(&cfg.DomainsToReplace, "domains-to-replace", cfg.DomainsToReplace, "Domains to replace in any found application routes")
+	verifyCmd.Flags().BoolVar(&cfg.IgnoreServiceKeys, "ignore-service-keys", cfg.IgnoreServiceKeys, "Don't verify service keys")
+	verifyCmd.Flags().StringVar(&cfg.EncryptionIdentity, "encryption-identity", cfg.EncryptionIdentity, "Private key file to decrypt an encrypted export with")
+	verifyCmd.PreRunE = validateAll(validateEncryption(cfg), validateMappings(cfg))
+
+	rootCmd.AddCommand(verifyCmd)
+}
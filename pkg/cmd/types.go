@@ -19,6 +19,7 @@ import (
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/bosh"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/credhub"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/om"
 )
@@ -65,6 +66,24 @@ type ImporterFactory interface {
 	NewSpaceImporter(importer migrate.ServiceInstanceImporter) SpaceImporter
 }
 
+//counterfeiter:generate -o fakes . Rollbacker
+
+type Rollbacker interface {
+	Rollback(ctx context.Context, dir string) error
+}
+
+//counterfeiter:generate -o fakes . PreflightChecker
+
+type PreflightChecker interface {
+	Check(ctx context.Context, dir string) (migrate.PreflightReport, error)
+}
+
+//counterfeiter:generate -o fakes . Verifier
+
+type Verifier interface {
+	Verify(ctx context.Context, dir string) (migrate.VerifyReport, error)
+}
+
 type NoopPropertiesProvider struct{}
 type NoopClientFactory struct{}
 type NoopBoshPropertiesBuilder struct{}
@@ -94,6 +113,7 @@ func (s NoopPropertiesProvider) TargetCCDBPropertiesBuilder(b config.BoshPropert
 }
 
 func (f NoopClientFactory) CFClient(bool) cf.Client       { return &cf.ClientImpl{} }
+func (f NoopClientFactory) BoshClient(bool) bosh.Client   { return &bosh.ClientImpl{} }
 func (f NoopClientFactory) SourceCFClient() cf.Client     { return &cf.ClientImpl{} }
 func (f NoopClientFactory) SourceOpsManClient() om.Client { return &om.ClientImpl{} }
 func (f NoopClientFactory) SourceBoshClient() bosh.Client { return &bosh.ClientImpl{} }
@@ -101,6 +121,11 @@ func (f NoopClientFactory) TargetCFClient() cf.Client     { return &cf.ClientImp
 func (f NoopClientFactory) TargetOpsManClient() om.Client { return &om.ClientImpl{} }
 func (f NoopClientFactory) TargetBoshClient() bosh.Client { return &bosh.ClientImpl{} }
 
+func (f NoopClientFactory) CredhubClient(bool) credhub.Client { return credhub.ClientImpl{} }
+func (f NoopClientFactory) RuntimeCredhubClient(bool) (credhub.Client, error) {
+	return credhub.ClientImpl{}, nil
+}
+
 func (b NoopBoshPropertiesBuilder) Build() *config.BoshProperties { return &config.BoshProperties{} }
 func (b NoopCFPropertiesBuilder) Build() *config.CFProperties     { return &config.CFProperties{} }
 func (b NoopCCDBPropertiesBuilder) Build() *config.CCDBProperties { return &config.CCDBProperties{} }
@@ -0,0 +1,65 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/io"
+)
+
+func CreateVerifyCommand(ctx context.Context, cfg *config.Config, v Verifier, fso io.FileSystemOperations) *cobra.Command {
+	verify := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify that exported service instances were imported into the target foundation.",
+		Long: `Verify that exported service instances were imported into the target foundation.
+
+For every service instance in the import directory, verify compares the export with the instance on the target
+foundation: that it exists, its service and plan (after applying mappings), tags, parameters, the apps it is bound
+to, its service keys, and for user-provided services the route service and syslog drain urls (after applying
+domains-to-replace). Nothing is changed on either foundation. The command prints one result per service instance
+and exits with a nonzero status when any service instance differs from the export.`,
+		Example: `service-instance-migrator verify
+service-instance-migrator verify --import-dir=/tmp
+service-instance-migrator verify --import-dir=/tmp --domains-to-replace='apps.cf1.example.com=apps.cf2.example.com'
+service-instance-migrator verify --instances='mysql-db,redis-cache'`,
+		RunE: verifyAll(ctx, cfg, v, fso),
+	}
+	return verify
+}
+
+func verifyAll(ctx context.Context, cfg *config.Config, v Verifier, fso io.FileSystemOperations) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if exists, _ := fso.Exists(cfg.ExportDir); !exists {
+			return fmt.Errorf("import directory %q does not exist", cfg.ExportDir)
+		}
+
+		report, err := v.Verify(ctx, cfg.ExportDir)
+		if err != nil {
+			return fmt.Errorf("failed to verify the import: %w", err)
+		}
+
+		report.Display(cmd.OutOrStdout())
+
+		if report.Failed() {
+			return errors.New("verification failed, some service instances were not fully migrated")
+		}
+
+		return nil
+	}
+}
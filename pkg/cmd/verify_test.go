@@ -0,0 +1,164 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cmd"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cmd/fakes"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	iofakes "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/io/fakes"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate"
+)
+
+func TestVerify(t *testing.T) {
+	passed := migrate.VerifyReport{{
+		Org:     "org1",
+		Space:   "space1",
+		Name:    "db",
+		Service: "p.mysql",
+		Checks:  []migrate.VerifyCheck{{Name: migrate.InstanceCheck, Passed: true}},
+	}}
+	failed := migrate.VerifyReport{{
+		Org:     "org1",
+		Space:   "space1",
+		Name:    "db",
+		Service: "p.mysql",
+		Checks:  []migrate.VerifyCheck{{Name: migrate.ServiceKeysCheck, Message: "service keys db-key not found"}},
+	}}
+	dirExists := func() *iofakes.FakeFileSystemOperations {
+		return &iofakes.FakeFileSystemOperations{
+			ExistsStub: func(s string) (bool, error) {
+				return true, nil
+			},
+		}
+	}
+
+	type args struct {
+		config       *config.Config
+		commandArgs  []string
+		verifier     *fakes.FakeVerifier
+		fsOperations *iofakes.FakeFileSystemOperations
+	}
+	tests := []struct {
+		name      string
+		args      args
+		setup     func(args args)
+		wantErr   bool
+		afterFunc func(args args, out string)
+	}{
+		{
+			name: "verifies the import dir",
+			args: args{
+				config:       &config.Config{ExportDir: "/path/to/export-dir"},
+				commandArgs:  []string{},
+				verifier:     new(fakes.FakeVerifier),
+				fsOperations: dirExists(),
+			},
+			setup: func(args args) {
+				args.verifier.VerifyReturns(passed, nil)
+			},
+			afterFunc: func(args args, out string) {
+				require.Equal(t, 1, args.verifier.VerifyCallCount())
+				_, dir := args.verifier.VerifyArgsForCall(0)
+				require.Equal(t, "/path/to/export-dir", dir)
+				require.Contains(t, out, "passed")
+			},
+		},
+		{
+			name: "import-dir flag overrides export_dir from config",
+			args: args{
+				config:       &config.Config{ExportDir: "/path/to/export-dir"},
+				commandArgs:  []string{"--import-dir", "/overridden/path"},
+				verifier:     new(fakes.FakeVerifier),
+				fsOperations: dirExists(),
+			},
+			setup: func(args args) {
+				args.verifier.VerifyReturns(passed, nil)
+			},
+			afterFunc: func(args args, out string) {
+				_, dir := args.verifier.VerifyArgsForCall(0)
+				require.Equal(t, "/overridden/path", dir)
+			},
+		},
+		{
+			name: "fails when an instance differs from the export",
+			args: args{
+				config:       &config.Config{ExportDir: "/path/to/export-dir"},
+				commandArgs:  []string{},
+				verifier:     new(fakes.FakeVerifier),
+				fsOperations: dirExists(),
+			},
+			setup: func(args args) {
+				args.verifier.VerifyReturns(failed, nil)
+			},
+			wantErr: true,
+			afterFunc: func(args args, out string) {
+				require.Contains(t, out, "failed: service keys: service keys db-key not found")
+			},
+		},
+		{
+			name: "fails when the target cannot be queried",
+			args: args{
+				config:       &config.Config{ExportDir: "/path/to/export-dir"},
+				commandArgs:  []string{},
+				verifier:     new(fakes.FakeVerifier),
+				fsOperations: dirExists(),
+			},
+			setup: func(args args) {
+				args.verifier.VerifyReturns(nil, errors.New("unauthorized"))
+			},
+			wantErr: true,
+			afterFunc: func(args args, out string) {
+				require.Equal(t, 1, args.verifier.VerifyCallCount())
+			},
+		},
+		{
+			name: "fails when the import dir does not exist",
+			args: args{
+				config:       &config.Config{ExportDir: "/path/to/export-dir"},
+				commandArgs:  []string{},
+				verifier:     new(fakes.FakeVerifier),
+				fsOperations: new(iofakes.FakeFileSystemOperations),
+			},
+			setup:   func(args args) {},
+			wantErr: true,
+			afterFunc: func(args args, out string) {
+				require.Equal(t, 0, args.verifier.VerifyCallCount())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(tt.args)
+			var out bytes.Buffer
+			verifyCmd := cmd.CreateVerifyCommand(context.TODO(), tt.args.config, tt.args.verifier, tt.args.fsOperations)
+			verifyCmd.Flags().StringVar(&tt.args.config.ExportDir, "import-dir", tt.args.config.ExportDir, "Directory where service instances will be placed or read")
+			verifyCmd.SetArgs(tt.args.commandArgs)
+			verifyCmd.SetOut(&out)
+			verifyCmd.SetErr(&bytes.Buffer{})
+
+			err := verifyCmd.Execute()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			tt.afterFunc(tt.args, out.String())
+		})
+	}
+}
@@ -31,29 +31,45 @@ import (
 	"github.com/spf13/viper"
 )
 
+// DefaultMaxParallel is how many service instances are migrated at the same time unless max_parallel is set
+const DefaultMaxParallel = 10
+
 type Config struct {
-	ConfigDir         string
-	ConfigFile        string
-	Debug             bool
-	DryRun            bool `mapstructure:"dry_run"`
-	DomainsToReplace  map[string]string
-	ExportDir         string   `mapstructure:"export_dir"`
-	ExcludedOrgs      []string `mapstructure:"exclude_orgs"`
-	IncludedOrgs      []string `mapstructure:"include_orgs"`
-	IgnoreServiceKeys bool     `mapstructure:"ignore_service_keys"`
-	Foundations       struct {
+	Bundle                  string `mapstructure:"bundle"`
+	ConfigDir               string
+	ConfigFile              string
+	ContinueOnError         bool `mapstructure:"continue_on_error"`
+	CreateMissingOrgsSpaces bool `mapstructure:"create_missing_orgs_spaces"`
+	Debug                   bool
+	DryRun                  bool `mapstructure:"dry_run"`
+	DomainsToReplace        map[string]string
+	Encrypt                 bool     `mapstructure:"encrypt"`
+	EncryptionIdentity      string   `mapstructure:"encryption_identity"`
+	EncryptionRecipient     string   `mapstructure:"encryption_recipient"`
+	ExportDir               string   `mapstructure:"export_dir"`
+	ExportFormat            string   `mapstructure:"export_format"`
+	ExcludedOrgs            []string `mapstructure:"exclude_orgs"`
+	FailFast                bool     `mapstructure:"fail_fast"`
+	IncludedOrgs            []string `mapstructure:"include_orgs"`
+	IgnoreServiceKeys       bool     `mapstructure:"ignore_service_keys"`
+	Foundations             struct {
 		Source OpsManager `yaml:"source"`
 		Target OpsManager `yaml:"target"`
 	} `yaml:"foundations"`
-	Migration   Migration
-	Name        string
-	Services    []string        `mapstructure:"services"`
-	Instances   []string        `mapstructure:"instances"`
-	SourceApi   CloudController `yaml:"source_api" mapstructure:"source_api"`
-	SourceBosh  Bosh            `yaml:"source_bosh" mapstructure:"source_bosh"`
-	TargetApi   CloudController `yaml:"target_api" mapstructure:"target_api"`
-	TargetBosh  Bosh            `yaml:"target_bosh" mapstructure:"target_bosh"`
-	initialized bool
+	Mappings     []Mapping `yaml:"mappings,omitempty" mapstructure:"mappings"`
+	MaxParallel  int       `mapstructure:"max_parallel"`
+	Migration    Migration
+	Name         string
+	ReportFile   string          `mapstructure:"report_file"`
+	ReportFormat string          `mapstructure:"report_format"`
+	Resume       bool            `mapstructure:"resume"`
+	Services     []string        `mapstructure:"services"`
+	Instances    []string        `mapstructure:"instances"`
+	SourceApi    CloudController `yaml:"source_api" mapstructure:"source_api"`
+	SourceBosh   Bosh            `yaml:"source_bosh" mapstructure:"source_bosh"`
+	TargetApi    CloudController `yaml:"target_api" mapstructure:"target_api"`
+	TargetBosh   Bosh            `yaml:"target_bosh" mapstructure:"target_bosh"`
+	initialized  bool
 }
 
 type CloudController struct {
@@ -70,8 +86,10 @@ type Migration struct {
 }
 
 type Migrator struct {
-	Name  string                 `yaml:"name" mapstructure:"name"`
-	Value map[string]interface{} `yaml:"migrator" mapstructure:"migrator"`
+	Name          string                 `yaml:"name" mapstructure:"name"`
+	MaxParallel   int                    `yaml:"max_parallel,omitempty" mapstructure:"max_parallel"`
+	ServiceLabels []string               `yaml:"service_labels,omitempty" mapstructure:"service_labels"`
+	Value         map[string]interface{} `yaml:"migrator" mapstructure:"migrator"`
 }
 
 type OpsManager struct {
@@ -178,10 +196,11 @@ func New(configDir string, configFile string) *Config {
 	}
 
 	c := &Config{
-		Name:       "si-migrator",
-		ConfigDir:  configDir,
-		ConfigFile: configFile,
-		ExportDir:  path.Join(cwd, "export"),
+		Name:        "si-migrator",
+		ConfigDir:   configDir,
+		ConfigFile:  configFile,
+		ExportDir:   path.Join(cwd, "export"),
+		MaxParallel: DefaultMaxParallel,
 	}
 
 	c.initViperConfig()
@@ -204,6 +223,7 @@ func Parse(configFilePath string) (Config, error) {
 	if err := cfg.Validate(); err != nil {
 		return Config{}, err
 	}
+	cfg.registerSecrets()
 
 	return cfg, nil
 }
@@ -330,6 +350,68 @@ func (c *Config) applyViperOverrides(v *viper.Viper) {
 	if c.ConfigFile == "" {
 		c.ConfigFile = v.ConfigFileUsed()
 	}
+	c.registerSecrets()
+}
+
+// registerSecrets masks the configured passwords and client secrets in logs and printed scripts
+func (c *Config) registerSecrets() {
+	log.RegisterSecrets(
+		c.SourceApi.Password, c.SourceApi.ClientSecret,
+		c.TargetApi.Password, c.TargetApi.ClientSecret,
+		c.Foundations.Source.Password, c.Foundations.Source.ClientSecret,
+		c.Foundations.Target.Password, c.Foundations.Target.ClientSecret,
+	)
+	for _, b := range []Bosh{c.SourceBosh, c.TargetBosh} {
+		log.RegisterSecrets(
+			b.Authentication.Basic.Password,
+			b.Authentication.UAA.ClientCredentials.Secret,
+			b.Authentication.UAA.UserCredentials.Password,
+		)
+	}
+	c.Migration.registerSecrets()
+}
+
+// migratorSecretKeys are the keys of the migrator settings holding credentials, e.g. the ccdb passwords or the
+// mysql backup storage keys
+var migratorSecretKeys = map[string]bool{
+	"access_key":        true,
+	"db_encryption_key": true,
+	"db_password":       true,
+	"private_key":       true,
+	"secret_access_key": true,
+	"secret_key":        true,
+	"ssh_password":      true,
+	"ssh_private_key":   true,
+}
+
+// registerSecrets masks the credentials found in the migrator settings in logs and printed scripts
+func (m Migration) registerSecrets() {
+	for _, migrator := range m.Migrators {
+		registerMigratorSecrets(migrator.Value)
+	}
+}
+
+func registerMigratorSecrets(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, val := range v {
+			registerMigratorSecret(key, val)
+		}
+	case map[interface{}]interface{}:
+		for key, val := range v {
+			registerMigratorSecret(fmt.Sprint(key), val)
+		}
+	}
+}
+
+func registerMigratorSecret(key string, value interface{}) {
+	if s, ok := value.(string); ok {
+		if migratorSecretKeys[key] {
+			log.RegisterSecrets(s)
+		}
+		return
+	}
+	registerMigratorSecrets(value)
 }
 
 func hasSuffix(configDir string) (string, bool) {
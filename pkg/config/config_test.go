@@ -43,6 +43,7 @@ func TestNewDefaultConfig(t *testing.T) {
 					"apps.cf1.example.com": "apps.cf2.example.com",
 				},
 				ExportDir:    "service-export",
+				MaxParallel:  DefaultMaxParallel,
 				ExcludedOrgs: []string{"org1", "org2"},
 				SourceApi: CloudController{
 					URL:          "https://api.cf1.example.com",
@@ -106,6 +107,7 @@ func TestNewDefaultConfig(t *testing.T) {
 					"apps.cf1.example.com": "apps.cf2.example.com",
 				},
 				ExportDir:    filepath.Join(pwd, "export"),
+				MaxParallel:  DefaultMaxParallel,
 				ExcludedOrgs: []string{},
 				DryRun:       false,
 				Debug:        false,
@@ -127,6 +129,7 @@ func TestNewDefaultConfig(t *testing.T) {
 					"apps.cf1.example.com": "apps.cf2.example.com",
 				},
 				ExportDir:    filepath.Join(pwd, "export"),
+				MaxParallel:  DefaultMaxParallel,
 				ExcludedOrgs: []string{},
 				DryRun:       false,
 				Debug:        false,
@@ -345,11 +348,16 @@ func TestNewConfig(t *testing.T) {
 					"apps.cf1.example.com": "apps.cf2.example.com",
 				},
 				ExportDir:         "service-export",
+				MaxParallel:       DefaultMaxParallel,
 				ExcludedOrgs:      []string{"org1", "org2"},
 				IgnoreServiceKeys: true,
-				DryRun:            false,
-				Debug:             false,
-				initialized:       true,
+				Mappings: []Mapping{
+					{Service: "p-mysql", TargetService: "p.mysql", Plan: "100mb", TargetPlan: "db-small"},
+					{Org: "org3", Service: "p-mysql", TargetPlan: "db-large"},
+				},
+				DryRun:      false,
+				Debug:       false,
+				initialized: true,
 			},
 			wantErr: false,
 		},
@@ -408,6 +416,7 @@ func TestNewConfig(t *testing.T) {
 					"apps.cf1.example.com": "apps.cf2.example.com",
 				},
 				ExportDir:    "service-export",
+				MaxParallel:  DefaultMaxParallel,
 				ExcludedOrgs: []string{"org1", "org2"},
 				DryRun:       false,
 				Debug:        false,
@@ -429,6 +438,7 @@ func TestNewConfig(t *testing.T) {
 					"apps.cf1.example.com": "apps.cf2.example.com",
 				},
 				ExportDir:    filepath.Join(pwd, "export"),
+				MaxParallel:  DefaultMaxParallel,
 				ExcludedOrgs: []string{},
 				DryRun:       false,
 				Debug:        false,
@@ -445,6 +455,7 @@ func TestNewConfig(t *testing.T) {
 				ConfigFile:  filepath.Join(pwd, "testdata", "config_services.yml"),
 				Name:        "si-migrator",
 				ExportDir:   filepath.Join(pwd, "export"),
+				MaxParallel: DefaultMaxParallel,
 				Services:    []string{"credhub", "sqlserver"},
 				initialized: true,
 			},
@@ -17,6 +17,8 @@ package config
 import (
 	"context"
 	"github.com/vbauerster/mpb/v7"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/journal"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/limit"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/report"
 )
 
@@ -26,6 +28,9 @@ const (
 	configKey key = iota
 	reportSummaryKey
 	progressKey
+	journalKey
+	trackerKey
+	limiterKey
 )
 
 func ContextWithConfig(ctx context.Context, config *Config) context.Context {
@@ -63,3 +68,39 @@ func ProgressFromContext(ctx context.Context) (*mpb.Progress, bool) {
 	}
 	return progress, ok
 }
+
+func ContextWithJournal(ctx context.Context, j *journal.Journal) context.Context {
+	return context.WithValue(ctx, journalKey, j)
+}
+
+func JournalFromContext(ctx context.Context) (*journal.Journal, bool) {
+	j, ok := ctx.Value(journalKey).(*journal.Journal)
+	if j == nil {
+		return j, false
+	}
+	return j, ok
+}
+
+func ContextWithTracker(ctx context.Context, t *journal.Tracker) context.Context {
+	return context.WithValue(ctx, trackerKey, t)
+}
+
+func TrackerFromContext(ctx context.Context) (*journal.Tracker, bool) {
+	t, ok := ctx.Value(trackerKey).(*journal.Tracker)
+	if t == nil {
+		return t, false
+	}
+	return t, ok
+}
+
+func ContextWithLimiter(ctx context.Context, l *limit.Limiter) context.Context {
+	return context.WithValue(ctx, limiterKey, l)
+}
+
+func LimiterFromContext(ctx context.Context) (*limit.Limiter, bool) {
+	l, ok := ctx.Value(limiterKey).(*limit.Limiter)
+	if l == nil {
+		return l, false
+	}
+	return l, ok
+}
@@ -0,0 +1,85 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Mapping replaces the service label and/or plan of exported service instances with the ones to use on the
+// target. Org limits the mapping to instances exported from that org, overriding mappings without an org.
+type Mapping struct {
+	Org           string `yaml:"org,omitempty" mapstructure:"org"`
+	Service       string `yaml:"service" mapstructure:"service"`
+	Plan          string `yaml:"plan,omitempty" mapstructure:"plan"`
+	TargetService string `yaml:"target_service,omitempty" mapstructure:"target_service"`
+	TargetPlan    string `yaml:"target_plan,omitempty" mapstructure:"target_plan"`
+}
+
+func (m Mapping) Validate() error {
+	if m.Service == "" {
+		return NewFieldError("service", errors.New("can't be empty"))
+	}
+	if m.TargetService == "" && m.TargetPlan == "" {
+		return NewFieldsError([]string{"target_service", "target_plan"}, errors.New("can't both be empty"))
+	}
+	return nil
+}
+
+func (m Mapping) matches(org, service, plan string) bool {
+	return m.Service == service && (m.Org == "" || m.Org == org) && (m.Plan == "" || m.Plan == plan)
+}
+
+// specificity ranks org overrides above mappings for all orgs, then mappings of a plan above mappings of any plan
+func (m Mapping) specificity() int {
+	s := 0
+	if m.Org != "" {
+		s += 2
+	}
+	if m.Plan != "" {
+		s++
+	}
+	return s
+}
+
+// ValidateMappings returns an error for the first invalid mapping
+func (c *Config) ValidateMappings() error {
+	for i, m := range c.Mappings {
+		if err := m.Validate(); err != nil {
+			return NewFieldError(fmt.Sprintf("mappings[%d]", i), err)
+		}
+	}
+	return nil
+}
+
+// MapService returns the service label and plan to create on the target for an instance of service and plan
+// exported from org. The label and the plan are each taken from the most specific mapping that sets them, and
+// are left unchanged when no mapping does.
+func (c *Config) MapService(org, service, plan string) (string, string) {
+	targetService, targetPlan := service, plan
+	serviceRank, planRank := -1, -1
+	for _, m := range c.Mappings {
+		if !m.matches(org, service, plan) {
+			continue
+		}
+		if m.TargetService != "" && m.specificity() > serviceRank {
+			targetService, serviceRank = m.TargetService, m.specificity()
+		}
+		if m.TargetPlan != "" && m.specificity() > planRank {
+			targetPlan, planRank = m.TargetPlan, m.specificity()
+		}
+	}
+	return targetService, targetPlan
+}
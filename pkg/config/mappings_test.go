@@ -0,0 +1,59 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_MapService(t *testing.T) {
+	cfg := &Config{
+		Mappings: []Mapping{
+			{Service: "p-mysql", TargetService: "p.mysql"},
+			{Service: "p-mysql", Plan: "100mb", TargetPlan: "db-small"},
+			{Org: "finance", Service: "p-mysql", TargetPlan: "db-large"},
+			{Org: "finance", Service: "p-mysql", Plan: "1gb", TargetPlan: "db-xlarge"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		org         string
+		service     string
+		plan        string
+		wantService string
+		wantPlan    string
+	}{
+		{name: "maps the service label of every plan", org: "dev", service: "p-mysql", plan: "1gb", wantService: "p.mysql", wantPlan: "1gb"},
+		{name: "maps the plan of a service", org: "dev", service: "p-mysql", plan: "100mb", wantService: "p.mysql", wantPlan: "db-small"},
+		{name: "prefers the org override", org: "finance", service: "p-mysql", plan: "100mb", wantService: "p.mysql", wantPlan: "db-large"},
+		{name: "prefers the org override of the plan", org: "finance", service: "p-mysql", plan: "1gb", wantService: "p.mysql", wantPlan: "db-xlarge"},
+		{name: "leaves unmapped services alone", org: "finance", service: "p.redis", plan: "cache-small", wantService: "p.redis", wantPlan: "cache-small"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, plan := cfg.MapService(tt.org, tt.service, tt.plan)
+			require.Equal(t, tt.wantService, service)
+			require.Equal(t, tt.wantPlan, plan)
+		})
+	}
+}
+
+func TestConfig_ValidateMappings(t *testing.T) {
+	require.NoError(t, (&Config{Mappings: []Mapping{{Service: "p-mysql", TargetPlan: "db-small"}}}).ValidateMappings())
+	require.EqualError(t, (&Config{Mappings: []Mapping{{TargetService: "p.mysql"}}}).ValidateMappings(), "mappings[0].service can't be empty")
+	require.EqualError(t, (&Config{Mappings: []Mapping{{Service: "p-mysql"}}}).ValidateMappings(), "mappings[0].target_service, target_plan can't both be empty")
+}
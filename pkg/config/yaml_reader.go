@@ -127,6 +127,7 @@ func (r *YAMLMigrationReader) read() (int, error) {
 	}
 
 	r.migration = m
+	m.registerSecrets()
 
 	return len(r.migrationBytes), err
 }
@@ -24,6 +24,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/stretchr/testify/require"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
 )
 
 func TestNewReader_GetMigration(t *testing.T) {
@@ -290,3 +291,31 @@ foundations:
 		})
 	}
 }
+
+func TestReadFromDir_RegistersMigratorSecrets(t *testing.T) {
+	defer log.ClearSecrets()
+	configFile := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+migrators:
+- name: sqlserver
+  migrator:
+    source_ccdb:
+      db_username: tas1_ccdb_username
+      db_password: tas1_ccdb_password
+      ssh_password: tas1_ssh_password
+- name: mysql
+  migrator:
+    backup_type: minio
+    minio:
+      access_key: minio-access-key
+      secret_key: minio-secret-key
+`), 0600))
+
+	_, err := config.ReadFromDir(configFile)
+	require.NoError(t, err)
+
+	for _, secret := range []string{"tas1_ccdb_password", "tas1_ssh_password", "minio-access-key", "minio-secret-key"} {
+		require.Equal(t, log.Redacted, log.Redact(secret))
+	}
+	require.Equal(t, "tas1_ccdb_username", log.Redact("tas1_ccdb_username"))
+}
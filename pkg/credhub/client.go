@@ -15,6 +15,7 @@
 package credhub
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
@@ -24,6 +25,7 @@ import (
 	golog "log"
 	"net"
 	"net/http"
+	neturl "net/url"
 	"strings"
 	"time"
 
@@ -42,33 +44,62 @@ import (
 
 type Client interface {
 	GetCreds(ref string) (map[string][]map[string]interface{}, error)
+	FindByPath(path string) ([]string, error)
+	GetCredential(name string) (Credential, error)
+	SetCredential(cred Credential) (Credential, error)
+	ExportCredentials(path string) ([]Credential, error)
+	ImportCredentials(creds []Credential) error
 }
 
 //counterfeiter:generate -o fakes . ClientFactory
 
 type ClientFactory interface {
-	New(url string, credhubPort string, uaaPort string, allProxy string, caCert []byte, clientID string, clientSecret string) Client
+	New(credhubURL string, uaaURL string, allProxy string, caCert []byte, skipSSLValidation bool, clientID string, clientSecret string, opts ...Option) Client
 }
 
-type ClientFactoryFunc func(url string, credhubPort string, uaaPort string, allProxy string, caCert []byte, clientID string, clientSecret string) Client
+type ClientFactoryFunc func(credhubURL string, uaaURL string, allProxy string, caCert []byte, skipSSLValidation bool, clientID string, clientSecret string, opts ...Option) Client
 
-func (f ClientFactoryFunc) New(url string, credhubPort string, uaaPort string, allProxy string, caCert []byte, clientID string, clientSecret string) Client {
-	return f(url, credhubPort, uaaPort, allProxy, caCert, clientID, clientSecret)
+func (f ClientFactoryFunc) New(credhubURL string, uaaURL string, allProxy string, caCert []byte, skipSSLValidation bool, clientID string, clientSecret string, opts ...Option) Client {
+	return f(credhubURL, uaaURL, allProxy, caCert, skipSSLValidation, clientID, clientSecret, opts...)
 }
 
+// Option configures a credhub client
+type Option func(*ClientImpl)
+
+// WithResolvedHosts dials the given ip instead of the host name it is mapped to, while the server certificates
+// are still verified against the host name. Use it for hosts whose names only resolve inside a deployment.
+func WithResolvedHosts(hosts map[string]string) Option {
+	return func(c *ClientImpl) {
+		dial := c.dialContextFunc
+		c.dialContextFunc = func(ctx context.Context, network, address string) (net.Conn, error) {
+			if host, port, err := net.SplitHostPort(address); err == nil {
+				if ip, ok := hosts[host]; ok {
+					address = net.JoinHostPort(ip, port)
+				}
+			}
+			return dial(ctx, network, address)
+		}
+	}
+}
+
+// Credential is a single credhub credential in the format used by the credhub bulk export and import
 type Credential struct {
+	Name  string      `json:"name" yaml:"name"`
+	Type  string      `json:"type" yaml:"type"`
+	Value interface{} `json:"value" yaml:"value"`
 }
 
 type ClientImpl struct {
-	url, credhubPort, uaaPort string
-	allProxy                  string
-	caCert                    []byte
-	clientID, clientSecret    string
-	dialContextFunc           DialContextFunc
+	credhubURL, uaaURL     string
+	allProxy               string
+	caCert                 []byte
+	skipSSLValidation      bool
+	clientID, clientSecret string
+	dialContextFunc        DialContextFunc
 }
 
 func NewClientFactory() ClientFactoryFunc {
-	return func(url string, credhubPort string, uaaPort string, allProxy string, caCert []byte, clientID string, clientSecret string) Client {
+	return func(credhubURL string, uaaURL string, allProxy string, caCert []byte, skipSSLValidation bool, clientID string, clientSecret string, opts ...Option) Client {
 		var dialContextFunc = (&net.Dialer{
 			Timeout:   30 * time.Second,
 			KeepAlive: 30 * time.Second,
@@ -78,21 +109,25 @@ func NewClientFactory() ClientFactoryFunc {
 			dialContextFunc = boshhttp.SOCKS5DialContextFuncFromAllProxy(allProxy, socks)
 		}
 
-		return ClientImpl{
-			allProxy:        allProxy,
-			url:             url,
-			credhubPort:     credhubPort,
-			uaaPort:         uaaPort,
-			caCert:          caCert,
-			clientID:        clientID,
-			clientSecret:    clientSecret,
-			dialContextFunc: dialContextFunc,
+		c := ClientImpl{
+			allProxy:          allProxy,
+			credhubURL:        credhubURL,
+			uaaURL:            uaaURL,
+			caCert:            caCert,
+			skipSSLValidation: skipSSLValidation,
+			clientID:          clientID,
+			clientSecret:      clientSecret,
+			dialContextFunc:   dialContextFunc,
+		}
+		for _, opt := range opts {
+			opt(&c)
 		}
+		return c
 	}
 }
 
 func (c ClientImpl) GetCreds(ref string) (map[string][]map[string]interface{}, error) {
-	url := fmt.Sprintf("%s:%s", c.url, c.credhubPort)
+	url := c.credhubURL
 
 	client, err := c.httpClient(c.dialContextFunc)
 	if err != nil {
@@ -135,8 +170,174 @@ func (c ClientImpl) GetCreds(ref string) (map[string][]map[string]interface{}, e
 	return creds, nil
 }
 
+// FindByPath returns the names of all the credentials stored under path
+func (c ClientImpl) FindByPath(path string) ([]string, error) {
+	client, token, err := c.session()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.findByPath(client, token, path)
+}
+
+// GetCredential returns the current value of the credential with the given name
+func (c ClientImpl) GetCredential(name string) (Credential, error) {
+	client, token, err := c.session()
+	if err != nil {
+		return Credential{}, err
+	}
+
+	return c.getCredential(client, token, name)
+}
+
+// SetCredential creates or overwrites a credential, returning the credential stored by credhub
+func (c ClientImpl) SetCredential(cred Credential) (Credential, error) {
+	client, token, err := c.session()
+	if err != nil {
+		return Credential{}, err
+	}
+
+	return c.setCredential(client, token, cred)
+}
+
+// ExportCredentials returns the current value of every credential stored under path
+func (c ClientImpl) ExportCredentials(path string) ([]Credential, error) {
+	client, token, err := c.session()
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := c.findByPath(client, token, path)
+	if err != nil {
+		return nil, err
+	}
+
+	creds := make([]Credential, 0, len(names))
+	for _, name := range names {
+		cred, err := c.getCredential(client, token, name)
+		if err != nil {
+			return nil, err
+		}
+		creds = append(creds, cred)
+	}
+
+	return creds, nil
+}
+
+// ImportCredentials sets every credential, stopping at the first one credhub rejects
+func (c ClientImpl) ImportCredentials(creds []Credential) error {
+	client, token, err := c.session()
+	if err != nil {
+		return err
+	}
+
+	for _, cred := range creds {
+		if _, err := c.setCredential(client, token, cred); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c ClientImpl) findByPath(client HTTPClient, token string, path string) ([]string, error) {
+	var res struct {
+		Credentials []struct {
+			Name string `json:"name"`
+		} `json:"credentials"`
+	}
+	if err := c.request(client, token, http.MethodGet, "/api/v1/data?path="+neturl.QueryEscape(path), nil, &res); err != nil {
+		return nil, fmt.Errorf("failed to find credentials, path '%s': %w", path, err)
+	}
+
+	names := make([]string, 0, len(res.Credentials))
+	for _, cred := range res.Credentials {
+		names = append(names, cred.Name)
+	}
+
+	return names, nil
+}
+
+func (c ClientImpl) getCredential(client HTTPClient, token string, name string) (Credential, error) {
+	var res struct {
+		Data []Credential `json:"data"`
+	}
+	if err := c.request(client, token, http.MethodGet, "/api/v1/data?current=true&name="+neturl.QueryEscape(name), nil, &res); err != nil {
+		return Credential{}, fmt.Errorf("failed to get credential, name '%s': %w", name, err)
+	}
+	if len(res.Data) == 0 {
+		return Credential{}, fmt.Errorf("failed to get credential, name '%s': not found", name)
+	}
+
+	return res.Data[0], nil
+}
+
+func (c ClientImpl) setCredential(client HTTPClient, token string, cred Credential) (Credential, error) {
+	var res Credential
+	if err := c.request(client, token, http.MethodPut, "/api/v1/data", cred, &res); err != nil {
+		return Credential{}, fmt.Errorf("failed to set credential, name '%s': %w", cred.Name, err)
+	}
+
+	return res, nil
+}
+
+// session returns an http client and access token that can be reused across several credhub requests
+func (c ClientImpl) session() (HTTPClient, string, error) {
+	client, err := c.httpClient(c.dialContextFunc)
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := c.getAccessToken(client)
+	if err != nil {
+		return nil, "", err
+	}
+
+	accessToken, ok := token["access_token"].(string)
+	if !ok {
+		return nil, "", fmt.Errorf("failed to get credhub access token from %s", c.uaaURL)
+	}
+
+	return client, accessToken, nil
+}
+
+func (c ClientImpl) request(client HTTPClient, token string, method string, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, c.credhubURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	content, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("status '%s'", http.StatusText(res.StatusCode))
+	}
+
+	return json.Unmarshal(content, out)
+}
+
 func (c ClientImpl) getAccessToken(client HTTPClient) (map[string]interface{}, error) {
-	url := fmt.Sprintf("%s:%s", c.url, c.uaaPort)
+	url := c.uaaURL
 	req, err := http.NewRequest("POST",
 		fmt.Sprintf("%s/oauth/token", url),
 		strings.NewReader(
@@ -187,6 +388,12 @@ func (c ClientImpl) httpClient(dialer DialContextFunc) (HTTPClient, error) {
 		log.Errorf("Error creating tls config. %v", err)
 		return nil, err
 	}
+	if c.skipSSLValidation {
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+		tlsConfig.InsecureSkipVerify = true
+	}
 	return NewHTTPClient(dialer, tlsConfig), nil
 }
 
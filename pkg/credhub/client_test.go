@@ -15,7 +15,8 @@
 package credhub_test
 
 import (
-	"fmt"
+	"encoding/json"
+	"encoding/pem"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -84,14 +85,12 @@ func TestClient_GetCreds(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			s := httptest.NewServer(tt.handler())
 			defer s.Close()
-			serverURL, err := url.Parse(s.URL)
-			require.NoError(t, err)
 			c := credhub.NewClientFactory().New(
-				fmt.Sprintf("%s://%s", serverURL.Scheme, serverURL.Hostname()),
-				serverURL.Port(),
-				serverURL.Port(),
+				s.URL,
+				s.URL,
 				tt.fields.allProxy,
 				tt.fields.caCert,
+				false,
 				tt.fields.clientID,
 				tt.fields.clientSecret,
 			)
@@ -108,6 +107,111 @@ func TestClient_GetCreds(t *testing.T) {
 	}
 }
 
+func TestClient_ExportCredentials(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/token", FakeHandler(t, fakeAccessToken))
+	mux.HandleFunc("/api/v1/data", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer fake-access-token", r.Header.Get("Authorization"))
+		switch {
+		case r.URL.Query().Get("path") == "/path/to":
+			FakeHandler(t, fakeFindByPath)(w, r)
+		case r.URL.Query().Get("name") == "/path/to/credential":
+			FakeHandler(t, fakeData)(w, r)
+		case r.URL.Query().Get("name") == "/path/to/other-credential":
+			FakeHandler(t, fakeOtherData)(w, r)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	c := credhub.NewClientFactory().New(s.URL, s.URL, "", nil, false, "", "")
+
+	names, err := c.FindByPath("/path/to")
+	require.NoError(t, err)
+	require.Equal(t, []string{"/path/to/credential", "/path/to/other-credential"}, names)
+
+	got, err := c.ExportCredentials("/path/to")
+	require.NoError(t, err)
+	require.Equal(t, []credhub.Credential{
+		{
+			Name: "/path/to/credential",
+			Type: "user",
+			Value: map[string]interface{}{
+				"username":      "some-user",
+				"password":      "some-password",
+				"password_hash": "some-password-hash",
+			},
+		},
+		{
+			Name:  "/path/to/other-credential",
+			Type:  "value",
+			Value: "some-value",
+		},
+	}, got)
+
+	_, err = c.GetCredential("/path/to/missing")
+	require.EqualError(t, err, "failed to get credential, name '/path/to/missing': status 'Not Found'")
+}
+
+func TestClient_ImportCredentials(t *testing.T) {
+	var got []credhub.Credential
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/token", FakeHandler(t, fakeAccessToken))
+	mux.HandleFunc("/api/v1/data", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPut, r.Method)
+		require.Equal(t, "Bearer fake-access-token", r.Header.Get("Authorization"))
+		var cred credhub.Credential
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&cred))
+		if cred.Name == "/path/to/rejected" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		got = append(got, cred)
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(cred))
+	})
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	c := credhub.NewClientFactory().New(s.URL, s.URL, "", nil, false, "", "")
+
+	creds := []credhub.Credential{
+		{Name: "/path/to/credential", Type: "json", Value: map[string]interface{}{"key": "value"}},
+		{Name: "/path/to/other-credential", Type: "value", Value: "some-value"},
+	}
+	require.NoError(t, c.ImportCredentials(creds))
+	require.Equal(t, creds, got)
+
+	err := c.ImportCredentials([]credhub.Credential{{Name: "/path/to/rejected", Type: "value", Value: "some-value"}})
+	require.EqualError(t, err, "failed to set credential, name '/path/to/rejected': status 'Bad Request'")
+}
+
+func TestClient_WithResolvedHosts(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/token", FakeHandler(t, fakeAccessToken))
+	mux.HandleFunc("/api/v1/data", FakeHandler(t, fakeFindByPath))
+	s := httptest.NewTLSServer(mux)
+	defer s.Close()
+
+	u, err := url.Parse(s.URL)
+	require.NoError(t, err)
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw})
+	serverURL := "https://example.com:" + u.Port()
+	hosts := map[string]string{"example.com": u.Hostname()}
+
+	c := credhub.NewClientFactory().New(serverURL, serverURL, "", caCert, false, "", "", credhub.WithResolvedHosts(hosts))
+	names, err := c.FindByPath("/path/to")
+	require.NoError(t, err)
+	require.Equal(t, []string{"/path/to/credential", "/path/to/other-credential"}, names)
+
+	otherName := "https://credhub.service.cf.internal:" + u.Port()
+	c = credhub.NewClientFactory().New(otherName, otherName, "", caCert, false, "", "", credhub.WithResolvedHosts(map[string]string{"credhub.service.cf.internal": u.Hostname()}))
+	_, err = c.FindByPath("/path/to")
+	require.ErrorContains(t, err, "not credhub.service.cf.internal")
+}
+
 func FakeHandler(t *testing.T, s string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -140,3 +244,28 @@ var fakeData = `{
       }
    ]
 }`
+
+var fakeFindByPath = `{
+   "credentials":[
+      {
+         "version_created_at":"2022-01-27T01:10:06Z",
+         "name":"/path/to/credential"
+      },
+      {
+         "version_created_at":"2022-01-27T01:10:06Z",
+         "name":"/path/to/other-credential"
+      }
+   ]
+}`
+
+var fakeOtherData = `{
+   "data":[
+      {
+         "type":"value",
+         "id":"some-other-uuid",
+         "name":"/path/to/other-credential",
+         "version_created_at":"2022-01-27T01:10:06Z",
+         "value":"some-value"
+      }
+   ]
+}`
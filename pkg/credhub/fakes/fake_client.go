@@ -8,6 +8,45 @@ import (
 )
 
 type FakeClient struct {
+	ExportCredentialsStub        func(string) ([]credhub.Credential, error)
+	exportCredentialsMutex       sync.RWMutex
+	exportCredentialsArgsForCall []struct {
+		arg1 string
+	}
+	exportCredentialsReturns struct {
+		result1 []credhub.Credential
+		result2 error
+	}
+	exportCredentialsReturnsOnCall map[int]struct {
+		result1 []credhub.Credential
+		result2 error
+	}
+	FindByPathStub        func(string) ([]string, error)
+	findByPathMutex       sync.RWMutex
+	findByPathArgsForCall []struct {
+		arg1 string
+	}
+	findByPathReturns struct {
+		result1 []string
+		result2 error
+	}
+	findByPathReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
+	GetCredentialStub        func(string) (credhub.Credential, error)
+	getCredentialMutex       sync.RWMutex
+	getCredentialArgsForCall []struct {
+		arg1 string
+	}
+	getCredentialReturns struct {
+		result1 credhub.Credential
+		result2 error
+	}
+	getCredentialReturnsOnCall map[int]struct {
+		result1 credhub.Credential
+		result2 error
+	}
 	GetCredsStub        func(string) (map[string][]map[string]interface{}, error)
 	getCredsMutex       sync.RWMutex
 	getCredsArgsForCall []struct {
@@ -21,10 +60,226 @@ type FakeClient struct {
 		result1 map[string][]map[string]interface{}
 		result2 error
 	}
+	ImportCredentialsStub        func([]credhub.Credential) error
+	importCredentialsMutex       sync.RWMutex
+	importCredentialsArgsForCall []struct {
+		arg1 []credhub.Credential
+	}
+	importCredentialsReturns struct {
+		result1 error
+	}
+	importCredentialsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetCredentialStub        func(credhub.Credential) (credhub.Credential, error)
+	setCredentialMutex       sync.RWMutex
+	setCredentialArgsForCall []struct {
+		arg1 credhub.Credential
+	}
+	setCredentialReturns struct {
+		result1 credhub.Credential
+		result2 error
+	}
+	setCredentialReturnsOnCall map[int]struct {
+		result1 credhub.Credential
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeClient) ExportCredentials(arg1 string) ([]credhub.Credential, error) {
+	fake.exportCredentialsMutex.Lock()
+	ret, specificReturn := fake.exportCredentialsReturnsOnCall[len(fake.exportCredentialsArgsForCall)]
+	fake.exportCredentialsArgsForCall = append(fake.exportCredentialsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ExportCredentialsStub
+	fakeReturns := fake.exportCredentialsReturns
+	fake.recordInvocation("ExportCredentials", []interface{}{arg1})
+	fake.exportCredentialsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ExportCredentialsCallCount() int {
+	fake.exportCredentialsMutex.RLock()
+	defer fake.exportCredentialsMutex.RUnlock()
+	return len(fake.exportCredentialsArgsForCall)
+}
+
+func (fake *FakeClient) ExportCredentialsCalls(stub func(string) ([]credhub.Credential, error)) {
+	fake.exportCredentialsMutex.Lock()
+	defer fake.exportCredentialsMutex.Unlock()
+	fake.ExportCredentialsStub = stub
+}
+
+func (fake *FakeClient) ExportCredentialsArgsForCall(i int) string {
+	fake.exportCredentialsMutex.RLock()
+	defer fake.exportCredentialsMutex.RUnlock()
+	argsForCall := fake.exportCredentialsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) ExportCredentialsReturns(result1 []credhub.Credential, result2 error) {
+	fake.exportCredentialsMutex.Lock()
+	defer fake.exportCredentialsMutex.Unlock()
+	fake.ExportCredentialsStub = nil
+	fake.exportCredentialsReturns = struct {
+		result1 []credhub.Credential
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ExportCredentialsReturnsOnCall(i int, result1 []credhub.Credential, result2 error) {
+	fake.exportCredentialsMutex.Lock()
+	defer fake.exportCredentialsMutex.Unlock()
+	fake.ExportCredentialsStub = nil
+	if fake.exportCredentialsReturnsOnCall == nil {
+		fake.exportCredentialsReturnsOnCall = make(map[int]struct {
+			result1 []credhub.Credential
+			result2 error
+		})
+	}
+	fake.exportCredentialsReturnsOnCall[i] = struct {
+		result1 []credhub.Credential
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) FindByPath(arg1 string) ([]string, error) {
+	fake.findByPathMutex.Lock()
+	ret, specificReturn := fake.findByPathReturnsOnCall[len(fake.findByPathArgsForCall)]
+	fake.findByPathArgsForCall = append(fake.findByPathArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.FindByPathStub
+	fakeReturns := fake.findByPathReturns
+	fake.recordInvocation("FindByPath", []interface{}{arg1})
+	fake.findByPathMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) FindByPathCallCount() int {
+	fake.findByPathMutex.RLock()
+	defer fake.findByPathMutex.RUnlock()
+	return len(fake.findByPathArgsForCall)
+}
+
+func (fake *FakeClient) FindByPathCalls(stub func(string) ([]string, error)) {
+	fake.findByPathMutex.Lock()
+	defer fake.findByPathMutex.Unlock()
+	fake.FindByPathStub = stub
+}
+
+func (fake *FakeClient) FindByPathArgsForCall(i int) string {
+	fake.findByPathMutex.RLock()
+	defer fake.findByPathMutex.RUnlock()
+	argsForCall := fake.findByPathArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) FindByPathReturns(result1 []string, result2 error) {
+	fake.findByPathMutex.Lock()
+	defer fake.findByPathMutex.Unlock()
+	fake.FindByPathStub = nil
+	fake.findByPathReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) FindByPathReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.findByPathMutex.Lock()
+	defer fake.findByPathMutex.Unlock()
+	fake.FindByPathStub = nil
+	if fake.findByPathReturnsOnCall == nil {
+		fake.findByPathReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.findByPathReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetCredential(arg1 string) (credhub.Credential, error) {
+	fake.getCredentialMutex.Lock()
+	ret, specificReturn := fake.getCredentialReturnsOnCall[len(fake.getCredentialArgsForCall)]
+	fake.getCredentialArgsForCall = append(fake.getCredentialArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetCredentialStub
+	fakeReturns := fake.getCredentialReturns
+	fake.recordInvocation("GetCredential", []interface{}{arg1})
+	fake.getCredentialMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) GetCredentialCallCount() int {
+	fake.getCredentialMutex.RLock()
+	defer fake.getCredentialMutex.RUnlock()
+	return len(fake.getCredentialArgsForCall)
+}
+
+func (fake *FakeClient) GetCredentialCalls(stub func(string) (credhub.Credential, error)) {
+	fake.getCredentialMutex.Lock()
+	defer fake.getCredentialMutex.Unlock()
+	fake.GetCredentialStub = stub
+}
+
+func (fake *FakeClient) GetCredentialArgsForCall(i int) string {
+	fake.getCredentialMutex.RLock()
+	defer fake.getCredentialMutex.RUnlock()
+	argsForCall := fake.getCredentialArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) GetCredentialReturns(result1 credhub.Credential, result2 error) {
+	fake.getCredentialMutex.Lock()
+	defer fake.getCredentialMutex.Unlock()
+	fake.GetCredentialStub = nil
+	fake.getCredentialReturns = struct {
+		result1 credhub.Credential
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetCredentialReturnsOnCall(i int, result1 credhub.Credential, result2 error) {
+	fake.getCredentialMutex.Lock()
+	defer fake.getCredentialMutex.Unlock()
+	fake.GetCredentialStub = nil
+	if fake.getCredentialReturnsOnCall == nil {
+		fake.getCredentialReturnsOnCall = make(map[int]struct {
+			result1 credhub.Credential
+			result2 error
+		})
+	}
+	fake.getCredentialReturnsOnCall[i] = struct {
+		result1 credhub.Credential
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) GetCreds(arg1 string) (map[string][]map[string]interface{}, error) {
 	fake.getCredsMutex.Lock()
 	ret, specificReturn := fake.getCredsReturnsOnCall[len(fake.getCredsArgsForCall)]
@@ -89,11 +344,151 @@ func (fake *FakeClient) GetCredsReturnsOnCall(i int, result1 map[string][]map[st
 	}{result1, result2}
 }
 
+func (fake *FakeClient) ImportCredentials(arg1 []credhub.Credential) error {
+	var arg1Copy []credhub.Credential
+	if arg1 != nil {
+		arg1Copy = make([]credhub.Credential, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.importCredentialsMutex.Lock()
+	ret, specificReturn := fake.importCredentialsReturnsOnCall[len(fake.importCredentialsArgsForCall)]
+	fake.importCredentialsArgsForCall = append(fake.importCredentialsArgsForCall, struct {
+		arg1 []credhub.Credential
+	}{arg1Copy})
+	stub := fake.ImportCredentialsStub
+	fakeReturns := fake.importCredentialsReturns
+	fake.recordInvocation("ImportCredentials", []interface{}{arg1Copy})
+	fake.importCredentialsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) ImportCredentialsCallCount() int {
+	fake.importCredentialsMutex.RLock()
+	defer fake.importCredentialsMutex.RUnlock()
+	return len(fake.importCredentialsArgsForCall)
+}
+
+func (fake *FakeClient) ImportCredentialsCalls(stub func([]credhub.Credential) error) {
+	fake.importCredentialsMutex.Lock()
+	defer fake.importCredentialsMutex.Unlock()
+	fake.ImportCredentialsStub = stub
+}
+
+func (fake *FakeClient) ImportCredentialsArgsForCall(i int) []credhub.Credential {
+	fake.importCredentialsMutex.RLock()
+	defer fake.importCredentialsMutex.RUnlock()
+	argsForCall := fake.importCredentialsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) ImportCredentialsReturns(result1 error) {
+	fake.importCredentialsMutex.Lock()
+	defer fake.importCredentialsMutex.Unlock()
+	fake.ImportCredentialsStub = nil
+	fake.importCredentialsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) ImportCredentialsReturnsOnCall(i int, result1 error) {
+	fake.importCredentialsMutex.Lock()
+	defer fake.importCredentialsMutex.Unlock()
+	fake.ImportCredentialsStub = nil
+	if fake.importCredentialsReturnsOnCall == nil {
+		fake.importCredentialsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.importCredentialsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) SetCredential(arg1 credhub.Credential) (credhub.Credential, error) {
+	fake.setCredentialMutex.Lock()
+	ret, specificReturn := fake.setCredentialReturnsOnCall[len(fake.setCredentialArgsForCall)]
+	fake.setCredentialArgsForCall = append(fake.setCredentialArgsForCall, struct {
+		arg1 credhub.Credential
+	}{arg1})
+	stub := fake.SetCredentialStub
+	fakeReturns := fake.setCredentialReturns
+	fake.recordInvocation("SetCredential", []interface{}{arg1})
+	fake.setCredentialMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) SetCredentialCallCount() int {
+	fake.setCredentialMutex.RLock()
+	defer fake.setCredentialMutex.RUnlock()
+	return len(fake.setCredentialArgsForCall)
+}
+
+func (fake *FakeClient) SetCredentialCalls(stub func(credhub.Credential) (credhub.Credential, error)) {
+	fake.setCredentialMutex.Lock()
+	defer fake.setCredentialMutex.Unlock()
+	fake.SetCredentialStub = stub
+}
+
+func (fake *FakeClient) SetCredentialArgsForCall(i int) credhub.Credential {
+	fake.setCredentialMutex.RLock()
+	defer fake.setCredentialMutex.RUnlock()
+	argsForCall := fake.setCredentialArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) SetCredentialReturns(result1 credhub.Credential, result2 error) {
+	fake.setCredentialMutex.Lock()
+	defer fake.setCredentialMutex.Unlock()
+	fake.SetCredentialStub = nil
+	fake.setCredentialReturns = struct {
+		result1 credhub.Credential
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) SetCredentialReturnsOnCall(i int, result1 credhub.Credential, result2 error) {
+	fake.setCredentialMutex.Lock()
+	defer fake.setCredentialMutex.Unlock()
+	fake.SetCredentialStub = nil
+	if fake.setCredentialReturnsOnCall == nil {
+		fake.setCredentialReturnsOnCall = make(map[int]struct {
+			result1 credhub.Credential
+			result2 error
+		})
+	}
+	fake.setCredentialReturnsOnCall[i] = struct {
+		result1 credhub.Credential
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.exportCredentialsMutex.RLock()
+	defer fake.exportCredentialsMutex.RUnlock()
+	fake.findByPathMutex.RLock()
+	defer fake.findByPathMutex.RUnlock()
+	fake.getCredentialMutex.RLock()
+	defer fake.getCredentialMutex.RUnlock()
 	fake.getCredsMutex.RLock()
 	defer fake.getCredsMutex.RUnlock()
+	fake.importCredentialsMutex.RLock()
+	defer fake.importCredentialsMutex.RUnlock()
+	fake.setCredentialMutex.RLock()
+	defer fake.setCredentialMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -8,16 +8,17 @@ import (
 )
 
 type FakeClientFactory struct {
-	NewStub        func(string, string, string, string, []byte, string, string) credhub.Client
+	NewStub        func(string, string, string, []byte, bool, string, string, ...credhub.Option) credhub.Client
 	newMutex       sync.RWMutex
 	newArgsForCall []struct {
 		arg1 string
 		arg2 string
 		arg3 string
-		arg4 string
-		arg5 []byte
+		arg4 []byte
+		arg5 bool
 		arg6 string
 		arg7 string
+		arg8 []credhub.Option
 	}
 	newReturns struct {
 		result1 credhub.Client
@@ -29,11 +30,11 @@ type FakeClientFactory struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeClientFactory) New(arg1 string, arg2 string, arg3 string, arg4 string, arg5 []byte, arg6 string, arg7 string) credhub.Client {
-	var arg5Copy []byte
-	if arg5 != nil {
-		arg5Copy = make([]byte, len(arg5))
-		copy(arg5Copy, arg5)
+func (fake *FakeClientFactory) New(arg1 string, arg2 string, arg3 string, arg4 []byte, arg5 bool, arg6 string, arg7 string, arg8 ...credhub.Option) credhub.Client {
+	var arg4Copy []byte
+	if arg4 != nil {
+		arg4Copy = make([]byte, len(arg4))
+		copy(arg4Copy, arg4)
 	}
 	fake.newMutex.Lock()
 	ret, specificReturn := fake.newReturnsOnCall[len(fake.newArgsForCall)]
@@ -41,17 +42,18 @@ func (fake *FakeClientFactory) New(arg1 string, arg2 string, arg3 string, arg4 s
 		arg1 string
 		arg2 string
 		arg3 string
-		arg4 string
-		arg5 []byte
+		arg4 []byte
+		arg5 bool
 		arg6 string
 		arg7 string
-	}{arg1, arg2, arg3, arg4, arg5Copy, arg6, arg7})
+		arg8 []credhub.Option
+	}{arg1, arg2, arg3, arg4Copy, arg5, arg6, arg7, arg8})
 	stub := fake.NewStub
 	fakeReturns := fake.newReturns
-	fake.recordInvocation("New", []interface{}{arg1, arg2, arg3, arg4, arg5Copy, arg6, arg7})
+	fake.recordInvocation("New", []interface{}{arg1, arg2, arg3, arg4Copy, arg5, arg6, arg7, arg8})
 	fake.newMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7)
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8...)
 	}
 	if specificReturn {
 		return ret.result1
@@ -65,17 +67,17 @@ func (fake *FakeClientFactory) NewCallCount() int {
 	return len(fake.newArgsForCall)
 }
 
-func (fake *FakeClientFactory) NewCalls(stub func(string, string, string, string, []byte, string, string) credhub.Client) {
+func (fake *FakeClientFactory) NewCalls(stub func(string, string, string, []byte, bool, string, string, ...credhub.Option) credhub.Client) {
 	fake.newMutex.Lock()
 	defer fake.newMutex.Unlock()
 	fake.NewStub = stub
 }
 
-func (fake *FakeClientFactory) NewArgsForCall(i int) (string, string, string, string, []byte, string, string) {
+func (fake *FakeClientFactory) NewArgsForCall(i int) (string, string, string, []byte, bool, string, string, []credhub.Option) {
 	fake.newMutex.RLock()
 	defer fake.newMutex.RUnlock()
 	argsForCall := fake.newArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7, argsForCall.arg8
 }
 
 func (fake *FakeClientFactory) NewReturns(result1 credhub.Client) {
@@ -0,0 +1,281 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package encryption
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+)
+
+// PassphraseEnvVar holds the passphrase used when no recipient public key or identity file is configured
+const PassphraseEnvVar = "SI_MIGRATOR_ENCRYPTION_PASSPHRASE"
+
+// Cipher encrypts and decrypts export files with the keys set in the config. Keys are resolved on every call, so
+// a Cipher can be created before the config is loaded from flags and files.
+type Cipher struct {
+	cfg    *config.Config
+	getenv func(string) string
+}
+
+func NewCipher(cfg *config.Config) *Cipher {
+	return &Cipher{
+		cfg:    cfg,
+		getenv: os.Getenv,
+	}
+}
+
+// FromContext returns a Cipher for the config stored in ctx
+func FromContext(ctx context.Context) *Cipher {
+	cfg, ok := config.FromContext(ctx)
+	if !ok {
+		cfg = &config.Config{}
+	}
+	return NewCipher(cfg)
+}
+
+// Enabled returns true if exported files must be encrypted
+func (c *Cipher) Enabled() bool {
+	return c.cfg.Encrypt
+}
+
+// Validate checks the configured keys can be loaded
+func (c *Cipher) Validate() error {
+	if c.Enabled() {
+		if _, err := c.recipients(); err != nil {
+			return err
+		}
+	}
+	if c.cfg.EncryptionIdentity != "" {
+		if _, err := c.identities(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Encrypt returns a WriteCloser that encrypts to dst, or writes to dst unchanged when encryption is not enabled
+func (c *Cipher) Encrypt(dst io.Writer) (io.WriteCloser, error) {
+	if !c.Enabled() {
+		return nopCloser{dst}, nil
+	}
+	recipients, err := c.recipients()
+	if err != nil {
+		return nil, err
+	}
+	return age.Encrypt(dst, recipients...)
+}
+
+// Decrypt returns a Reader of the plaintext of src. Files that are not encrypted are read unchanged, so imports
+// work the same whether or not the export was encrypted.
+func (c *Cipher) Decrypt(src io.Reader) (io.Reader, error) {
+	r, _, err := c.decrypt(src)
+	return r, err
+}
+
+func (c *Cipher) decrypt(src io.Reader) (io.Reader, bool, error) {
+	br := bufio.NewReader(src)
+	peek, _ := br.Peek(len(header) + 1)
+	if !IsEncrypted(peek) {
+		return br, false, nil
+	}
+	identities, err := c.identities()
+	if err != nil {
+		return nil, true, err
+	}
+	r, err := age.Decrypt(br, identities...)
+	if err != nil {
+		return nil, true, err
+	}
+	return r, true, nil
+}
+
+// WriteFile writes data to name, encrypting it when encryption is enabled
+func (c *Cipher) WriteFile(name string, data []byte, perm os.FileMode) error {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	w, err := c.Encrypt(f)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	if _, err = w.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err = w.Close(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadFile reads name, decrypting it if it is encrypted
+func (c *Cipher) ReadFile(name string) ([]byte, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	r, err := c.Decrypt(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %q: %w", name, err)
+	}
+	return io.ReadAll(r)
+}
+
+// EncryptFile encrypts name in place when encryption is enabled
+func (c *Cipher) EncryptFile(name string) error {
+	if !c.Enabled() {
+		return nil
+	}
+
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+
+	// the encrypted copy replaces the original only once it is complete
+	tmp, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if err = c.copyEncrypted(tmp, src); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to encrypt %q: %w", name, err)
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if info, err := src.Stat(); err == nil {
+		_ = os.Chmod(tmp.Name(), info.Mode().Perm())
+	}
+
+	return os.Rename(tmp.Name(), name)
+}
+
+// DecryptFile returns the path of a decrypted copy of name and a func that removes it. When name is not encrypted
+// its own path is returned and nothing is removed.
+func (c *Cipher) DecryptFile(name string) (string, func(), error) {
+	noop := func() {}
+
+	src, err := os.Open(name)
+	if err != nil {
+		return "", noop, err
+	}
+	defer func() { _ = src.Close() }()
+
+	r, encrypted, err := c.decrypt(src)
+	if err != nil {
+		return "", noop, fmt.Errorf("failed to decrypt %q: %w", name, err)
+	}
+	if !encrypted {
+		return name, noop, nil
+	}
+
+	tmp, err := os.CreateTemp("", filepath.Base(name)+".*")
+	if err != nil {
+		return "", noop, err
+	}
+	cleanup := func() { _ = os.Remove(tmp.Name()) }
+
+	if _, err = io.Copy(tmp, r); err != nil {
+		_ = tmp.Close()
+		cleanup()
+		return "", noop, fmt.Errorf("failed to decrypt %q: %w", name, err)
+	}
+	if err = tmp.Close(); err != nil {
+		cleanup()
+		return "", noop, err
+	}
+
+	return tmp.Name(), cleanup, nil
+}
+
+func (c *Cipher) copyEncrypted(dst io.Writer, src io.Reader) error {
+	w, err := c.Encrypt(dst)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(w, src); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func (c *Cipher) recipients() ([]age.Recipient, error) {
+	if c.cfg.EncryptionRecipient != "" {
+		r, err := age.ParseX25519Recipient(strings.TrimSpace(c.cfg.EncryptionRecipient))
+		if err != nil {
+			return nil, fmt.Errorf("malformed public key %q: %w", c.cfg.EncryptionRecipient, err)
+		}
+		return []age.Recipient{r}, nil
+	}
+	if passphrase := c.getenv(PassphraseEnvVar); passphrase != "" {
+		r, err := age.NewScryptRecipient(passphrase)
+		if err != nil {
+			return nil, err
+		}
+		return []age.Recipient{r}, nil
+	}
+	return nil, fmt.Errorf("encryption requires a recipient public key or the %s environment variable", PassphraseEnvVar)
+}
+
+func (c *Cipher) identities() ([]age.Identity, error) {
+	var identities []age.Identity
+	if c.cfg.EncryptionIdentity != "" {
+		f, err := os.Open(c.cfg.EncryptionIdentity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read identity file: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		ids, err := age.ParseIdentities(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse identity file %q: %w", c.cfg.EncryptionIdentity, err)
+		}
+		identities = append(identities, ids...)
+	}
+	if passphrase := c.getenv(PassphraseEnvVar); passphrase != "" {
+		id, err := age.NewScryptIdentity(passphrase)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, id)
+	}
+	if len(identities) == 0 {
+		return nil, errors.New("file is encrypted, provide an identity file or set the " + PassphraseEnvVar + " environment variable")
+	}
+	return identities, nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
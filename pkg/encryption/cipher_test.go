@@ -0,0 +1,149 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package encryption_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/encryption"
+)
+
+func TestCipher_Files(t *testing.T) {
+	id, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	other, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	dir := t.TempDir()
+	identityFile := filepath.Join(dir, "si-migrator.key")
+	require.NoError(t, os.WriteFile(identityFile, []byte("# public key: "+id.Recipient().String()+"\n"+id.String()+"\n"), 0600))
+
+	tests := []struct {
+		name          string
+		passphrase    string
+		exportConfig  *config.Config
+		importConfig  *config.Config
+		wantEncrypted bool
+		wantErr       string
+	}{
+		{
+			name:         "leaves files as they are when encryption is disabled",
+			exportConfig: &config.Config{},
+			importConfig: &config.Config{},
+		},
+		{
+			name:          "encrypts to the recipient",
+			exportConfig:  &config.Config{Encrypt: true, EncryptionRecipient: id.Recipient().String()},
+			importConfig:  &config.Config{EncryptionIdentity: identityFile},
+			wantEncrypted: true,
+		},
+		{
+			name:          "encrypts with the passphrase",
+			passphrase:    "correct horse",
+			exportConfig:  &config.Config{Encrypt: true},
+			importConfig:  &config.Config{},
+			wantEncrypted: true,
+		},
+		{
+			name:          "fails to decrypt with another key",
+			exportConfig:  &config.Config{Encrypt: true, EncryptionRecipient: other.Recipient().String()},
+			importConfig:  &config.Config{EncryptionIdentity: identityFile},
+			wantEncrypted: true,
+			wantErr:       "no identity matched any of the recipients",
+		},
+		{
+			name:          "fails to decrypt without a key",
+			exportConfig:  &config.Config{Encrypt: true, EncryptionRecipient: id.Recipient().String()},
+			importConfig:  &config.Config{},
+			wantEncrypted: true,
+			wantErr:       "provide an identity file or set the " + encryption.PassphraseEnvVar,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(encryption.PassphraseEnvVar, tt.passphrase)
+			plaintext := []byte("backup contents")
+			file := filepath.Join(t.TempDir(), "dump.rdb")
+			require.NoError(t, os.WriteFile(file, plaintext, 0600))
+
+			require.NoError(t, encryption.NewCipher(tt.exportConfig).EncryptFile(file))
+			b, err := os.ReadFile(file)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantEncrypted, encryption.IsEncrypted(b))
+
+			c := encryption.FromContext(config.ContextWithConfig(context.Background(), tt.importConfig))
+			got, cleanup, err := c.DecryptFile(file)
+			defer cleanup()
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantEncrypted, got != file)
+			b, err = os.ReadFile(got)
+			require.NoError(t, err)
+			require.Equal(t, plaintext, b)
+
+			b, err = c.ReadFile(file)
+			require.NoError(t, err)
+			require.Equal(t, plaintext, b)
+		})
+	}
+}
+
+func TestCipher_Validate(t *testing.T) {
+	t.Setenv(encryption.PassphraseEnvVar, "")
+
+	tests := []struct {
+		name    string
+		config  *config.Config
+		wantErr string
+	}{
+		{
+			name:   "nothing to validate when encryption is disabled",
+			config: &config.Config{},
+		},
+		{
+			name:    "requires a key when encryption is enabled",
+			config:  &config.Config{Encrypt: true},
+			wantErr: "encryption requires a recipient public key",
+		},
+		{
+			name:    "rejects a malformed recipient",
+			config:  &config.Config{Encrypt: true, EncryptionRecipient: "not-a-key"},
+			wantErr: "malformed public key",
+		},
+		{
+			name:    "rejects a missing identity file",
+			config:  &config.Config{EncryptionIdentity: filepath.Join(t.TempDir(), "missing.key")},
+			wantErr: "failed to read identity file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := encryption.NewCipher(tt.config).Validate()
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// Package encryption encrypts the files written to an export directory in the age format
+// (https://age-encryption.org/v1), either to an X25519 public key or to a passphrase. Encrypted files can also be
+// decrypted with the age command line tool.
+package encryption
+
+import (
+	"bytes"
+)
+
+const header = "age-encryption.org/v1"
+
+// IsEncrypted returns true if data starts with the header of an encrypted file
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(header+"\n"))
+}
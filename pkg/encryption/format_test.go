@@ -0,0 +1,40 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package encryption_test
+
+import (
+	"bytes"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/encryption"
+)
+
+func TestIsEncrypted(t *testing.T) {
+	id, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, id.Recipient())
+	require.NoError(t, err)
+	_, err = w.Write([]byte("name: si-name\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	require.True(t, encryption.IsEncrypted(buf.Bytes()))
+	require.False(t, encryption.IsEncrypted([]byte("name: si-name\n")))
+	require.False(t, encryption.IsEncrypted(nil))
+}
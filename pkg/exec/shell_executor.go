@@ -130,7 +130,7 @@ func (e *ShellScriptExecutor) LastResult() Result {
 }
 
 func (e *ShellScriptExecutor) printInput(writer io.Writer, input string) error {
-	_, err := fmt.Fprintln(writer, input)
+	_, err := fmt.Fprintln(writer, log.Redact(input))
 	if err != nil {
 		return errors.Wrap(err, fmt.Sprintf("failed to print %q", input))
 	}
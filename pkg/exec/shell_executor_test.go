@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
 )
 
 func TestNewExecutor(t *testing.T) {
@@ -97,3 +98,13 @@ func TestShellScriptExecutor_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestShellScriptExecutor_printInputRedactsSecrets(t *testing.T) {
+	log.RegisterSecrets("opsman-s3cret")
+	defer log.ClearSecrets()
+
+	var out bytes.Buffer
+	err := (&ShellScriptExecutor{dryRun: true}).printInput(&out, "om -u admin -p 'opsman-s3cret' curl -p /api/v0/info")
+	require.NoError(t, err)
+	require.Equal(t, "om -u admin -p '[REDACTED]' curl -p /api/v0/info\n", out.String())
+}
@@ -17,6 +17,7 @@ package exec
 import (
 	"fmt"
 	"github.com/pkg/errors"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
 	"os/exec"
 	"strings"
 	"time"
@@ -56,7 +57,7 @@ func newStatus(cmd *exec.Cmd, script string, body string, output string, err err
 }
 
 func (s *Status) Sprintf(msg string) string {
-	return fmt.Sprintf("%s: error: %s, script: %q, body: %q, output: %q, exit code: %d", msg, s.Error, s.ScriptName, s.ScriptBody, s.Output, s.ExitCode)
+	return log.Redact(fmt.Sprintf("%s: error: %s, script: %q, body: %q, output: %q, exit code: %d", msg, s.Error, s.ScriptName, s.ScriptBody, s.Output, s.ExitCode))
 }
 
 func StatusError(err error) error {
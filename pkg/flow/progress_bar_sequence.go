@@ -20,12 +20,14 @@ import (
 	"github.com/vbauerster/mpb/v7"
 	"github.com/vbauerster/mpb/v7/decor"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
 )
 
 type ProgressBarStep struct {
-	stepFn  StepFunc
-	display string
-	bar     *mpb.Bar
+	stepFn    StepFunc
+	display   string
+	bar       *mpb.Bar
+	alwaysRun bool
 }
 
 func (p ProgressBarStep) String() string {
@@ -54,6 +56,14 @@ func WithDisplay(display string) ProgressBarOption {
 	}
 }
 
+// WithAlwaysRun marks a step that must run even when a resumed migration has already
+// completed it, e.g. logging in or fetching credentials that later steps rely on
+func WithAlwaysRun() ProgressBarOption {
+	return func(step *ProgressBarStep) {
+		step.alwaysRun = true
+	}
+}
+
 func ProgressBarSequence(msg string, steps ...*ProgressBarStep) Flow {
 	return StepFunc(func(ctx context.Context, data interface{}, dryRun bool) (Result, error) {
 		var bar *mpb.Bar
@@ -67,14 +77,42 @@ func ProgressBarSequence(msg string, steps ...*ProgressBarStep) Flow {
 			)
 		}
 
+		tracker, track := config.TrackerFromContext(ctx)
+		next := 0
+		if track {
+			next = tracker.NextStep()
+		}
+
 		var res Result
 		var err error
-		for _, step := range steps {
+		for i, step := range steps {
 			step.bar = bar
+			if i < next && !step.alwaysRun {
+				log.Debugf("Skipping %q, already completed in a previous run", step.display)
+				if bar != nil {
+					bar.Increment()
+				}
+				continue
+			}
 			res, err = step.stepFn(ctx, data, dryRun)
 			if err != nil {
+				if track {
+					failed := i
+					if failed < next {
+						// keep the progress made by the previous run
+						failed = next
+					}
+					if jerr := tracker.StepFailed(failed, step.display, err); jerr != nil {
+						log.Warnf("failed to record step %q in journal: %v", step.display, jerr)
+					}
+				}
 				return res, err
 			}
+			if track && i >= next {
+				if jerr := tracker.StepSucceeded(i, step.display); jerr != nil {
+					log.Warnf("failed to record step %q in journal: %v", step.display, jerr)
+				}
+			}
 			if bar != nil {
 				bar.Increment()
 			}
@@ -17,12 +17,15 @@ package flow_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/vbauerster/mpb/v7"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/flow"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/journal"
+	"path/filepath"
 	"testing"
 )
 
@@ -91,3 +94,37 @@ func TestProgressBarSequence(t *testing.T) {
 		})
 	}
 }
+
+func TestProgressBarSequenceResumesAtFailedStep(t *testing.T) {
+	j, err := journal.Open(filepath.Join(t.TempDir(), journal.ImportFile), false)
+	require.NoError(t, err)
+	ctx := config.ContextWithTracker(context.TODO(), j.Track("org", "space", "instance"))
+
+	var calls []string
+	fail := true
+	step := func(name string) flow.StepFunc {
+		return func(ctx context.Context, data interface{}, dryRun bool) (flow.Result, error) {
+			calls = append(calls, name)
+			if name == "step 3" && fail {
+				return nil, errors.New("step 3 failed")
+			}
+			return nil, nil
+		}
+	}
+	seq := flow.ProgressBarSequence("Importing",
+		flow.StepWithProgressBar(step("login"), flow.WithDisplay("login"), flow.WithAlwaysRun()),
+		flow.StepWithProgressBar(step("step 2"), flow.WithDisplay("step 2")),
+		flow.StepWithProgressBar(step("step 3"), flow.WithDisplay("step 3")),
+		flow.StepWithProgressBar(step("step 4"), flow.WithDisplay("step 4")),
+	)
+
+	_, err = flow.RunWith(seq, ctx, nil, false)
+	require.Error(t, err)
+	require.Equal(t, []string{"login", "step 2", "step 3"}, calls)
+
+	calls = nil
+	fail = false
+	_, err = flow.RunWith(seq, ctx, nil, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{"login", "step 3", "step 4"}, calls)
+}
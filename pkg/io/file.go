@@ -34,14 +34,32 @@ type FileDescriptor struct {
 
 const pathSep = string(os.PathSeparator)
 
-var supportedExtensions = []string{".yml", ".yaml"}
+var supportedExtensions = []string{".yml", ".yaml", ".json"}
+
+const (
+	// YAMLFormat writes exported files as yaml, the default
+	YAMLFormat = "yaml"
+	// JSONFormat writes exported files as json
+	JSONFormat = "json"
+)
+
+// FormatExtension returns the extension of the files written in the given export format
+func FormatExtension(format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "", YAMLFormat:
+		return "yml", nil
+	case JSONFormat:
+		return "json", nil
+	}
+	return "", fmt.Errorf("unsupported export format %q, must be one of %s or %s", format, YAMLFormat, JSONFormat)
+}
 
 type InvalidFileExtensionError struct {
 	Ext string
 }
 
 func (e *InvalidFileExtensionError) Error() string {
-	return fmt.Sprintf("extension %q does not match yml or yaml", e.Ext)
+	return fmt.Sprintf("extension %q does not match yml, yaml or json", e.Ext)
 }
 
 func NewFileDescriptor(path string) (FileDescriptor, error) {
@@ -74,6 +92,9 @@ func isSupported(ext string) bool {
 
 func GetOrgSpace(path string) (string, string) {
 	parts := strings.Split(path, pathSep)
+	if len(parts) < 3 {
+		return "", ""
+	}
 	orgSpace := parts[len(parts)-3 : len(parts)-1]
 
 	return orgSpace[0], orgSpace[1]
@@ -115,11 +136,18 @@ func CopyToTempFile(src io.Reader) (*os.File, error) {
 	return file, nil
 }
 
+// Tar writes src to a tar archive. When src is a directory, files are stored under their path relative to src.
 func Tar(src string, writers ...io.Writer) error {
-	if _, err := os.Stat(src); err != nil {
+	info, err := os.Stat(src)
+	if err != nil {
 		return fmt.Errorf("failed to tar files in %s, %v", src, err)
 	}
 
+	base := src
+	if !info.IsDir() {
+		base = filepath.Dir(src)
+	}
+
 	mw := io.MultiWriter(writers...)
 	tw := tar.NewWriter(mw)
 	defer func(tw *tar.Writer) {
@@ -140,7 +168,11 @@ func Tar(src string, writers ...io.Writer) error {
 			return err
 		}
 
-		header.Name = fi.Name()
+		rel, err := filepath.Rel(base, file)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
 
 		if err := tw.WriteHeader(header); err != nil {
 			return err
@@ -161,8 +193,10 @@ func Tar(src string, writers ...io.Writer) error {
 	})
 }
 
+// Untar extracts a tar archive into dst, creating the directories of the files it contains
 func Untar(dst string, r io.Reader) error {
 	tr := tar.NewReader(r)
+	root := filepath.Clean(dst) + pathSep
 
 	for {
 		hdr, err := tr.Next()
@@ -178,6 +212,9 @@ func Untar(dst string, r io.Reader) error {
 		}
 
 		target := filepath.Join(dst, hdr.Name)
+		if !strings.HasPrefix(target+pathSep, root) {
+			return fmt.Errorf("tar entry %q is outside of %s", hdr.Name, dst)
+		}
 
 		switch hdr.Typeflag {
 		case tar.TypeDir:
@@ -187,7 +224,10 @@ func Untar(dst string, r io.Reader) error {
 				}
 			}
 		case tar.TypeReg:
-			f, err := os.OpenFile(target, os.O_CREATE|os.O_RDWR, os.FileMode(hdr.Mode))
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_RDWR|os.O_TRUNC, os.FileMode(hdr.Mode))
 			if err != nil {
 				return err
 			}
@@ -15,6 +15,8 @@
 package io
 
 import (
+	"archive/tar"
+	"bytes"
 	"fmt"
 	"github.com/stretchr/testify/require"
 	"io"
@@ -117,6 +119,14 @@ func TestGetOrgSpace(t *testing.T) {
 			org:   "cloudfoundry",
 			space: "test-app",
 		},
+		{
+			name: "returns no org or space for a file without parent directories",
+			args: args{
+				path: "mysqldb.yml",
+			},
+			org:   "",
+			space: "",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -188,7 +198,20 @@ func TestNewFileDescriptor(t *testing.T) {
 			},
 		},
 		{
-			name: "returns error if not yaml extension",
+			name: "creates a file descriptor for a json file",
+			args: args{
+				path: filepath.Join(pwd, "testdata", "cloudfoundry", "test-app", "cups-test.json"),
+			},
+			want: FileDescriptor{
+				Name:      "cups-test",
+				Org:       "cloudfoundry",
+				Space:     "test-app",
+				BaseDir:   filepath.Join(pwd, "testdata"),
+				Extension: "json",
+			},
+		},
+		{
+			name: "returns error if not yaml or json extension",
 			args: args{
 				path: filepath.Join(pwd, "testdata", "backup.tar"),
 			},
@@ -247,3 +270,59 @@ func TestTarUntar(t *testing.T) {
 		require.NoError(t, err, fmt.Sprintf("failed to untar file '%s'", tarFilePath))
 	}
 }
+
+func TestTarUntar_Directory(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "org1", "space1"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "org1", "space1", "db.yml"), []byte("name: db"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "index.json"), []byte("{}"), 0644))
+
+	var buf bytes.Buffer
+	require.NoError(t, Tar(src, &buf))
+
+	dst := t.TempDir()
+	require.NoError(t, Untar(dst, &buf))
+
+	b, err := os.ReadFile(filepath.Join(dst, "org1", "space1", "db.yml"))
+	require.NoError(t, err)
+	require.Equal(t, "name: db", string(b))
+	require.FileExists(t, filepath.Join(dst, "index.json"))
+}
+
+func TestUntar_RejectsPathsOutsideDst(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "../escape.txt", Mode: 0644, Size: 4, Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte("oops"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	dst := filepath.Join(t.TempDir(), "dst")
+	err = Untar(dst, &buf)
+	require.Error(t, err)
+	require.NoFileExists(t, filepath.Join(filepath.Dir(dst), "escape.txt"))
+}
+
+func TestFormatExtension(t *testing.T) {
+	tests := []struct {
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{format: "", want: "yml"},
+		{format: "yaml", want: "yml"},
+		{format: "JSON", want: "json"},
+		{format: "xml", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			got, err := FormatExtension(tt.format)
+			if tt.wantErr {
+				require.EqualError(t, err, `unsupported export format "xml", must be one of yaml or json`)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
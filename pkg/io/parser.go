@@ -15,19 +15,42 @@
 package io
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
+	"io"
 	"os"
 	"path"
 	"strings"
 )
 
+// Cipher encrypts the files written by a Parser and decrypts the files it reads
+type Cipher interface {
+	Encrypt(dst io.Writer) (io.WriteCloser, error)
+	Decrypt(src io.Reader) (io.Reader, error)
+}
+
+type ParserOption func(*Parser)
+
+// WithCipher encrypts marshalled files and decrypts unmarshalled files with the given cipher
+func WithCipher(c Cipher) ParserOption {
+	return func(p *Parser) {
+		p.cipher = c
+	}
+}
+
 type Parser struct {
+	cipher Cipher
 }
 
-func NewParser() *Parser {
-	return &Parser{}
+func NewParser(opts ...ParserOption) *Parser {
+	p := &Parser{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
 
 func (p *Parser) Unmarshal(out interface{}, fd FileDescriptor) error {
@@ -37,7 +60,38 @@ func (p *Parser) Unmarshal(out interface{}, fd FileDescriptor) error {
 		return errors.Wrap(err, fmt.Sprintf("cannot open file: %s", file))
 	}
 
-	err = yaml.Unmarshal(data, out)
+	if p.cipher != nil {
+		r, err := p.cipher.Decrypt(bytes.NewReader(data))
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("cannot decrypt file: %s", file))
+		}
+		data, err = io.ReadAll(r)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("cannot decrypt file: %s", file))
+		}
+	}
+
+	var doc map[string]interface{}
+	err = decode(data, &doc, fd.Extension)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("cannot unmarshal data: %v", err))
+	}
+
+	version, err := schemaVersion(doc, file)
+	if err != nil {
+		return err
+	}
+
+	if version < SchemaVersion && doc != nil {
+		if err = upgradeSchema(doc, version); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("cannot read file: %s", file))
+		}
+		if data, err = encode(doc, fd.Extension); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("cannot read file: %s", file))
+		}
+	}
+
+	err = decode(data, out, fd.Extension)
 	if err != nil {
 		return errors.Wrap(err, fmt.Sprintf("cannot unmarshal data: %v", err))
 	}
@@ -45,15 +99,47 @@ func (p *Parser) Unmarshal(out interface{}, fd FileDescriptor) error {
 	return nil
 }
 
+// Marshal writes in to the file of the descriptor with the current schema version, so it can be upgraded when a
+// later release reads it back
 func (p *Parser) Marshal(in interface{}, fd FileDescriptor) error {
-	b, err := yaml.Marshal(in)
+	b, err := marshalVersioned(in, fd.Extension)
 	if err != nil {
 		return errors.Wrap(err, fmt.Sprintf("cannot marshal data: %v", in))
 	}
 
+	return p.write(b, fd)
+}
+
+// MarshalUnversioned writes in to the file of the descriptor as is, for files such as app manifests that are
+// read by other tools rather than by the parser
+func (p *Parser) MarshalUnversioned(in interface{}, fd FileDescriptor) error {
+	b, err := marshalUnversioned(in, fd.Extension)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("cannot marshal data: %v", in))
+	}
+
+	return p.write(b, fd)
+}
+
+func (p *Parser) write(b []byte, fd FileDescriptor) error {
+	if p.cipher != nil {
+		var buf bytes.Buffer
+		w, err := p.cipher.Encrypt(&buf)
+		if err != nil {
+			return errors.Wrap(err, "cannot encrypt data")
+		}
+		if _, err = w.Write(b); err != nil {
+			return errors.Wrap(err, "cannot encrypt data")
+		}
+		if err = w.Close(); err != nil {
+			return errors.Wrap(err, "cannot encrypt data")
+		}
+		b = buf.Bytes()
+	}
+
 	d := NewFileSystemHelper()
 	dir := path.Join(fd.BaseDir, fd.Org, fd.Space)
-	err = d.Mkdir(dir)
+	err := d.Mkdir(dir)
 	if err != nil {
 		return errors.Wrap(err, fmt.Sprintf("cannot create directory: %s", dir))
 	}
@@ -66,3 +152,100 @@ func (p *Parser) Marshal(in interface{}, fd FileDescriptor) error {
 
 	return nil
 }
+
+// marshalUnversioned marshals in without a schema version, indenting json like the versioned files
+func marshalUnversioned(in interface{}, ext string) ([]byte, error) {
+	b, err := encode(in, ext)
+	if err != nil || !isJSON(ext) {
+		return b, err
+	}
+
+	var out bytes.Buffer
+	if err = json.Indent(&out, bytes.TrimSpace(b), "", "  "); err != nil {
+		return nil, err
+	}
+	out.WriteByte('\n')
+
+	return out.Bytes(), nil
+}
+
+// marshalVersioned marshals in with the current schema version as its first key
+func marshalVersioned(in interface{}, ext string) ([]byte, error) {
+	if isJSON(ext) {
+		return marshalVersionedJSON(in)
+	}
+
+	b, err := yaml.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.MapSlice
+	if err = yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(append(yaml.MapSlice{{Key: SchemaVersionKey, Value: SchemaVersion}}, doc...))
+}
+
+// marshalVersionedJSON marshals in to indented json, adding the schema version in front of the fields of the
+// object so they keep the order of the struct
+func marshalVersionedJSON(in interface{}) ([]byte, error) {
+	b, err := encode(in, JSONFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	b = bytes.TrimSpace(b)
+	if bytes.Equal(b, []byte("null")) {
+		b = []byte("{}")
+	}
+	if len(b) < 2 || b[0] != '{' {
+		return nil, fmt.Errorf("cannot add %s to %s, only objects are supported", SchemaVersionKey, b)
+	}
+
+	var versioned bytes.Buffer
+	versioned.WriteString(fmt.Sprintf(`{%q:%d`, SchemaVersionKey, SchemaVersion))
+	if len(b) > 2 {
+		versioned.WriteByte(',')
+	}
+	versioned.Write(b[1:])
+
+	var out bytes.Buffer
+	if err = json.Indent(&out, versioned.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	out.WriteByte('\n')
+
+	return out.Bytes(), nil
+}
+
+func isJSON(ext string) bool {
+	return ext == JSONFormat
+}
+
+func encode(in interface{}, ext string) ([]byte, error) {
+	if !isJSON(ext) {
+		return yaml.Marshal(in)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(in); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decode unmarshals data in the encoding of the extension. Json numbers are kept as json.Number so large integers
+// in credentials and parameters survive a schema upgrade unchanged.
+func decode(data []byte, out interface{}, ext string) error {
+	if !isJSON(ext) {
+		return yaml.Unmarshal(data, out)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(out)
+}
@@ -15,7 +15,11 @@
 package io_test
 
 import (
+	"encoding/json"
 	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/encryption"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/io"
 	"os"
 	"path"
@@ -52,13 +56,40 @@ func TestParser_Unmarshal(t *testing.T) {
 	err = os.WriteFile(path.Join(p, "si-name.yml"), []byte(`name: si-name
 guid: maybe-a-guid
 type: user-provided
+`), 0755)
+	require.NoError(t, err)
+	err = os.WriteFile(path.Join(p, "versioned.yml"), []byte(`schema_version: 1
+name: si-name
+guid: maybe-a-guid
+type: user-provided
+`), 0755)
+	require.NoError(t, err)
+	err = os.WriteFile(path.Join(p, "newer.yml"), []byte(`schema_version: 2
+name: si-name
+`), 0755)
+	require.NoError(t, err)
+	err = os.WriteFile(path.Join(p, "invalid.yml"), []byte(`schema_version: latest
+name: si-name
 `), 0755)
 	require.NoError(t, err)
 
+	err = os.WriteFile(path.Join(p, "si-name.json"), []byte(`{
+  "schema_version": 1,
+  "name": "si-name",
+  "guid": "maybe-a-guid",
+  "type": "user-provided"
+}
+`), 0755)
+	require.NoError(t, err)
+	err = os.WriteFile(path.Join(p, "unversioned.json"), []byte(`{"name": "si-name", "guid": "maybe-a-guid", "type": "user-provided"}`), 0755)
+	require.NoError(t, err)
+	err = os.WriteFile(path.Join(p, "newer.json"), []byte(`{"schema_version": 2, "name": "si-name"}`), 0755)
+	require.NoError(t, err)
+
 	type serviceInstance struct {
-		Name string `yaml:"name,omitempty"`
-		GUID string `yaml:"guid,omitempty"`
-		Type string `yaml:"type,omitempty"`
+		Name string `yaml:"name,omitempty" json:"name,omitempty"`
+		GUID string `yaml:"guid,omitempty" json:"guid,omitempty"`
+		Type string `yaml:"type,omitempty" json:"type,omitempty"`
 	}
 
 	type args struct {
@@ -90,6 +121,108 @@ type: user-provided
 				Type: "user-provided",
 			},
 		},
+		{
+			name: "unmarshal yaml file with the current schema version",
+			args: args{
+				b: &serviceInstance{},
+				fd: io.FileDescriptor{
+					Name:      "versioned",
+					Org:       "org",
+					Space:     "spacey",
+					BaseDir:   tmpDir,
+					Extension: "yml",
+				},
+			},
+			wantErr: false,
+			want: &serviceInstance{
+				Name: "si-name",
+				GUID: "maybe-a-guid",
+				Type: "user-provided",
+			},
+		},
+		{
+			name: "rejects a newer schema version",
+			args: args{
+				b: &serviceInstance{},
+				fd: io.FileDescriptor{
+					Name:      "newer",
+					Org:       "org",
+					Space:     "spacey",
+					BaseDir:   tmpDir,
+					Extension: "yml",
+				},
+			},
+			wantErr: true,
+			want:    &serviceInstance{},
+		},
+		{
+			name: "rejects an invalid schema version",
+			args: args{
+				b: &serviceInstance{},
+				fd: io.FileDescriptor{
+					Name:      "invalid",
+					Org:       "org",
+					Space:     "spacey",
+					BaseDir:   tmpDir,
+					Extension: "yml",
+				},
+			},
+			wantErr: true,
+			want:    &serviceInstance{},
+		},
+		{
+			name: "unmarshal json file into struct",
+			args: args{
+				b: &serviceInstance{},
+				fd: io.FileDescriptor{
+					Name:      "si-name",
+					Org:       "org",
+					Space:     "spacey",
+					BaseDir:   tmpDir,
+					Extension: "json",
+				},
+			},
+			wantErr: false,
+			want: &serviceInstance{
+				Name: "si-name",
+				GUID: "maybe-a-guid",
+				Type: "user-provided",
+			},
+		},
+		{
+			name: "unmarshal json file without a schema version",
+			args: args{
+				b: &serviceInstance{},
+				fd: io.FileDescriptor{
+					Name:      "unversioned",
+					Org:       "org",
+					Space:     "spacey",
+					BaseDir:   tmpDir,
+					Extension: "json",
+				},
+			},
+			wantErr: false,
+			want: &serviceInstance{
+				Name: "si-name",
+				GUID: "maybe-a-guid",
+				Type: "user-provided",
+			},
+		},
+		{
+			name: "rejects a newer schema version in a json file",
+			args: args{
+				b: &serviceInstance{},
+				fd: io.FileDescriptor{
+					Name:      "newer",
+					Org:       "org",
+					Space:     "spacey",
+					BaseDir:   tmpDir,
+					Extension: "json",
+				},
+			},
+			wantErr: true,
+			want:    &serviceInstance{},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -139,7 +272,8 @@ func TestParser_Marshal(t *testing.T) {
 				},
 			},
 			wantErr: false,
-			want: `name: si-name
+			want: `schema_version: 1
+name: si-name
 guid: maybe-a-guid
 type: user-provided
 `,
@@ -157,3 +291,109 @@ type: user-provided
 		})
 	}
 }
+
+func TestParser_MarshalJSON(t *testing.T) {
+	dir := t.TempDir()
+	fd := io.FileDescriptor{
+		Name:      "si-name",
+		Org:       "org",
+		Space:     "spacey",
+		BaseDir:   dir,
+		Extension: "json",
+	}
+	in := &cf.ServiceInstance{
+		Name:        "si-name",
+		GUID:        "maybe-a-guid",
+		Type:        "user-provided",
+		Credentials: map[string]interface{}{"uri": "https://example.com?a=1&b=2", "port": 12345678901234567},
+		Params:      map[string]interface{}{"size": 3},
+	}
+
+	err := io.NewParser().Marshal(in, fd)
+	require.NoError(t, err)
+
+	b, err := os.ReadFile(path.Join(dir, "org", "spacey", "si-name.json"))
+	require.NoError(t, err)
+	require.Equal(t, `{
+  "schema_version": 1,
+  "name": "si-name",
+  "guid": "maybe-a-guid",
+  "type": "user-provided",
+  "params": {
+    "size": 3
+  },
+  "credentials": {
+    "port": 12345678901234567,
+    "uri": "https://example.com?a=1&b=2"
+  },
+  "app_manifest": {
+    "applications": null
+  }
+}
+`, string(b))
+
+	out := &cf.ServiceInstance{}
+	err = io.NewParser().Unmarshal(out, fd)
+	require.NoError(t, err)
+	require.Equal(t, "si-name", out.Name)
+	require.Equal(t, "https://example.com?a=1&b=2", out.Credentials["uri"])
+	require.Equal(t, json.Number("12345678901234567"), out.Credentials["port"])
+}
+
+func TestParser_MarshalUnversioned(t *testing.T) {
+	dir := t.TempDir()
+	in := &cf.Manifest{
+		Applications: []cf.Application{{Name: "some-app"}},
+	}
+
+	for _, ext := range []string{"yml", "json"} {
+		fd := io.FileDescriptor{
+			Name:      "some-app_manifest",
+			Org:       "org",
+			Space:     "spacey",
+			BaseDir:   dir,
+			Extension: ext,
+		}
+		err := io.NewParser().MarshalUnversioned(in, fd)
+		require.NoError(t, err)
+
+		b, err := os.ReadFile(path.Join(dir, "org", "spacey", "some-app_manifest."+ext))
+		require.NoError(t, err)
+		require.NotContains(t, string(b), io.SchemaVersionKey)
+		require.Contains(t, string(b), "some-app")
+	}
+}
+
+func TestParser_MarshalWithCipher(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(encryption.PassphraseEnvVar, "correct horse")
+
+	type serviceInstance struct {
+		Name string `yaml:"name,omitempty"`
+		GUID string `yaml:"guid,omitempty"`
+	}
+	fd := io.FileDescriptor{
+		Name:      "si-name",
+		Org:       "org",
+		Space:     "spacey",
+		BaseDir:   dir,
+		Extension: "yml",
+	}
+	in := &serviceInstance{Name: "si-name", GUID: "maybe-a-guid"}
+
+	err := io.NewParser(io.WithCipher(encryption.NewCipher(&config.Config{Encrypt: true}))).Marshal(in, fd)
+	require.NoError(t, err)
+
+	b, err := os.ReadFile(path.Join(dir, "org", "spacey", "si-name.yml"))
+	require.NoError(t, err)
+	require.True(t, encryption.IsEncrypted(b))
+	require.NotContains(t, string(b), "maybe-a-guid")
+
+	out := &serviceInstance{}
+	err = io.NewParser().Unmarshal(out, fd)
+	require.Error(t, err, "parser without a cipher should not read an encrypted file")
+
+	err = io.NewParser(io.WithCipher(encryption.NewCipher(&config.Config{}))).Unmarshal(out, fd)
+	require.NoError(t, err)
+	require.Equal(t, in, out)
+}
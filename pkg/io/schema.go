@@ -0,0 +1,76 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package io
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SchemaVersion is the version of the layout of the files written by a Parser. Increase it whenever an exported
+// field is renamed, removed or changes type, and append an upgrade from the previous version to upgrades.
+const SchemaVersion = 1
+
+// SchemaVersionKey is the top level key holding the schema version of every exported file
+const SchemaVersionKey = "schema_version"
+
+// upgrade converts a document of one schema version to the layout of the next version
+type upgrade func(doc map[string]interface{}) error
+
+// upgrades holds the upgrade from version i to version i+1 at index i. Files written before schema_version was
+// introduced have no version and are read as version 0.
+var upgrades = []upgrade{
+	// version 0 has the same layout as version 1
+	func(doc map[string]interface{}) error { return nil },
+}
+
+// UnsupportedSchemaVersionError is returned when a file was written with a schema this release cannot read
+type UnsupportedSchemaVersionError struct {
+	File    string
+	Version interface{}
+}
+
+func (e *UnsupportedSchemaVersionError) Error() string {
+	return fmt.Sprintf("file %s has schema version %v, this release reads schema versions up to %d, use a newer release to import it", e.File, e.Version, SchemaVersion)
+}
+
+// schemaVersion returns the schema version of a document, 0 when it has none
+func schemaVersion(doc map[string]interface{}, file string) (int, error) {
+	v, ok := doc[SchemaVersionKey]
+	if !ok {
+		return 0, nil
+	}
+
+	version, ok := v.(int)
+	if n, isNumber := v.(json.Number); isNumber {
+		i, err := n.Int64()
+		version, ok = int(i), err == nil
+	}
+	if !ok || version < 0 || version > SchemaVersion {
+		return 0, &UnsupportedSchemaVersionError{File: file, Version: v}
+	}
+
+	return version, nil
+}
+
+// upgradeSchema converts a document of the given schema version to the current layout
+func upgradeSchema(doc map[string]interface{}, version int) error {
+	for v := version; v < SchemaVersion; v++ {
+		if err := upgrades[v](doc); err != nil {
+			return fmt.Errorf("cannot upgrade from schema version %d: %w", v, err)
+		}
+	}
+	doc[SchemaVersionKey] = SchemaVersion
+	return nil
+}
@@ -0,0 +1,57 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package io
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser_UnmarshalUpgradesOlderSchema(t *testing.T) {
+	saved := upgrades
+	t.Cleanup(func() { upgrades = saved })
+	upgrades = []upgrade{
+		func(doc map[string]interface{}) error {
+			doc["name"] = doc["instance_name"]
+			delete(doc, "instance_name")
+			return nil
+		},
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "org", "space"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "org", "space", "si.yml"), []byte("instance_name: si-name\n"), 0644))
+
+	out := &struct {
+		Name string `yaml:"name"`
+	}{}
+	err := NewParser().Unmarshal(out, FileDescriptor{BaseDir: dir, Org: "org", Space: "space", Name: "si", Extension: "yml"})
+	require.NoError(t, err)
+	require.Equal(t, "si-name", out.Name)
+}
+
+func TestParser_UnmarshalRejectsNewerSchema(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "org", "space"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "org", "space", "si.yml"), []byte("schema_version: 99\nname: si-name\n"), 0644))
+
+	err := NewParser().Unmarshal(&struct{}{}, FileDescriptor{BaseDir: dir, Org: "org", Space: "space", Name: "si", Extension: "yml"})
+	var versionErr *UnsupportedSchemaVersionError
+	require.True(t, errors.As(err, &versionErr))
+	require.Equal(t, 99, versionErr.Version)
+}
@@ -154,6 +154,7 @@ func createLogger() *Log {
 	}
 	logger.SetOutput(f)
 	logger.SetReportCaller(true)
+	logger.AddHook(redactHook{})
 
 	return &Log{
 		Logger: logger,
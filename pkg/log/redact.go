@@ -0,0 +1,156 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package log
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Redacted replaces every registered secret in log messages and printed scripts
+const Redacted = "[REDACTED]"
+
+// minCredentialLength keeps short credential values such as "true" or port numbers from masking unrelated text
+const minCredentialLength = 6
+
+var secrets = &secretRegistry{values: make(map[string]struct{})}
+
+type secretRegistry struct {
+	mu       sync.RWMutex
+	values   map[string]struct{}
+	replacer *strings.Replacer
+}
+
+// RegisterSecrets masks the given values wherever they are logged or printed from now on
+func RegisterSecrets(values ...string) {
+	secrets.register(values...)
+}
+
+// RegisterCredentials registers every string value in a credentials map, including nested maps and lists
+func RegisterCredentials(creds interface{}) {
+	var values []string
+	collectStrings(creds, &values)
+	long := values[:0]
+	for _, v := range values {
+		if len(v) >= minCredentialLength {
+			long = append(long, v)
+		}
+	}
+	secrets.register(long...)
+}
+
+// ClearSecrets forgets every registered secret
+func ClearSecrets() {
+	secrets.mu.Lock()
+	defer secrets.mu.Unlock()
+	secrets.values = make(map[string]struct{})
+	secrets.replacer = nil
+}
+
+// Redact returns s with every registered secret replaced by Redacted
+func Redact(s string) string {
+	secrets.mu.RLock()
+	defer secrets.mu.RUnlock()
+	if secrets.replacer == nil {
+		return s
+	}
+	return secrets.replacer.Replace(s)
+}
+
+func (r *secretRegistry) register(values ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	added := false
+	for _, v := range values {
+		if strings.TrimSpace(v) == "" {
+			continue
+		}
+		// values logged with %q are escaped, so the escaped form is masked as well
+		for _, form := range []string{v, strings.Trim(strconv.Quote(v), `"`)} {
+			if _, ok := r.values[form]; !ok {
+				r.values[form] = struct{}{}
+				added = true
+			}
+		}
+	}
+	if !added {
+		return
+	}
+
+	all := make([]string, 0, len(r.values))
+	for v := range r.values {
+		all = append(all, v)
+	}
+	// the replacer tries the old strings in order, so a secret containing another one must come first
+	sort.Slice(all, func(i, j int) bool { return len(all[i]) > len(all[j]) })
+
+	oldnew := make([]string, 0, 2*len(all))
+	for _, v := range all {
+		oldnew = append(oldnew, v, Redacted)
+	}
+	r.replacer = strings.NewReplacer(oldnew...)
+}
+
+func collectStrings(v interface{}, values *[]string) {
+	switch t := v.(type) {
+	case string:
+		*values = append(*values, t)
+	case map[string]interface{}:
+		for _, e := range t {
+			collectStrings(e, values)
+		}
+	case map[interface{}]interface{}:
+		for _, e := range t {
+			collectStrings(e, values)
+		}
+	case map[string]string:
+		for _, e := range t {
+			*values = append(*values, e)
+		}
+	case []interface{}:
+		for _, e := range t {
+			collectStrings(e, values)
+		}
+	}
+}
+
+// redactHook masks secrets in the message and fields of every entry before it is formatted
+type redactHook struct{}
+
+func (redactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (redactHook) Fire(e *logrus.Entry) error {
+	e.Message = Redact(e.Message)
+	for k, v := range e.Data {
+		switch t := v.(type) {
+		case string:
+			e.Data[k] = Redact(t)
+		case error:
+			e.Data[k] = errors.New(Redact(t.Error()))
+		case fmt.Stringer:
+			e.Data[k] = Redact(t.String())
+		}
+	}
+	return nil
+}
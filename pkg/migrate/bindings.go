@@ -0,0 +1,272 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/cloudfoundry-community/go-cfclient"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/flow"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
+)
+
+var (
+	serviceInstanceReadyTimeout = 15 * time.Minute
+	serviceInstanceReadyPause   = 10 * time.Second
+)
+
+// exportBindings converts the app bindings of a service instance and returns the names of the bound
+// apps keyed by binding guid, so the bindings can be recreated on import
+func exportBindings(client cf.Client, bindings []cfclient.ServiceBinding, withParams bool) ([]cf.ServiceBinding, map[string]string, error) {
+	configBindings := convertBindings(bindings)
+	apps := make(map[string]string, len(configBindings))
+
+	for i, b := range configBindings {
+		if withParams {
+			params, err := client.GetServiceBindingParams(b.Guid)
+			if err != nil && !cfclient.IsServiceFetchBindingParametersNotSupportedError(err) {
+				return nil, nil, fmt.Errorf("could not fetch parameters for service binding %s: %w", b.Guid, err)
+			}
+			configBindings[i].Parameters = params
+		}
+
+		if b.AppGuid == "" {
+			continue
+		}
+		app, err := client.GetAppByGuidNoInlineCall(b.AppGuid)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not find app %s bound with service binding %s: %w", b.AppGuid, b.Guid, err)
+		}
+		if app.Name != "" {
+			apps[b.Guid] = app.Name
+		}
+	}
+
+	return configBindings, apps, nil
+}
+
+// BindApps recreates the app bindings of the service instance on the target foundation. Each binding is
+// made to the app with the same name as the app it was bound to on the source foundation, using the
+// original binding name and parameters. Apps that have not been pushed to the target are skipped.
+func BindApps(orgName, spaceName string, instance *cf.ServiceInstance, h ClientHolder, isExport bool) flow.StepFunc {
+	return func(ctx context.Context, c interface{}, dryRun bool) (flow.Result, error) {
+		if isExport || len(instance.ServiceBindings) == 0 {
+			return instance, nil
+		}
+
+		client := h.CFClient(isExport)
+
+		org, space, guid, err := findTargetServiceInstance(client, orgName, spaceName, instance)
+		if err != nil {
+			return nil, err
+		}
+
+		ready := false
+		for _, binding := range instance.ServiceBindings {
+			appName, ok := instance.Apps[binding.Guid]
+			if !ok {
+				log.Debugf("No app recorded for service binding %q of %q, skipping", binding.Name, instance.Name)
+				continue
+			}
+
+			app, err := client.AppByName(appName, space.Guid, org.Guid)
+			if err != nil && !cfclient.IsAppNotFoundError(err) {
+				return nil, fmt.Errorf("failed to look up app %q in %s/%s: %w", appName, orgName, spaceName, err)
+			}
+			if err != nil || app.Guid == "" {
+				log.Warnf("App %q not found in %s/%s, skipping its binding to %q", appName, orgName, spaceName, instance.Name)
+				continue
+			}
+
+			if dryRun {
+				log.Infof("Would bind %q to app %q", instance.Name, appName)
+				continue
+			}
+
+			existing, err := client.ListServiceBindingsByQuery(url.Values{
+				"app_guids":              []string{app.Guid},
+				"service_instance_guids": []string{guid},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to look up bindings of app %q to %q: %w", appName, instance.Name, err)
+			}
+			if len(existing) > 0 {
+				log.Debugf("App %q is already bound to %q", appName, instance.Name)
+				continue
+			}
+
+			if !ready {
+				if err = waitForServiceInstance(ctx, client, instance, guid); err != nil {
+					return nil, err
+				}
+				ready = true
+			}
+
+			log.Infof("Binding %q to app %q", instance.Name, appName)
+			_, err = client.CreateServiceBinding(cf.CreateServiceBindingRequest{
+				Name:                binding.Name,
+				AppGuid:             app.Guid,
+				ServiceInstanceGuid: guid,
+				Parameters:          binding.Parameters,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to bind %q to app %q in %s/%s: %w", instance.Name, appName, orgName, spaceName, err)
+			}
+		}
+
+		return instance, nil
+	}
+}
+
+// CreateServiceKeys recreates the service keys of the service instance on the target foundation,
+// unless service keys are ignored
+func CreateServiceKeys(orgName, spaceName string, instance *cf.ServiceInstance, h ClientHolder, isExport bool) flow.StepFunc {
+	return func(ctx context.Context, c interface{}, dryRun bool) (flow.Result, error) {
+		if isExport || len(instance.ServiceKeys) == 0 {
+			return instance, nil
+		}
+
+		if cfg, ok := config.FromContext(ctx); ok && cfg.IgnoreServiceKeys {
+			return instance, nil
+		}
+
+		client := h.CFClient(isExport)
+
+		_, _, guid, err := findTargetServiceInstance(client, orgName, spaceName, instance)
+		if err != nil {
+			return nil, err
+		}
+
+		ready := false
+		for _, key := range instance.ServiceKeys {
+			if dryRun {
+				log.Infof("Would create service key %q for %q", key.Name, instance.Name)
+				continue
+			}
+
+			existing, err := client.ListServiceKeysByQuery(url.Values{
+				"names":                  []string{key.Name},
+				"service_instance_guids": []string{guid},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to look up service key %q of %q: %w", key.Name, instance.Name, err)
+			}
+			if len(existing) > 0 {
+				log.Debugf("Service key %q of %q already exists", key.Name, instance.Name)
+				continue
+			}
+
+			if !ready {
+				if err = waitForServiceInstance(ctx, client, instance, guid); err != nil {
+					return nil, err
+				}
+				ready = true
+			}
+
+			log.Infof("Creating service key %q for %q", key.Name, instance.Name)
+			_, err = client.CreateServiceKey(cfclient.CreateServiceKeyRequest{
+				Name:                key.Name,
+				ServiceInstanceGuid: guid,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to create service key %q for %q in %s/%s: %w", key.Name, instance.Name, orgName, spaceName, err)
+			}
+		}
+
+		return instance, nil
+	}
+}
+
+// findTargetServiceInstance looks the service instance up by name, so steps work the same when
+// a migration is resumed after the instance was created
+func findTargetServiceInstance(client cf.Client, orgName, spaceName string, instance *cf.ServiceInstance) (cfclient.Org, cfclient.Space, string, error) {
+	org, err := client.GetOrgByName(orgName)
+	if err != nil {
+		return cfclient.Org{}, cfclient.Space{}, "", fmt.Errorf("could not find org %q, %w", orgName, err)
+	}
+
+	space, err := client.GetSpaceByName(spaceName, org.Guid)
+	if err != nil {
+		return cfclient.Org{}, cfclient.Space{}, "", fmt.Errorf("could not find find space %q in org %q, %w", spaceName, orgName, err)
+	}
+
+	query := url.Values{
+		"space_guids": []string{space.Guid},
+		"names":       []string{instance.Name},
+	}
+
+	var guid string
+	if ServiceType(instance.Type) == UserProvidedService {
+		ups, err := client.ListUserProvidedServiceInstancesByQuery(query)
+		if err != nil {
+			return org, space, "", fmt.Errorf("failed to look up user-provided-service %q in %s/%s: %w", instance.Name, orgName, spaceName, err)
+		}
+		if len(ups) > 0 {
+			guid = ups[0].Guid
+		}
+	} else {
+		sis, err := client.ListServiceInstancesByQuery(query)
+		if err != nil {
+			return org, space, "", fmt.Errorf("failed to look up service instance %q in %s/%s: %w", instance.Name, orgName, spaceName, err)
+		}
+		if len(sis) > 0 {
+			guid = sis[0].Guid
+		}
+	}
+
+	if guid == "" {
+		return org, space, "", fmt.Errorf("service instance %q not found in %s/%s", instance.Name, orgName, spaceName)
+	}
+
+	return org, space, guid, nil
+}
+
+// waitForServiceInstance waits for the last operation on a managed service instance to finish,
+// bindings and keys cannot be created while the instance is still being provisioned or updated
+func waitForServiceInstance(ctx context.Context, client cf.Client, instance *cf.ServiceInstance, guid string) error {
+	if ServiceType(instance.Type) == UserProvidedService {
+		return nil
+	}
+
+	timeout := time.After(serviceInstanceReadyTimeout)
+	for {
+		si, err := client.GetServiceInstanceByGuid(guid)
+		if err != nil {
+			return fmt.Errorf("failed to check status of service instance %q: %w", instance.Name, err)
+		}
+
+		switch si.LastOperation.State {
+		case "failed":
+			return fmt.Errorf("service instance %q is in a failed state: %s", instance.Name, si.LastOperation.Description)
+		case "in progress":
+			log.Debugf("Waiting for %s of %q to finish", si.LastOperation.Type, instance.Name)
+		default:
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout:
+			return fmt.Errorf("timed out waiting for service instance %q to become ready", instance.Name)
+		case <-time.After(serviceInstanceReadyPause):
+		}
+	}
+}
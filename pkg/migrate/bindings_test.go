@@ -0,0 +1,225 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package migrate_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/cloudfoundry-community/go-cfclient"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	cffakes "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf/fakes"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/flow"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/fakes"
+)
+
+func newBindingsClient() *cffakes.FakeClient {
+	client := new(cffakes.FakeClient)
+	client.GetOrgByNameReturns(cfclient.Org{Guid: "org-guid", Name: "some-org"}, nil)
+	client.GetSpaceByNameReturns(cfclient.Space{Guid: "space-guid", Name: "some-space"}, nil)
+	client.ListServiceInstancesByQueryReturns([]cfclient.ServiceInstance{{Guid: "target-si-guid", Name: "some-service"}}, nil)
+	client.GetServiceInstanceByGuidReturns(cfclient.ServiceInstance{LastOperation: cfclient.LastOperation{State: "succeeded"}}, nil)
+	return client
+}
+
+func TestBindApps(t *testing.T) {
+	instance := func() *cf.ServiceInstance {
+		return &cf.ServiceInstance{
+			Name: "some-service",
+			Type: "managed_service_instance",
+			ServiceBindings: []cf.ServiceBinding{
+				{Guid: "binding-1", Name: "db", AppGuid: "source-app-1", Parameters: map[string]interface{}{"role": "admin"}},
+				{Guid: "binding-2", AppGuid: "source-app-2"},
+			},
+			Apps: map[string]string{
+				"binding-1": "app-1",
+				"binding-2": "app-2",
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		beforeFunc func(client *cffakes.FakeClient)
+		dryRun     bool
+		wantErr    bool
+		afterFunc  func(t *testing.T, client *cffakes.FakeClient)
+	}{
+		{
+			name: "binds the instance to the apps with the same names on the target",
+			beforeFunc: func(client *cffakes.FakeClient) {
+				client.AppByNameStub = func(name, spaceGUID, orgGUID string) (cfclient.App, error) {
+					return cfclient.App{Guid: "target-" + name, Name: name}, nil
+				}
+			},
+			afterFunc: func(t *testing.T, client *cffakes.FakeClient) {
+				require.Equal(t, 2, client.CreateServiceBindingCallCount())
+				require.Equal(t, cf.CreateServiceBindingRequest{
+					Name:                "db",
+					AppGuid:             "target-app-1",
+					ServiceInstanceGuid: "target-si-guid",
+					Parameters:          map[string]interface{}{"role": "admin"},
+				}, client.CreateServiceBindingArgsForCall(0))
+				require.Equal(t, cf.CreateServiceBindingRequest{
+					AppGuid:             "target-app-2",
+					ServiceInstanceGuid: "target-si-guid",
+				}, client.CreateServiceBindingArgsForCall(1))
+				require.Equal(t, url.Values{
+					"app_guids":              []string{"target-app-1"},
+					"service_instance_guids": []string{"target-si-guid"},
+				}, client.ListServiceBindingsByQueryArgsForCall(0))
+			},
+		},
+		{
+			name: "skips apps missing on the target",
+			beforeFunc: func(client *cffakes.FakeClient) {
+				client.AppByNameStub = func(name, spaceGUID, orgGUID string) (cfclient.App, error) {
+					if name == "app-2" {
+						return cfclient.App{}, cfclient.NewAppNotFoundError()
+					}
+					return cfclient.App{Guid: "target-" + name, Name: name}, nil
+				}
+			},
+			afterFunc: func(t *testing.T, client *cffakes.FakeClient) {
+				require.Equal(t, 1, client.CreateServiceBindingCallCount())
+				require.Equal(t, "target-app-1", client.CreateServiceBindingArgsForCall(0).AppGuid)
+			},
+		},
+		{
+			name: "fails when an app can't be looked up",
+			beforeFunc: func(client *cffakes.FakeClient) {
+				client.AppByNameReturns(cfclient.App{}, errors.New("connection refused"))
+			},
+			wantErr: true,
+			afterFunc: func(t *testing.T, client *cffakes.FakeClient) {
+				require.Equal(t, 0, client.CreateServiceBindingCallCount())
+			},
+		},
+		{
+			name: "skips apps that are already bound",
+			beforeFunc: func(client *cffakes.FakeClient) {
+				client.AppByNameStub = func(name, spaceGUID, orgGUID string) (cfclient.App, error) {
+					return cfclient.App{Guid: "target-" + name, Name: name}, nil
+				}
+				client.ListServiceBindingsByQueryReturnsOnCall(0, []cfclient.ServiceBinding{{Guid: "existing"}}, nil)
+			},
+			afterFunc: func(t *testing.T, client *cffakes.FakeClient) {
+				require.Equal(t, 1, client.CreateServiceBindingCallCount())
+				require.Equal(t, "target-app-2", client.CreateServiceBindingArgsForCall(0).AppGuid)
+			},
+		},
+		{
+			name: "does not bind on a dry run",
+			beforeFunc: func(client *cffakes.FakeClient) {
+				client.AppByNameStub = func(name, spaceGUID, orgGUID string) (cfclient.App, error) {
+					return cfclient.App{Guid: "target-" + name, Name: name}, nil
+				}
+			},
+			dryRun: true,
+			afterFunc: func(t *testing.T, client *cffakes.FakeClient) {
+				require.Equal(t, 0, client.CreateServiceBindingCallCount())
+			},
+		},
+		{
+			name: "fails when the instance failed to provision",
+			beforeFunc: func(client *cffakes.FakeClient) {
+				client.AppByNameStub = func(name, spaceGUID, orgGUID string) (cfclient.App, error) {
+					return cfclient.App{Guid: "target-" + name, Name: name}, nil
+				}
+				client.GetServiceInstanceByGuidReturns(cfclient.ServiceInstance{LastOperation: cfclient.LastOperation{State: "failed"}}, nil)
+			},
+			wantErr: true,
+			afterFunc: func(t *testing.T, client *cffakes.FakeClient) {
+				require.Equal(t, 0, client.CreateServiceBindingCallCount())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newBindingsClient()
+			tt.beforeFunc(client)
+			holder := new(fakes.FakeClientHolder)
+			holder.CFClientReturns(client)
+
+			_, err := flow.RunWith(migrate.BindApps("some-org", "some-space", instance(), holder, false), context.TODO(), nil, tt.dryRun)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			tt.afterFunc(t, client)
+		})
+	}
+}
+
+func TestCreateServiceKeys(t *testing.T) {
+	instance := &cf.ServiceInstance{
+		Name: "some-service",
+		Type: "managed_service_instance",
+		ServiceKeys: []cf.ServiceKey{
+			{Name: "key-1", Guid: "source-key-1"},
+			{Name: "key-2", Guid: "source-key-2"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		cfg        *config.Config
+		beforeFunc func(client *cffakes.FakeClient)
+		afterFunc  func(t *testing.T, client *cffakes.FakeClient)
+	}{
+		{
+			name: "recreates the service keys that do not exist",
+			cfg:  &config.Config{},
+			beforeFunc: func(client *cffakes.FakeClient) {
+				client.ListServiceKeysByQueryReturnsOnCall(1, []cfclient.ServiceKey{{Name: "key-2"}}, nil)
+			},
+			afterFunc: func(t *testing.T, client *cffakes.FakeClient) {
+				require.Equal(t, 1, client.CreateServiceKeyCallCount())
+				require.Equal(t, cfclient.CreateServiceKeyRequest{
+					Name:                "key-1",
+					ServiceInstanceGuid: "target-si-guid",
+				}, client.CreateServiceKeyArgsForCall(0))
+			},
+		},
+		{
+			name:       "does not create service keys when they are ignored",
+			cfg:        &config.Config{IgnoreServiceKeys: true},
+			beforeFunc: func(client *cffakes.FakeClient) {},
+			afterFunc: func(t *testing.T, client *cffakes.FakeClient) {
+				require.Equal(t, 0, client.CreateServiceKeyCallCount())
+				require.Equal(t, 0, client.ListServiceKeysByQueryCallCount())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newBindingsClient()
+			tt.beforeFunc(client)
+			holder := new(fakes.FakeClientHolder)
+			holder.CFClientReturns(client)
+
+			_, err := flow.RunWith(migrate.CreateServiceKeys("some-org", "some-space", instance, holder, false), config.ContextWithConfig(context.TODO(), tt.cfg), nil, false)
+			require.NoError(t, err)
+			tt.afterFunc(t, client)
+		})
+	}
+}
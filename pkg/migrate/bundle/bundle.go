@@ -0,0 +1,334 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	sio "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/io"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/journal"
+)
+
+// ManifestFile is the name of the manifest stored at the root of a bundle
+const ManifestFile string = ".bundle-manifest.json"
+
+// Manifest is the index of a bundle, listing the exported service instances and the checksum of every file
+type Manifest struct {
+	Version   string     `json:"version"`
+	CreatedAt time.Time  `json:"created_at"`
+	Instances []Instance `json:"instances"`
+	Files     []File     `json:"files"`
+}
+
+// Instance is a service instance exported into a bundle
+type Instance struct {
+	Org      string `json:"org"`
+	Space    string `json:"space"`
+	Name     string `json:"name"`
+	Service  string `json:"service"`
+	Migrator string `json:"migrator,omitempty"`
+}
+
+// File is a file in a bundle, its path is relative to the export directory
+type File struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Create writes every file in dir but the export journal to a gzipped tar at path, together with a manifest of the
+// service instances and the checksum of each file. The bundle is written to a temp file first so a failed export
+// never leaves a truncated bundle behind.
+func Create(path, dir, version string, instances []Instance) (*Manifest, error) {
+	if inside(path, dir) {
+		return nil, fmt.Errorf("bundle %q cannot be written inside the export directory %q", path, dir)
+	}
+
+	files, err := checksums(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(instances, func(i, j int) bool {
+		a, b := instances[i], instances[j]
+		if a.Org != b.Org {
+			return a.Org < b.Org
+		}
+		if a.Space != b.Space {
+			return a.Space < b.Space
+		}
+		return a.Name < b.Name
+	})
+
+	m := &Manifest{
+		Version:   version,
+		CreatedAt: time.Now().UTC(),
+		Instances: instances,
+		Files:     files,
+	}
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal bundle manifest: %w", err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, ManifestFile), b, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write bundle manifest: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bundle %q: %w", path, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	gw := gzip.NewWriter(tmp)
+	if err = writeTar(gw, dir, m); err != nil {
+		_ = tmp.Close()
+		return nil, fmt.Errorf("failed to write bundle %q: %w", path, err)
+	}
+	if err = gw.Close(); err != nil {
+		_ = tmp.Close()
+		return nil, fmt.Errorf("failed to write bundle %q: %w", path, err)
+	}
+	if err = tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write bundle %q: %w", path, err)
+	}
+
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return nil, fmt.Errorf("failed to write bundle %q: %w", path, err)
+	}
+
+	return m, nil
+}
+
+// Extract unpacks the bundle at path into dir and verifies every file against the manifest. Dir must be empty
+// or not exist yet, so no file that isn't in the bundle is imported along with it.
+func Extract(path, dir string) (*Manifest, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read directory %q: %w", dir, err)
+	}
+	if len(entries) > 0 {
+		return nil, fmt.Errorf("cannot extract bundle %q into %q, the directory is not empty", path, dir)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bundle %q: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle %q: %w", path, err)
+	}
+	defer func() { _ = gr.Close() }()
+
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory %q: %w", dir, err)
+	}
+
+	if err = sio.Untar(dir, gr); err != nil {
+		return nil, fmt.Errorf("failed to extract bundle %q: %w", path, err)
+	}
+
+	m, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = m.Verify(dir); err != nil {
+		return nil, fmt.Errorf("bundle %q is corrupt: %w", path, err)
+	}
+
+	return m, nil
+}
+
+// ReadManifest reads the manifest of a bundle extracted to dir
+func ReadManifest(dir string) (*Manifest, error) {
+	b, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.New("bundle has no manifest")
+		}
+		return nil, fmt.Errorf("failed to read bundle manifest: %w", err)
+	}
+
+	m := &Manifest{}
+	if err = json.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle manifest: %w", err)
+	}
+
+	return m, nil
+}
+
+// Verify checks that every file listed in the manifest is in dir with the recorded size and checksum, and that dir
+// holds no other files than the manifest and the journal of an import
+func (m *Manifest) Verify(dir string) error {
+	var problems []string
+	listed := map[string]bool{ManifestFile: true, journal.ImportFile: true}
+	for _, f := range m.Files {
+		listed[f.Path] = true
+		path := filepath.Join(dir, filepath.FromSlash(f.Path))
+		sum, size, err := checksum(path)
+		switch {
+		case os.IsNotExist(err):
+			problems = append(problems, fmt.Sprintf("%s is missing", f.Path))
+		case err != nil:
+			problems = append(problems, fmt.Sprintf("%s cannot be read: %v", f.Path, err))
+		case size != f.Size || sum != f.SHA256:
+			problems = append(problems, fmt.Sprintf("%s does not match its checksum", f.Path))
+		}
+	}
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if !listed[filepath.ToSlash(rel)] {
+			problems = append(problems, fmt.Sprintf("%s is not in the manifest", filepath.ToSlash(rel)))
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list files in %q: %w", dir, err)
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+// writeTar writes the manifest and the files it lists to w
+func writeTar(w io.Writer, dir string, m *Manifest) error {
+	tw := tar.NewWriter(w)
+
+	paths := []string{ManifestFile}
+	for _, f := range m.Files {
+		paths = append(paths, f.Path)
+	}
+	for _, p := range paths {
+		if err := addFile(tw, dir, p); err != nil {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
+
+func addFile(tw *tar.Writer, dir, name string) error {
+	f, err := os.Open(filepath.Join(dir, filepath.FromSlash(name)))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = name
+
+	if err = tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+func checksums(dir string) ([]File, error) {
+	var files []File
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == ManifestFile || rel == journal.ExportFile {
+			return nil
+		}
+
+		sum, size, err := checksum(path)
+		if err != nil {
+			return err
+		}
+		files = append(files, File{Path: rel, Size: size, SHA256: sum})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to checksum files in %q: %w", dir, err)
+	}
+
+	return files, nil
+}
+
+func checksum(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// inside returns true if path is in dir or one of its subdirectories
+func inside(path, dir string) bool {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(absPath, absDir+string(os.PathSeparator))
+}
@@ -0,0 +1,120 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package bundle_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/bundle"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/journal"
+)
+
+func writeExport(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "org1", "space1"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "org1", "space1", "db.yml"), []byte("name: db\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "org1", "space1", "db-backup.tgz"), []byte("backup"), 0644))
+	return dir
+}
+
+func TestCreateExtract(t *testing.T) {
+	dir := writeExport(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, journal.ExportFile), []byte("{}"), 0644))
+	path := filepath.Join(t.TempDir(), "export.tgz")
+
+	created, err := bundle.Create(path, dir, "1.2.3", []bundle.Instance{
+		{Org: "org1", Space: "space1", Name: "db", Service: "p.mysql", Migrator: "mysql"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", created.Version)
+	require.Len(t, created.Files, 2)
+	assert.Equal(t, "org1/space1/db-backup.tgz", created.Files[0].Path)
+	assert.Equal(t, "org1/space1/db.yml", created.Files[1].Path)
+	assert.Equal(t, int64(9), created.Files[1].Size)
+	assert.Len(t, created.Files[1].SHA256, 64)
+
+	target := filepath.Join(t.TempDir(), "import")
+	extracted, err := bundle.Extract(path, target)
+	require.NoError(t, err)
+	assert.Equal(t, created.Files, extracted.Files)
+	assert.Equal(t, created.Instances, extracted.Instances)
+
+	b, err := os.ReadFile(filepath.Join(target, "org1", "space1", "db.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, "name: db\n", string(b))
+	assert.NoFileExists(t, filepath.Join(target, journal.ExportFile))
+}
+
+func TestCreateFailsInsideExportDir(t *testing.T) {
+	dir := writeExport(t)
+
+	_, err := bundle.Create(filepath.Join(dir, "export.tgz"), dir, "1.2.3", nil)
+	require.Error(t, err)
+	assert.NoFileExists(t, filepath.Join(dir, "export.tgz"))
+}
+
+func TestManifestVerify(t *testing.T) {
+	dir := writeExport(t)
+	_, err := bundle.Create(filepath.Join(t.TempDir(), "export.tgz"), dir, "1.2.3", nil)
+	require.NoError(t, err)
+
+	m, err := bundle.ReadManifest(dir)
+	require.NoError(t, err)
+	require.NoError(t, m.Verify(dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "org1", "space1", "db.yml"), []byte("name: other\n"), 0644))
+	require.NoError(t, os.Remove(filepath.Join(dir, "org1", "space1", "db-backup.tgz")))
+
+	err = m.Verify(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "org1/space1/db.yml does not match its checksum")
+	assert.Contains(t, err.Error(), "org1/space1/db-backup.tgz is missing")
+}
+
+func TestManifestVerifyRejectsUnlistedFiles(t *testing.T) {
+	dir := writeExport(t)
+	_, err := bundle.Create(filepath.Join(t.TempDir(), "export.tgz"), dir, "1.2.3", nil)
+	require.NoError(t, err)
+	m, err := bundle.ReadManifest(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, journal.ImportFile), []byte("{}"), 0644))
+	require.NoError(t, m.Verify(dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "org1", "space1", "stale.yml"), []byte("name: stale\n"), 0644))
+	require.EqualError(t, m.Verify(dir), "org1/space1/stale.yml is not in the manifest")
+}
+
+func TestExtractFailsWhenBundleIsMissing(t *testing.T) {
+	_, err := bundle.Extract(filepath.Join(t.TempDir(), "missing.tgz"), t.TempDir())
+	require.Error(t, err)
+}
+
+func TestExtractFailsWhenDirIsNotEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.tgz")
+	_, err := bundle.Create(path, writeExport(t), "1.2.3", nil)
+	require.NoError(t, err)
+
+	target := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(target, "stale.yml"), []byte("name: stale\n"), 0644))
+
+	_, err = bundle.Extract(path, target)
+	require.ErrorContains(t, err, "the directory is not empty")
+	assert.NoFileExists(t, filepath.Join(target, bundle.ManifestFile))
+}
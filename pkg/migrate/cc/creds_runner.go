@@ -15,12 +15,8 @@
 package cc
 
 import (
-	"bufio"
 	"context"
 	"fmt"
-	"io"
-	"regexp"
-	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/bosh"
@@ -31,7 +27,7 @@ import (
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
 )
 
-func SetCloudControllerDatabaseCredentials(e exec.Executor, cfg *DatabaseConfig, om config.OpsManager) flow.StepFunc {
+func SetCloudControllerDatabaseCredentials(client bosh.Client, credhubClient credhub.Client, cfg *DatabaseConfig, om config.OpsManager) flow.StepFunc {
 	return func(ctx context.Context, c interface{}, dryRun bool) (flow.Result, error) {
 		var (
 			deploymentName string
@@ -40,20 +36,15 @@ func SetCloudControllerDatabaseCredentials(e exec.Executor, cfg *DatabaseConfig,
 
 		if cfg.Host == "" || cfg.Username == "" || cfg.Password == "" || cfg.EncryptionKey == "" {
 			log.Debugf("Fetching ccdb creds for %s", om.Hostname)
-			deploymentName, err = findDeploymentName(ctx, e, om, "^cf-")
+			deploymentName, err = findDeploymentName(client, "^cf-")
 			if err != nil {
 				return exec.Result{}, err
 			}
 		}
 
 		if cfg.Host == "" {
-			databaseInstance, err := findInstanceName(ctx, e, om, deploymentName)
-			if err != nil {
-				return exec.Result{}, err
-			}
-
-			log.Debugf("Fetching ccdb ip address for %s", databaseInstance)
-			ipAddress, err := findInstanceIPAddress(ctx, e, om, deploymentName, databaseInstance)
+			log.Debugf("Fetching ccdb ip address from %s", deploymentName)
+			ipAddress, err := findDatabaseAddress(client, deploymentName)
 			if err != nil {
 				return exec.Result{}, err
 			}
@@ -63,11 +54,12 @@ func SetCloudControllerDatabaseCredentials(e exec.Executor, cfg *DatabaseConfig,
 		}
 
 		if cfg.Username == "" || cfg.Password == "" {
-			username, password, err := getCredentials(ctx, e, om, deploymentName)
+			username, password, err := getCredentials(credhubClient, deploymentName)
 			if err != nil {
 				return exec.Result{}, err
 			}
 
+			log.RegisterSecrets(password)
 			log.Debugf("ccdb username='%s'", username)
 			log.Debugf("ccdb password='%s'", password)
 			cfg.Username = username
@@ -75,11 +67,12 @@ func SetCloudControllerDatabaseCredentials(e exec.Executor, cfg *DatabaseConfig,
 		}
 
 		if cfg.EncryptionKey == "" {
-			encryptionKey, err := getEncryptionKey(ctx, e, om, deploymentName)
+			encryptionKey, err := getEncryptionKey(credhubClient, deploymentName)
 			if err != nil {
 				return exec.Result{}, err
 			}
 
+			log.RegisterSecrets(encryptionKey)
 			log.Debugf("ccdb encryptionKey='%s'", encryptionKey)
 			cfg.EncryptionKey = encryptionKey
 		}
@@ -88,81 +81,70 @@ func SetCloudControllerDatabaseCredentials(e exec.Executor, cfg *DatabaseConfig,
 	}
 }
 
-func findInstanceName(ctx context.Context, e exec.Executor, om config.OpsManager, deployment string) (string, error) {
-	res, err := bosh.Run(e, ctx, om, "-d", deployment, "--column=instance", "--column=process", "is", "-p", "|", "awk", "-F' '", "'$2==\"proxy\"'", "|", "awk", "'{print $1}'", "|", "head", "-1", "|", "tr", "-d", "'\\t\\n'")
+// findDatabaseAddress returns the address of the first instance running the mysql proxy, preferring its dns name
+func findDatabaseAddress(client bosh.Client, deployment string) (string, error) {
+	instances, err := client.FindInstances(deployment)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to bosh instance running credhub")
+		return "", errors.Wrap(err, "failed to find bosh instance running the database proxy")
 	}
 
-	return strings.TrimSuffix(res.Status.Output, "\t\n"), nil
-}
-
-func findInstanceIPAddress(ctx context.Context, e exec.Executor, om config.OpsManager, deployment string, instance string) (string, error) {
-	res, err := bosh.Run(e, ctx, om, "-d", deployment, "--column=instance", "--column=ips", "is", "--dns", "|", "grep", instance, "|", "awk", "'{print $2}'", "|", "tr", "-d", "'\\t\\n'")
-	if err != nil {
-		return "", errors.Wrap(err, "failed to bosh instance running credhub")
+	for _, instance := range instances {
+		for _, p := range instance.Processes {
+			if p.Name != "proxy" {
+				continue
+			}
+			if len(instance.DNS) > 0 {
+				return instance.DNS[0], nil
+			}
+			if len(instance.IPs) > 0 {
+				return instance.IPs[0], nil
+			}
+		}
 	}
 
-	return strings.TrimSuffix(res.Status.Output, "\t\n"), nil
+	return "", fmt.Errorf("failed to find bosh instance running the database proxy in %q", deployment)
 }
 
-func findDeploymentName(ctx context.Context, e exec.Executor, om config.OpsManager, pattern string) (string, error) {
-	res, err := bosh.Run(e, ctx, om, "deps", "--column=name", "|", "grep", "'"+pattern+"'", "|", "tr", "-d", "'\\t\\n'")
+func findDeploymentName(client bosh.Client, pattern string) (string, error) {
+	deployment, found, err := client.FindDeployment(pattern)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to get deployments")
 	}
-	deployment := strings.TrimSuffix(res.Status.Output, "\t\n")
-	match, _ := regexp.MatchString(pattern, deployment)
-	if !match {
+	if !found {
 		return "", fmt.Errorf("failed to find deployment name with pattern %q", pattern)
 	}
 
-	return deployment, nil
+	return deployment.Name, nil
 }
 
-func getCredentials(ctx context.Context, e exec.Executor, om config.OpsManager, deployment string) (string, string, error) {
-	res, err := credhub.Run(e, ctx, om, "get", "-n", fmt.Sprintf("/p-bosh/%s/cc-db-credentials", deployment), "-q")
+func getCredentials(client credhub.Client, deployment string) (string, string, error) {
+	cred, err := client.GetCredential(fmt.Sprintf("/p-bosh/%s/cc-db-credentials", deployment))
 	if err != nil {
 		return "", "", errors.Wrap(err, fmt.Sprintf("failed to get creds from %s", deployment))
 	}
 
-	scanner := bufio.NewScanner(strings.NewReader(res.Status.Output))
-	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
-		lines := strings.Split(string(data), "\n")
-		if atEOF {
-			return 0, nil, io.EOF
-		}
-		for _, l := range lines {
-			fields := strings.Split(l, ": ")
-			if len(fields) > 1 {
-				return len(l) + 1, []byte(fields[1]), nil
-			}
-		}
-		return 0, nil, nil
-	})
-
-	fields := make([]string, 0, 3)
-	for scanner.Scan() {
-		if scanner.Text() != "" {
-			text := scanner.Text()
-			fields = append(fields, text)
-		}
-	}
-	if scanner.Err() != nil {
-		return "", "", scanner.Err()
+	value, ok := cred.Value.(map[string]interface{})
+	if !ok {
+		return "", "", fmt.Errorf("failed to get creds from %s, unexpected credential type %q", deployment, cred.Type)
 	}
 
-	username := strings.TrimSuffix(fields[2], "\t\n")
-	password := strings.TrimSuffix(fields[0], "\t\n")
+	username, _ := value["username"].(string)
+	password, _ := value["password"].(string)
 
 	return username, password, nil
 }
 
-func getEncryptionKey(ctx context.Context, e exec.Executor, om config.OpsManager, deployment string) (string, error) {
-	res, err := credhub.Run(e, ctx, om, "get", "-n", fmt.Sprintf("/opsmgr/%s/cloud_controller/db_encryption_credentials", deployment), "-q", "-k", "password", "|", "tr", "-d", "'\\t\\n'")
+func getEncryptionKey(client credhub.Client, deployment string) (string, error) {
+	cred, err := client.GetCredential(fmt.Sprintf("/opsmgr/%s/cloud_controller/db_encryption_credentials", deployment))
 	if err != nil {
 		return "", errors.Wrap(err, "failed to get encryption key")
 	}
 
-	return strings.TrimSuffix(res.Status.Output, "\t\n"), nil
+	value, ok := cred.Value.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("failed to get encryption key, unexpected credential type %q", cred.Type)
+	}
+	password, _ := value["password"].(string)
+
+	return password, nil
 }
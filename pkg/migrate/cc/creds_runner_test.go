@@ -16,21 +16,21 @@ package cc
 
 import (
 	"context"
-	"io"
+	"errors"
 	"testing"
 
+	"github.com/cloudfoundry/bosh-cli/director"
 	"github.com/stretchr/testify/require"
+	boshfakes "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/bosh/fakes"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
-	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/exec"
-	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/exec/fakes"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/credhub"
+	credhubfakes "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/credhub/fakes"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/flow"
 )
 
 func Test_getCredentials(t *testing.T) {
 	type args struct {
-		ctx        context.Context
-		e          *fakes.FakeExecutor
-		om         config.OpsManager
+		client     *credhubfakes.FakeClient
 		deployment string
 	}
 	tests := []struct {
@@ -43,30 +43,41 @@ func Test_getCredentials(t *testing.T) {
 		{
 			name: "get credhub credentials returns username and password",
 			args: args{
-				ctx: context.TODO(),
-				e: &fakes.FakeExecutor{
-					ExecuteStub: func(ctx context.Context, reader io.Reader) (exec.Result, error) {
-						return exec.Result{
-							Status: &exec.Status{
-								Output: `password: some-password
-password_hash: some-password-hash
-username: some-username
-`,
+				client: &credhubfakes.FakeClient{
+					GetCredentialStub: func(name string) (credhub.Credential, error) {
+						return credhub.Credential{
+							Name: name,
+							Type: "user",
+							Value: map[string]interface{}{
+								"password":      "some-password",
+								"password_hash": "some-password-hash",
+								"username":      "some-username",
 							},
 						}, nil
 					},
 				},
-				om:         config.OpsManager{},
 				deployment: "cf",
 			},
 			username: "some-username",
 			password: "some-password",
 			wantErr:  false,
 		},
+		{
+			name: "get credhub credentials fails",
+			args: args{
+				client: &credhubfakes.FakeClient{
+					GetCredentialStub: func(name string) (credhub.Credential, error) {
+						return credhub.Credential{}, errors.New("status 'Not Found'")
+					},
+				},
+				deployment: "cf",
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			username, password, err := getCredentials(tt.args.ctx, tt.args.e, tt.args.om, tt.args.deployment)
+			username, password, err := getCredentials(tt.args.client, tt.args.deployment)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("getCredentials() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -77,15 +88,14 @@ username: some-username
 			if password != tt.password {
 				t.Errorf("getCredentials() got1 = %v, want %v", password, tt.password)
 			}
+			require.Equal(t, "/p-bosh/cf/cc-db-credentials", tt.args.client.GetCredentialArgsForCall(0))
 		})
 	}
 }
 
 func Test_getEncryptionKey(t *testing.T) {
 	type args struct {
-		ctx        context.Context
-		e          *fakes.FakeExecutor
-		om         config.OpsManager
+		client     *credhubfakes.FakeClient
 		deployment string
 	}
 	tests := []struct {
@@ -97,26 +107,36 @@ func Test_getEncryptionKey(t *testing.T) {
 		{
 			name: "get credhub credentials returns encryption key",
 			args: args{
-				ctx: context.TODO(),
-				e: &fakes.FakeExecutor{
-					ExecuteStub: func(ctx context.Context, reader io.Reader) (exec.Result, error) {
-						return exec.Result{
-							Status: &exec.Status{
-								Output: "some-encryption-key",
-							},
+				client: &credhubfakes.FakeClient{
+					GetCredentialStub: func(name string) (credhub.Credential, error) {
+						return credhub.Credential{
+							Name:  name,
+							Type:  "password",
+							Value: map[string]interface{}{"password": "some-encryption-key"},
 						}, nil
 					},
 				},
-				om:         config.OpsManager{},
 				deployment: "cf",
 			},
 			want:    "some-encryption-key",
 			wantErr: false,
 		},
+		{
+			name: "errors when the credential is not a map",
+			args: args{
+				client: &credhubfakes.FakeClient{
+					GetCredentialStub: func(name string) (credhub.Credential, error) {
+						return credhub.Credential{Name: name, Type: "value", Value: "some-encryption-key"}, nil
+					},
+				},
+				deployment: "cf",
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := getEncryptionKey(tt.args.ctx, tt.args.e, tt.args.om, tt.args.deployment)
+			got, err := getEncryptionKey(tt.args.client, tt.args.deployment)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("getEncryptionKey() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -124,15 +144,14 @@ func Test_getEncryptionKey(t *testing.T) {
 			if got != tt.want {
 				t.Errorf("getEncryptionKey() got = %v, want %v", got, tt.want)
 			}
+			require.Equal(t, "/opsmgr/cf/cloud_controller/db_encryption_credentials", tt.args.client.GetCredentialArgsForCall(0))
 		})
 	}
 }
 
 func Test_findDeploymentName(t *testing.T) {
 	type args struct {
-		ctx     context.Context
-		e       *fakes.FakeExecutor
-		om      config.OpsManager
+		client  *boshfakes.FakeClient
 		pattern string
 	}
 	tests := []struct {
@@ -144,26 +163,32 @@ func Test_findDeploymentName(t *testing.T) {
 		{
 			name: "get cf deployment name from bosh",
 			args: args{
-				ctx: context.TODO(),
-				e: &fakes.FakeExecutor{
-					ExecuteStub: func(ctx context.Context, reader io.Reader) (exec.Result, error) {
-						return exec.Result{
-							Status: &exec.Status{
-								Output: "cf-abc12345678de1234567",
-							},
-						}, nil
+				client: &boshfakes.FakeClient{
+					FindDeploymentStub: func(pattern string) (director.DeploymentResp, bool, error) {
+						return director.DeploymentResp{Name: "cf-abc12345678de1234567"}, true, nil
 					},
 				},
-				om:      config.OpsManager{},
 				pattern: "^cf",
 			},
 			want:    "cf-abc12345678de1234567",
 			wantErr: false,
 		},
+		{
+			name: "errors when no deployment matches",
+			args: args{
+				client: &boshfakes.FakeClient{
+					FindDeploymentStub: func(pattern string) (director.DeploymentResp, bool, error) {
+						return director.DeploymentResp{}, false, nil
+					},
+				},
+				pattern: "^cf",
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := findDeploymentName(tt.args.ctx, tt.args.e, tt.args.om, tt.args.pattern)
+			got, err := findDeploymentName(tt.args.client, tt.args.pattern)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("findDeploymentName() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -175,11 +200,9 @@ func Test_findDeploymentName(t *testing.T) {
 	}
 }
 
-func Test_findInstanceName(t *testing.T) {
+func Test_findDatabaseAddress(t *testing.T) {
 	type args struct {
-		ctx        context.Context
-		e          exec.Executor
-		om         config.OpsManager
+		client     *boshfakes.FakeClient
 		deployment string
 	}
 	tests := []struct {
@@ -189,83 +212,60 @@ func Test_findInstanceName(t *testing.T) {
 		wantErr bool
 	}{
 		{
-			name: "get mysql database instance name from bosh",
+			name: "get ccdb dns name from bosh",
 			args: args{
-				ctx: context.TODO(),
-				e: &fakes.FakeExecutor{
-					ExecuteStub: func(ctx context.Context, reader io.Reader) (exec.Result, error) {
-						return exec.Result{
-							Status: &exec.Status{
-								Output: "192.168.1.21",
-							},
+				client: &boshfakes.FakeClient{
+					FindInstancesStub: func(deployment string) ([]director.VMInfo, error) {
+						return []director.VMInfo{
+							{JobName: "diego_cell", IPs: []string{"192.168.1.30"}, Processes: []director.VMInfoProcess{{Name: "rep"}}},
+							{JobName: "mysql_proxy", IPs: []string{"192.168.1.21"}, DNS: []string{"guid.mysql-proxy.cf.bosh"}, Processes: []director.VMInfoProcess{{Name: "proxy"}}},
 						}, nil
 					},
 				},
-				om:         config.OpsManager{},
 				deployment: "cf-abc12345678de1234567",
 			},
-			want:    "192.168.1.21",
+			want:    "guid.mysql-proxy.cf.bosh",
 			wantErr: false,
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got, err := findInstanceName(tt.args.ctx, tt.args.e, tt.args.om, tt.args.deployment)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("findInstanceName() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if got != tt.want {
-				t.Errorf("findInstanceName() got = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-func Test_findInstanceIPAddress(t *testing.T) {
-	type args struct {
-		ctx        context.Context
-		e          exec.Executor
-		om         config.OpsManager
-		deployment string
-		instance   string
-	}
-	tests := []struct {
-		name    string
-		args    args
-		want    string
-		wantErr bool
-	}{
 		{
-			name: "get ccdb ip address from bosh",
+			name: "get ccdb ip address from bosh when there is no dns name",
 			args: args{
-				ctx: context.TODO(),
-				e: &fakes.FakeExecutor{
-					ExecuteStub: func(ctx context.Context, reader io.Reader) (exec.Result, error) {
-						return exec.Result{
-							Status: &exec.Status{
-								Output: "192.168.1.21",
-							},
+				client: &boshfakes.FakeClient{
+					FindInstancesStub: func(deployment string) ([]director.VMInfo, error) {
+						return []director.VMInfo{
+							{JobName: "mysql_proxy", IPs: []string{"192.168.1.21"}, Processes: []director.VMInfoProcess{{Name: "proxy"}}},
 						}, nil
 					},
 				},
-				om:         config.OpsManager{},
 				deployment: "cf-abc12345678de1234567",
-				instance:   "mysql_proxy/guid",
 			},
 			want:    "192.168.1.21",
 			wantErr: false,
 		},
+		{
+			name: "errors when no instance runs the proxy",
+			args: args{
+				client: &boshfakes.FakeClient{
+					FindInstancesStub: func(deployment string) ([]director.VMInfo, error) {
+						return []director.VMInfo{
+							{JobName: "diego_cell", IPs: []string{"192.168.1.30"}, Processes: []director.VMInfoProcess{{Name: "rep"}}},
+						}, nil
+					},
+				},
+				deployment: "cf-abc12345678de1234567",
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := findInstanceIPAddress(tt.args.ctx, tt.args.e, tt.args.om, tt.args.deployment, tt.args.instance)
+			got, err := findDatabaseAddress(tt.args.client, tt.args.deployment)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("findInstanceIPAddress() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("findDatabaseAddress() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if got != tt.want {
-				t.Errorf("findInstanceIPAddress() got = %v, want %v", got, tt.want)
+				t.Errorf("findDatabaseAddress() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -273,195 +273,155 @@ func Test_findInstanceIPAddress(t *testing.T) {
 
 func TestRetrieveCloudControllerDatabaseCredentials(t *testing.T) {
 	type args struct {
-		e        *fakes.FakeExecutor
-		cfg      *DatabaseConfig
-		isExport bool
-		config   *config.Migration
-		dryRun   bool
-		om       config.OpsManager
+		client        *boshfakes.FakeClient
+		credhubClient *credhubfakes.FakeClient
+		cfg           *DatabaseConfig
+		config        *config.Migration
+		dryRun        bool
+		om            config.OpsManager
 	}
 	tests := []struct {
-		name       string
-		args       args
-		wantErr    error
-		beforeFunc func(args args)
-		afterFunc  func(args args)
+		name      string
+		args      args
+		want      *DatabaseConfig
+		wantErr   error
+		afterFunc func(t *testing.T, args args)
 	}{
 		{
 			name: "retrieves all ccdb credentials",
 			args: args{
-				e:      new(fakes.FakeExecutor),
-				cfg:    &DatabaseConfig{},
-				config: &config.Migration{},
+				client:        newBoshClient(),
+				credhubClient: newCredhubClient(),
+				cfg:           &DatabaseConfig{},
+				config:        &config.Migration{},
 				om: config.OpsManager{
 					Hostname: "om.target.com",
 				},
 			},
-			beforeFunc: func(args args) {
-				args.e.ExecuteReturnsOnCall(0, exec.Result{
-					Status: &exec.Status{
-						Output: "cf-abc12345678de1234567",
-					},
-				}, nil)
-				args.e.ExecuteReturnsOnCall(1, exec.Result{
-					Status: &exec.Status{
-						Output: "mysql_proxy/guid",
-					},
-				}, nil)
-				args.e.ExecuteReturnsOnCall(2, exec.Result{
-					Status: &exec.Status{
-						Output: "192.168.1.21",
-					},
-				}, nil)
-				args.e.ExecuteReturnsOnCall(3, exec.Result{
-					Status: &exec.Status{
-						Output: `password: some-password
-password_hash: some-password-hash
-username: some-username
-`,
-					},
-				}, nil)
-				args.e.ExecuteReturnsOnCall(4, exec.Result{
-					Status: &exec.Status{
-						Output: "some-encryption-key",
-					},
-				}, nil)
+			want: &DatabaseConfig{
+				Host:          "192.168.1.21",
+				Username:      "some-username",
+				Password:      "some-password",
+				EncryptionKey: "some-encryption-key",
 			},
-			afterFunc: func(args args) {
-				require.Equal(t, 5, args.e.ExecuteCallCount())
+			afterFunc: func(t *testing.T, args args) {
+				require.Equal(t, 1, args.client.FindDeploymentCallCount())
+				require.Equal(t, 1, args.client.FindInstancesCallCount())
+				require.Equal(t, 2, args.credhubClient.GetCredentialCallCount())
 			},
 		},
 		{
 			name: "does not retrieve creds if already set",
 			args: args{
-				e: new(fakes.FakeExecutor),
+				client:        newBoshClient(),
+				credhubClient: newCredhubClient(),
 				cfg: &DatabaseConfig{
 					Username: "ccdb-username",
 					Password: "ccdb-password",
 				},
-				isExport: false,
 				om: config.OpsManager{
 					Hostname: "om.target.com",
 				},
 				config: &config.Migration{},
 			},
-			beforeFunc: func(args args) {
-				args.e.ExecuteReturnsOnCall(0, exec.Result{
-					Status: &exec.Status{
-						Output: "cf-abc12345678de1234567",
-					},
-				}, nil)
-				args.e.ExecuteReturnsOnCall(1, exec.Result{
-					Status: &exec.Status{
-						Output: "mysql_proxy/guid",
-					},
-				}, nil)
-				args.e.ExecuteReturnsOnCall(2, exec.Result{
-					Status: &exec.Status{
-						Output: "192.168.1.21",
-					},
-				}, nil)
-				args.e.ExecuteReturnsOnCall(3, exec.Result{
-					Status: &exec.Status{
-						Output: "some-encryption-key",
-					},
-				}, nil)
+			want: &DatabaseConfig{
+				Host:          "192.168.1.21",
+				Username:      "ccdb-username",
+				Password:      "ccdb-password",
+				EncryptionKey: "some-encryption-key",
 			},
-			afterFunc: func(args args) {
-				require.Equal(t, 4, args.e.ExecuteCallCount())
+			afterFunc: func(t *testing.T, args args) {
+				require.Equal(t, 1, args.client.FindInstancesCallCount())
+				require.Equal(t, 1, args.credhubClient.GetCredentialCallCount())
 			},
 		},
 		{
 			name: "does not retrieve encryption key if already set",
 			args: args{
-				e: new(fakes.FakeExecutor),
+				client:        newBoshClient(),
+				credhubClient: newCredhubClient(),
 				cfg: &DatabaseConfig{
 					EncryptionKey: "some-key",
 				},
-				isExport: false,
 				om: config.OpsManager{
 					Hostname: "om.target.com",
 				},
 				config: &config.Migration{},
 			},
-			beforeFunc: func(args args) {
-				args.e.ExecuteReturnsOnCall(0, exec.Result{
-					Status: &exec.Status{
-						Output: "cf-abc12345678de1234567",
-					},
-				}, nil)
-				args.e.ExecuteReturnsOnCall(1, exec.Result{
-					Status: &exec.Status{
-						Output: "mysql_proxy/guid",
-					},
-				}, nil)
-				args.e.ExecuteReturnsOnCall(2, exec.Result{
-					Status: &exec.Status{
-						Output: "192.168.1.21",
-					},
-				}, nil)
-				args.e.ExecuteReturnsOnCall(3, exec.Result{
-					Status: &exec.Status{
-						Output: `password: some-password
-password_hash: some-password-hash
-username: some-username
-`,
-					},
-				}, nil)
+			want: &DatabaseConfig{
+				Host:          "192.168.1.21",
+				Username:      "some-username",
+				Password:      "some-password",
+				EncryptionKey: "some-key",
 			},
-			afterFunc: func(args args) {
-				require.Equal(t, 4, args.e.ExecuteCallCount())
+			afterFunc: func(t *testing.T, args args) {
+				require.Equal(t, 1, args.client.FindInstancesCallCount())
+				require.Equal(t, 1, args.credhubClient.GetCredentialCallCount())
 			},
 		},
 		{
 			name: "does not retrieve ip address if already set",
 			args: args{
-				e: new(fakes.FakeExecutor),
+				client:        newBoshClient(),
+				credhubClient: newCredhubClient(),
 				cfg: &DatabaseConfig{
 					Host: "192.168.1.20",
 				},
-				isExport: false,
 				om: config.OpsManager{
 					Hostname: "om.target.com",
 				},
 				config: &config.Migration{},
 			},
-			beforeFunc: func(args args) {
-				args.e.ExecuteReturnsOnCall(0, exec.Result{
-					Status: &exec.Status{
-						Output: "cf-abc12345678de1234567",
-					},
-				}, nil)
-				args.e.ExecuteReturnsOnCall(1, exec.Result{
-					Status: &exec.Status{
-						Output: `password: some-password
-password_hash: some-password-hash
-username: some-username
-`,
-					},
-				}, nil)
-				args.e.ExecuteReturnsOnCall(2, exec.Result{
-					Status: &exec.Status{
-						Output: "some-encryption-key",
-					},
-				}, nil)
+			want: &DatabaseConfig{
+				Host:          "192.168.1.20",
+				Username:      "some-username",
+				Password:      "some-password",
+				EncryptionKey: "some-encryption-key",
 			},
-			afterFunc: func(args args) {
-				require.Equal(t, 3, args.e.ExecuteCallCount())
+			afterFunc: func(t *testing.T, args args) {
+				require.Equal(t, 0, args.client.FindInstancesCallCount())
+				require.Equal(t, 2, args.credhubClient.GetCredentialCallCount())
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.beforeFunc(tt.args)
-			_, err := flow.RunWith(SetCloudControllerDatabaseCredentials(tt.args.e, tt.args.cfg, tt.args.om), context.TODO(), tt.args.config, tt.args.dryRun)
-			if err != nil && tt.wantErr == nil {
-				require.NoError(t, err)
-			} else if tt.wantErr != nil {
-				require.Error(t, err, tt.wantErr)
+			_, err := flow.RunWith(SetCloudControllerDatabaseCredentials(tt.args.client, tt.args.credhubClient, tt.args.cfg, tt.args.om), context.TODO(), tt.args.config, tt.args.dryRun)
+			if tt.wantErr != nil {
 				require.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.want, tt.args.cfg)
 			}
-			tt.afterFunc(tt.args)
+			tt.afterFunc(t, tt.args)
 		})
 	}
 }
+
+func newBoshClient() *boshfakes.FakeClient {
+	client := new(boshfakes.FakeClient)
+	client.FindDeploymentReturns(director.DeploymentResp{Name: "cf-abc12345678de1234567"}, true, nil)
+	client.FindInstancesReturns([]director.VMInfo{
+		{JobName: "mysql_proxy", IPs: []string{"192.168.1.21"}, Processes: []director.VMInfoProcess{{Name: "proxy"}}},
+	}, nil)
+	return client
+}
+
+func newCredhubClient() *credhubfakes.FakeClient {
+	return &credhubfakes.FakeClient{
+		GetCredentialStub: func(name string) (credhub.Credential, error) {
+			switch name {
+			case "/p-bosh/cf-abc12345678de1234567/cc-db-credentials":
+				return credhub.Credential{Name: name, Type: "user", Value: map[string]interface{}{
+					"username": "some-username",
+					"password": "some-password",
+				}}, nil
+			case "/opsmgr/cf-abc12345678de1234567/cloud_controller/db_encryption_credentials":
+				return credhub.Credential{Name: name, Type: "password", Value: map[string]interface{}{
+					"password": "some-encryption-key",
+				}}, nil
+			}
+			return credhub.Credential{}, errors.New("status 'Not Found'")
+		},
+	}
+}
@@ -37,6 +37,9 @@ type Repository interface {
 	CreateServiceInstance(si cfclient.ServiceInstance, targetSpace cfclient.Space, targetPlan cfclient.ServicePlan, targetService cfclient.Service, key string) error
 	DeleteServiceInstance(spaceGUID string, serviceInstanceGUID string) (bool, error)
 	CreateServiceBinding(binding cfclient.ServiceBinding, appGUID string, encryptionKey string) error
+	SnapshotServiceInstance(serviceInstanceGUID string) (*ServiceInstanceSnapshot, error)
+	RestoreServiceInstance(snapshot *ServiceInstanceSnapshot) error
+	PurgeServiceInstance(serviceInstanceGUID string) error
 }
 
 // NewCCDBConnection will establish a connection to the CCDB on the host with the specified
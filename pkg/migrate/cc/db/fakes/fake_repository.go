@@ -51,6 +51,28 @@ type FakeRepository struct {
 		result1 bool
 		result2 error
 	}
+	PurgeServiceInstanceStub        func(string) error
+	purgeServiceInstanceMutex       sync.RWMutex
+	purgeServiceInstanceArgsForCall []struct {
+		arg1 string
+	}
+	purgeServiceInstanceReturns struct {
+		result1 error
+	}
+	purgeServiceInstanceReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RestoreServiceInstanceStub        func(*db.ServiceInstanceSnapshot) error
+	restoreServiceInstanceMutex       sync.RWMutex
+	restoreServiceInstanceArgsForCall []struct {
+		arg1 *db.ServiceInstanceSnapshot
+	}
+	restoreServiceInstanceReturns struct {
+		result1 error
+	}
+	restoreServiceInstanceReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ServiceInstanceExistsStub        func(string) (bool, error)
 	serviceInstanceExistsMutex       sync.RWMutex
 	serviceInstanceExistsArgsForCall []struct {
@@ -64,6 +86,19 @@ type FakeRepository struct {
 		result1 bool
 		result2 error
 	}
+	SnapshotServiceInstanceStub        func(string) (*db.ServiceInstanceSnapshot, error)
+	snapshotServiceInstanceMutex       sync.RWMutex
+	snapshotServiceInstanceArgsForCall []struct {
+		arg1 string
+	}
+	snapshotServiceInstanceReturns struct {
+		result1 *db.ServiceInstanceSnapshot
+		result2 error
+	}
+	snapshotServiceInstanceReturnsOnCall map[int]struct {
+		result1 *db.ServiceInstanceSnapshot
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -261,6 +296,128 @@ func (fake *FakeRepository) DeleteServiceInstanceReturnsOnCall(i int, result1 bo
 	}{result1, result2}
 }
 
+func (fake *FakeRepository) PurgeServiceInstance(arg1 string) error {
+	fake.purgeServiceInstanceMutex.Lock()
+	ret, specificReturn := fake.purgeServiceInstanceReturnsOnCall[len(fake.purgeServiceInstanceArgsForCall)]
+	fake.purgeServiceInstanceArgsForCall = append(fake.purgeServiceInstanceArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.PurgeServiceInstanceStub
+	fakeReturns := fake.purgeServiceInstanceReturns
+	fake.recordInvocation("PurgeServiceInstance", []interface{}{arg1})
+	fake.purgeServiceInstanceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRepository) PurgeServiceInstanceCallCount() int {
+	fake.purgeServiceInstanceMutex.RLock()
+	defer fake.purgeServiceInstanceMutex.RUnlock()
+	return len(fake.purgeServiceInstanceArgsForCall)
+}
+
+func (fake *FakeRepository) PurgeServiceInstanceCalls(stub func(string) error) {
+	fake.purgeServiceInstanceMutex.Lock()
+	defer fake.purgeServiceInstanceMutex.Unlock()
+	fake.PurgeServiceInstanceStub = stub
+}
+
+func (fake *FakeRepository) PurgeServiceInstanceArgsForCall(i int) string {
+	fake.purgeServiceInstanceMutex.RLock()
+	defer fake.purgeServiceInstanceMutex.RUnlock()
+	argsForCall := fake.purgeServiceInstanceArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRepository) PurgeServiceInstanceReturns(result1 error) {
+	fake.purgeServiceInstanceMutex.Lock()
+	defer fake.purgeServiceInstanceMutex.Unlock()
+	fake.PurgeServiceInstanceStub = nil
+	fake.purgeServiceInstanceReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) PurgeServiceInstanceReturnsOnCall(i int, result1 error) {
+	fake.purgeServiceInstanceMutex.Lock()
+	defer fake.purgeServiceInstanceMutex.Unlock()
+	fake.PurgeServiceInstanceStub = nil
+	if fake.purgeServiceInstanceReturnsOnCall == nil {
+		fake.purgeServiceInstanceReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.purgeServiceInstanceReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) RestoreServiceInstance(arg1 *db.ServiceInstanceSnapshot) error {
+	fake.restoreServiceInstanceMutex.Lock()
+	ret, specificReturn := fake.restoreServiceInstanceReturnsOnCall[len(fake.restoreServiceInstanceArgsForCall)]
+	fake.restoreServiceInstanceArgsForCall = append(fake.restoreServiceInstanceArgsForCall, struct {
+		arg1 *db.ServiceInstanceSnapshot
+	}{arg1})
+	stub := fake.RestoreServiceInstanceStub
+	fakeReturns := fake.restoreServiceInstanceReturns
+	fake.recordInvocation("RestoreServiceInstance", []interface{}{arg1})
+	fake.restoreServiceInstanceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRepository) RestoreServiceInstanceCallCount() int {
+	fake.restoreServiceInstanceMutex.RLock()
+	defer fake.restoreServiceInstanceMutex.RUnlock()
+	return len(fake.restoreServiceInstanceArgsForCall)
+}
+
+func (fake *FakeRepository) RestoreServiceInstanceCalls(stub func(*db.ServiceInstanceSnapshot) error) {
+	fake.restoreServiceInstanceMutex.Lock()
+	defer fake.restoreServiceInstanceMutex.Unlock()
+	fake.RestoreServiceInstanceStub = stub
+}
+
+func (fake *FakeRepository) RestoreServiceInstanceArgsForCall(i int) *db.ServiceInstanceSnapshot {
+	fake.restoreServiceInstanceMutex.RLock()
+	defer fake.restoreServiceInstanceMutex.RUnlock()
+	argsForCall := fake.restoreServiceInstanceArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRepository) RestoreServiceInstanceReturns(result1 error) {
+	fake.restoreServiceInstanceMutex.Lock()
+	defer fake.restoreServiceInstanceMutex.Unlock()
+	fake.RestoreServiceInstanceStub = nil
+	fake.restoreServiceInstanceReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) RestoreServiceInstanceReturnsOnCall(i int, result1 error) {
+	fake.restoreServiceInstanceMutex.Lock()
+	defer fake.restoreServiceInstanceMutex.Unlock()
+	fake.RestoreServiceInstanceStub = nil
+	if fake.restoreServiceInstanceReturnsOnCall == nil {
+		fake.restoreServiceInstanceReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.restoreServiceInstanceReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRepository) ServiceInstanceExists(arg1 string) (bool, error) {
 	fake.serviceInstanceExistsMutex.Lock()
 	ret, specificReturn := fake.serviceInstanceExistsReturnsOnCall[len(fake.serviceInstanceExistsArgsForCall)]
@@ -325,6 +482,70 @@ func (fake *FakeRepository) ServiceInstanceExistsReturnsOnCall(i int, result1 bo
 	}{result1, result2}
 }
 
+func (fake *FakeRepository) SnapshotServiceInstance(arg1 string) (*db.ServiceInstanceSnapshot, error) {
+	fake.snapshotServiceInstanceMutex.Lock()
+	ret, specificReturn := fake.snapshotServiceInstanceReturnsOnCall[len(fake.snapshotServiceInstanceArgsForCall)]
+	fake.snapshotServiceInstanceArgsForCall = append(fake.snapshotServiceInstanceArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.SnapshotServiceInstanceStub
+	fakeReturns := fake.snapshotServiceInstanceReturns
+	fake.recordInvocation("SnapshotServiceInstance", []interface{}{arg1})
+	fake.snapshotServiceInstanceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) SnapshotServiceInstanceCallCount() int {
+	fake.snapshotServiceInstanceMutex.RLock()
+	defer fake.snapshotServiceInstanceMutex.RUnlock()
+	return len(fake.snapshotServiceInstanceArgsForCall)
+}
+
+func (fake *FakeRepository) SnapshotServiceInstanceCalls(stub func(string) (*db.ServiceInstanceSnapshot, error)) {
+	fake.snapshotServiceInstanceMutex.Lock()
+	defer fake.snapshotServiceInstanceMutex.Unlock()
+	fake.SnapshotServiceInstanceStub = stub
+}
+
+func (fake *FakeRepository) SnapshotServiceInstanceArgsForCall(i int) string {
+	fake.snapshotServiceInstanceMutex.RLock()
+	defer fake.snapshotServiceInstanceMutex.RUnlock()
+	argsForCall := fake.snapshotServiceInstanceArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRepository) SnapshotServiceInstanceReturns(result1 *db.ServiceInstanceSnapshot, result2 error) {
+	fake.snapshotServiceInstanceMutex.Lock()
+	defer fake.snapshotServiceInstanceMutex.Unlock()
+	fake.SnapshotServiceInstanceStub = nil
+	fake.snapshotServiceInstanceReturns = struct {
+		result1 *db.ServiceInstanceSnapshot
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) SnapshotServiceInstanceReturnsOnCall(i int, result1 *db.ServiceInstanceSnapshot, result2 error) {
+	fake.snapshotServiceInstanceMutex.Lock()
+	defer fake.snapshotServiceInstanceMutex.Unlock()
+	fake.SnapshotServiceInstanceStub = nil
+	if fake.snapshotServiceInstanceReturnsOnCall == nil {
+		fake.snapshotServiceInstanceReturnsOnCall = make(map[int]struct {
+			result1 *db.ServiceInstanceSnapshot
+			result2 error
+		})
+	}
+	fake.snapshotServiceInstanceReturnsOnCall[i] = struct {
+		result1 *db.ServiceInstanceSnapshot
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRepository) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -334,8 +555,14 @@ func (fake *FakeRepository) Invocations() map[string][][]interface{} {
 	defer fake.createServiceInstanceMutex.RUnlock()
 	fake.deleteServiceInstanceMutex.RLock()
 	defer fake.deleteServiceInstanceMutex.RUnlock()
+	fake.purgeServiceInstanceMutex.RLock()
+	defer fake.purgeServiceInstanceMutex.RUnlock()
+	fake.restoreServiceInstanceMutex.RLock()
+	defer fake.restoreServiceInstanceMutex.RUnlock()
 	fake.serviceInstanceExistsMutex.RLock()
 	defer fake.serviceInstanceExistsMutex.RUnlock()
+	fake.snapshotServiceInstanceMutex.RLock()
+	defer fake.snapshotServiceInstanceMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
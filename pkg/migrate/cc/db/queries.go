@@ -25,4 +25,11 @@ const (
 	GetIDFromGUIDLimitToOneTemplateQuery        = `SELECT id FROM %s WHERE guid=? LIMIT 1`
 	GetIDFromGUIDTemplateQuery                  = `SELECT id FROM %s WHERE guid=?`
 	GetServiceInstanceSharesQuery               = `SELECT service_instance_guid, target_space_guid FROM service_instance_shares WHERE service_instance_guid=? AND target_space_guid=?`
+	GetServiceInstanceRowQuery                  = `SELECT * FROM service_instances WHERE guid=?`
+	GetServiceBindingRowsQuery                  = `SELECT * FROM service_bindings WHERE service_instance_guid=?`
+	GetServiceKeyRowsQuery                      = `SELECT * FROM service_keys WHERE service_instance_id=?`
+	GetServiceInstanceOperationRowsQuery        = `SELECT * FROM service_instance_operations WHERE service_instance_id=?`
+	InsertRowTemplateStatement                  = "INSERT INTO `%s` (%s) VALUES (%s)"
+	DeleteServiceInstanceByGUIDSQLStatement     = `DELETE FROM service_instances WHERE guid=?`
+	DeleteServiceUsageEventsSQLStatement        = `DELETE FROM service_usage_events WHERE service_instance_guid=?`
 )
@@ -0,0 +1,235 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package db
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
+)
+
+// columnName guards the generated insert statements, since snapshots are read back from the export directory
+var columnName = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// Row is a CCDB table row keyed by column name
+type Row map[string]interface{}
+
+// ServiceInstanceSnapshot holds the CCDB rows of a service instance, captured before
+// the instance is deleted so that it can be put back on rollback
+type ServiceInstanceSnapshot struct {
+	ServiceInstance           Row   `json:"service_instance"`
+	ServiceBindings           []Row `json:"service_bindings,omitempty"`
+	ServiceKeys               []Row `json:"service_keys,omitempty"`
+	ServiceInstanceOperations []Row `json:"service_instance_operations,omitempty"`
+}
+
+// GUID is the guid of the captured service instance
+func (s ServiceInstanceSnapshot) GUID() string {
+	if guid, ok := s.ServiceInstance["guid"].(string); ok {
+		return guid
+	}
+	return ""
+}
+
+// SnapshotServiceInstance captures the service instance row along with its bindings, keys and operations
+func (d *CloudController) SnapshotServiceInstance(serviceInstanceGUID string) (*ServiceInstanceSnapshot, error) {
+	instances, err := d.selectRows(GetServiceInstanceRowQuery, serviceInstanceGUID)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("could not find service instance with guid %s", serviceInstanceGUID))
+	}
+
+	if len(instances) == 0 {
+		return nil, fmt.Errorf("could not find service instance with guid %s", serviceInstanceGUID)
+	}
+
+	id := instances[0]["id"]
+
+	bindings, err := d.selectRows(GetServiceBindingRowsQuery, serviceInstanceGUID)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("could not get service bindings for service instance %s", serviceInstanceGUID))
+	}
+
+	keys, err := d.selectRows(GetServiceKeyRowsQuery, id)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("could not get service keys for service instance %s", serviceInstanceGUID))
+	}
+
+	operations, err := d.selectRows(GetServiceInstanceOperationRowsQuery, id)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("could not get service instance operations for service instance %s", serviceInstanceGUID))
+	}
+
+	return &ServiceInstanceSnapshot{
+		ServiceInstance:           instances[0],
+		ServiceBindings:           bindings,
+		ServiceKeys:               keys,
+		ServiceInstanceOperations: operations,
+	}, nil
+}
+
+// RestoreServiceInstance re-inserts the rows captured in the snapshot. Nothing is inserted
+// if the service instance still exists.
+func (d *CloudController) RestoreServiceInstance(snapshot *ServiceInstanceSnapshot) error {
+	guid := snapshot.GUID()
+	if guid == "" {
+		return errors.New("snapshot does not contain a service instance")
+	}
+
+	exists, err := d.ServiceInstanceExists(guid)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		log.Debugf("Service instance %q already exists, nothing to restore", guid)
+		return nil
+	}
+
+	tx, err := d.DB.Beginx()
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Restoring service instance %q in ccdb...", guid)
+	if err = insertRow(tx, "service_instances", snapshot.ServiceInstance); err != nil {
+		return rollback(tx, err)
+	}
+
+	for _, row := range snapshot.ServiceInstanceOperations {
+		if err = insertRow(tx, "service_instance_operations", row); err != nil {
+			return rollback(tx, err)
+		}
+	}
+
+	for _, row := range snapshot.ServiceKeys {
+		if err = insertRow(tx, "service_keys", row); err != nil {
+			return rollback(tx, err)
+		}
+	}
+
+	for _, row := range snapshot.ServiceBindings {
+		if err = insertRow(tx, "service_bindings", row); err != nil {
+			return rollback(tx, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return rollback(tx, err)
+	}
+
+	return nil
+}
+
+// PurgeServiceInstance deletes a service instance created by an import along with its bindings,
+// keys, operations and usage events
+func (d *CloudController) PurgeServiceInstance(serviceInstanceGUID string) error {
+	var serviceInstanceIDs []idResponse
+	if err := d.DB.Select(&serviceInstanceIDs, fmt.Sprintf(GetIDFromGUIDTemplateQuery, "service_instances"), serviceInstanceGUID); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("could not find service instance with guid %s", serviceInstanceGUID))
+	}
+
+	tx, err := d.DB.Beginx()
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Purging service instance %q from ccdb...", serviceInstanceGUID)
+	if _, err = tx.Exec(DeleteServiceBindingsSQLStatement, serviceInstanceGUID); err != nil {
+		return rollback(tx, err)
+	}
+
+	for _, row := range serviceInstanceIDs {
+		if _, err = tx.Exec(DeleteServiceKeysSQLStatement, row.ID); err != nil {
+			return rollback(tx, err)
+		}
+		if _, err = tx.Exec(DeleteServiceInstanceOperationsSQLStatement, row.ID); err != nil {
+			return rollback(tx, err)
+		}
+	}
+
+	if _, err = tx.Exec(DeleteServiceUsageEventsSQLStatement, serviceInstanceGUID); err != nil {
+		return rollback(tx, err)
+	}
+
+	if _, err = tx.Exec(DeleteServiceInstanceByGUIDSQLStatement, serviceInstanceGUID); err != nil {
+		return rollback(tx, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return rollback(tx, err)
+	}
+
+	return nil
+}
+
+func (d *CloudController) selectRows(query string, args ...interface{}) ([]Row, error) {
+	rows, err := d.DB.Queryx(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []Row
+	for rows.Next() {
+		row := make(map[string]interface{})
+		if err = rows.MapScan(row); err != nil {
+			return nil, err
+		}
+		for k, v := range row {
+			// the mysql driver returns text columns as raw bytes
+			if b, ok := v.([]byte); ok {
+				row[k] = string(b)
+			}
+		}
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
+
+func insertRow(tx *sqlx.Tx, table string, row Row) error {
+	columns := make([]string, 0, len(row))
+	for c := range row {
+		columns = append(columns, c)
+	}
+	sort.Strings(columns)
+
+	quoted := make([]string, len(columns))
+	placeholders := make([]string, len(columns))
+	values := make([]interface{}, len(columns))
+	for i, c := range columns {
+		if !columnName.MatchString(c) {
+			return fmt.Errorf("invalid column name %q for table %s", c, table)
+		}
+		quoted[i] = "`" + c + "`"
+		placeholders[i] = "?"
+		values[i] = row[c]
+	}
+
+	_, err := tx.Exec(fmt.Sprintf(InsertRowTemplateStatement, table, strings.Join(quoted, ", "), strings.Join(placeholders, ", ")), values...)
+	return err
+}
+
+func rollback(tx *sqlx.Tx, err error) error {
+	if rollbackErr := tx.Rollback(); rollbackErr != nil {
+		return fmt.Errorf("%v: %w", err, rollbackErr)
+	}
+	return err
+}
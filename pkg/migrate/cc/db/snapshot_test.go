@@ -0,0 +1,201 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package db_test
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/cc/db"
+)
+
+func TestSnapshotServiceInstance(t *testing.T) {
+	spec.Run(t, "SnapshotServiceInstance", testSnapshotServiceInstance, spec.Report(report.Terminal{}))
+}
+
+func testSnapshotServiceInstance(t *testing.T, when spec.G, it spec.S) {
+	var (
+		dbConn *sql.DB
+		ccdb   db.Repository
+		mock   sqlmock.Sqlmock
+	)
+
+	it.Before(func() {
+		RegisterTestingT(t)
+
+		var err error
+
+		dbConn, mock, err = sqlmock.New()
+		Expect(err).NotTo(HaveOccurred())
+
+		dbx := sqlx.NewDb(dbConn, "mysql")
+		ccdb = &db.CloudController{
+			DB:         dbx,
+			SaltLength: 8,
+		}
+	})
+
+	it.After(func() {
+		dbConn.Close()
+	})
+
+	when("taking a snapshot of a service instance", func() {
+		when("the service instance exists", func() {
+			it.Before(func() {
+				mock.ExpectQuery(`SELECT \* FROM service_instances`).WithArgs("123").WillReturnRows(sqlmock.NewRows([]string{"id", "guid", "name"}).AddRow(42, []byte("123"), []byte("my-si")))
+				mock.ExpectQuery(`SELECT \* FROM service_bindings`).WithArgs("123").WillReturnRows(sqlmock.NewRows([]string{"id", "guid", "service_instance_guid"}).AddRow(1, []byte("binding-guid"), []byte("123")))
+				mock.ExpectQuery(`SELECT \* FROM service_keys`).WithArgs(42).WillReturnRows(sqlmock.NewRows([]string{"id", "guid", "service_instance_id"}).AddRow(2, []byte("key-guid"), 42))
+				mock.ExpectQuery(`SELECT \* FROM service_instance_operations`).WithArgs(42).WillReturnRows(sqlmock.NewRows([]string{"id", "service_instance_id"}))
+			})
+
+			it("captures the rows", func() {
+				snapshot, err := ccdb.SnapshotServiceInstance("123")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(snapshot.GUID()).To(Equal("123"))
+				Expect(snapshot.ServiceInstance).To(Equal(db.Row{"id": int64(42), "guid": "123", "name": "my-si"}))
+				Expect(snapshot.ServiceBindings).To(Equal([]db.Row{{"id": int64(1), "guid": "binding-guid", "service_instance_guid": "123"}}))
+				Expect(snapshot.ServiceKeys).To(Equal([]db.Row{{"id": int64(2), "guid": "key-guid", "service_instance_id": int64(42)}}))
+				Expect(snapshot.ServiceInstanceOperations).To(BeEmpty())
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+		})
+
+		when("the service instance does not exist", func() {
+			it.Before(func() {
+				mock.ExpectQuery(`SELECT \* FROM service_instances`).WithArgs("123").WillReturnRows(sqlmock.NewRows([]string{"id", "guid"}))
+			})
+
+			it("fails", func() {
+				_, err := ccdb.SnapshotServiceInstance("123")
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	when("restoring a service instance", func() {
+		var snapshot *db.ServiceInstanceSnapshot
+
+		it.Before(func() {
+			snapshot = &db.ServiceInstanceSnapshot{
+				ServiceInstance: db.Row{"id": 42, "guid": "123", "name": "my-si"},
+				ServiceKeys:     []db.Row{{"id": 2, "service_instance_id": 42}},
+				ServiceBindings: []db.Row{{"guid": "binding-guid", "service_instance_guid": "123"}},
+			}
+		})
+
+		when("the service instance was deleted", func() {
+			it.Before(func() {
+				mock.ExpectQuery(`SELECT id FROM service_instances`).WithArgs("123").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `service_instances` (`guid`, `id`, `name`) VALUES (?, ?, ?)")).WithArgs("123", 42, "my-si").WillReturnResult(sqlmock.NewResult(42, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `service_keys` (`id`, `service_instance_id`) VALUES (?, ?)")).WithArgs(2, 42).WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `service_bindings` (`guid`, `service_instance_guid`) VALUES (?, ?)")).WithArgs("binding-guid", "123").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			})
+
+			it("re-inserts the rows", func() {
+				err := ccdb.RestoreServiceInstance(snapshot)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+		})
+
+		when("the service instance still exists", func() {
+			it.Before(func() {
+				mock.ExpectQuery(`SELECT id FROM service_instances`).WithArgs("123").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
+			})
+
+			it("does nothing", func() {
+				err := ccdb.RestoreServiceInstance(snapshot)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+		})
+
+		when("an insert fails", func() {
+			it.Before(func() {
+				mock.ExpectQuery(`SELECT id FROM service_instances`).WithArgs("123").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `service_instances`").WillReturnError(errors.New("test-error"))
+				mock.ExpectRollback()
+			})
+
+			it("rolls back the transaction", func() {
+				err := ccdb.RestoreServiceInstance(snapshot)
+				Expect(err).To(HaveOccurred())
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+		})
+
+		when("the snapshot contains an invalid column", func() {
+			it.Before(func() {
+				snapshot.ServiceInstance["guid`) VALUES (1); --"] = "x"
+				mock.ExpectQuery(`SELECT id FROM service_instances`).WithArgs("123").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			})
+
+			it("fails", func() {
+				err := ccdb.RestoreServiceInstance(snapshot)
+				Expect(err).To(HaveOccurred())
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+		})
+	})
+
+	when("purging a service instance", func() {
+		when("the service instance exists", func() {
+			it.Before(func() {
+				mock.ExpectQuery(`SELECT id FROM service_instances`).WithArgs("123").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
+				mock.ExpectBegin()
+				mock.ExpectExec(`DELETE FROM service_bindings`).WithArgs("123").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`DELETE FROM service_keys`).WithArgs(42).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`DELETE FROM service_instance_operations`).WithArgs(42).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`DELETE FROM service_usage_events`).WithArgs("123").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`DELETE FROM service_instances`).WithArgs("123").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			})
+
+			it("deletes the rows created by the import", func() {
+				err := ccdb.PurgeServiceInstance("123")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+		})
+
+		when("deleting the usage events fails", func() {
+			it.Before(func() {
+				mock.ExpectQuery(`SELECT id FROM service_instances`).WithArgs("123").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectBegin()
+				mock.ExpectExec(`DELETE FROM service_bindings`).WithArgs("123").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`DELETE FROM service_usage_events`).WithArgs("123").WillReturnError(errors.New("test-error"))
+				mock.ExpectRollback()
+			})
+
+			it("rolls back the transaction", func() {
+				err := ccdb.PurgeServiceInstance("123")
+				Expect(err).To(HaveOccurred())
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+		})
+	})
+}
@@ -17,28 +17,30 @@ package cc
 import (
 	"context"
 	"fmt"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/bosh"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
-	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/exec"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/credhub"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/flow"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
 )
 
-func NewExportSequence(org, space string, service Service, instance *cf.ServiceInstance, executor exec.Executor, manager config.OpsManager, controller *DatabaseConfig) flow.Flow {
+func NewExportSequence(org, space string, service Service, instance *cf.ServiceInstance, client bosh.Client, credhubClient credhub.Client, manager config.OpsManager, controller *DatabaseConfig, rollback RollbackLog) flow.Flow {
 	return flow.ProgressBarSequence(
 		fmt.Sprintf("Exporting %s", instance.Name),
 		flow.StepWithProgressBar(
-			SetCloudControllerDatabaseCredentials(executor, controller, manager),
+			SetCloudControllerDatabaseCredentials(client, credhubClient, controller, manager),
 			flow.WithDisplay("Setting cc credentials"),
+			flow.WithAlwaysRun(),
 		),
 		flow.StepWithProgressBar(
-			Export(org, space, service, instance),
+			Export(org, space, service, instance, rollback),
 			flow.WithDisplay("Removing service instance"),
 		),
 	)
 }
 
-func Export(org, space string, service Service, instance *cf.ServiceInstance) flow.StepFunc {
+func Export(org, space string, service Service, instance *cf.ServiceInstance, rollback RollbackLog) flow.StepFunc {
 	return func(ctx context.Context, c interface{}, dryRun bool) (flow.Result, error) {
 		instance.Apps = make(map[string]string)
 		for _, binding := range instance.ServiceBindings {
@@ -57,8 +59,17 @@ func Export(org, space string, service Service, instance *cf.ServiceInstance) fl
 			}
 		}
 
+		snapshot, err := service.Snapshot(instance)
+		if err != nil {
+			return instance, err
+		}
+
+		if err = rollback.Register(RestoreServiceInstance, org, space, instance, snapshot); err != nil {
+			return instance, fmt.Errorf("failed to register rollback for service instance %q: %w", instance.Name, err)
+		}
+
 		log.Debugf("Deleting service instance %q in ccdb...", instance.GUID)
-		err := service.Delete(org, space, instance)
+		err = service.Delete(org, space, instance)
 		if err != nil {
 			return instance, err
 		}
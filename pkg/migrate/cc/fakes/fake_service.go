@@ -6,6 +6,7 @@ import (
 
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/cc"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/cc/db"
 )
 
 type FakeService struct {
@@ -104,6 +105,28 @@ type FakeService struct {
 		result1 string
 		result2 error
 	}
+	PurgeStub        func(*cf.ServiceInstance) error
+	purgeMutex       sync.RWMutex
+	purgeArgsForCall []struct {
+		arg1 *cf.ServiceInstance
+	}
+	purgeReturns struct {
+		result1 error
+	}
+	purgeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RestoreStub        func(*db.ServiceInstanceSnapshot) error
+	restoreMutex       sync.RWMutex
+	restoreArgsForCall []struct {
+		arg1 *db.ServiceInstanceSnapshot
+	}
+	restoreReturns struct {
+		result1 error
+	}
+	restoreReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ServiceInstanceExistsStub        func(string, string, string) (bool, error)
 	serviceInstanceExistsMutex       sync.RWMutex
 	serviceInstanceExistsArgsForCall []struct {
@@ -119,6 +142,19 @@ type FakeService struct {
 		result1 bool
 		result2 error
 	}
+	SnapshotStub        func(*cf.ServiceInstance) (*db.ServiceInstanceSnapshot, error)
+	snapshotMutex       sync.RWMutex
+	snapshotArgsForCall []struct {
+		arg1 *cf.ServiceInstance
+	}
+	snapshotReturns struct {
+		result1 *db.ServiceInstanceSnapshot
+		result2 error
+	}
+	snapshotReturnsOnCall map[int]struct {
+		result1 *db.ServiceInstanceSnapshot
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -571,6 +607,128 @@ func (fake *FakeService) FindAppByGUIDReturnsOnCall(i int, result1 string, resul
 	}{result1, result2}
 }
 
+func (fake *FakeService) Purge(arg1 *cf.ServiceInstance) error {
+	fake.purgeMutex.Lock()
+	ret, specificReturn := fake.purgeReturnsOnCall[len(fake.purgeArgsForCall)]
+	fake.purgeArgsForCall = append(fake.purgeArgsForCall, struct {
+		arg1 *cf.ServiceInstance
+	}{arg1})
+	stub := fake.PurgeStub
+	fakeReturns := fake.purgeReturns
+	fake.recordInvocation("Purge", []interface{}{arg1})
+	fake.purgeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeService) PurgeCallCount() int {
+	fake.purgeMutex.RLock()
+	defer fake.purgeMutex.RUnlock()
+	return len(fake.purgeArgsForCall)
+}
+
+func (fake *FakeService) PurgeCalls(stub func(*cf.ServiceInstance) error) {
+	fake.purgeMutex.Lock()
+	defer fake.purgeMutex.Unlock()
+	fake.PurgeStub = stub
+}
+
+func (fake *FakeService) PurgeArgsForCall(i int) *cf.ServiceInstance {
+	fake.purgeMutex.RLock()
+	defer fake.purgeMutex.RUnlock()
+	argsForCall := fake.purgeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeService) PurgeReturns(result1 error) {
+	fake.purgeMutex.Lock()
+	defer fake.purgeMutex.Unlock()
+	fake.PurgeStub = nil
+	fake.purgeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeService) PurgeReturnsOnCall(i int, result1 error) {
+	fake.purgeMutex.Lock()
+	defer fake.purgeMutex.Unlock()
+	fake.PurgeStub = nil
+	if fake.purgeReturnsOnCall == nil {
+		fake.purgeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.purgeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeService) Restore(arg1 *db.ServiceInstanceSnapshot) error {
+	fake.restoreMutex.Lock()
+	ret, specificReturn := fake.restoreReturnsOnCall[len(fake.restoreArgsForCall)]
+	fake.restoreArgsForCall = append(fake.restoreArgsForCall, struct {
+		arg1 *db.ServiceInstanceSnapshot
+	}{arg1})
+	stub := fake.RestoreStub
+	fakeReturns := fake.restoreReturns
+	fake.recordInvocation("Restore", []interface{}{arg1})
+	fake.restoreMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeService) RestoreCallCount() int {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	return len(fake.restoreArgsForCall)
+}
+
+func (fake *FakeService) RestoreCalls(stub func(*db.ServiceInstanceSnapshot) error) {
+	fake.restoreMutex.Lock()
+	defer fake.restoreMutex.Unlock()
+	fake.RestoreStub = stub
+}
+
+func (fake *FakeService) RestoreArgsForCall(i int) *db.ServiceInstanceSnapshot {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	argsForCall := fake.restoreArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeService) RestoreReturns(result1 error) {
+	fake.restoreMutex.Lock()
+	defer fake.restoreMutex.Unlock()
+	fake.RestoreStub = nil
+	fake.restoreReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeService) RestoreReturnsOnCall(i int, result1 error) {
+	fake.restoreMutex.Lock()
+	defer fake.restoreMutex.Unlock()
+	fake.RestoreStub = nil
+	if fake.restoreReturnsOnCall == nil {
+		fake.restoreReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.restoreReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeService) ServiceInstanceExists(arg1 string, arg2 string, arg3 string) (bool, error) {
 	fake.serviceInstanceExistsMutex.Lock()
 	ret, specificReturn := fake.serviceInstanceExistsReturnsOnCall[len(fake.serviceInstanceExistsArgsForCall)]
@@ -637,6 +795,70 @@ func (fake *FakeService) ServiceInstanceExistsReturnsOnCall(i int, result1 bool,
 	}{result1, result2}
 }
 
+func (fake *FakeService) Snapshot(arg1 *cf.ServiceInstance) (*db.ServiceInstanceSnapshot, error) {
+	fake.snapshotMutex.Lock()
+	ret, specificReturn := fake.snapshotReturnsOnCall[len(fake.snapshotArgsForCall)]
+	fake.snapshotArgsForCall = append(fake.snapshotArgsForCall, struct {
+		arg1 *cf.ServiceInstance
+	}{arg1})
+	stub := fake.SnapshotStub
+	fakeReturns := fake.snapshotReturns
+	fake.recordInvocation("Snapshot", []interface{}{arg1})
+	fake.snapshotMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeService) SnapshotCallCount() int {
+	fake.snapshotMutex.RLock()
+	defer fake.snapshotMutex.RUnlock()
+	return len(fake.snapshotArgsForCall)
+}
+
+func (fake *FakeService) SnapshotCalls(stub func(*cf.ServiceInstance) (*db.ServiceInstanceSnapshot, error)) {
+	fake.snapshotMutex.Lock()
+	defer fake.snapshotMutex.Unlock()
+	fake.SnapshotStub = stub
+}
+
+func (fake *FakeService) SnapshotArgsForCall(i int) *cf.ServiceInstance {
+	fake.snapshotMutex.RLock()
+	defer fake.snapshotMutex.RUnlock()
+	argsForCall := fake.snapshotArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeService) SnapshotReturns(result1 *db.ServiceInstanceSnapshot, result2 error) {
+	fake.snapshotMutex.Lock()
+	defer fake.snapshotMutex.Unlock()
+	fake.SnapshotStub = nil
+	fake.snapshotReturns = struct {
+		result1 *db.ServiceInstanceSnapshot
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeService) SnapshotReturnsOnCall(i int, result1 *db.ServiceInstanceSnapshot, result2 error) {
+	fake.snapshotMutex.Lock()
+	defer fake.snapshotMutex.Unlock()
+	fake.SnapshotStub = nil
+	if fake.snapshotReturnsOnCall == nil {
+		fake.snapshotReturnsOnCall = make(map[int]struct {
+			result1 *db.ServiceInstanceSnapshot
+			result2 error
+		})
+	}
+	fake.snapshotReturnsOnCall[i] = struct {
+		result1 *db.ServiceInstanceSnapshot
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeService) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -654,8 +876,14 @@ func (fake *FakeService) Invocations() map[string][][]interface{} {
 	defer fake.downloadManifestMutex.RUnlock()
 	fake.findAppByGUIDMutex.RLock()
 	defer fake.findAppByGUIDMutex.RUnlock()
+	fake.purgeMutex.RLock()
+	defer fake.purgeMutex.RUnlock()
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
 	fake.serviceInstanceExistsMutex.RLock()
 	defer fake.serviceInstanceExistsMutex.RUnlock()
+	fake.snapshotMutex.RLock()
+	defer fake.snapshotMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
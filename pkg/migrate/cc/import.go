@@ -17,22 +17,23 @@ package cc
 import (
 	"context"
 	"fmt"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/bosh"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
-	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/exec"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/credhub"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/flow"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
 )
 
-func NewImportSequence(org, space string, service Service, instance *cf.ServiceInstance, encryptionKey string, executor exec.Executor, manager config.OpsManager, controller *DatabaseConfig) flow.Flow {
+func NewImportSequence(org, space string, service Service, instance *cf.ServiceInstance, encryptionKey string, client bosh.Client, credhubClient credhub.Client, manager config.OpsManager, controller *DatabaseConfig, rollback RollbackLog) flow.Flow {
 	return flow.ProgressBarSequence(
 		fmt.Sprintf("Importing %s", instance.Name),
-		flow.StepWithProgressBar(SetCloudControllerDatabaseCredentials(executor, controller, manager), flow.WithDisplay("Setting cc credentials")),
-		flow.StepWithProgressBar(Import(org, space, service, instance, encryptionKey), flow.WithDisplay("Creating service instance")),
+		flow.StepWithProgressBar(SetCloudControllerDatabaseCredentials(client, credhubClient, controller, manager), flow.WithDisplay("Setting cc credentials"), flow.WithAlwaysRun()),
+		flow.StepWithProgressBar(Import(org, space, service, instance, encryptionKey, rollback), flow.WithDisplay("Creating service instance")),
 	)
 }
 
-func Import(org, space string, service Service, instance *cf.ServiceInstance, encryptionKey string) flow.StepFunc {
+func Import(org, space string, service Service, instance *cf.ServiceInstance, encryptionKey string, rollback RollbackLog) flow.StepFunc {
 	return func(ctx context.Context, c interface{}, dryRun bool) (flow.Result, error) {
 		exists, err := service.ServiceInstanceExists(org, space, instance.Name)
 		if err != nil {
@@ -47,6 +48,10 @@ func Import(org, space string, service Service, instance *cf.ServiceInstance, en
 			return nil, fmt.Errorf("ccdb encryption key is not set")
 		}
 
+		if err = rollback.Register(PurgeServiceInstance, org, space, instance, nil); err != nil {
+			return nil, fmt.Errorf("failed to register rollback for service instance %q: %w", instance.Name, err)
+		}
+
 		log.Debugf("Creating service instance %q in ccdb...", instance.GUID)
 		err = service.Create(org, space, instance, encryptionKey)
 		if err != nil {
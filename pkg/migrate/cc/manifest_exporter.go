@@ -96,8 +96,8 @@ func (m *DefaultManifestExporter) ExportAppManifest(org cfclient.Org, space cfcl
 	if v3app.Lifecycle.Type == "docker" {
 		manifestApp.Buildpacks = nil
 		manifestApp.Docker = struct {
-			Image    string "yaml:\"image,omitempty\""
-			Username string "yaml:\"username,omitempty\""
+			Image    string "yaml:\"image,omitempty\" json:\"image,omitempty\""
+			Username string "yaml:\"username,omitempty\" json:\"username,omitempty\""
 		}{
 			Image:    app.DockerImage,
 			Username: app.DockerCredentials.Username,
@@ -145,7 +145,7 @@ func (m *DefaultManifestExporter) ExportAppManifest(org cfclient.Org, space cfcl
 
 	manifestApp.NoRoute = len(routesResponse.Resources) == 0
 	manifestApp.Routes = make([]struct {
-		Route string `yaml:"route,omitempty"`
+		Route string `yaml:"route,omitempty" json:"route,omitempty"`
 	}, 0)
 
 	var routes []string
@@ -158,12 +158,13 @@ func (m *DefaultManifestExporter) ExportAppManifest(org cfclient.Org, space cfcl
 	adjustedRoutes := routeMapper.AdjustRoutes(routes)
 	for _, adjustedRoute := range adjustedRoutes {
 		manifestApp.Routes = append(manifestApp.Routes, struct {
-			Route string `yaml:"route,omitempty"`
+			Route string `yaml:"route,omitempty" json:"route,omitempty"`
 		}{adjustedRoute})
 	}
 
 	err = client.DoWithRetry(func() error {
-		req := client.NewRequest(http.MethodGet, fmt.Sprintf("/v2/apps/%s/service_bindings", app.Guid))
+		var err error
+		req := client.NewRequest(http.MethodGet, fmt.Sprintf("/v3/service_credential_bindings?type=app&app_guids=%s&include=service_instance", app.Guid))
 		res, err = client.DoRequest(req)
 		if err == nil {
 			if res.StatusCode >= 500 && res.StatusCode <= 599 {
@@ -182,8 +183,25 @@ func (m *DefaultManifestExporter) ExportAppManifest(org cfclient.Org, space cfcl
 		return cf.Application{}, err
 	}
 
-	var bindings cfclient.ServiceBindingsResponse
-	if err = json.NewDecoder(res.Body).Decode(&bindings); err != nil {
+	var bindingsResponse = struct {
+		Resources []struct {
+			Relationships struct {
+				ServiceInstance struct {
+					Data struct {
+						GUID string `json:"guid"`
+					} `json:"data"`
+				} `json:"service_instance"`
+			} `json:"relationships"`
+		} `json:"resources"`
+		Included struct {
+			ServiceInstances []struct {
+				GUID string `json:"guid"`
+				Name string `json:"name"`
+			} `json:"service_instances"`
+		} `json:"included"`
+	}{}
+
+	if err = json.NewDecoder(res.Body).Decode(&bindingsResponse); err != nil {
 		if res.Body != nil {
 			_ = res.Body.Close()
 		}
@@ -193,41 +211,16 @@ func (m *DefaultManifestExporter) ExportAppManifest(org cfclient.Org, space cfcl
 		_ = res.Body.Close()
 	}
 
-	manifestApp.Services = make([]string, 0, len(bindings.Resources))
-	for _, binding := range bindings.Resources {
-		var res *http.Response
-		err := client.DoWithRetry(func() error {
-			req := client.NewRequest(http.MethodGet, binding.Entity.ServiceInstanceUrl)
-			res, err = client.DoRequest(req)
-			if err == nil {
-				if res.StatusCode >= 500 && res.StatusCode <= 599 {
-					if res.Body != nil {
-						_ = res.Body.Close()
-					}
-					return cf.ErrRetry
-				}
-			}
-			return err
-		})
-		if err != nil {
-			if res != nil && res.Body != nil {
-				_ = res.Body.Close()
-			}
-			return cf.Application{}, err
-		}
+	instanceNames := make(map[string]string, len(bindingsResponse.Included.ServiceInstances))
+	for _, si := range bindingsResponse.Included.ServiceInstances {
+		instanceNames[si.GUID] = si.Name
+	}
 
-		var si cfclient.ServiceInstanceResource
-		if err = json.NewDecoder(res.Body).Decode(&si); err != nil {
-			if res.Body != nil {
-				_ = res.Body.Close()
-			}
-			return cf.Application{}, err
+	manifestApp.Services = make([]string, 0, len(bindingsResponse.Resources))
+	for _, binding := range bindingsResponse.Resources {
+		if name, ok := instanceNames[binding.Relationships.ServiceInstance.Data.GUID]; ok {
+			manifestApp.Services = append(manifestApp.Services, name)
 		}
-		if res != nil && res.Body != nil {
-			_ = res.Body.Close()
-		}
-
-		manifestApp.Services = append(manifestApp.Services, si.Entity.Name)
 	}
 
 	return manifestApp, nil
@@ -75,8 +75,8 @@ func TestDefaultManifestExporter_ExportAppManifest(t *testing.T) {
 				Command:    "",
 				DiskQuota:  "0M",
 				Docker: struct {
-					Image    string `yaml:"image,omitempty"`
-					Username string `yaml:"username,omitempty"`
+					Image    string `yaml:"image,omitempty" json:"image,omitempty"`
+					Username string `yaml:"username,omitempty" json:"username,omitempty"`
 				}{
 					Image:    "",
 					Username: "",
@@ -88,7 +88,7 @@ func TestDefaultManifestExporter_ExportAppManifest(t *testing.T) {
 				Memory:                  "0M",
 				NoRoute:                 false,
 				Routes: []struct {
-					Route string `yaml:"route,omitempty"`
+					Route string `yaml:"route,omitempty" json:"route,omitempty"`
 				}{
 					{
 						Route: "a-hostname.a-domain.com/some_path",
@@ -117,11 +117,7 @@ func TestDefaultManifestExporter_ExportAppManifest(t *testing.T) {
 				}, nil)
 				fakeClient.DoRequestReturnsOnCall(2, &http.Response{
 					StatusCode: http.StatusOK,
-					Body:       io.NopCloser(strings.NewReader(v2TestAppServiceBindings)),
-				}, nil)
-				fakeClient.DoRequestReturnsOnCall(3, &http.Response{
-					StatusCode: http.StatusOK,
-					Body:       io.NopCloser(strings.NewReader(v2TestAppServiceInstances)),
+					Body:       io.NopCloser(strings.NewReader(v3TestAppServiceCredentialBindings)),
 				}, nil)
 				fakeHolder.SourceCFClientStub = func() cf.Client {
 					return fakeClient
@@ -303,93 +299,75 @@ var v3TestAppRoutes = `{
 }
 `
 
-var v2TestAppServiceBindings = `{
-  "total_results": 1,
-  "total_pages": 1,
-  "prev_url": null,
-  "next_url": null,
+var v3TestAppServiceCredentialBindings = `{
+  "pagination": {
+    "total_results": 1,
+    "total_pages": 1,
+    "first": {
+      "href": "https://api.example.org/v3/service_credential_bindings?app_guids=2a3820bb-febd-4c90-ab66-80faa4362142&include=service_instance&page=1&per_page=50&type=app"
+    },
+    "last": {
+      "href": "https://api.example.org/v3/service_credential_bindings?app_guids=2a3820bb-febd-4c90-ab66-80faa4362142&include=service_instance&page=1&per_page=50&type=app"
+    },
+    "next": null,
+    "previous": null
+  },
   "resources": [
     {
+      "guid": "0b6e8fe9-b173-4845-a7aa-e093f1081c94",
+      "created_at": "2016-06-08T16:41:43Z",
+      "updated_at": "2016-06-08T16:41:26Z",
+      "name": "prod-db",
+      "type": "app",
+      "last_operation": {
+        "type": "create",
+        "state": "succeeded",
+        "description": "",
+        "updated_at": "2018-02-28T16:25:19Z",
+        "created_at": "2018-02-28T16:25:19Z"
+      },
       "metadata": {
-        "guid": "0b6e8fe9-b173-4845-a7aa-e093f1081c94",
-        "url": "/v2/service_bindings/0b6e8fe9-b173-4845-a7aa-e093f1081c94",
-        "created_at": "2016-06-08T16:41:43Z",
-        "updated_at": "2016-06-08T16:41:26Z"
+        "annotations": {},
+        "labels": {}
       },
-      "entity": {
-        "app_guid": "2a3820bb-febd-4c90-ab66-80faa4362142",
-        "service_instance_guid": "92f0f510-dbb1-4c04-aa7c-28a8dc0797b4",
-        "credentials": {
-          "creds-key-72": "creds-val-72"
-        },
-        "binding_options": {
-
-        },
-        "gateway_data": null,
-        "gateway_name": "",
-        "syslog_drain_url": null,
-        "volume_mounts": [
-
-        ],
-        "name": "prod-db",
-        "last_operation": {
-          "type": "create",
-          "state": "succeeded",
-          "description": "",
-          "updated_at": "2018-02-28T16:25:19Z",
-          "created_at": "2018-02-28T16:25:19Z"
+      "relationships": {
+        "app": {
+          "data": {
+            "guid": "2a3820bb-febd-4c90-ab66-80faa4362142"
+          }
         },
-        "app_url": "/v2/apps/2a3820bb-febd-4c90-ab66-80faa4362142",
-        "service_instance_url": "/v2/service_instances/92f0f510-dbb1-4c04-aa7c-28a8dc0797b4",
-        "service_binding_parameters_url": "/v2/service_bindings/0b6e8fe9-b173-4845-a7aa-e093f1081c94/parameters"
+        "service_instance": {
+          "data": {
+            "guid": "0d632575-bb06-4ea5-bb19-a451a9644d92"
+          }
+        }
       }
     }
-  ]
-}
-`
-
-var v2TestAppServiceInstances = `{
-  "metadata": {
-    "guid": "0d632575-bb06-4ea5-bb19-a451a9644d92",
-    "url": "/v2/service_instances/0d632575-bb06-4ea5-bb19-a451a9644d92",
-    "created_at": "2016-06-08T16:41:29Z",
-    "updated_at": "2016-06-08T16:41:26Z"
-  },
-  "entity": {
-    "name": "name-1508",
-    "credentials": {
-      "creds-key-38": "creds-val-38"
-    },
-    "service_guid": "a14baddf-1ccc-5299-0152-ab9s49de4422",
-    "service_plan_guid": "779d2df0-9cdd-48e8-9781-ea05301cedb1",
-    "space_guid": "38511660-89d9-4a6e-a889-c32c7e94f139",
-    "gateway_data": null,
-    "dashboard_url": null,
-    "type": "managed_service_instance",
-    "last_operation": {
-      "type": "create",
-      "state": "succeeded",
-      "description": "service broker-provided description",
-      "updated_at": "2016-06-08T16:41:29Z",
-      "created_at": "2016-06-08T16:41:29Z"
-    },
-    "tags": [
-      "accounting",
-      "mongodb"
-    ],
-    "maintenance_info": {
-      "version": "2.1.1",
-      "description": "OS image update.\nExpect downtime."
-    },
-    "space_url": "/v2/spaces/38511660-89d9-4a6e-a889-c32c7e94f139",
-    "service_url": "/v2/services/a14baddf-1ccc-5299-0152-ab9s49de4422",
-    "service_plan_url": "/v2/service_plans/779d2df0-9cdd-48e8-9781-ea05301cedb1",
-    "service_bindings_url": "/v2/service_instances/0d632575-bb06-4ea5-bb19-a451a9644d92/service_bindings",
-    "service_keys_url": "/v2/service_instances/0d632575-bb06-4ea5-bb19-a451a9644d92/service_keys",
-    "routes_url": "/v2/service_instances/0d632575-bb06-4ea5-bb19-a451a9644d92/routes",
-    "shared_from_url": "/v2/service_instances/0d632575-bb06-4ea5-bb19-a451a9644d92/shared_from",
-    "shared_to_url": "/v2/service_instances/0d632575-bb06-4ea5-bb19-a451a9644d92/shared_to",
-    "service_instance_parameters_url": "/v2/service_instances/0d632575-bb06-4ea5-bb19-a451a9644d92/parameters"
+  ],
+  "included": {
+    "service_instances": [
+      {
+        "guid": "0d632575-bb06-4ea5-bb19-a451a9644d92",
+        "name": "name-1508",
+        "type": "managed",
+        "tags": [
+          "accounting",
+          "mongodb"
+        ],
+        "relationships": {
+          "space": {
+            "data": {
+              "guid": "38511660-89d9-4a6e-a889-c32c7e94f139"
+            }
+          },
+          "service_plan": {
+            "data": {
+              "guid": "779d2df0-9cdd-48e8-9781-ea05301cedb1"
+            }
+          }
+        }
+      }
+    ]
   }
 }
 `
@@ -67,6 +67,7 @@ func TestMigrate_Export(t *testing.T) {
 			wantErr: false,
 			afterFunc: func(want *cf.ServiceInstance, fakeCloudControllerService *fakes.FakeService) {
 				_, _, si := fakeCloudControllerService.DeleteArgsForCall(0)
+				require.Equal(t, 1, fakeCloudControllerService.SnapshotCallCount())
 				require.Equal(t, 1, fakeCloudControllerService.DeleteCallCount())
 				require.Equal(t, want, si)
 				require.Equal(t, 0, fakeCloudControllerService.CreateCallCount())
@@ -117,7 +118,7 @@ func TestMigrate_Export(t *testing.T) {
 				},
 				Apps: map[string]string{"some-binding-guid": "some-app-name"},
 				AppManifest: struct {
-					Applications []cf.Application `yaml:"applications"`
+					Applications []cf.Application `yaml:"applications" json:"applications"`
 				}{Applications: []cf.Application{
 					{
 						Name: "some-app-name",
@@ -192,7 +193,7 @@ func TestMigrate_Export(t *testing.T) {
 					"two-binding-guid": "some-app-name",
 				},
 				AppManifest: struct {
-					Applications []cf.Application `yaml:"applications"`
+					Applications []cf.Application `yaml:"applications" json:"applications"`
 				}{Applications: []cf.Application{
 					{
 						Name: "some-app-name-1",
@@ -225,7 +226,7 @@ func TestMigrate_Export(t *testing.T) {
 				tt.beforeFunc(tt.fields.CloudControllerService)
 			}
 			m := cc.NewMigrator(
-				cc.Export("some-org", "some-space", tt.fields.CloudControllerService, tt.fields.ServiceInstance),
+				cc.Export("some-org", "some-space", tt.fields.CloudControllerService, tt.fields.ServiceInstance, cc.RollbackLog{}),
 			)
 			got, err := m.Migrate(tt.args.ctx)
 			tt.afterFunc(tt.want, tt.fields.CloudControllerService)
@@ -490,7 +491,7 @@ func TestMigrate_Import(t *testing.T) {
 				tt.beforeFunc(tt.fields.CloudControllerService)
 			}
 			m := cc.NewMigrator(
-				cc.Import("some-org", "some-space", tt.fields.CloudControllerService, tt.fields.ServiceInstance, "some-encryption-key"),
+				cc.Import("some-org", "some-space", tt.fields.CloudControllerService, tt.fields.ServiceInstance, "some-encryption-key", cc.RollbackLog{}),
 			)
 			got, err := m.Migrate(tt.args.ctx)
 			tt.afterFunc(tt.want, tt.fields.CloudControllerService)
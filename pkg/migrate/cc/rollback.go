@@ -0,0 +1,178 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/cc/db"
+)
+
+// RollbackDir is the directory inside the export directory where compensating actions are kept
+const RollbackDir = ".rollback"
+
+type CompensationType string
+
+const (
+	// RestoreServiceInstance puts back the source foundation rows deleted by an export
+	RestoreServiceInstance CompensationType = "restore_service_instance"
+	// PurgeServiceInstance removes the target foundation rows inserted by an import
+	PurgeServiceInstance CompensationType = "purge_service_instance"
+)
+
+// Compensation is an action that undoes a ccdb change made by a cc flow step
+type Compensation struct {
+	Type         CompensationType            `json:"type"`
+	Migrator     string                      `json:"migrator"`
+	Export       bool                        `json:"export"`
+	Org          string                      `json:"org"`
+	Space        string                      `json:"space"`
+	Instance     string                      `json:"instance"`
+	GUID         string                      `json:"guid"`
+	Snapshot     *db.ServiceInstanceSnapshot `json:"snapshot,omitempty"`
+	RegisteredAt time.Time                   `json:"registered_at"`
+}
+
+// Apply runs the compensating action against the cloud controller database
+func (c Compensation) Apply(svc Service) error {
+	switch c.Type {
+	case RestoreServiceInstance:
+		if c.Snapshot == nil {
+			return fmt.Errorf("no snapshot recorded for service instance %q", c.Instance)
+		}
+		return svc.Restore(c.Snapshot)
+	case PurgeServiceInstance:
+		return svc.Purge(&cf.ServiceInstance{Name: c.Instance, GUID: c.GUID})
+	}
+	return fmt.Errorf("unknown compensation type %q", c.Type)
+}
+
+// RollbackLog persists the compensating actions for one migrator under the export directory
+type RollbackLog struct {
+	dir      string
+	migrator string
+	isExport bool
+}
+
+func NewRollbackLog(dir, migrator string, isExport bool) RollbackLog {
+	return RollbackLog{
+		dir:      dir,
+		migrator: migrator,
+		isExport: isExport,
+	}
+}
+
+// Register appends a compensating action for the service instance, it must be called before the change is made
+func (l RollbackLog) Register(t CompensationType, org, space string, instance *cf.ServiceInstance, snapshot *db.ServiceInstanceSnapshot) error {
+	if l.dir == "" {
+		return nil
+	}
+
+	path := l.path(org, space, instance.GUID)
+	compensations, err := readCompensations(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	compensations = append(compensations, Compensation{
+		Type:         t,
+		Migrator:     l.migrator,
+		Export:       l.isExport,
+		Org:          org,
+		Space:        space,
+		Instance:     instance.Name,
+		GUID:         instance.GUID,
+		Snapshot:     snapshot,
+		RegisteredAt: time.Now().UTC(),
+	})
+
+	b, err := json.MarshalIndent(compensations, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create rollback dir: %w", err)
+	}
+
+	return os.WriteFile(path, b, 0600)
+}
+
+func (l RollbackLog) path(org, space, guid string) string {
+	phase := "import"
+	if l.isExport {
+		phase = "export"
+	}
+	return filepath.Join(l.dir, RollbackDir, phase, org, space, guid+".json")
+}
+
+// CompensationLog is the set of compensating actions recorded for a single service instance
+type CompensationLog struct {
+	Path          string
+	Compensations []Compensation
+}
+
+// LoadRollbackLogs reads every compensation log found in the export directory
+func LoadRollbackLogs(dir string) ([]CompensationLog, error) {
+	root := filepath.Join(dir, RollbackDir)
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	var logs []CompensationLog
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		compensations, err := readCompensations(path)
+		if err != nil {
+			return err
+		}
+		if len(compensations) == 0 {
+			return nil
+		}
+		logs = append(logs, CompensationLog{Path: path, Compensations: compensations})
+		return nil
+	})
+
+	return logs, err
+}
+
+func readCompensations(path string) ([]Compensation, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var compensations []Compensation
+	decoder := json.NewDecoder(f)
+	// keep column values as they were read from the ccdb instead of converting them to floats
+	decoder.UseNumber()
+	if err = decoder.Decode(&compensations); err != nil {
+		return nil, fmt.Errorf("failed to read rollback log %q: %w", path, err)
+	}
+
+	return compensations, nil
+}
@@ -0,0 +1,140 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cc_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/cc"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/cc/db"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/cc/fakes"
+)
+
+func TestRollbackLog(t *testing.T) {
+	dir := t.TempDir()
+	instance := &cf.ServiceInstance{Name: "some-instance", GUID: "some-guid"}
+	snapshot := &db.ServiceInstanceSnapshot{
+		ServiceInstance: db.Row{"id": 42, "guid": "some-guid", "name": "some-instance"},
+		ServiceKeys:     []db.Row{{"id": 7, "service_instance_id": 42}},
+	}
+
+	err := cc.NewRollbackLog(dir, "sqlserver", true).Register(cc.RestoreServiceInstance, "some-org", "some-space", instance, snapshot)
+	require.NoError(t, err)
+	err = cc.NewRollbackLog(dir, "ecs", false).Register(cc.PurgeServiceInstance, "other-org", "other-space", instance, nil)
+	require.NoError(t, err)
+
+	logs, err := cc.LoadRollbackLogs(dir)
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+
+	var export, imp cc.Compensation
+	for _, l := range logs {
+		require.Len(t, l.Compensations, 1)
+		if l.Compensations[0].Export {
+			export = l.Compensations[0]
+		} else {
+			imp = l.Compensations[0]
+		}
+	}
+
+	require.Equal(t, cc.RestoreServiceInstance, export.Type)
+	require.Equal(t, "sqlserver", export.Migrator)
+	require.Equal(t, "some-org", export.Org)
+	require.Equal(t, "some-space", export.Space)
+	require.Equal(t, "some-instance", export.Instance)
+	require.Equal(t, "some-guid", export.Snapshot.GUID())
+	require.Equal(t, json.Number("42"), export.Snapshot.ServiceInstance["id"])
+	require.Equal(t, json.Number("42"), export.Snapshot.ServiceKeys[0]["service_instance_id"])
+
+	require.Equal(t, cc.PurgeServiceInstance, imp.Type)
+	require.Equal(t, "ecs", imp.Migrator)
+	require.Nil(t, imp.Snapshot)
+}
+
+func TestLoadRollbackLogsWithoutRollbackDir(t *testing.T) {
+	logs, err := cc.LoadRollbackLogs(t.TempDir())
+	require.NoError(t, err)
+	require.Empty(t, logs)
+}
+
+func TestCompensation_Apply(t *testing.T) {
+	tests := []struct {
+		name         string
+		compensation cc.Compensation
+		service      *fakes.FakeService
+		wantErr      bool
+		afterFunc    func(*testing.T, *fakes.FakeService)
+	}{
+		{
+			name: "restores a deleted service instance",
+			compensation: cc.Compensation{
+				Type:     cc.RestoreServiceInstance,
+				Instance: "some-instance",
+				GUID:     "some-guid",
+				Snapshot: &db.ServiceInstanceSnapshot{ServiceInstance: db.Row{"guid": "some-guid"}},
+			},
+			service: new(fakes.FakeService),
+			afterFunc: func(t *testing.T, service *fakes.FakeService) {
+				require.Equal(t, 1, service.RestoreCallCount())
+				require.Equal(t, "some-guid", service.RestoreArgsForCall(0).GUID())
+				require.Equal(t, 0, service.PurgeCallCount())
+			},
+		},
+		{
+			name: "fails to restore without a snapshot",
+			compensation: cc.Compensation{
+				Type:     cc.RestoreServiceInstance,
+				Instance: "some-instance",
+				GUID:     "some-guid",
+			},
+			service: new(fakes.FakeService),
+			wantErr: true,
+			afterFunc: func(t *testing.T, service *fakes.FakeService) {
+				require.Equal(t, 0, service.RestoreCallCount())
+			},
+		},
+		{
+			name: "purges an imported service instance",
+			compensation: cc.Compensation{
+				Type:     cc.PurgeServiceInstance,
+				Instance: "some-instance",
+				GUID:     "some-guid",
+			},
+			service: &fakes.FakeService{
+				PurgeStub: func(instance *cf.ServiceInstance) error {
+					return errors.New("purge failed")
+				},
+			},
+			wantErr: true,
+			afterFunc: func(t *testing.T, service *fakes.FakeService) {
+				require.Equal(t, 1, service.PurgeCallCount())
+				require.Equal(t, &cf.ServiceInstance{Name: "some-instance", GUID: "some-guid"}, service.PurgeArgsForCall(0))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.compensation.Apply(tt.service)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			tt.afterFunc(t, tt.service)
+		})
+	}
+}
@@ -83,6 +83,18 @@ func (m DefaultCloudControllerService) Delete(org, space string, instance *cf.Se
 	return nil
 }
 
+func (m DefaultCloudControllerService) Snapshot(instance *cf.ServiceInstance) (*db.ServiceInstanceSnapshot, error) {
+	return m.Database.SnapshotServiceInstance(instance.GUID)
+}
+
+func (m DefaultCloudControllerService) Restore(snapshot *db.ServiceInstanceSnapshot) error {
+	return m.Database.RestoreServiceInstance(snapshot)
+}
+
+func (m DefaultCloudControllerService) Purge(instance *cf.ServiceInstance) error {
+	return m.Database.PurgeServiceInstance(instance.GUID)
+}
+
 func (m DefaultCloudControllerService) Create(org, space string, instance *cf.ServiceInstance, encryptionKey string) error {
 	targetOrg, err := m.Client.GetOrgByName(org)
 	if err != nil {
@@ -94,40 +106,9 @@ func (m DefaultCloudControllerService) Create(org, space string, instance *cf.Se
 		return errors.Wrap(err, fmt.Sprintf("could not find space %q in org %q", space, targetOrg.Name))
 	}
 
-	targetPlans, err := m.Client.ListServicePlans()
-	if err != nil {
-		return errors.Wrap(err, "could not get service plans from target foundation")
-	}
-
-	var targetPlan cfclient.ServicePlan
-	var foundPlan bool
-	for _, plan := range targetPlans {
-		if plan.Name == instance.Plan {
-			targetPlan = plan
-			foundPlan = true
-			break
-		}
-	}
-	if !foundPlan {
-		return fmt.Errorf("failed to find a plan %s for service instance %s", instance.Plan, instance.GUID)
-	}
-
-	targetServices, err := m.Client.ListServices()
+	targetService, targetPlan, err := cf.FindServicePlan(m.Client, instance.ServiceToCreate(), instance.PlanToCreate())
 	if err != nil {
-		return errors.Wrap(err, "could not get service plans from target foundation")
-	}
-
-	var targetService cfclient.Service
-	var foundService bool
-	for _, service := range targetServices {
-		if service.Label == instance.Service {
-			targetService = service
-			foundService = true
-			break
-		}
-	}
-	if !foundService {
-		return fmt.Errorf("failed to find %s service for service instance %q with guid %q", instance.Service, instance.Name, instance.GUID)
+		return errors.Wrap(err, fmt.Sprintf("failed to find plan %s for service instance %q with guid %q", instance.PlanToCreate(), instance.Name, instance.GUID))
 	}
 
 	si := cfclient.ServiceInstance{
@@ -229,11 +210,11 @@ func (m DefaultCloudControllerService) ServiceInstanceExists(org, space, instanc
 		return false, errors.Wrap(err, fmt.Sprintf("could not find space %q in org %q", space, targetOrg.Name))
 	}
 
-	sis, err := m.Client.ListServiceInstancesByQuery(url.Values{"q": []string{
-		fmt.Sprintf("organization_guid:%s", targetOrg.Guid),
-		fmt.Sprintf("space_guid:%s", targetSpace.Guid),
-		fmt.Sprintf("name:%s", instanceName),
-	}})
+	sis, err := m.Client.ListServiceInstancesByQuery(url.Values{
+		"organization_guids": []string{targetOrg.Guid},
+		"space_guids":        []string{targetSpace.Guid},
+		"names":              []string{instanceName},
+	})
 	if err != nil {
 		return false, err
 	}
@@ -101,7 +101,7 @@ func TestDefaultCloudControllerService_Create(t *testing.T) {
 					GetSpaceByNameStub: func(string, string) (cfclient.Space, error) {
 						return cfclient.Space{}, nil
 					},
-					ListServicePlansStub: func() ([]cfclient.ServicePlan, error) {
+					ListServicePlansByQueryStub: func(url.Values) ([]cfclient.ServicePlan, error) {
 						return []cfclient.ServicePlan{
 							{
 								Name: "sharedVM",
@@ -153,12 +153,8 @@ func TestDefaultCloudControllerService_Create(t *testing.T) {
 					GetSpaceByNameStub: func(string, string) (cfclient.Space, error) {
 						return cfclient.Space{}, nil
 					},
-					ListServicePlansStub: func() ([]cfclient.ServicePlan, error) {
-						return []cfclient.ServicePlan{
-							{
-								Name: "sharedVM",
-							},
-						}, nil
+					ListServicePlansByQueryStub: func(url.Values) ([]cfclient.ServicePlan, error) {
+						return []cfclient.ServicePlan{}, nil
 					},
 					ListServicesStub: func() ([]cfclient.Service, error) {
 						return []cfclient.Service{
@@ -205,7 +201,7 @@ func TestDefaultCloudControllerService_Create(t *testing.T) {
 					GetSpaceByNameStub: func(string, string) (cfclient.Space, error) {
 						return cfclient.Space{}, nil
 					},
-					ListServicePlansStub: func() ([]cfclient.ServicePlan, error) {
+					ListServicePlansByQueryStub: func(url.Values) ([]cfclient.ServicePlan, error) {
 						return []cfclient.ServicePlan{
 							{
 								Name: "sharedVM",
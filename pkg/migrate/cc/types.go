@@ -45,6 +45,9 @@ type Service interface {
 	CreateServiceBinding(binding *cf.ServiceBinding, appGUID string, encryptionKey string) error
 	FindAppByGUID(guid string) (string, error)
 	DownloadManifest(org, space, appName string) (cf.Application, error)
+	Snapshot(instance *cf.ServiceInstance) (*db.ServiceInstanceSnapshot, error)
+	Restore(snapshot *db.ServiceInstanceSnapshot) error
+	Purge(instance *cf.ServiceInstance) error
 }
 
 //counterfeiter:generate -o fakes . ManifestExporter
@@ -16,33 +16,50 @@ package migrate
 
 import (
 	"crypto/x509"
+	"fmt"
+	"net/url"
+	"sync"
+
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/bosh"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/credhub"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/om"
 )
 
+const (
+	runtimeCredhubHost = "credhub.service.cf.internal"
+	runtimeUAAHost     = "uaa.service.cf.internal"
+)
+
 type ClientFactory struct {
-	ConfigLoader     config.Loader
-	BoshFactory      bosh.ClientFactory
-	OpsmanFactory    om.ClientFactory
-	OpsmanConfig     config.OpsManager
-	sourceCFClient   cf.Client
-	targetCFClient   cf.Client
-	sourceOMClient   om.Client
-	targetOMClient   om.Client
-	sourceBOSHClient bosh.Client
-	targetBOSHClient bosh.Client
+	ConfigLoader         config.Loader
+	BoshFactory          bosh.ClientFactory
+	CredhubFactory       credhub.ClientFactory
+	OpsmanFactory        om.ClientFactory
+	OpsmanConfig         config.OpsManager
+	sourceCFClient       cf.Client
+	targetCFClient       cf.Client
+	sourceOMClient       om.Client
+	targetOMClient       om.Client
+	sourceBOSHClient     bosh.Client
+	targetBOSHClient     bosh.Client
+	sourceCredhubClient  credhub.Client
+	targetCredhubClient  credhub.Client
+	sourceRuntimeCredhub credhub.Client
+	targetRuntimeCredhub credhub.Client
+	runtimeCredhubMu     sync.Mutex
 }
 
 func NewClientFactory(
 	configLoader config.Loader,
 	boshFactory bosh.ClientFactory,
+	credhubFactory credhub.ClientFactory,
 	opsmanFactory om.ClientFactory,
 	opsManConfig config.OpsManager,
 ) *ClientFactory {
-	return &ClientFactory{ConfigLoader: configLoader, BoshFactory: boshFactory, OpsmanFactory: opsmanFactory, OpsmanConfig: opsManConfig}
+	return &ClientFactory{ConfigLoader: configLoader, BoshFactory: boshFactory, CredhubFactory: credhubFactory, OpsmanFactory: opsmanFactory, OpsmanConfig: opsManConfig}
 }
 
 func (f *ClientFactory) CFClient(toSource bool) cf.Client {
@@ -82,6 +99,13 @@ func (f *ClientFactory) TargetOpsManClient() om.Client {
 	return f.targetOMClient
 }
 
+func (f *ClientFactory) BoshClient(toSource bool) bosh.Client {
+	if toSource {
+		return f.SourceBoshClient()
+	}
+	return f.TargetBoshClient()
+}
+
 func (f *ClientFactory) SourceBoshClient() bosh.Client {
 	if f.sourceBOSHClient == nil {
 		cfg := f.ConfigLoader.SourceBoshConfig()
@@ -98,6 +122,39 @@ func (f *ClientFactory) TargetBoshClient() bosh.Client {
 	return f.targetBOSHClient
 }
 
+// CredhubClient returns a client for the credhub colocated with the bosh director
+func (f *ClientFactory) CredhubClient(toSource bool) credhub.Client {
+	if toSource {
+		if f.sourceCredhubClient == nil {
+			f.sourceCredhubClient = newCredhubClient(f.CredhubFactory, *f.ConfigLoader.SourceBoshConfig())
+		}
+		return f.sourceCredhubClient
+	}
+	if f.targetCredhubClient == nil {
+		f.targetCredhubClient = newCredhubClient(f.CredhubFactory, *f.ConfigLoader.TargetBoshConfig())
+	}
+	return f.targetCredhubClient
+}
+
+// RuntimeCredhubClient returns a client for the credhub deployed with cf, where service brokers store credentials.
+// It is safe to call from concurrent migrations, and an error finding the credhub is retried on the next call.
+func (f *ClientFactory) RuntimeCredhubClient(toSource bool) (credhub.Client, error) {
+	f.runtimeCredhubMu.Lock()
+	defer f.runtimeCredhubMu.Unlock()
+
+	var err error
+	if toSource {
+		if f.sourceRuntimeCredhub == nil {
+			f.sourceRuntimeCredhub, err = newRuntimeCredhubClient(f.CredhubFactory, f.SourceBoshClient(), f.SourceOpsManClient(), *f.ConfigLoader.SourceBoshConfig())
+		}
+		return f.sourceRuntimeCredhub, err
+	}
+	if f.targetRuntimeCredhub == nil {
+		f.targetRuntimeCredhub, err = newRuntimeCredhubClient(f.CredhubFactory, f.TargetBoshClient(), f.TargetOpsManClient(), *f.ConfigLoader.TargetBoshConfig())
+	}
+	return f.targetRuntimeCredhub, err
+}
+
 func newBoshClient(b bosh.ClientFactory, cfg config.Bosh) bosh.Client {
 	certPool, err := x509.SystemCertPool()
 	if err != nil {
@@ -117,6 +174,87 @@ func newBoshClient(b bosh.ClientFactory, cfg config.Bosh) bosh.Client {
 	return client
 }
 
+func newCredhubClient(c credhub.ClientFactory, cfg config.Bosh) credhub.Client {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		log.Fatalf("error parsing bosh url: %v", err)
+	}
+
+	return c.New(
+		fmt.Sprintf("%s://%s:8844", u.Scheme, u.Hostname()),
+		cfg.Authentication.UAA.URL,
+		cfg.AllProxy,
+		[]byte(cfg.TrustedCert),
+		false,
+		cfg.Authentication.UAA.ClientCredentials.ID,
+		cfg.Authentication.UAA.ClientCredentials.Secret)
+}
+
+// newRuntimeCredhubClient connects to the cf credhub and uaa instances by ip through the bosh proxy, where the
+// *.service.cf.internal names in their certificates cannot be resolved. Their certificates are still verified
+// against those names and the certificate authorities of ops manager.
+func newRuntimeCredhubClient(c credhub.ClientFactory, bc bosh.Client, omc om.Client, cfg config.Bosh) (credhub.Client, error) {
+	deployment, found, err := bc.FindDeployment("^cf-")
+	if err != nil {
+		return nil, fmt.Errorf("error finding cf deployment: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("error finding cf deployment: not found")
+	}
+
+	credhubIP, err := findInstanceIP(bc, deployment.Name, "credhub")
+	if err != nil {
+		return nil, err
+	}
+	uaaIP, err := findInstanceIP(bc, deployment.Name, "uaa")
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := omc.DeployedProductCredentials(deployment.Name, ".uaa.credhub_admin_client_client_credentials")
+	if err != nil {
+		return nil, fmt.Errorf("error finding credhub admin credentials: %w", err)
+	}
+	clientID := creds.Credential.Value["identity"]
+	if clientID == "" {
+		clientID = "credhub_admin_client"
+	}
+
+	authorities, err := omc.CertificateAuthorities()
+	if err != nil {
+		return nil, fmt.Errorf("error finding ops manager certificate authorities: %w", err)
+	}
+	var caCert []byte
+	for _, ca := range authorities {
+		caCert = append(caCert, []byte(ca.CertPEM+"\n")...)
+	}
+
+	return c.New(
+		fmt.Sprintf("https://%s:8844", runtimeCredhubHost),
+		fmt.Sprintf("https://%s:8443", runtimeUAAHost),
+		cfg.AllProxy,
+		caCert,
+		false,
+		clientID,
+		creds.Credential.Value["password"],
+		credhub.WithResolvedHosts(map[string]string{
+			runtimeCredhubHost: credhubIP,
+			runtimeUAAHost:     uaaIP,
+		})), nil
+}
+
+func findInstanceIP(bc bosh.Client, deployment, process string) (string, error) {
+	vm, found, err := bc.FindVM(deployment, process)
+	if err != nil {
+		return "", fmt.Errorf("error finding bosh instance running %s: %w", process, err)
+	}
+	if !found || len(vm.IPs) == 0 {
+		return "", fmt.Errorf("error finding bosh instance running %s in %q", process, deployment)
+	}
+
+	return vm.IPs[0], nil
+}
+
 func newCFClient(cfg *config.CloudController) cf.Client {
 	client, err := cf.NewClient(&cf.Config{
 		URL:          cfg.URL,
@@ -15,10 +15,13 @@
 package migrate_test
 
 import (
+	"github.com/cloudfoundry/bosh-cli/director"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/stretchr/testify/require"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/uaa"
 	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/bosh"
@@ -26,17 +29,21 @@ import (
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config/fakes"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/credhub"
+	credhubfakes "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/credhub/fakes"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/om"
 	omfakes "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/om/fakes"
+	omhttpclient "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/om/httpclient"
 )
 
 func TestNewClientFactory(t *testing.T) {
 	type args struct {
-		configLoader  config.Loader
-		boshFactory   bosh.ClientFactory
-		opsmanFactory om.ClientFactory
-		opsmanConfig  config.OpsManager
+		configLoader   config.Loader
+		boshFactory    bosh.ClientFactory
+		credhubFactory credhub.ClientFactory
+		opsmanFactory  om.ClientFactory
+		opsmanConfig   config.OpsManager
 	}
 	tests := []struct {
 		name string
@@ -46,9 +53,10 @@ func TestNewClientFactory(t *testing.T) {
 		{
 			name: "creates a client factory",
 			args: args{
-				configLoader:  new(fakes.FakeLoader),
-				boshFactory:   new(boshfakes.FakeClientFactory),
-				opsmanFactory: new(omfakes.FakeClientFactory),
+				configLoader:   new(fakes.FakeLoader),
+				boshFactory:    new(boshfakes.FakeClientFactory),
+				credhubFactory: new(credhubfakes.FakeClientFactory),
+				opsmanFactory:  new(omfakes.FakeClientFactory),
 				opsmanConfig: config.OpsManager{
 					URL:          "https://opsman.url.com",
 					Username:     "admin",
@@ -62,9 +70,10 @@ func TestNewClientFactory(t *testing.T) {
 				},
 			},
 			want: &migrate.ClientFactory{
-				ConfigLoader:  new(fakes.FakeLoader),
-				BoshFactory:   new(boshfakes.FakeClientFactory),
-				OpsmanFactory: new(omfakes.FakeClientFactory),
+				ConfigLoader:   new(fakes.FakeLoader),
+				BoshFactory:    new(boshfakes.FakeClientFactory),
+				CredhubFactory: new(credhubfakes.FakeClientFactory),
+				OpsmanFactory:  new(omfakes.FakeClientFactory),
 				OpsmanConfig: config.OpsManager{
 					URL:          "https://opsman.url.com",
 					Username:     "admin",
@@ -81,7 +90,7 @@ func TestNewClientFactory(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := migrate.NewClientFactory(tt.args.configLoader, tt.args.boshFactory, tt.args.opsmanFactory, tt.args.opsmanConfig); !reflect.DeepEqual(got, tt.want) {
+			if got := migrate.NewClientFactory(tt.args.configLoader, tt.args.boshFactory, tt.args.credhubFactory, tt.args.opsmanFactory, tt.args.opsmanConfig); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("NewClientFactory() = %v, want %v", got, tt.want)
 			}
 		})
@@ -546,3 +555,153 @@ func TestClientFactory_TargetBoshClient(t *testing.T) {
 		})
 	}
 }
+
+func TestClientFactory_CredhubClient(t *testing.T) {
+	credhubFactory := &credhubfakes.FakeClientFactory{
+		NewStub: func(string, string, string, []byte, bool, string, string, ...credhub.Option) credhub.Client {
+			return new(credhubfakes.FakeClient)
+		},
+	}
+	l := migrate.ClientFactory{
+		ConfigLoader: &fakes.FakeLoader{
+			SourceBoshConfigStub: func() *config.Bosh {
+				return &config.Bosh{
+					URL:         "https://10.1.0.1:25555",
+					AllProxy:    "ssh+socks5://some-user@opsman.example.com:22?private-key=/path/to/ssh-key",
+					TrustedCert: "a very trustworthy cert",
+					Authentication: config.Authentication{
+						UAA: config.UAAAuthentication{
+							URL: "https://10.1.0.1:8443",
+							ClientCredentials: config.ClientCredentials{
+								ID:     "some-client-id",
+								Secret: "some-client-secret",
+							},
+						},
+					},
+				}
+			},
+		},
+		CredhubFactory: credhubFactory,
+	}
+
+	got := l.CredhubClient(true)
+	require.Same(t, got, l.CredhubClient(true))
+	require.Equal(t, 1, credhubFactory.NewCallCount())
+	credhubURL, uaaURL, allProxy, caCert, skipSSLValidation, clientID, clientSecret, _ := credhubFactory.NewArgsForCall(0)
+	require.Equal(t, "https://10.1.0.1:8844", credhubURL)
+	require.Equal(t, "https://10.1.0.1:8443", uaaURL)
+	require.Equal(t, "ssh+socks5://some-user@opsman.example.com:22?private-key=/path/to/ssh-key", allProxy)
+	require.Equal(t, []byte("a very trustworthy cert"), caCert)
+	require.False(t, skipSSLValidation)
+	require.Equal(t, "some-client-id", clientID)
+	require.Equal(t, "some-client-secret", clientSecret)
+}
+
+func TestClientFactory_RuntimeCredhubClient(t *testing.T) {
+	credhubFactory := &credhubfakes.FakeClientFactory{
+		NewStub: func(string, string, string, []byte, bool, string, string, ...credhub.Option) credhub.Client {
+			return new(credhubfakes.FakeClient)
+		},
+	}
+	boshClient := &boshfakes.FakeClient{
+		FindDeploymentStub: func(string) (director.DeploymentResp, bool, error) {
+			return director.DeploymentResp{Name: "cf-abc123"}, true, nil
+		},
+		FindVMStub: func(deployment string, process string) (director.VMInfo, bool, error) {
+			if process == "credhub" {
+				return director.VMInfo{IPs: []string{"10.0.4.10"}}, true, nil
+			}
+			return director.VMInfo{IPs: []string{"10.0.4.11"}}, true, nil
+		},
+	}
+	omClient := &omfakes.FakeClient{
+		DeployedProductCredentialsStub: func(deployment string, ref string) (omhttpclient.DeployedProductCredential, error) {
+			return omhttpclient.DeployedProductCredential{
+				Credential: omhttpclient.Credential{
+					Type:  "simple_credentials",
+					Value: map[string]string{"identity": "credhub_admin_client", "password": "some-secret"},
+				},
+			}, nil
+		},
+		CertificateAuthoritiesStub: func() ([]omhttpclient.CA, error) {
+			return []omhttpclient.CA{{CertPEM: "some-root-ca"}, {CertPEM: "some-other-root-ca"}}, nil
+		},
+	}
+	l := migrate.ClientFactory{
+		ConfigLoader: &fakes.FakeLoader{
+			TargetBoshConfigStub: func() *config.Bosh {
+				return &config.Bosh{
+					URL:      "https://10.1.0.1:25555",
+					AllProxy: "ssh+socks5://some-user@opsman.example.com:22?private-key=/path/to/ssh-key",
+				}
+			},
+		},
+		CredhubFactory: credhubFactory,
+		BoshFactory: &boshfakes.FakeClientFactory{
+			NewStub: func(string, string, []byte, bosh.CertAppender, config.Authentication) (bosh.Client, error) {
+				return boshClient, nil
+			},
+		},
+		OpsmanFactory: &omfakes.FakeClientFactory{
+			NewStub: func(string, []byte, om.CertAppender, config.Authentication) (om.Client, error) {
+				return omClient, nil
+			},
+		},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := l.RuntimeCredhubClient(false)
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 1, credhubFactory.NewCallCount())
+	credhubURL, uaaURL, allProxy, caCert, skipSSLValidation, clientID, clientSecret, opts := credhubFactory.NewArgsForCall(0)
+	require.Equal(t, "https://credhub.service.cf.internal:8844", credhubURL)
+	require.Equal(t, "https://uaa.service.cf.internal:8443", uaaURL)
+	require.Equal(t, "ssh+socks5://some-user@opsman.example.com:22?private-key=/path/to/ssh-key", allProxy)
+	require.Equal(t, "some-root-ca\nsome-other-root-ca\n", string(caCert))
+	require.False(t, skipSSLValidation)
+	require.Len(t, opts, 1)
+	require.Equal(t, "credhub_admin_client", clientID)
+	require.Equal(t, "some-secret", clientSecret)
+	deployment, ref := omClient.DeployedProductCredentialsArgsForCall(0)
+	require.Equal(t, "cf-abc123", deployment)
+	require.Equal(t, ".uaa.credhub_admin_client_client_credentials", ref)
+}
+
+func TestClientFactory_RuntimeCredhubClientWithoutCFDeployment(t *testing.T) {
+	credhubFactory := new(credhubfakes.FakeClientFactory)
+	boshClient := new(boshfakes.FakeClient)
+	boshClient.FindDeploymentReturns(director.DeploymentResp{}, false, nil)
+	l := migrate.ClientFactory{
+		ConfigLoader: &fakes.FakeLoader{
+			SourceBoshConfigStub: func() *config.Bosh {
+				return &config.Bosh{URL: "https://10.1.0.1:25555"}
+			},
+		},
+		CredhubFactory: credhubFactory,
+		BoshFactory: &boshfakes.FakeClientFactory{
+			NewStub: func(string, string, []byte, bosh.CertAppender, config.Authentication) (bosh.Client, error) {
+				return boshClient, nil
+			},
+		},
+		OpsmanFactory: &omfakes.FakeClientFactory{
+			NewStub: func(string, []byte, om.CertAppender, config.Authentication) (om.Client, error) {
+				return new(omfakes.FakeClient), nil
+			},
+		},
+	}
+
+	_, err := l.RuntimeCredhubClient(true)
+	require.EqualError(t, err, "error finding cf deployment: not found")
+	require.Equal(t, 0, credhubFactory.NewCallCount())
+
+	_, err = l.RuntimeCredhubClient(true)
+	require.Error(t, err)
+	require.Equal(t, 2, boshClient.FindDeploymentCallCount())
+}
@@ -0,0 +1,78 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package migrate
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"golang.org/x/sync/errgroup"
+)
+
+// group runs goroutines like an errgroup. With continue_on_error set a failing goroutine doesn't cancel the
+// others, and Wait returns the errors of all of them once every goroutine has finished.
+type group struct {
+	g               *errgroup.Group
+	continueOnError bool
+	mu              sync.Mutex
+	errs            []error
+}
+
+// newGroup returns a group that runs at most max_parallel goroutines at once, so listing orgs, spaces and
+// service instances doesn't flood the cloud controller
+func newGroup(ctx context.Context) (*group, context.Context) {
+	g, gctx := errgroup.WithContext(ctx)
+	cg := &group{g: g}
+	if cfg, ok := config.FromContext(ctx); ok {
+		if cfg.MaxParallel > 0 {
+			g.SetLimit(cfg.MaxParallel)
+		}
+		cg.continueOnError = cfg.ContinueOnError
+	}
+	return cg, gctx
+}
+
+func (g *group) Go(f func() error) {
+	if !g.continueOnError {
+		g.g.Go(f)
+		return
+	}
+	g.g.Go(func() error {
+		if err := f(); err != nil {
+			g.mu.Lock()
+			g.errs = append(g.errs, err)
+			g.mu.Unlock()
+		}
+		return nil
+	})
+}
+
+func (g *group) Wait() error {
+	if err := g.g.Wait(); err != nil {
+		return err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return errors.Join(g.errs...)
+}
+
+// acquireSlot blocks until the migrator may start another migration under the limits of the run and returns a
+// func that frees the slot
+func acquireSlot(ctx context.Context, migrator string) (func(), error) {
+	l, _ := config.LimiterFromContext(ctx)
+	return l.Acquire(ctx, migrator)
+}
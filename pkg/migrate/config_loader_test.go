@@ -2504,7 +2504,7 @@ func FakeBoshClientFactory(ips []string) bosh.ClientFactoryFunc {
 }
 
 func FakeCredhubClientFactory(creds map[string][]map[string]interface{}, err error) credhub.ClientFactoryFunc {
-	return func(url string, credhubPort string, uaaPort string, allProxy string, caCert []byte, clientID string, clientSecret string) credhub.Client {
+	return func(credhubURL string, uaaURL string, allProxy string, caCert []byte, skipSSLValidation bool, clientID string, clientSecret string, opts ...credhub.Option) credhub.Client {
 		return &credhubfakes.FakeClient{
 			GetCredsStub: func(s string) (map[string][]map[string]interface{}, error) {
 				return creds, err
@@ -37,8 +37,8 @@ func NewImportSequence(org, space string, instance *cf.ServiceInstance, om confi
 
 	return flow.ProgressBarSequence(
 		fmt.Sprintf("Importing %s", instance.Name),
-		flow.StepWithProgressBar(SetCredentials(instance), flow.WithDisplay("Setting credentials")),
-		flow.StepWithProgressBar(cf.LoginTargetFoundation(executor, om, api, org, space, cfHome), flow.WithDisplay("Logging into target foundation")),
+		flow.StepWithProgressBar(SetCredentials(instance), flow.WithDisplay("Setting credentials"), flow.WithAlwaysRun()),
+		flow.StepWithProgressBar(cf.LoginTargetFoundation(executor, om, api, org, space, cfHome), flow.WithDisplay("Logging into target foundation"), flow.WithAlwaysRun()),
 		flow.StepWithProgressBar(cf.CreateServiceInstance(executor, cfHome, *instance), flow.WithDisplay("Creating service instance")),
 	)
 }
@@ -46,6 +46,7 @@ func NewImportSequence(org, space string, instance *cf.ServiceInstance, om confi
 func SetCredentials(instance *cf.ServiceInstance) flow.StepFunc {
 	return func(ctx context.Context, data interface{}, dryRun bool) (flow.Result, error) {
 		if len(instance.Credentials) > 0 {
+			log.RegisterCredentials(instance.Credentials)
 			var domainsToReplace = make(map[string]string)
 			if c, ok := config.FromContext(ctx); ok {
 				domainsToReplace = c.DomainsToReplace
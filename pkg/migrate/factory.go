@@ -25,8 +25,22 @@ import (
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/credhub"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/mysql"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/mysql/s3"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/rabbitmq"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/redis"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/script"
 )
 
+func init() {
+	Register(ECS.String(), []string{ECSBucketService.String()}, newCCMigrator)
+	Register(MySQL.String(), []string{MySQLService.String()}, newMySQLMigrator)
+	Register(SQLServer.String(), []string{SQLServerService.String(), CustomSQLServerService.String()}, newCCMigrator)
+	Register(CredHub.String(), []string{CredHubService.String()}, newCredhubMigrator)
+	Register(RabbitMQ.String(), []string{RabbitMQService.String()}, newRabbitMQMigrator)
+	Register(Redis.String(), []string{RedisService.String(), LegacyRedisService.String()}, newRedisMigrator)
+	// script migrators only handle the service labels configured for them in si-migrator.yml
+	Register(Script.String(), nil, newScriptMigrator)
+}
+
 type MigratorFactory struct {
 	l  config.Loader
 	h  ClientHolder
@@ -53,22 +67,29 @@ func (f *MigratorFactory) New(org, space string, si *cf.ServiceInstance, om conf
 
 	switch ServiceType(si.Type) {
 	case ManagedService:
-		switch Service(si.Service) {
-		case MySQLService:
-			mySQLFlow, err := f.buildMySQLFlow(org, space, si, om, dir, isExport)
-			return mysql.NewMigrator(mySQLFlow, f.mh.GetReader()), err
-		case CredHubService:
-			credhubFlow, err := f.buildCredhubFlow(org, space, si, om, h, isExport)
-			return credhub.NewMigrator(credhubFlow, f.mh.GetReader()), err
-		case CustomSQLServerService, SQLServerService, ECSBucketService:
-			ccFlow, err := f.buildCCFlow(org, space, si, om, l, isExport)
-			return cc.NewMigrator(ccFlow), err
-		default:
-			if cfg.UseDefaultMigrator {
-				serviceFlow, err := f.buildManagedServiceFlow(org, space, si, isExport)
-				return NewManagedServiceMigrator(serviceFlow), err
+		if m, ok := f.mh.GetMigratorType(si.Service); ok {
+			if build, ok := lookupBuilder(m.String()); ok {
+				return build(MigratorOptions{
+					Name:                          m.String(),
+					Org:                           org,
+					Space:                         space,
+					Instance:                      si,
+					OpsManager:                    om,
+					Dir:                           dir,
+					IsExport:                      isExport,
+					ConfigLoader:                  l,
+					ClientHolder:                  h,
+					MigrationReader:               f.mh.GetReader(),
+					MigratorHelper:                f.mh,
+					Executor:                      f.e,
+					CloudControllerServiceFactory: f.sf,
+				})
 			}
 		}
+		if cfg.UseDefaultMigrator {
+			serviceFlow, err := f.buildManagedServiceFlow(org, space, si, isExport)
+			return NewManagedServiceMigrator(serviceFlow), err
+		}
 	case UserProvidedService:
 		serviceFlow, err := f.buildUserProvidedServiceFlow(org, space, si, isExport)
 		return NewUserProvidedServiceMigrator(serviceFlow), err
@@ -79,58 +100,105 @@ func (f *MigratorFactory) New(org, space string, si *cf.ServiceInstance, om conf
 	return nil, nil
 }
 
-func (f *MigratorFactory) buildMySQLFlow(org, space string, si *cf.ServiceInstance, om config.OpsManager, dir string, isExport bool) (flow.Flow, error) {
-	d, err := s3.NewDownloader(f.mh.GetReader())
+func newMySQLMigrator(o MigratorOptions) (ServiceInstanceMigrator, error) {
+	d, err := s3.NewDownloader(o.MigrationReader)
 	if err != nil {
 		return nil, err
 	}
 
-	api := f.h.CFClient(isExport).GetClientConfig().ApiAddress
+	api := o.ClientHolder.CFClient(o.IsExport).GetClientConfig().ApiAddress
+
+	var sequence flow.Flow
+	if o.IsExport {
+		sequence = mysql.NewExportSequence(api, o.Org, o.Space, o.Instance, o.OpsManager, o.ClientHolder, d, o.Executor, o.Dir)
+	} else {
+		sequence = mysql.NewImportSequence(api, o.Org, o.Space, o.Instance, o.OpsManager, o.ClientHolder.BoshClient(o.IsExport), o.Executor)
+	}
+	return mysql.NewMigrator(sequence, o.MigrationReader), nil
+}
+
+func newCredhubMigrator(o MigratorOptions) (ServiceInstanceMigrator, error) {
+	var sequence flow.Flow
+	if o.IsExport {
+		sequence = credhub.NewExportSequence(o.Org, o.Space, o.Instance, o.OpsManager, o.ClientHolder, o.Executor)
+	} else {
+		sequence = credhub.NewImportSequence(o.Org, o.Space, o.Instance, o.OpsManager, o.ClientHolder, o.Executor)
+	}
+
+	return credhub.NewMigrator(sequence, o.MigrationReader), nil
+}
+
+func newRabbitMQMigrator(o MigratorOptions) (ServiceInstanceMigrator, error) {
+	api := o.ClientHolder.CFClient(o.IsExport).GetClientConfig().ApiAddress
 
 	var sequence flow.Flow
-	if isExport {
-		sequence = mysql.NewExportSequence(api, org, space, si, om, d, f.e, dir)
+	if o.IsExport {
+		sequence = rabbitmq.NewExportSequence(api, o.Org, o.Space, o.Instance, o.OpsManager, o.Executor, o.Dir)
 	} else {
-		sequence = mysql.NewImportSequence(api, org, space, si, om, f.e)
+		sequence = rabbitmq.NewImportSequence(api, o.Org, o.Space, o.Instance, o.OpsManager, o.Executor)
 	}
-	return sequence, nil
+	return rabbitmq.NewMigrator(sequence, o.MigrationReader), nil
 }
 
-func (f *MigratorFactory) buildCredhubFlow(org, space string, si *cf.ServiceInstance, om config.OpsManager, h ClientHolder, isExport bool) (flow.Flow, error) {
+func newRedisMigrator(o MigratorOptions) (ServiceInstanceMigrator, error) {
+	api := o.ClientHolder.CFClient(o.IsExport).GetClientConfig().ApiAddress
+
 	var sequence flow.Flow
-	if isExport {
-		sequence = credhub.NewExportSequence(org, space, si, om, h, f.e)
+	if o.IsExport {
+		sequence = redis.NewExportSequence(api, o.Org, o.Space, o.Instance, o.OpsManager, o.ClientHolder.BoshClient(o.IsExport), o.Executor, o.Dir)
 	} else {
-		sequence = credhub.NewImportSequence(org, space, si, om, h, f.e)
+		sequence = redis.NewImportSequence(api, o.Org, o.Space, o.Instance, o.OpsManager, o.ClientHolder.BoshClient(o.IsExport), o.Executor)
+	}
+	return redis.NewMigrator(sequence, o.MigrationReader), nil
+}
+
+func newScriptMigrator(o MigratorOptions) (ServiceInstanceMigrator, error) {
+	migration, err := o.MigrationReader.GetMigration()
+	if err != nil {
+		return nil, err
 	}
 
-	return sequence, nil
+	cfg, err := script.LookupConfig(*migration, o.Instance.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	api := o.ClientHolder.CFClient(o.IsExport).GetClientConfig().ApiAddress
+
+	var sequence flow.Flow
+	if o.IsExport {
+		sequence = script.NewExportSequence(api, o.Org, o.Space, o.Instance, o.OpsManager, o.Executor, cfg)
+	} else {
+		sequence = script.NewImportSequence(api, o.Org, o.Space, o.Instance, o.OpsManager, o.Executor, cfg)
+	}
+	return script.NewMigrator(sequence, o.MigrationReader, cfg), nil
 }
 
-func (f *MigratorFactory) buildCCFlow(org, space string, si *cf.ServiceInstance, om config.OpsManager, l config.Loader, isExport bool) (flow.Flow, error) {
-	m, _ := f.mh.GetMigratorType(si.Service)
-	ccConfig := l.CCDBConfig(m.String(), isExport).(*cc.Config)
-	if ccConfig == nil {
-		return nil, fmt.Errorf("failed to find ccdb config for %s", si.Service)
+func newCCMigrator(o MigratorOptions) (ServiceInstanceMigrator, error) {
+	ccConfig, ok := o.ConfigLoader.CCDBConfig(o.Name, o.IsExport).(*cc.Config)
+	if !ok || ccConfig == nil {
+		return nil, fmt.Errorf("failed to find ccdb config for %s", o.Instance.Service)
 	}
 
-	if err := ccConfig.Validate(isExport); err != nil {
-		return nil, fmt.Errorf("migration config validation failed for %s, %w", m, err)
+	if err := ccConfig.Validate(o.IsExport); err != nil {
+		return nil, fmt.Errorf("migration config validation failed for %s, %w", o.Name, err)
 	}
 
-	svc, err := f.sf.NewCloudControllerService(ccConfig, isExport)
+	svc, err := o.CloudControllerServiceFactory.NewCloudControllerService(ccConfig, o.IsExport)
 	if err != nil {
 		return nil, err
 	}
 
+	rollback := cc.NewRollbackLog(o.Dir, o.Name, o.IsExport)
+
 	var sequence flow.Flow
-	if isExport {
-		sequence = cc.NewExportSequence(org, space, svc, si, f.e, om, &ccConfig.SourceCloudControllerDatabase)
+	if o.IsExport {
+		sequence = cc.NewExportSequence(o.Org, o.Space, svc, o.Instance, o.ClientHolder.BoshClient(o.IsExport), o.ClientHolder.CredhubClient(o.IsExport), o.OpsManager, &ccConfig.SourceCloudControllerDatabase, rollback)
 	} else {
-		sequence = cc.NewImportSequence(org, space, svc, si, ccConfig.TargetCloudControllerDatabase.EncryptionKey, f.e, om, &ccConfig.TargetCloudControllerDatabase)
+		sequence = cc.NewImportSequence(o.Org, o.Space, svc, o.Instance, ccConfig.TargetCloudControllerDatabase.EncryptionKey, o.ClientHolder.BoshClient(o.IsExport), o.ClientHolder.CredhubClient(o.IsExport), o.OpsManager, &ccConfig.TargetCloudControllerDatabase, rollback)
 	}
 
-	return sequence, nil
+	return cc.NewMigrator(sequence), nil
 }
 
 func (f *MigratorFactory) buildManagedServiceFlow(org, space string, si *cf.ServiceInstance, isExport bool) (flow.Flow, error) {
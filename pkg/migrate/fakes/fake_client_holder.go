@@ -6,11 +6,23 @@ import (
 
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/bosh"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/credhub"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/om"
 )
 
 type FakeClientHolder struct {
+	BoshClientStub        func(bool) bosh.Client
+	boshClientMutex       sync.RWMutex
+	boshClientArgsForCall []struct {
+		arg1 bool
+	}
+	boshClientReturns struct {
+		result1 bosh.Client
+	}
+	boshClientReturnsOnCall map[int]struct {
+		result1 bosh.Client
+	}
 	CFClientStub        func(bool) cf.Client
 	cFClientMutex       sync.RWMutex
 	cFClientArgsForCall []struct {
@@ -22,6 +34,30 @@ type FakeClientHolder struct {
 	cFClientReturnsOnCall map[int]struct {
 		result1 cf.Client
 	}
+	CredhubClientStub        func(bool) credhub.Client
+	credhubClientMutex       sync.RWMutex
+	credhubClientArgsForCall []struct {
+		arg1 bool
+	}
+	credhubClientReturns struct {
+		result1 credhub.Client
+	}
+	credhubClientReturnsOnCall map[int]struct {
+		result1 credhub.Client
+	}
+	RuntimeCredhubClientStub        func(bool) (credhub.Client, error)
+	runtimeCredhubClientMutex       sync.RWMutex
+	runtimeCredhubClientArgsForCall []struct {
+		arg1 bool
+	}
+	runtimeCredhubClientReturns struct {
+		result1 credhub.Client
+		result2 error
+	}
+	runtimeCredhubClientReturnsOnCall map[int]struct {
+		result1 credhub.Client
+		result2 error
+	}
 	SourceBoshClientStub        func() bosh.Client
 	sourceBoshClientMutex       sync.RWMutex
 	sourceBoshClientArgsForCall []struct {
@@ -86,6 +122,67 @@ type FakeClientHolder struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeClientHolder) BoshClient(arg1 bool) bosh.Client {
+	fake.boshClientMutex.Lock()
+	ret, specificReturn := fake.boshClientReturnsOnCall[len(fake.boshClientArgsForCall)]
+	fake.boshClientArgsForCall = append(fake.boshClientArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	stub := fake.BoshClientStub
+	fakeReturns := fake.boshClientReturns
+	fake.recordInvocation("BoshClient", []interface{}{arg1})
+	fake.boshClientMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClientHolder) BoshClientCallCount() int {
+	fake.boshClientMutex.RLock()
+	defer fake.boshClientMutex.RUnlock()
+	return len(fake.boshClientArgsForCall)
+}
+
+func (fake *FakeClientHolder) BoshClientCalls(stub func(bool) bosh.Client) {
+	fake.boshClientMutex.Lock()
+	defer fake.boshClientMutex.Unlock()
+	fake.BoshClientStub = stub
+}
+
+func (fake *FakeClientHolder) BoshClientArgsForCall(i int) bool {
+	fake.boshClientMutex.RLock()
+	defer fake.boshClientMutex.RUnlock()
+	argsForCall := fake.boshClientArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClientHolder) BoshClientReturns(result1 bosh.Client) {
+	fake.boshClientMutex.Lock()
+	defer fake.boshClientMutex.Unlock()
+	fake.BoshClientStub = nil
+	fake.boshClientReturns = struct {
+		result1 bosh.Client
+	}{result1}
+}
+
+func (fake *FakeClientHolder) BoshClientReturnsOnCall(i int, result1 bosh.Client) {
+	fake.boshClientMutex.Lock()
+	defer fake.boshClientMutex.Unlock()
+	fake.BoshClientStub = nil
+	if fake.boshClientReturnsOnCall == nil {
+		fake.boshClientReturnsOnCall = make(map[int]struct {
+			result1 bosh.Client
+		})
+	}
+	fake.boshClientReturnsOnCall[i] = struct {
+		result1 bosh.Client
+	}{result1}
+}
+
 func (fake *FakeClientHolder) CFClient(arg1 bool) cf.Client {
 	fake.cFClientMutex.Lock()
 	ret, specificReturn := fake.cFClientReturnsOnCall[len(fake.cFClientArgsForCall)]
@@ -147,6 +244,131 @@ func (fake *FakeClientHolder) CFClientReturnsOnCall(i int, result1 cf.Client) {
 	}{result1}
 }
 
+func (fake *FakeClientHolder) CredhubClient(arg1 bool) credhub.Client {
+	fake.credhubClientMutex.Lock()
+	ret, specificReturn := fake.credhubClientReturnsOnCall[len(fake.credhubClientArgsForCall)]
+	fake.credhubClientArgsForCall = append(fake.credhubClientArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	stub := fake.CredhubClientStub
+	fakeReturns := fake.credhubClientReturns
+	fake.recordInvocation("CredhubClient", []interface{}{arg1})
+	fake.credhubClientMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClientHolder) CredhubClientCallCount() int {
+	fake.credhubClientMutex.RLock()
+	defer fake.credhubClientMutex.RUnlock()
+	return len(fake.credhubClientArgsForCall)
+}
+
+func (fake *FakeClientHolder) CredhubClientCalls(stub func(bool) credhub.Client) {
+	fake.credhubClientMutex.Lock()
+	defer fake.credhubClientMutex.Unlock()
+	fake.CredhubClientStub = stub
+}
+
+func (fake *FakeClientHolder) CredhubClientArgsForCall(i int) bool {
+	fake.credhubClientMutex.RLock()
+	defer fake.credhubClientMutex.RUnlock()
+	argsForCall := fake.credhubClientArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClientHolder) CredhubClientReturns(result1 credhub.Client) {
+	fake.credhubClientMutex.Lock()
+	defer fake.credhubClientMutex.Unlock()
+	fake.CredhubClientStub = nil
+	fake.credhubClientReturns = struct {
+		result1 credhub.Client
+	}{result1}
+}
+
+func (fake *FakeClientHolder) CredhubClientReturnsOnCall(i int, result1 credhub.Client) {
+	fake.credhubClientMutex.Lock()
+	defer fake.credhubClientMutex.Unlock()
+	fake.CredhubClientStub = nil
+	if fake.credhubClientReturnsOnCall == nil {
+		fake.credhubClientReturnsOnCall = make(map[int]struct {
+			result1 credhub.Client
+		})
+	}
+	fake.credhubClientReturnsOnCall[i] = struct {
+		result1 credhub.Client
+	}{result1}
+}
+
+func (fake *FakeClientHolder) RuntimeCredhubClient(arg1 bool) (credhub.Client, error) {
+	fake.runtimeCredhubClientMutex.Lock()
+	ret, specificReturn := fake.runtimeCredhubClientReturnsOnCall[len(fake.runtimeCredhubClientArgsForCall)]
+	fake.runtimeCredhubClientArgsForCall = append(fake.runtimeCredhubClientArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	stub := fake.RuntimeCredhubClientStub
+	fakeReturns := fake.runtimeCredhubClientReturns
+	fake.recordInvocation("RuntimeCredhubClient", []interface{}{arg1})
+	fake.runtimeCredhubClientMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClientHolder) RuntimeCredhubClientCallCount() int {
+	fake.runtimeCredhubClientMutex.RLock()
+	defer fake.runtimeCredhubClientMutex.RUnlock()
+	return len(fake.runtimeCredhubClientArgsForCall)
+}
+
+func (fake *FakeClientHolder) RuntimeCredhubClientCalls(stub func(bool) (credhub.Client, error)) {
+	fake.runtimeCredhubClientMutex.Lock()
+	defer fake.runtimeCredhubClientMutex.Unlock()
+	fake.RuntimeCredhubClientStub = stub
+}
+
+func (fake *FakeClientHolder) RuntimeCredhubClientArgsForCall(i int) bool {
+	fake.runtimeCredhubClientMutex.RLock()
+	defer fake.runtimeCredhubClientMutex.RUnlock()
+	argsForCall := fake.runtimeCredhubClientArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClientHolder) RuntimeCredhubClientReturns(result1 credhub.Client, result2 error) {
+	fake.runtimeCredhubClientMutex.Lock()
+	defer fake.runtimeCredhubClientMutex.Unlock()
+	fake.RuntimeCredhubClientStub = nil
+	fake.runtimeCredhubClientReturns = struct {
+		result1 credhub.Client
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClientHolder) RuntimeCredhubClientReturnsOnCall(i int, result1 credhub.Client, result2 error) {
+	fake.runtimeCredhubClientMutex.Lock()
+	defer fake.runtimeCredhubClientMutex.Unlock()
+	fake.RuntimeCredhubClientStub = nil
+	if fake.runtimeCredhubClientReturnsOnCall == nil {
+		fake.runtimeCredhubClientReturnsOnCall = make(map[int]struct {
+			result1 credhub.Client
+			result2 error
+		})
+	}
+	fake.runtimeCredhubClientReturnsOnCall[i] = struct {
+		result1 credhub.Client
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClientHolder) SourceBoshClient() bosh.Client {
 	fake.sourceBoshClientMutex.Lock()
 	ret, specificReturn := fake.sourceBoshClientReturnsOnCall[len(fake.sourceBoshClientArgsForCall)]
@@ -468,8 +690,14 @@ func (fake *FakeClientHolder) TargetOpsManClientReturnsOnCall(i int, result1 om.
 func (fake *FakeClientHolder) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.boshClientMutex.RLock()
+	defer fake.boshClientMutex.RUnlock()
 	fake.cFClientMutex.RLock()
 	defer fake.cFClientMutex.RUnlock()
+	fake.credhubClientMutex.RLock()
+	defer fake.credhubClientMutex.RUnlock()
+	fake.runtimeCredhubClientMutex.RLock()
+	defer fake.runtimeCredhubClientMutex.RUnlock()
 	fake.sourceBoshClientMutex.RLock()
 	defer fake.sourceBoshClientMutex.RUnlock()
 	fake.sourceCFClientMutex.RLock()
@@ -30,6 +30,17 @@ type FakeMigratorRegistry struct {
 		result2 bool
 		result3 error
 	}
+	MigratorNameStub        func(*cf.ServiceInstance) string
+	migratorNameMutex       sync.RWMutex
+	migratorNameArgsForCall []struct {
+		arg1 *cf.ServiceInstance
+	}
+	migratorNameReturns struct {
+		result1 string
+	}
+	migratorNameReturnsOnCall map[int]struct {
+		result1 string
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -106,11 +117,74 @@ func (fake *FakeMigratorRegistry) LookupReturnsOnCall(i int, result1 migrate.Ser
 	}{result1, result2, result3}
 }
 
+func (fake *FakeMigratorRegistry) MigratorName(arg1 *cf.ServiceInstance) string {
+	fake.migratorNameMutex.Lock()
+	ret, specificReturn := fake.migratorNameReturnsOnCall[len(fake.migratorNameArgsForCall)]
+	fake.migratorNameArgsForCall = append(fake.migratorNameArgsForCall, struct {
+		arg1 *cf.ServiceInstance
+	}{arg1})
+	stub := fake.MigratorNameStub
+	fakeReturns := fake.migratorNameReturns
+	fake.recordInvocation("MigratorName", []interface{}{arg1})
+	fake.migratorNameMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeMigratorRegistry) MigratorNameCallCount() int {
+	fake.migratorNameMutex.RLock()
+	defer fake.migratorNameMutex.RUnlock()
+	return len(fake.migratorNameArgsForCall)
+}
+
+func (fake *FakeMigratorRegistry) MigratorNameCalls(stub func(*cf.ServiceInstance) string) {
+	fake.migratorNameMutex.Lock()
+	defer fake.migratorNameMutex.Unlock()
+	fake.MigratorNameStub = stub
+}
+
+func (fake *FakeMigratorRegistry) MigratorNameArgsForCall(i int) *cf.ServiceInstance {
+	fake.migratorNameMutex.RLock()
+	defer fake.migratorNameMutex.RUnlock()
+	argsForCall := fake.migratorNameArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeMigratorRegistry) MigratorNameReturns(result1 string) {
+	fake.migratorNameMutex.Lock()
+	defer fake.migratorNameMutex.Unlock()
+	fake.MigratorNameStub = nil
+	fake.migratorNameReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeMigratorRegistry) MigratorNameReturnsOnCall(i int, result1 string) {
+	fake.migratorNameMutex.Lock()
+	defer fake.migratorNameMutex.Unlock()
+	fake.MigratorNameStub = nil
+	if fake.migratorNameReturnsOnCall == nil {
+		fake.migratorNameReturnsOnCall = make(map[int]struct {
+			result1 string
+		})
+	}
+	fake.migratorNameReturnsOnCall[i] = struct {
+		result1 string
+	}{result1}
+}
+
 func (fake *FakeMigratorRegistry) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.lookupMutex.RLock()
 	defer fake.lookupMutex.RUnlock()
+	fake.migratorNameMutex.RLock()
+	defer fake.migratorNameMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
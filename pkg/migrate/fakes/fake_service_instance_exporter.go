@@ -26,6 +26,20 @@ type FakeServiceInstanceExporter struct {
 	exportManagedServicesReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ExportOrgSpaceStub        func(context.Context, cfclient.Org, cfclient.Space, string) error
+	exportOrgSpaceMutex       sync.RWMutex
+	exportOrgSpaceArgsForCall []struct {
+		arg1 context.Context
+		arg2 cfclient.Org
+		arg3 cfclient.Space
+		arg4 string
+	}
+	exportOrgSpaceReturns struct {
+		result1 error
+	}
+	exportOrgSpaceReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ExportUserProvidedServicesStub        func(context.Context, cfclient.Org, cfclient.Space, string) error
 	exportUserProvidedServicesMutex       sync.RWMutex
 	exportUserProvidedServicesArgsForCall []struct {
@@ -109,6 +123,70 @@ func (fake *FakeServiceInstanceExporter) ExportManagedServicesReturnsOnCall(i in
 	}{result1}
 }
 
+func (fake *FakeServiceInstanceExporter) ExportOrgSpace(arg1 context.Context, arg2 cfclient.Org, arg3 cfclient.Space, arg4 string) error {
+	fake.exportOrgSpaceMutex.Lock()
+	ret, specificReturn := fake.exportOrgSpaceReturnsOnCall[len(fake.exportOrgSpaceArgsForCall)]
+	fake.exportOrgSpaceArgsForCall = append(fake.exportOrgSpaceArgsForCall, struct {
+		arg1 context.Context
+		arg2 cfclient.Org
+		arg3 cfclient.Space
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.ExportOrgSpaceStub
+	fakeReturns := fake.exportOrgSpaceReturns
+	fake.recordInvocation("ExportOrgSpace", []interface{}{arg1, arg2, arg3, arg4})
+	fake.exportOrgSpaceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeServiceInstanceExporter) ExportOrgSpaceCallCount() int {
+	fake.exportOrgSpaceMutex.RLock()
+	defer fake.exportOrgSpaceMutex.RUnlock()
+	return len(fake.exportOrgSpaceArgsForCall)
+}
+
+func (fake *FakeServiceInstanceExporter) ExportOrgSpaceCalls(stub func(context.Context, cfclient.Org, cfclient.Space, string) error) {
+	fake.exportOrgSpaceMutex.Lock()
+	defer fake.exportOrgSpaceMutex.Unlock()
+	fake.ExportOrgSpaceStub = stub
+}
+
+func (fake *FakeServiceInstanceExporter) ExportOrgSpaceArgsForCall(i int) (context.Context, cfclient.Org, cfclient.Space, string) {
+	fake.exportOrgSpaceMutex.RLock()
+	defer fake.exportOrgSpaceMutex.RUnlock()
+	argsForCall := fake.exportOrgSpaceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeServiceInstanceExporter) ExportOrgSpaceReturns(result1 error) {
+	fake.exportOrgSpaceMutex.Lock()
+	defer fake.exportOrgSpaceMutex.Unlock()
+	fake.ExportOrgSpaceStub = nil
+	fake.exportOrgSpaceReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeServiceInstanceExporter) ExportOrgSpaceReturnsOnCall(i int, result1 error) {
+	fake.exportOrgSpaceMutex.Lock()
+	defer fake.exportOrgSpaceMutex.Unlock()
+	fake.ExportOrgSpaceStub = nil
+	if fake.exportOrgSpaceReturnsOnCall == nil {
+		fake.exportOrgSpaceReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.exportOrgSpaceReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeServiceInstanceExporter) ExportUserProvidedServices(arg1 context.Context, arg2 cfclient.Org, arg3 cfclient.Space, arg4 string) error {
 	fake.exportUserProvidedServicesMutex.Lock()
 	ret, specificReturn := fake.exportUserProvidedServicesReturnsOnCall[len(fake.exportUserProvidedServicesArgsForCall)]
@@ -178,6 +256,8 @@ func (fake *FakeServiceInstanceExporter) Invocations() map[string][][]interface{
 	defer fake.invocationsMutex.RUnlock()
 	fake.exportManagedServicesMutex.RLock()
 	defer fake.exportManagedServicesMutex.RUnlock()
+	fake.exportOrgSpaceMutex.RLock()
+	defer fake.exportOrgSpaceMutex.RUnlock()
 	fake.exportUserProvidedServicesMutex.RLock()
 	defer fake.exportUserProvidedServicesMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -21,6 +21,18 @@ type FakeServiceInstanceParser struct {
 	marshalReturnsOnCall map[int]struct {
 		result1 error
 	}
+	MarshalUnversionedStub        func(interface{}, io.FileDescriptor) error
+	marshalUnversionedMutex       sync.RWMutex
+	marshalUnversionedArgsForCall []struct {
+		arg1 interface{}
+		arg2 io.FileDescriptor
+	}
+	marshalUnversionedReturns struct {
+		result1 error
+	}
+	marshalUnversionedReturnsOnCall map[int]struct {
+		result1 error
+	}
 	UnmarshalStub        func(interface{}, io.FileDescriptor) error
 	unmarshalMutex       sync.RWMutex
 	unmarshalArgsForCall []struct {
@@ -99,6 +111,68 @@ func (fake *FakeServiceInstanceParser) MarshalReturnsOnCall(i int, result1 error
 	}{result1}
 }
 
+func (fake *FakeServiceInstanceParser) MarshalUnversioned(arg1 interface{}, arg2 io.FileDescriptor) error {
+	fake.marshalUnversionedMutex.Lock()
+	ret, specificReturn := fake.marshalUnversionedReturnsOnCall[len(fake.marshalUnversionedArgsForCall)]
+	fake.marshalUnversionedArgsForCall = append(fake.marshalUnversionedArgsForCall, struct {
+		arg1 interface{}
+		arg2 io.FileDescriptor
+	}{arg1, arg2})
+	stub := fake.MarshalUnversionedStub
+	fakeReturns := fake.marshalUnversionedReturns
+	fake.recordInvocation("MarshalUnversioned", []interface{}{arg1, arg2})
+	fake.marshalUnversionedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeServiceInstanceParser) MarshalUnversionedCallCount() int {
+	fake.marshalUnversionedMutex.RLock()
+	defer fake.marshalUnversionedMutex.RUnlock()
+	return len(fake.marshalUnversionedArgsForCall)
+}
+
+func (fake *FakeServiceInstanceParser) MarshalUnversionedCalls(stub func(interface{}, io.FileDescriptor) error) {
+	fake.marshalUnversionedMutex.Lock()
+	defer fake.marshalUnversionedMutex.Unlock()
+	fake.MarshalUnversionedStub = stub
+}
+
+func (fake *FakeServiceInstanceParser) MarshalUnversionedArgsForCall(i int) (interface{}, io.FileDescriptor) {
+	fake.marshalUnversionedMutex.RLock()
+	defer fake.marshalUnversionedMutex.RUnlock()
+	argsForCall := fake.marshalUnversionedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeServiceInstanceParser) MarshalUnversionedReturns(result1 error) {
+	fake.marshalUnversionedMutex.Lock()
+	defer fake.marshalUnversionedMutex.Unlock()
+	fake.MarshalUnversionedStub = nil
+	fake.marshalUnversionedReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeServiceInstanceParser) MarshalUnversionedReturnsOnCall(i int, result1 error) {
+	fake.marshalUnversionedMutex.Lock()
+	defer fake.marshalUnversionedMutex.Unlock()
+	fake.MarshalUnversionedStub = nil
+	if fake.marshalUnversionedReturnsOnCall == nil {
+		fake.marshalUnversionedReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.marshalUnversionedReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeServiceInstanceParser) Unmarshal(arg1 interface{}, arg2 io.FileDescriptor) error {
 	fake.unmarshalMutex.Lock()
 	ret, specificReturn := fake.unmarshalReturnsOnCall[len(fake.unmarshalArgsForCall)]
@@ -166,6 +240,8 @@ func (fake *FakeServiceInstanceParser) Invocations() map[string][][]interface{}
 	defer fake.invocationsMutex.RUnlock()
 	fake.marshalMutex.RLock()
 	defer fake.marshalMutex.RUnlock()
+	fake.marshalUnversionedMutex.RLock()
+	defer fake.marshalUnversionedMutex.RUnlock()
 	fake.unmarshalMutex.RLock()
 	defer fake.unmarshalMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
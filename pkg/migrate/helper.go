@@ -18,20 +18,25 @@ import (
 	"fmt"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/cc"
 	"sync"
 )
 
 type (
-	Migrator    int64
+	Migrator    string
 	Service     string
 	ServiceType string
 )
 
 const (
-	ECS Migrator = iota
-	MySQL
-	SQLServer
-	CredHub
+	ECS       Migrator = "ecs"
+	MySQL     Migrator = "mysql"
+	SQLServer Migrator = "sqlserver"
+	CredHub   Migrator = "credhub"
+	RabbitMQ  Migrator = "rabbitmq"
+	Redis     Migrator = "redis"
+	Script    Migrator = "script"
 )
 
 const (
@@ -40,6 +45,9 @@ const (
 	SQLServerService       Service = "SQLServer"
 	CustomSQLServerService Service = "MSSQL-Broker"
 	CredHubService         Service = "credhub"
+	RabbitMQService        Service = "p.rabbitmq"
+	RedisService           Service = "p.redis"
+	LegacyRedisService     Service = "p-redis"
 )
 
 const (
@@ -48,31 +56,11 @@ const (
 )
 
 func (m Migrator) String() string {
-	switch m {
-	case ECS:
-		return "ecs"
-	case MySQL:
-		return "mysql"
-	case SQLServer:
-		return "sqlserver"
-	case CredHub:
-		return "credhub"
-	}
-	return "unknown"
+	return string(m)
 }
 
 func (m Migrator) HasCCDBConfig() bool {
-	switch m {
-	case ECS:
-		return true
-	case MySQL:
-		return false
-	case SQLServer:
-		return true
-	case CredHub:
-		return false
-	}
-	return false
+	return cc.IsCCDBTypeMigrator(m.String())
 }
 
 func (s Service) String() string {
@@ -122,7 +110,7 @@ func (h *MigratorHelper) GetMigratorType(service string) (Migrator, bool) {
 		return m, true
 	}
 
-	return 0, false
+	return "", false
 }
 
 func (h *MigratorHelper) GetReader() config.MigrationReader {
@@ -138,9 +126,28 @@ func (h *MigratorHelper) IsCCDBMigrator(service string) bool {
 
 func (h *MigratorHelper) loadSupportedMigrators() {
 	h.supportedMigrators = make(map[Service]Migrator)
-	h.supportedMigrators[CustomSQLServerService] = SQLServer
-	h.supportedMigrators[SQLServerService] = SQLServer
-	h.supportedMigrators[ECSBucketService] = ECS
-	h.supportedMigrators[MySQLService] = MySQL
-	h.supportedMigrators[CredHubService] = CredHub
+	for label, name := range registeredServiceLabels() {
+		h.supportedMigrators[Service(label)] = Migrator(name)
+	}
+
+	if h.migrationReader == nil {
+		return
+	}
+
+	// service labels in si-migrator.yml map custom broker names to a registered migrator
+	cfg, err := h.migrationReader.GetMigration()
+	if err != nil || cfg == nil {
+		return
+	}
+	for _, m := range cfg.Migrators {
+		if _, ok := lookupBuilder(m.Name); !ok {
+			if len(m.ServiceLabels) > 0 {
+				log.Warnf("Ignoring service labels %v, migrator %q is not supported", m.ServiceLabels, m.Name)
+			}
+			continue
+		}
+		for _, label := range m.ServiceLabels {
+			h.supportedMigrators[Service(label)] = Migrator(m.Name)
+		}
+	}
 }
@@ -0,0 +1,242 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ExportFile is the name of the journal written to the export directory during an export
+	ExportFile string = ".export-journal.json"
+	// ImportFile is the name of the journal written to the import directory during an import
+	ImportFile string = ".import-journal.json"
+
+	separator string = "%"
+)
+
+// Status is the migration state of a single service instance
+type Status string
+
+const (
+	InProgress Status = "in_progress"
+	Failed     Status = "failed"
+	Completed  Status = "completed"
+)
+
+// Entry records the progress of a single service instance migration
+type Entry struct {
+	Org       string    `json:"org"`
+	Space     string    `json:"space"`
+	Instance  string    `json:"instance"`
+	Status    Status    `json:"status"`
+	Step      int       `json:"step"`
+	StepName  string    `json:"step_name,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Journal is a thread safe record of service instance migrations persisted to disk
+// after every change, so an interrupted migration can be resumed
+type Journal struct {
+	path    string
+	entries map[string]*Entry
+	mu      sync.Mutex
+}
+
+// Open returns the journal stored at the given path. When resume is false any
+// existing journal is discarded and an empty one is started.
+func Open(path string, resume bool) (*Journal, error) {
+	j := &Journal{
+		path:    path,
+		entries: make(map[string]*Entry),
+	}
+
+	if !resume {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove journal %q: %w", path, err)
+		}
+		return j, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return j, nil
+		}
+		return nil, fmt.Errorf("failed to read journal %q: %w", path, err)
+	}
+
+	var entries []*Entry
+	if err = json.Unmarshal(b, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse journal %q: %w", path, err)
+	}
+
+	for _, e := range entries {
+		j.entries[key(e.Org, e.Space, e.Instance)] = e
+	}
+
+	return j, nil
+}
+
+// Path is the location of the journal on disk
+func (j *Journal) Path() string {
+	return j.path
+}
+
+// Entries returns a copy of all the entries in the journal
+func (j *Journal) Entries() []Entry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entries := make([]Entry, 0, len(j.entries))
+	for _, e := range j.entries {
+		entries = append(entries, *e)
+	}
+	return entries
+}
+
+// Track returns a Tracker that records progress for the given service instance
+func (j *Journal) Track(org, space, instance string) *Tracker {
+	return &Tracker{
+		journal:  j,
+		org:      org,
+		space:    space,
+		instance: instance,
+	}
+}
+
+func (j *Journal) get(org, space, instance string) (Entry, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	e, ok := j.entries[key(org, space, instance)]
+	if !ok {
+		return Entry{}, false
+	}
+	return *e, true
+}
+
+func (j *Journal) update(org, space, instance string, fn func(e *Entry)) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	k := key(org, space, instance)
+	e, ok := j.entries[k]
+	if !ok {
+		e = &Entry{
+			Org:      org,
+			Space:    space,
+			Instance: instance,
+		}
+		j.entries[k] = e
+	}
+	fn(e)
+	e.UpdatedAt = time.Now().UTC()
+
+	return j.save()
+}
+
+// save writes the journal to a temp file first so a crash never leaves a truncated journal behind
+func (j *Journal) save() error {
+	entries := make([]*Entry, 0, len(j.entries))
+	for _, e := range j.entries {
+		entries = append(entries, e)
+	}
+
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal journal: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(j.path), filepath.Base(j.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write journal %q: %w", j.path, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err = tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write journal %q: %w", j.path, err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write journal %q: %w", j.path, err)
+	}
+
+	return os.Rename(tmp.Name(), j.path)
+}
+
+func key(org, space, instance string) string {
+	return strings.Join([]string{org, space, instance}, separator)
+}
+
+// Tracker records the progress of a single service instance in a Journal
+type Tracker struct {
+	journal  *Journal
+	org      string
+	space    string
+	instance string
+}
+
+// Completed returns true if the service instance was fully migrated by a previous run
+func (t *Tracker) Completed() bool {
+	e, ok := t.journal.get(t.org, t.space, t.instance)
+	return ok && e.Status == Completed
+}
+
+// NextStep returns the index of the first flow step that has not succeeded yet
+func (t *Tracker) NextStep() int {
+	e, ok := t.journal.get(t.org, t.space, t.instance)
+	if !ok || e.Status == Completed {
+		return 0
+	}
+	return e.Step
+}
+
+// StepSucceeded records that the flow step at index step finished successfully
+func (t *Tracker) StepSucceeded(step int, name string) error {
+	return t.journal.update(t.org, t.space, t.instance, func(e *Entry) {
+		e.Status = InProgress
+		e.Step = step + 1
+		e.StepName = name
+		e.Error = ""
+	})
+}
+
+// StepFailed records that the flow step at index step returned an error
+func (t *Tracker) StepFailed(step int, name string, err error) error {
+	return t.journal.update(t.org, t.space, t.instance, func(e *Entry) {
+		e.Status = Failed
+		e.Step = step
+		e.StepName = name
+		if err != nil {
+			e.Error = err.Error()
+		}
+	})
+}
+
+// Complete records that the service instance was fully migrated
+func (t *Tracker) Complete() error {
+	return t.journal.update(t.org, t.space, t.instance, func(e *Entry) {
+		e.Status = Completed
+		e.Error = ""
+	})
+}
@@ -0,0 +1,128 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package journal_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/journal"
+)
+
+func TestTrackerRecordsSteps(t *testing.T) {
+	path := filepath.Join(t.TempDir(), journal.ImportFile)
+	j, err := journal.Open(path, false)
+	require.NoError(t, err)
+
+	tracker := j.Track("some-org", "some-space", "some-instance")
+	assert.False(t, tracker.Completed())
+	assert.Equal(t, 0, tracker.NextStep())
+
+	require.NoError(t, tracker.StepSucceeded(0, "Logging in"))
+	require.NoError(t, tracker.StepSucceeded(1, "Creating service instance"))
+	assert.Equal(t, 2, tracker.NextStep())
+
+	require.NoError(t, tracker.StepFailed(2, "Restoring from backup", errors.New("restore failed")))
+	assert.Equal(t, 2, tracker.NextStep())
+	assert.False(t, tracker.Completed())
+
+	entries := j.Entries()
+	require.Len(t, entries, 1)
+	assert.Equal(t, journal.Failed, entries[0].Status)
+	assert.Equal(t, "Restoring from backup", entries[0].StepName)
+	assert.Equal(t, "restore failed", entries[0].Error)
+
+	require.NoError(t, tracker.Complete())
+	assert.True(t, tracker.Completed())
+	assert.Equal(t, 0, tracker.NextStep())
+}
+
+func TestOpen(t *testing.T) {
+	tests := []struct {
+		name         string
+		resume       bool
+		wantNextStep int
+	}{
+		{
+			name:         "resume loads the previous journal",
+			resume:       true,
+			wantNextStep: 1,
+		},
+		{
+			name:         "without resume the previous journal is discarded",
+			resume:       false,
+			wantNextStep: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), journal.ExportFile)
+			j, err := journal.Open(path, false)
+			require.NoError(t, err)
+			require.NoError(t, j.Track("org", "space", "done").Complete())
+			require.NoError(t, j.Track("org", "space", "partial").StepSucceeded(0, "Logging in"))
+
+			j, err = journal.Open(path, tt.resume)
+			require.NoError(t, err)
+			assert.Equal(t, tt.resume, j.Track("org", "space", "done").Completed())
+			assert.False(t, j.Track("org", "space", "partial").Completed())
+			assert.Equal(t, tt.wantNextStep, j.Track("org", "space", "partial").NextStep())
+			if !tt.resume {
+				_, err = os.Stat(path)
+				assert.True(t, os.IsNotExist(err))
+			}
+		})
+	}
+}
+
+func TestOpenMissingJournal(t *testing.T) {
+	j, err := journal.Open(filepath.Join(t.TempDir(), journal.ImportFile), true)
+	require.NoError(t, err)
+	assert.Empty(t, j.Entries())
+}
+
+func TestOpenInvalidJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), journal.ImportFile)
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0644))
+	_, err := journal.Open(path, true)
+	assert.Error(t, err)
+}
+
+func TestTrackerConcurrently(t *testing.T) {
+	path := filepath.Join(t.TempDir(), journal.ImportFile)
+	j, err := journal.Open(path, false)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			tracker := j.Track("org", "space", name)
+			assert.NoError(t, tracker.StepSucceeded(0, "step"))
+			assert.NoError(t, tracker.Complete())
+		}(name)
+	}
+	wg.Wait()
+
+	j, err = journal.Open(path, true)
+	require.NoError(t, err)
+	assert.Len(t, j.Entries(), 8)
+}
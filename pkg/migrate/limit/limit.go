@@ -0,0 +1,79 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package limit
+
+import (
+	"context"
+)
+
+// Limiter bounds how many service instances are migrated at the same time across a whole run, both in total and
+// for each migrator. A nil Limiter doesn't limit anything.
+type Limiter struct {
+	all       chan struct{}
+	migrators map[string]chan struct{}
+}
+
+// New returns a Limiter allowing maxParallel migrations at once and at most migrators[name] migrations by the
+// migrator with that name. Limits of zero or less are unbounded.
+func New(maxParallel int, migrators map[string]int) *Limiter {
+	l := &Limiter{
+		migrators: make(map[string]chan struct{}),
+	}
+	if maxParallel > 0 {
+		l.all = make(chan struct{}, maxParallel)
+	}
+	for name, n := range migrators {
+		if n > 0 {
+			l.migrators[name] = make(chan struct{}, n)
+		}
+	}
+	return l
+}
+
+// Acquire blocks until the migrator may run another migration and returns a func that frees the slot again.
+// It returns the context error if ctx is done first.
+func (l *Limiter) Acquire(ctx context.Context, migrator string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	// the migrator slot is taken first so a migration waiting on its own migrator doesn't hold up the others
+	releaseMigrator, err := acquire(ctx, l.migrators[migrator])
+	if err != nil {
+		return nil, err
+	}
+	releaseAll, err := acquire(ctx, l.all)
+	if err != nil {
+		releaseMigrator()
+		return nil, err
+	}
+
+	return func() {
+		releaseAll()
+		releaseMigrator()
+	}, nil
+}
+
+func acquire(ctx context.Context, slots chan struct{}) (func(), error) {
+	if slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,122 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package limit_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/limit"
+)
+
+func TestLimiter(t *testing.T) {
+	tests := []struct {
+		name        string
+		limiter     *limit.Limiter
+		migrators   []string
+		wantAtMost  map[string]int32
+		wantOverall int32
+	}{
+		{
+			name:        "limits all migrations",
+			limiter:     limit.New(3, nil),
+			migrators:   []string{"mysql", "redis", "ecs", "mysql", "redis", "ecs", "mysql", "redis"},
+			wantOverall: 3,
+		},
+		{
+			name:        "limits migrations per migrator",
+			limiter:     limit.New(0, map[string]int{"mysql": 2}),
+			migrators:   []string{"mysql", "mysql", "mysql", "mysql", "mysql", "redis", "redis"},
+			wantAtMost:  map[string]int32{"mysql": 2},
+			wantOverall: 7,
+		},
+		{
+			name:        "applies both limits",
+			limiter:     limit.New(4, map[string]int{"mysql": 1}),
+			migrators:   []string{"mysql", "mysql", "mysql", "redis", "redis", "redis", "redis", "redis"},
+			wantAtMost:  map[string]int32{"mysql": 1},
+			wantOverall: 4,
+		},
+		{
+			name:        "nil limiter doesn't limit",
+			limiter:     nil,
+			migrators:   []string{"mysql", "mysql", "mysql"},
+			wantOverall: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				overall, maxOverall int32
+				mu                  sync.Mutex
+				running             = make(map[string]int32)
+				maxRunning          = make(map[string]int32)
+				wg                  sync.WaitGroup
+			)
+			for _, m := range tt.migrators {
+				wg.Add(1)
+				go func(m string) {
+					defer wg.Done()
+					release, err := tt.limiter.Acquire(context.Background(), m)
+					require.NoError(t, err)
+					defer release()
+
+					mu.Lock()
+					running[m]++
+					if running[m] > maxRunning[m] {
+						maxRunning[m] = running[m]
+					}
+					mu.Unlock()
+					n := atomic.AddInt32(&overall, 1)
+					for {
+						prev := atomic.LoadInt32(&maxOverall)
+						if n <= prev || atomic.CompareAndSwapInt32(&maxOverall, prev, n) {
+							break
+						}
+					}
+
+					time.Sleep(20 * time.Millisecond)
+
+					atomic.AddInt32(&overall, -1)
+					mu.Lock()
+					running[m]--
+					mu.Unlock()
+				}(m)
+			}
+			wg.Wait()
+
+			require.LessOrEqual(t, maxOverall, tt.wantOverall)
+			for m, n := range tt.wantAtMost {
+				require.LessOrEqual(t, maxRunning[m], n, m)
+			}
+		})
+	}
+}
+
+func TestLimiter_AcquireCanceled(t *testing.T) {
+	l := limit.New(1, nil)
+	release, err := l.Acquire(context.Background(), "mysql")
+	require.NoError(t, err)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = l.Acquire(ctx, "redis")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
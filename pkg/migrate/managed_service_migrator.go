@@ -65,6 +65,14 @@ func NewManagedServiceFlow(org, space string, h ClientHolder, instance *cf.Servi
 			CreateService(org, space, instance, h, isExport),
 			flow.WithDisplay("Creating managed service"),
 		),
+		flow.StepWithProgressBar(
+			BindApps(org, space, instance, h, isExport),
+			flow.WithDisplay("Binding apps"),
+		),
+		flow.StepWithProgressBar(
+			CreateServiceKeys(org, space, instance, h, isExport),
+			flow.WithDisplay("Creating service keys"),
+		),
 	)
 }
 
@@ -105,6 +113,7 @@ func CreateService(orgName, spaceName string, instance *cf.ServiceInstance, h Cl
 
 		creds := map[string]interface{}{}
 		if len(instance.Credentials) > 0 {
+			log.RegisterCredentials(instance.Credentials)
 			for k, v := range instance.Credentials {
 				creds[k] = v
 				if val, ok := v.(string); ok {
@@ -112,14 +121,16 @@ func CreateService(orgName, spaceName string, instance *cf.ServiceInstance, h Cl
 				}
 			}
 			instance.Credentials = creds
+			// the replaced domains may have turned a registered secret into a new value
+			log.RegisterCredentials(instance.Credentials)
 			log.Debugf("Set creds to %v", instance.Credentials)
 		}
 
-		sis, err := client.ListServiceInstancesByQuery(url.Values{"q": []string{
-			fmt.Sprintf("organization_guid:%s", org.Guid),
-			fmt.Sprintf("space_guid:%s", space.Guid),
-			fmt.Sprintf("name:%s", instance.Name),
-		}})
+		sis, err := client.ListServiceInstancesByQuery(url.Values{
+			"organization_guids": []string{org.Guid},
+			"space_guids":        []string{space.Guid},
+			"names":              []string{instance.Name},
+		})
 		if err != nil {
 			log.Errorf("Error looking up user provided services by name: %q in %s/%s, %v", instance.Name, orgName, spaceName, err)
 			sis = []cfclient.ServiceInstance{}
@@ -141,22 +152,9 @@ func CreateService(orgName, spaceName string, instance *cf.ServiceInstance, h Cl
 		if len(sis) == 0 {
 			log.Debugf("Creating service instance %+v", instance)
 
-			servicePlans, err := client.ListServicePlans()
+			_, targetPlan, err := cf.FindServicePlan(client, instance.ServiceToCreate(), instance.PlanToCreate())
 			if err != nil {
-				return nil, fmt.Errorf("failed to list service plans from target foundation: %w", err)
-			}
-
-			var targetPlan cfclient.ServicePlan
-			var foundPlan bool
-			for _, plan := range servicePlans {
-				if plan.Name == instance.Plan {
-					targetPlan = plan
-					foundPlan = true
-					break
-				}
-			}
-			if !foundPlan {
-				return nil, fmt.Errorf("failed to find a service plan %q for service instance %q", instance.Plan, instance.Name)
+				return nil, fmt.Errorf("failed to find a service plan %q for service instance %q: %w", instance.PlanToCreate(), instance.Name, err)
 			}
 			_, err = client.CreateServiceInstance(cfclient.ServiceInstanceRequest{
 				Name:            instance.Name,
@@ -55,9 +55,16 @@ func TestManagedServiceMigrator_Migrate(t *testing.T) {
 		{
 			name: "creates a service when service does not exist",
 			cfClient: &cffakes.FakeClient{
-				ListServicePlansStub: func() ([]cfclient.ServicePlan, error) {
+				ListServicesStub: func() ([]cfclient.Service, error) {
+					return []cfclient.Service{
+						{Guid: "other-service-guid", Label: "other-service"},
+						{Guid: "some-service-guid", Label: "p.mysql"},
+					}, nil
+				},
+				ListServicePlansByQueryStub: func(values url.Values) ([]cfclient.ServicePlan, error) {
 					return []cfclient.ServicePlan{
 						{
+							Guid: "some-plan-guid",
 							Name: "some-plan",
 						},
 					}, nil
@@ -69,10 +76,11 @@ func TestManagedServiceMigrator_Migrate(t *testing.T) {
 					DryRun:           false,
 				},
 				instance: &cf.ServiceInstance{
-					Name:   "some-service",
-					Tags:   "tag1,tag2",
-					Params: map[string]interface{}{"username": "some-user", "password": "some-password"},
-					Plan:   "some-plan",
+					Name:    "some-service",
+					Tags:    "tag1,tag2",
+					Params:  map[string]interface{}{"username": "some-user", "password": "some-password"},
+					Plan:    "some-plan",
+					Service: "p.mysql",
 				},
 				Org:          "some-org",
 				Space:        "some-space",
@@ -83,13 +91,19 @@ func TestManagedServiceMigrator_Migrate(t *testing.T) {
 				ctx: context.TODO(),
 			},
 			want: &cf.ServiceInstance{
-				Name:   "some-service",
-				Tags:   "tag1,tag2",
-				Params: map[string]interface{}{"username": "some-user", "password": "some-password"},
-				Plan:   "some-plan",
+				Name:    "some-service",
+				Tags:    "tag1,tag2",
+				Params:  map[string]interface{}{"username": "some-user", "password": "some-password"},
+				Plan:    "some-plan",
+				Service: "p.mysql",
 			},
 			wantErr: false,
 			afterFunc: func(t *testing.T, fakeClient *cffakes.FakeClient) {
+				require.Equal(t, url.Values{
+					"service_offering_guids": []string{"some-service-guid"},
+					"names":                  []string{"some-plan"},
+				}, fakeClient.ListServicePlansByQueryArgsForCall(0))
+				require.Equal(t, "some-plan-guid", fakeClient.CreateServiceInstanceArgsForCall(0).ServicePlanGuid)
 				require.Equal(t, 1, fakeClient.CreateServiceInstanceCallCount())
 				require.Equal(t, 0, fakeClient.UpdateSICallCount())
 			},
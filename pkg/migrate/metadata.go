@@ -0,0 +1,157 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package migrate
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
+)
+
+// exportMetadata adds the labels and annotations of the service instance, its app bindings and its service
+// keys on the source foundation to the exported service instance
+func exportMetadata(client cf.Client, instance *cf.ServiceInstance) error {
+	m, err := client.GetServiceInstanceMetadata(instance.GUID)
+	if err != nil {
+		return err
+	}
+	instance.Labels, instance.Annotations = m.Labels, m.Annotations
+
+	for i, b := range instance.ServiceBindings {
+		m, err = client.GetServiceCredentialBindingMetadata(b.Guid)
+		if err != nil {
+			return err
+		}
+		instance.ServiceBindings[i].Labels, instance.ServiceBindings[i].Annotations = m.Labels, m.Annotations
+	}
+
+	for i, k := range instance.ServiceKeys {
+		m, err = client.GetServiceCredentialBindingMetadata(k.Guid)
+		if err != nil {
+			return err
+		}
+		instance.ServiceKeys[i].Labels, instance.ServiceKeys[i].Annotations = m.Labels, m.Annotations
+	}
+
+	return nil
+}
+
+// hasMetadata returns true if the service instance, one of its app bindings or one of its service keys was
+// exported with labels or annotations
+func hasMetadata(instance *cf.ServiceInstance) bool {
+	if !metadataOf(instance.Labels, instance.Annotations).IsEmpty() {
+		return true
+	}
+	for _, b := range instance.ServiceBindings {
+		if !metadataOf(b.Labels, b.Annotations).IsEmpty() {
+			return true
+		}
+	}
+	for _, k := range instance.ServiceKeys {
+		if !metadataOf(k.Labels, k.Annotations).IsEmpty() {
+			return true
+		}
+	}
+	return false
+}
+
+// applyMetadata adds the exported labels and annotations to the imported service instance, its app bindings
+// and its service keys through the Cloud Controller API. It runs after every migrator, so instances restored
+// straight into the Cloud Controller database get their metadata too. Bindings are matched by the name of the
+// bound app and keys by name, the ones that were not recreated on the target are skipped.
+func applyMetadata(client cf.Client, orgName, spaceName string, instance *cf.ServiceInstance) error {
+	_, _, guid, err := findTargetServiceInstance(client, orgName, spaceName, instance)
+	if err != nil {
+		return err
+	}
+
+	if m := metadataOf(instance.Labels, instance.Annotations); !m.IsEmpty() {
+		if err = client.UpdateServiceInstanceMetadata(guid, m); err != nil {
+			return fmt.Errorf("failed to add labels and annotations to %q: %w", instance.Name, err)
+		}
+	}
+
+	if err = applyBindingMetadata(client, guid, instance); err != nil {
+		return err
+	}
+
+	for _, key := range instance.ServiceKeys {
+		m := metadataOf(key.Labels, key.Annotations)
+		if m.IsEmpty() {
+			continue
+		}
+
+		keys, err := client.ListServiceKeysByQuery(url.Values{
+			"names":                  []string{key.Name},
+			"service_instance_guids": []string{guid},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to look up service key %q of %q: %w", key.Name, instance.Name, err)
+		}
+		if len(keys) == 0 {
+			log.Debugf("Service key %q of %q was not imported, skipping its labels and annotations", key.Name, instance.Name)
+			continue
+		}
+
+		if err = client.UpdateServiceCredentialBindingMetadata(keys[0].Guid, m); err != nil {
+			return fmt.Errorf("failed to add labels and annotations to service key %q of %q: %w", key.Name, instance.Name, err)
+		}
+	}
+
+	log.Debugf("Added labels and annotations to %q", instance.Name)
+
+	return nil
+}
+
+func applyBindingMetadata(client cf.Client, guid string, instance *cf.ServiceInstance) error {
+	metadata := make(map[string]cf.Metadata)
+	for _, b := range instance.ServiceBindings {
+		m := metadataOf(b.Labels, b.Annotations)
+		if appName, ok := instance.Apps[b.Guid]; ok && !m.IsEmpty() {
+			metadata[appName] = m
+		}
+	}
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	bindings, err := client.ListServiceBindingsByQuery(url.Values{"service_instance_guids": []string{guid}})
+	if err != nil {
+		return fmt.Errorf("failed to look up bindings of %q: %w", instance.Name, err)
+	}
+
+	for _, b := range bindings {
+		app, err := client.GetAppByGuidNoInlineCall(b.AppGuid)
+		if err != nil {
+			return fmt.Errorf("could not find app %s bound to %q: %w", b.AppGuid, instance.Name, err)
+		}
+		m, ok := metadata[app.Name]
+		if !ok {
+			continue
+		}
+
+		if err = client.UpdateServiceCredentialBindingMetadata(b.Guid, m); err != nil {
+			return fmt.Errorf("failed to add labels and annotations to the binding of %q to app %q: %w", instance.Name, app.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func metadataOf(labels, annotations map[string]string) cf.Metadata {
+	return cf.Metadata{Labels: labels, Annotations: annotations}
+}
@@ -15,10 +15,8 @@
 package mysql
 
 import (
-	"bufio"
 	"context"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -27,24 +25,28 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
-	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/bosh"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/credhub"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/encryption"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/exec"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/flow"
 	sio "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/io"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
 	mysql "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/mysql/config"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/mysql/s3"
-	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/om"
 )
 
 type BackupDateTimeExtractor func(s string) (string, string, error)
 type BackupIDExtractor func(s string) (string, error)
 type BackupFilenameExtractor func(s string) (string, error)
-type EncryptionKeyExtractor func(s string) (string, error)
 
-func NewExportSequence(api, org, space string, instance *cf.ServiceInstance, om config.OpsManager, downloader s3.ObjectDownloader, executor exec.Executor, exportDir string) flow.Flow {
+// ClientHolder provides the client for the runtime credhub, where the broker stores the encryption keys of the backups
+type ClientHolder interface {
+	RuntimeCredhubClient(toSource bool) (credhub.Client, error)
+}
+
+func NewExportSequence(api, org, space string, instance *cf.ServiceInstance, om config.OpsManager, h ClientHolder, downloader s3.ObjectDownloader, executor exec.Executor, exportDir string) flow.Flow {
 	cfHome, err := os.MkdirTemp("", instance.GUID)
 	if err != nil {
 		panic("failed to create CF_HOME")
@@ -55,10 +57,12 @@ func NewExportSequence(api, org, space string, instance *cf.ServiceInstance, om
 		flow.StepWithProgressBar(
 			cf.LoginSourceFoundation(executor, om, api, org, space, cfHome),
 			flow.WithDisplay("Logging into source foundation"),
+			flow.WithAlwaysRun(),
 		),
 		flow.StepWithProgressBar(
 			InstallADBRPlugin(executor, cfHome),
 			flow.WithDisplay("Installing adbr plugin"),
+			flow.WithAlwaysRun(),
 		),
 		flow.StepWithProgressBar(
 			CreateBackup(executor, cfHome, *instance),
@@ -71,14 +75,17 @@ func NewExportSequence(api, org, space string, instance *cf.ServiceInstance, om
 		flow.StepWithProgressBar(
 			GetLatestBackup(executor, cfHome, *instance),
 			flow.WithDisplay("Getting latest backup"),
+			flow.WithAlwaysRun(),
 		),
 		flow.StepWithProgressBar(
 			DownloadBackup(executor, instance, downloader, backupDateTimeExtractor, backupIDExtractor, sio.NewFileSystemHelper(), exportDir),
 			flow.WithDisplay("Downloading backup"),
+			flow.WithAlwaysRun(),
 		),
 		flow.StepWithProgressBar(
-			RetrieveEncryptionKey(executor, om, instance, encryptionKeyExtractor),
+			RetrieveEncryptionKey(h, instance),
 			flow.WithDisplay("Retrieving encryption key"),
+			flow.WithAlwaysRun(),
 		),
 	)
 }
@@ -165,20 +172,30 @@ func DownloadBackup(e exec.Executor, instance *cf.ServiceInstance, downloader s3
 		}
 		log.Infof("Downloading latest backup to %q", cfg.BackupDirectory)
 
+		var err error
 		switch cfg.Type {
 		case mysql.SCP:
-			return exec.Result{}, scpDownload(ctx, cfg, e, instance, dateTimeExtractor, idExtractor, dryRun)
+			err = scpDownload(ctx, cfg, e, instance, dateTimeExtractor, idExtractor, dryRun)
 		case mysql.S3:
-			return exec.Result{}, s3Download(ctx, cfg, e, instance, downloader, dateTimeExtractor, idExtractor, fso)
+			err = s3Download(ctx, cfg, e, instance, downloader, dateTimeExtractor, idExtractor, fso)
 		case mysql.Minio:
-			return exec.Result{}, minioDownload(ctx, cfg, e, instance, dateTimeExtractor, idExtractor, dryRun)
+			err = minioDownload(ctx, cfg, e, instance, dateTimeExtractor, idExtractor, dryRun)
+		default:
+			return exec.Result{}, fmt.Errorf("failed to backup strategy for type '%s'", cfg.Type)
+		}
+		if err != nil || dryRun {
+			return exec.Result{}, err
+		}
+
+		if err = encryption.FromContext(ctx).EncryptFile(instance.BackupFile); err != nil {
+			return exec.Result{}, errors.Wrap(err, fmt.Sprintf("failed to encrypt backup %q", instance.BackupFile))
 		}
 
-		return exec.Result{}, fmt.Errorf("failed to backup strategy for type '%s'", cfg.Type)
+		return exec.Result{}, nil
 	}
 }
 
-func RetrieveEncryptionKey(e exec.Executor, om config.OpsManager, instance *cf.ServiceInstance, encryptionKeyExtractor EncryptionKeyExtractor) flow.StepFunc {
+func RetrieveEncryptionKey(h ClientHolder, instance *cf.ServiceInstance) flow.StepFunc {
 	return func(ctx context.Context, c interface{}, dryRun bool) (flow.Result, error) {
 		log.Infof("Retrieving encryption key")
 		if err := checkRequiredParams("missing required param: %q is not set",
@@ -193,79 +210,32 @@ func RetrieveEncryptionKey(e exec.Executor, om config.OpsManager, instance *cf.S
 			}
 			return exec.Result{}, err
 		}
-
-		cfDeploymentName, err := findDeploymentName(ctx, e, om, "^cf-")
-		if err != nil {
-			return exec.Result{}, err
+		if dryRun {
+			return exec.Result{DryRun: true}, nil
 		}
 
-		credhubAdminSecret, err := findCredhubAdminSecret(ctx, e, om, cfDeploymentName)
+		name := fmt.Sprintf("/tanzu-mysql/backups/%s_%s", instance.GUID, instance.BackupID)
+		log.Debugf("Getting encryption key %q from credhub", name)
+		client, err := h.RuntimeCredhubClient(true)
 		if err != nil {
 			return exec.Result{}, err
 		}
-
-		log.Debugf("Got credhub admin secret %q", credhubAdminSecret)
-		res, err := bosh.Run(e, ctx, om, "deps", "--column=name", "|", "grep", "pivotal-mysql")
-		if err != nil {
-			return exec.Result{}, errors.Wrap(err, "failed to get pivotal-mysql deployment name")
-		}
-		status := res.Status
-		log.Debugln(status.Output)
-		mysqlDeploymentName := strings.TrimSuffix(status.Output, "\t\n")
-		log.Debugf("Getting encryption key from %q", mysqlDeploymentName)
-		sshCmd := strings.Join(
-			[]string{
-				fmt.Sprintln("\"/var/vcap/packages/credhub-cli/bin/credhub api https://credhub.service.cf.internal:8844 --ca-cert /var/vcap/jobs/adbr-api/config/credhub_ca.pem && \\"),
-				fmt.Sprintf("/var/vcap/packages/credhub-cli/bin/credhub login --client-name credhub_admin_client --client-secret %s && \\", credhubAdminSecret),
-				fmt.Sprintf("/var/vcap/packages/credhub-cli/bin/credhub get -n /tanzu-mysql/backups/%s_%s -q\"", instance.GUID, instance.BackupID),
-			}, "\n")
-		res, err = bosh.Run(e, ctx, om, "-d", mysqlDeploymentName, "ssh", "dedicated-mysql-broker/0", "-c", sshCmd)
+		cred, err := client.GetCredential(name)
 		if err != nil {
-			return exec.Result{}, errors.Wrap(err, fmt.Sprintf("failed to get encryption key from credhub: cmd: %q", sshCmd))
+			return exec.Result{}, errors.Wrap(err, fmt.Sprintf("failed to get encryption key %q from credhub", name))
 		}
-		status = res.Status
-		key, err := encryptionKeyExtractor(status.Output)
-		if err != nil {
-			return exec.Result{}, err
+		key, ok := cred.Value.(string)
+		if !ok || key == "" {
+			return exec.Result{}, fmt.Errorf("credential %q does not contain an encryption key", name)
 		}
 
-		log.Debugf("Encrypt key is %q", key)
+		log.RegisterSecrets(key)
 		instance.BackupEncryptionKey = key
 
 		return exec.Result{}, nil
 	}
 }
 
-func findDeploymentName(ctx context.Context, e exec.Executor, om config.OpsManager, pattern string) (string, error) {
-	res, err := bosh.Run(e, ctx, om, "deps", "--column=name", "|", "grep", "'"+pattern+"'", "|", "tr", "-d", "'\\t\\n'")
-	if err != nil {
-		return "", errors.Wrap(err, "failed to get deployments")
-	}
-	status := res.Status
-	log.Debugln(status.Output)
-	deployment := strings.TrimSuffix(status.Output, "\t\n")
-	match, _ := regexp.MatchString(pattern, deployment)
-	if !match {
-		return "", fmt.Errorf("failed to find deployment name")
-	}
-
-	return deployment, nil
-}
-
-func findCredhubAdminSecret(ctx context.Context, e exec.Executor, opsman config.OpsManager, deploymentName string) (string, error) {
-	log.Debugf("Getting credhub admin credentials from %q", deploymentName)
-	res, err := om.Run(e, ctx, opsman,
-		fmt.Sprintf("curl -s -p /api/v0/deployed/products/%s/credentials/.uaa.credhub_admin_client_client_credentials | "+
-			"jq -r .credential.value.password", deploymentName))
-	if err != nil {
-		return "", errors.Wrap(err, "failed to find credhub admin credentials")
-	}
-	status := res.Status
-	credhubAdminSecret := strings.TrimSuffix(status.Output, "\n")
-
-	return credhubAdminSecret, nil
-}
-
 func scpDownload(ctx context.Context, cfg mysql.Config, e exec.Executor, instance *cf.ServiceInstance, dateTimeExtractor BackupDateTimeExtractor, idExtractor BackupIDExtractor, dryRun bool) error {
 	if err := checkRequiredParams("required param %q is not set in si-migrator.yml for scp backup",
 		map[string]string{
@@ -528,38 +498,6 @@ func backupDateTimeExtractor(s string) (string, string, error) {
 	return dt.Format("2006/01/02"), dt.Format("15:04:05"), nil
 }
 
-func encryptionKeyExtractor(input string) (string, error) {
-	if len(input) == 0 {
-		return "", fmt.Errorf("couldn't extract encryption key, output is empty")
-	}
-	log.Debugln(input)
-	scanner := bufio.NewScanner(strings.NewReader(input))
-	split := func(data []byte, atEOF bool) (int, []byte, error) {
-		lines := strings.Split(string(data), "\n")
-		if atEOF {
-			return 0, nil, io.EOF
-		}
-		for _, l := range lines {
-			fields := strings.Split(l, "| ")
-			return len(l) + 1, []byte(fields[1]), nil
-		}
-		return 0, nil, nil
-	}
-	scanner.Split(split)
-
-	fields := make([]string, 0, 6)
-	for scanner.Scan() {
-		if scanner.Text() != "" {
-			fields = append(fields, scanner.Text())
-		}
-	}
-	if scanner.Err() != nil {
-		return "", scanner.Err()
-	}
-
-	return strings.TrimSuffix(fields[len(fields)-2], "\r"), nil
-}
-
 func extractBackupDetails(s string, dateTimeExtractor BackupDateTimeExtractor, idExtractor BackupIDExtractor) (string, string, string, error) {
 	var backupDate, backupTime, backupID string
 	var err error
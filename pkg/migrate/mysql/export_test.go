@@ -28,6 +28,8 @@ import (
 
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/credhub"
+	credhubfakes "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/credhub/fakes"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/exec"
 	scriptfakes "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/exec/fakes"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/flow"
@@ -501,90 +503,71 @@ rm -f /path/to/mysql-backups/some-guid_1637790300.tar`))
 	}
 }
 
+type clientHolder struct {
+	credhubClient credhub.Client
+	err           error
+}
+
+func (h clientHolder) RuntimeCredhubClient(bool) (credhub.Client, error) {
+	return h.credhubClient, h.err
+}
+
 func TestRetrieveEncryptionKey(t *testing.T) {
-	fakeScriptExecutor := new(scriptfakes.FakeExecutor)
-	type args struct {
-		config   *config.Migration
-		instance *cf.ServiceInstance
-		dryRun   bool
-		want1    string
-		want2    string
-		want3    string
-		want4    string
-		om       config.OpsManager
-	}
 	tests := []struct {
-		name               string
-		args               args
-		wantErr            error
-		fakeScriptExecutor *scriptfakes.FakeExecutor
+		name      string
+		value     interface{}
+		err       error
+		clientErr error
+		dryRun    bool
+		want      string
+		wantErr   bool
 	}{
 		{
-			name: "retrieves encryption key",
-			args: args{
-				dryRun: false,
-				instance: &cf.ServiceInstance{
-					GUID:       "some-guid",
-					BackupID:   "some-backup-id",
-					BackupFile: "/path/to/mysql-backup-1638566224-e41880ee-c5a5-4de1-a570-e7fe117bdfa8.tar.gpg",
-				},
-				om: config.OpsManager{
-					URL:          "opsman.tas2.example.com",
-					Username:     "admin",
-					Password:     "admin-password",
-					ClientID:     "",
-					ClientSecret: "",
-					PrivateKey:   "opsman-private-key",
-					IP:           "10.1.1.1",
-					SshUser:      "ubuntu",
-				},
-				config: &config.Migration{},
-				want1:  "ssh_key_path=$(mktemp)\ncat \"opsman-private-key\" >\"$ssh_key_path\"\nchmod 0600 \"${ssh_key_path}\"\nbosh_ca_path=$(mktemp)\nbosh_ca_cert=\"$(OM_CLIENT_ID='' OM_CLIENT_SECRET='' OM_USERNAME='admin' OM_PASSWORD='admin-password' om -t opsman.tas2.example.com -k certificate-authorities -f json | jq -r '.[] | select(.active==true) | .cert_pem')\"\necho \"$bosh_ca_cert\" >\"$bosh_ca_path\"\nchmod 0600 \"${bosh_ca_path}\"\ncreds=\"$(OM_CLIENT_ID='' OM_CLIENT_SECRET='' OM_USERNAME='admin' OM_PASSWORD='admin-password' om -t opsman.tas2.example.com -k curl -s -p /api/v0/deployed/director/credentials/bosh_commandline_credentials)\"\nbosh_all=\"$(echo \"$creds\" | jq -r .credential | tr ' ' '\\n' | grep '=')\"\nbosh_client=\"$(echo \"$bosh_all\" | tr ' ' '\\n' | grep 'BOSH_CLIENT=')\"\nbosh_env=\"$(echo \"$bosh_all\" | tr ' ' '\\n' | grep 'BOSH_ENVIRONMENT=')\"\nbosh_secret=\"$(echo \"$bosh_all\" | tr ' ' '\\n' | grep 'BOSH_CLIENT_SECRET=')\"\nbosh_ca_cert=\"BOSH_CA_CERT=$bosh_ca_path\"\nbosh_proxy=\"BOSH_ALL_PROXY=ssh+socks5://ubuntu@10.1.1.1:22?private-key=${ssh_key_path}\"\nbosh_gw_host=\"BOSH_GW_HOST=10.1.1.1\"\nbosh_gw_user=\"BOSH_GW_USER=ubuntu\"\nbosh_gw_private_key=\"BOSH_GW_PRIVATE_KEY=${ssh_key_path}\"\ntrap 'rm -f ${ssh_key_path} ${bosh_ca_path}' EXIT\n/usr/bin/env \"$bosh_client\" \"$bosh_env\" \"$bosh_secret\" \"$bosh_ca_cert\" \"$bosh_proxy\" \"$bosh_gw_host\" \"$bosh_gw_user\" \"$bosh_gw_private_key\" bosh deps --column=name | grep '^cf-' | tr -d '\\t\\n'",
-				want2:  "OM_CLIENT_ID='' OM_CLIENT_SECRET='' OM_USERNAME='admin' OM_PASSWORD='admin-password' om -t 'opsman.tas2.example.com' -k curl -s -p /api/v0/deployed/products/cf-7de431470b92530a463b/credentials/.uaa.credhub_admin_client_client_credentials | jq -r .credential.value.password",
-				want3:  "ssh_key_path=$(mktemp)\ncat \"opsman-private-key\" >\"$ssh_key_path\"\nchmod 0600 \"${ssh_key_path}\"\nbosh_ca_path=$(mktemp)\nbosh_ca_cert=\"$(OM_CLIENT_ID='' OM_CLIENT_SECRET='' OM_USERNAME='admin' OM_PASSWORD='admin-password' om -t opsman.tas2.example.com -k certificate-authorities -f json | jq -r '.[] | select(.active==true) | .cert_pem')\"\necho \"$bosh_ca_cert\" >\"$bosh_ca_path\"\nchmod 0600 \"${bosh_ca_path}\"\ncreds=\"$(OM_CLIENT_ID='' OM_CLIENT_SECRET='' OM_USERNAME='admin' OM_PASSWORD='admin-password' om -t opsman.tas2.example.com -k curl -s -p /api/v0/deployed/director/credentials/bosh_commandline_credentials)\"\nbosh_all=\"$(echo \"$creds\" | jq -r .credential | tr ' ' '\\n' | grep '=')\"\nbosh_client=\"$(echo \"$bosh_all\" | tr ' ' '\\n' | grep 'BOSH_CLIENT=')\"\nbosh_env=\"$(echo \"$bosh_all\" | tr ' ' '\\n' | grep 'BOSH_ENVIRONMENT=')\"\nbosh_secret=\"$(echo \"$bosh_all\" | tr ' ' '\\n' | grep 'BOSH_CLIENT_SECRET=')\"\nbosh_ca_cert=\"BOSH_CA_CERT=$bosh_ca_path\"\nbosh_proxy=\"BOSH_ALL_PROXY=ssh+socks5://ubuntu@10.1.1.1:22?private-key=${ssh_key_path}\"\nbosh_gw_host=\"BOSH_GW_HOST=10.1.1.1\"\nbosh_gw_user=\"BOSH_GW_USER=ubuntu\"\nbosh_gw_private_key=\"BOSH_GW_PRIVATE_KEY=${ssh_key_path}\"\ntrap 'rm -f ${ssh_key_path} ${bosh_ca_path}' EXIT\n/usr/bin/env \"$bosh_client\" \"$bosh_env\" \"$bosh_secret\" \"$bosh_ca_cert\" \"$bosh_proxy\" \"$bosh_gw_host\" \"$bosh_gw_user\" \"$bosh_gw_private_key\" bosh deps --column=name | grep pivotal-mysql",
-				want4:  "ssh_key_path=$(mktemp)\ncat \"opsman-private-key\" >\"$ssh_key_path\"\nchmod 0600 \"${ssh_key_path}\"\nbosh_ca_path=$(mktemp)\nbosh_ca_cert=\"$(OM_CLIENT_ID='' OM_CLIENT_SECRET='' OM_USERNAME='admin' OM_PASSWORD='admin-password' om -t opsman.tas2.example.com -k certificate-authorities -f json | jq -r '.[] | select(.active==true) | .cert_pem')\"\necho \"$bosh_ca_cert\" >\"$bosh_ca_path\"\nchmod 0600 \"${bosh_ca_path}\"\ncreds=\"$(OM_CLIENT_ID='' OM_CLIENT_SECRET='' OM_USERNAME='admin' OM_PASSWORD='admin-password' om -t opsman.tas2.example.com -k curl -s -p /api/v0/deployed/director/credentials/bosh_commandline_credentials)\"\nbosh_all=\"$(echo \"$creds\" | jq -r .credential | tr ' ' '\\n' | grep '=')\"\nbosh_client=\"$(echo \"$bosh_all\" | tr ' ' '\\n' | grep 'BOSH_CLIENT=')\"\nbosh_env=\"$(echo \"$bosh_all\" | tr ' ' '\\n' | grep 'BOSH_ENVIRONMENT=')\"\nbosh_secret=\"$(echo \"$bosh_all\" | tr ' ' '\\n' | grep 'BOSH_CLIENT_SECRET=')\"\nbosh_ca_cert=\"BOSH_CA_CERT=$bosh_ca_path\"\nbosh_proxy=\"BOSH_ALL_PROXY=ssh+socks5://ubuntu@10.1.1.1:22?private-key=${ssh_key_path}\"\nbosh_gw_host=\"BOSH_GW_HOST=10.1.1.1\"\nbosh_gw_user=\"BOSH_GW_USER=ubuntu\"\nbosh_gw_private_key=\"BOSH_GW_PRIVATE_KEY=${ssh_key_path}\"\ntrap 'rm -f ${ssh_key_path} ${bosh_ca_path}' EXIT\n/usr/bin/env \"$bosh_client\" \"$bosh_env\" \"$bosh_secret\" \"$bosh_ca_cert\" \"$bosh_proxy\" \"$bosh_gw_host\" \"$bosh_gw_user\" \"$bosh_gw_private_key\" bosh -d pivotal-mysql-205aa795cf42568cc984 ssh dedicated-mysql-broker/0 -c \"/var/vcap/packages/credhub-cli/bin/credhub api https://credhub.service.cf.internal:8844 --ca-cert /var/vcap/jobs/adbr-api/config/credhub_ca.pem && \\\n\n/var/vcap/packages/credhub-cli/bin/credhub login --client-name credhub_admin_client --client-secret credhub-secret && \\\n/var/vcap/packages/credhub-cli/bin/credhub get -n /tanzu-mysql/backups/some-guid_some-backup-id -q\"",
-			},
-			fakeScriptExecutor: fakeScriptExecutor,
+			name:  "retrieves encryption key from the runtime credhub",
+			value: "some-enc-key",
+			want:  "some-enc-key",
+		},
+		{
+			name:    "fails when credhub returns an error",
+			err:     errors.New("credential not found"),
+			wantErr: true,
+		},
+		{
+			name:      "fails when the runtime credhub can't be found",
+			clientErr: errors.New("error finding cf deployment: not found"),
+			wantErr:   true,
+		},
+		{
+			name:    "fails when the credential has no key",
+			value:   map[string]interface{}{"password": "some-enc-key"},
+			wantErr: true,
+		},
+		{
+			name:   "does not call credhub on dry run",
+			dryRun: true,
 		},
 	}
 	for _, tt := range tests {
-		tt.fakeScriptExecutor.ExecuteReturnsOnCall(0, exec.Result{
-			Status: &exec.Status{
-				Output: `cf-7de431470b92530a463b`,
-			},
-		}, nil)
-		tt.fakeScriptExecutor.ExecuteReturnsOnCall(1, exec.Result{
-			Status: &exec.Status{
-				Output: `credhub-secret`,
-			},
-		}, nil)
-		tt.fakeScriptExecutor.ExecuteReturnsOnCall(2, exec.Result{
-			Status: &exec.Status{
-				Output: `pivotal-mysql-205aa795cf42568cc984`,
-			},
-		}, nil)
-		tt.fakeScriptExecutor.ExecuteReturnsOnCall(3, exec.Result{
-			Status: &exec.Status{
-				Output: `some-enc-key`,
-			},
-		}, nil)
 		t.Run(tt.name, func(t *testing.T) {
-			if _, err := flow.RunWith(RetrieveEncryptionKey(tt.fakeScriptExecutor, tt.args.om, tt.args.instance, func(s string) (string, error) {
-				return "some-enc-key", nil
-			}), context.TODO(), tt.args.config, tt.args.dryRun); err != tt.wantErr {
-				t.Errorf("Run() = %v, want %v", err, tt.wantErr)
+			fakeCredhubClient := new(credhubfakes.FakeClient)
+			fakeCredhubClient.GetCredentialReturns(credhub.Credential{Value: tt.value}, tt.err)
+			instance := &cf.ServiceInstance{
+				GUID:     "some-guid",
+				BackupID: "some-backup-id",
+			}
+
+			_, err := flow.RunWith(RetrieveEncryptionKey(clientHolder{credhubClient: fakeCredhubClient, err: tt.clientErr}, instance), context.TODO(), &config.Migration{}, tt.dryRun)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			require.Equal(t, tt.want, instance.BackupEncryptionKey)
+			if tt.dryRun || tt.clientErr != nil {
+				require.Equal(t, 0, fakeCredhubClient.GetCredentialCallCount())
+				return
 			}
+			require.Equal(t, 1, fakeCredhubClient.GetCredentialCallCount())
+			require.Equal(t, "/tanzu-mysql/backups/some-guid_some-backup-id", fakeCredhubClient.GetCredentialArgsForCall(0))
 		})
-		require.Equal(t, 4, tt.fakeScriptExecutor.ExecuteCallCount())
-		_, got1 := tt.fakeScriptExecutor.ExecuteArgsForCall(0)
-		require.Equal(t, tt.args.want1, copyFrom(t, got1).String())
-		_, got2 := tt.fakeScriptExecutor.ExecuteArgsForCall(1)
-		require.Equal(t, tt.args.want2, copyFrom(t, got2).String())
-		_, got3 := tt.fakeScriptExecutor.ExecuteArgsForCall(2)
-		require.Equal(t, tt.args.want3, copyFrom(t, got3).String())
-		_, got4 := tt.fakeScriptExecutor.ExecuteArgsForCall(3)
-		println(got4)
-		require.Equal(t, tt.args.want4, copyFrom(t, got4).String())
 	}
 }
 
@@ -748,45 +731,6 @@ Backup ID                                         Time of Backup
 	}
 }
 
-func Test_encryptionKeyExtractor(t *testing.T) {
-	type args struct {
-		s string
-	}
-	tests := []struct {
-		name    string
-		args    args
-		want    string
-		wantErr bool
-	}{
-		{
-			name: "extract encryption key from output",
-			args: args{
-				s: `dedicated-mysql-broker/9f971072-d71a-4535-bba6-fac6b0e68daa: stderr | Unauthorized use is strictly prohibited. All access and activity
-dedicated-mysql-broker/9f971072-d71a-4535-bba6-fac6b0e68daa: stderr | is subject to logging and monitoring.
-dedicated-mysql-broker/9f971072-d71a-4535-bba6-fac6b0e68daa: stdout | Setting the target url: https://credhub.service.cf.internal:8844
-dedicated-mysql-broker/9f971072-d71a-4535-bba6-fac6b0e68daa: stdout | Login Successful
-dedicated-mysql-broker/9f971072-d71a-4535-bba6-fac6b0e68daa: stdout | 0bvUHqAFqoCK
-dedicated-mysql-broker/9f971072-d71a-4535-bba6-fac6b0e68daa: stderr | Connection to 192.168.2.31 closed.
-`,
-			},
-			want:    "0bvUHqAFqoCK",
-			wantErr: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got, err := encryptionKeyExtractor(tt.args.s)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("encryptionKeyExtractor() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if got != tt.want {
-				t.Errorf("encryptionKeyExtractor() got = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
 func Test_checkRequiredParams(t *testing.T) {
 	type args struct {
 		msg    string
@@ -25,12 +25,13 @@ import (
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/bosh"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/encryption"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/exec"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/flow"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
 )
 
-func NewImportSequence(api, org, space string, instance *cf.ServiceInstance, om config.OpsManager, executor exec.Executor) flow.Flow {
+func NewImportSequence(api, org, space string, instance *cf.ServiceInstance, om config.OpsManager, client bosh.Client, executor exec.Executor) flow.Flow {
 	cfHome, err := os.MkdirTemp("", instance.GUID)
 	if err != nil {
 		panic("failed to create CF_HOME")
@@ -40,41 +41,60 @@ func NewImportSequence(api, org, space string, instance *cf.ServiceInstance, om
 
 	return flow.ProgressBarSequence(
 		fmt.Sprintf("Importing %s", instance.Name),
-		flow.StepWithProgressBar(cf.LoginTargetFoundation(executor, om, api, org, space, cfHome), flow.WithDisplay("Logging into target foundation")),
+		flow.StepWithProgressBar(cf.LoginTargetFoundation(executor, om, api, org, space, cfHome), flow.WithDisplay("Logging into target foundation"), flow.WithAlwaysRun()),
 		flow.StepWithProgressBar(cf.CreateServiceInstance(executor, cfHome, *instance), flow.WithDisplay("Creating service instance")),
-		flow.StepWithProgressBar(cf.GetServiceInstance(executor, cfHome, instance, 15*time.Minute, 10*time.Second), flow.WithDisplay("Waiting for service instance to create")),
-		flow.StepWithProgressBar(TransferBackup(executor, om, instance), flow.WithDisplay("Transferring backup")),
-		flow.StepWithProgressBar(RestoreBackup(executor, om, instance), flow.WithDisplay("Restoring from backup")),
+		// always run so a resumed import looks up the guid of the target instance again
+		flow.StepWithProgressBar(cf.GetServiceInstance(executor, cfHome, instance, 15*time.Minute, 10*time.Second), flow.WithDisplay("Waiting for service instance to create"), flow.WithAlwaysRun()),
+		flow.StepWithProgressBar(TransferBackup(client, instance), flow.WithDisplay("Transferring backup")),
+		flow.StepWithProgressBar(RestoreBackup(client, instance), flow.WithDisplay("Restoring from backup")),
 	)
 }
 
-func TransferBackup(e exec.Executor, om config.OpsManager, instance *cf.ServiceInstance) flow.StepFunc {
+func TransferBackup(client bosh.Client, instance *cf.ServiceInstance) flow.StepFunc {
 	return func(ctx context.Context, c interface{}, dryRun bool) (flow.Result, error) {
 		log.Infof("Transferring backup")
 		if _, err := os.Stat(instance.BackupFile); os.IsNotExist(err) {
 			return exec.Result{}, fmt.Errorf("failed to transfer backup: %q, file does not exist", instance.BackupFile)
 		}
+		_, file := filepath.Split(instance.BackupFile)
 		log.Debugf("Transferring %q to %q", instance.BackupFile, fmt.Sprintf("service-instance_%s", instance.GUID))
-		return bosh.Run(e, ctx, om, "-d", fmt.Sprintf("service-instance_%s", instance.GUID), "scp",
-			fmt.Sprintf("%s mysql/0:/tmp", instance.BackupFile))
+		if dryRun {
+			return exec.Result{DryRun: true}, nil
+		}
+
+		backupFile, cleanup, err := encryption.FromContext(ctx).DecryptFile(instance.BackupFile)
+		if err != nil {
+			return exec.Result{}, err
+		}
+		defer cleanup()
+
+		if err := client.UploadFile(fmt.Sprintf("service-instance_%s", instance.GUID), "mysql/0", backupFile, filepath.Join("/tmp", file)); err != nil {
+			return exec.Result{}, err
+		}
+
+		return exec.Result{}, nil
 	}
 }
 
-func RestoreBackup(e exec.Executor, om config.OpsManager, instance *cf.ServiceInstance) flow.StepFunc {
+func RestoreBackup(client bosh.Client, instance *cf.ServiceInstance) flow.StepFunc {
 	return func(ctx context.Context, c interface{}, dryRun bool) (flow.Result, error) {
 		log.Infof("Restoring backup")
-		log.Debugf("Restoring %q on %q with %q encryption key", instance.BackupFile, fmt.Sprintf("service-instance_%s", instance.GUID), instance.BackupEncryptionKey)
+		log.Debugf("Restoring %q on %q", instance.BackupFile, fmt.Sprintf("service-instance_%s", instance.GUID))
+		if dryRun {
+			return exec.Result{DryRun: true}, nil
+		}
+
 		_, file := filepath.Split(instance.BackupFile)
-		result, err := bosh.Run(e, ctx, om, "-d", fmt.Sprintf("service-instance_%s", instance.GUID), "ssh", "mysql/0", "-c",
-			fmt.Sprintf("\"sudo mysql-restore --encryption-key %s --restore-file %s\"", instance.BackupEncryptionKey, filepath.Join("/tmp", file)))
+		out, err := client.RunCommand(fmt.Sprintf("service-instance_%s", instance.GUID), "mysql/0",
+			fmt.Sprintf("sudo mysql-restore --encryption-key %s --restore-file %s", instance.BackupEncryptionKey, filepath.Join("/tmp", file)))
 		if err != nil {
-			if strings.Contains(result.Status.Output, "Restore is permitted only in a non-empty service instance") {
+			if strings.Contains(out, "Restore is permitted only in a non-empty service instance") {
 				log.Warnln("failed to restore backup, restore is permitted only in a empty service instance")
-				return result, nil
+				return exec.Result{Output: out}, nil
 			}
-			return result, err
+			return exec.Result{Output: out}, err
 		}
 
-		return result, err
+		return exec.Result{Output: out}, nil
 	}
 }
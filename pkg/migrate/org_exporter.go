@@ -26,7 +26,6 @@ import (
 
 	"github.com/cloudfoundry-community/go-cfclient"
 	"github.com/pkg/errors"
-	"golang.org/x/sync/errgroup"
 )
 
 type OrgExporter struct {
@@ -46,7 +45,7 @@ func NewOrgExporter(e ServiceInstanceExporter, h ClientHolder, includedOrgs []st
 }
 
 func (e OrgExporter) Export(ctx context.Context, om config.OpsManager, dir string, orgs ...string) error {
-	g, gctx := errgroup.WithContext(ctx)
+	g, gctx := newGroup(ctx)
 
 	for _, o := range orgs {
 
@@ -91,6 +90,12 @@ func (e OrgExporter) Export(ctx context.Context, om config.OpsManager, dir strin
 						return e.ServiceInstanceExporter.ExportUserProvidedServices(gctx, o, s, dir)
 					})
 				}(org, space)
+
+				func(o cfclient.Org, s cfclient.Space) {
+					g.Go(func() error {
+						return e.ServiceInstanceExporter.ExportOrgSpace(gctx, o, s, dir)
+					})
+				}(org, space)
 			}
 
 			if len(spaces) < resultsPerPage {
@@ -113,7 +118,7 @@ func (e OrgExporter) Export(ctx context.Context, om config.OpsManager, dir strin
 }
 
 func (e OrgExporter) ExportAll(ctx context.Context, om config.OpsManager, dir string) error {
-	g, gctx := errgroup.WithContext(ctx)
+	g, gctx := newGroup(ctx)
 	client := e.ClientHolder.SourceCFClient()
 
 	spaces, err := client.ListSpaces()
@@ -145,6 +150,12 @@ func (e OrgExporter) ExportAll(ctx context.Context, om config.OpsManager, dir st
 				return e.ServiceInstanceExporter.ExportUserProvidedServices(gctx, o, s, dir)
 			})
 		}(org, space)
+
+		func(o cfclient.Org, s cfclient.Space) {
+			g.Go(func() error {
+				return e.ServiceInstanceExporter.ExportOrgSpace(gctx, o, s, dir)
+			})
+		}(org, space)
 	}
 
 	err = g.Wait()
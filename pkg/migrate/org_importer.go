@@ -21,7 +21,6 @@ import (
 
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
-	"golang.org/x/sync/errgroup"
 )
 
 type OrgImporter struct {
@@ -39,7 +38,7 @@ func NewOrgImporter(spaceImporter *SpaceImporter, includedOrgs []string, exclude
 }
 
 func (i OrgImporter) Import(ctx context.Context, om config.OpsManager, dir string, orgs ...string) error {
-	g, gctx := errgroup.WithContext(ctx)
+	g, gctx := newGroup(ctx)
 
 	err := i.importOrg(gctx, g, om, dir, orgs...)
 	if err != nil {
@@ -50,7 +49,7 @@ func (i OrgImporter) Import(ctx context.Context, om config.OpsManager, dir strin
 }
 
 func (i OrgImporter) ImportAll(ctx context.Context, om config.OpsManager, dir string) error {
-	g, gctx := errgroup.WithContext(ctx)
+	g, gctx := newGroup(ctx)
 
 	err := i.importOrg(gctx, g, om, dir)
 	if err != nil {
@@ -60,8 +59,8 @@ func (i OrgImporter) ImportAll(ctx context.Context, om config.OpsManager, dir st
 	return g.Wait()
 }
 
-func (i OrgImporter) importOrg(ctx context.Context, g *errgroup.Group, om config.OpsManager, dir string, orgs ...string) error {
-	serviceInstanceMap, err := i.createServiceInstances(dir, orgs...)
+func (i OrgImporter) importOrg(ctx context.Context, g *group, om config.OpsManager, dir string, orgs ...string) error {
+	serviceInstanceMap, err := i.createServiceInstances(ctx, dir, orgs...)
 	if err != nil {
 		return err
 	}
@@ -73,6 +72,11 @@ func (i OrgImporter) importOrg(ctx context.Context, g *errgroup.Group, om config
 		}
 
 		for space, instances := range spaces {
+			if err := i.createOrgSpace(ctx, org, space, instances, dir); err != nil {
+				g.Go(func() error { return err })
+				continue
+			}
+
 			func(org, space string, instances []*cf.ServiceInstance) {
 				for _, instance := range instances {
 					func(si *cf.ServiceInstance) {
@@ -0,0 +1,256 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package migrate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/cloudfoundry-community/go-cfclient"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/encryption"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
+)
+
+// OrgSpacesFile is the name of the file in the export directory recording the settings of the exported orgs and spaces
+const OrgSpacesFile string = ".org-spaces.json"
+
+// OrgSettings are the settings of an org copied to the target when the org is created on import
+type OrgSettings struct {
+	Quota  string                   `json:"quota,omitempty"`
+	Spaces map[string]SpaceSettings `json:"spaces,omitempty"`
+}
+
+// SpaceSettings are the settings of a space copied to the target when the space is created on import
+type SpaceSettings struct {
+	IsolationSegment string   `json:"isolation_segment,omitempty"`
+	Managers         []string `json:"managers,omitempty"`
+	Developers       []string `json:"developers,omitempty"`
+	Auditors         []string `json:"auditors,omitempty"`
+}
+
+// orgSpacesMutex serializes updates of the org spaces file by the spaces exported in parallel
+var orgSpacesMutex sync.Mutex
+
+// ExportOrgSpace records the quota of the org and the roles and isolation segment of the space in the export
+// directory, so they can be copied when the org and space are created on the target
+func (e *DefaultServiceInstanceExporter) ExportOrgSpace(ctx context.Context, org cfclient.Org, space cfclient.Space, dir string) error {
+	client := e.ClientHolder.SourceCFClient()
+	if cfg, ok := config.FromContext(ctx); ok && cfg.DryRun {
+		return nil
+	}
+
+	// the settings are only needed when the org or space is missing on the target, so a lookup the user isn't
+	// permitted to make leaves the setting empty instead of failing the export
+	var quota string
+	if org.QuotaDefinitionGuid != "" {
+		quotas, err := client.ListOrgQuotas()
+		if err != nil {
+			log.Warnf("Could not list org quotas, org %q is created with the default quota if missing on the target: %v", org.Name, err)
+		}
+		for _, q := range quotas {
+			if q.Guid == org.QuotaDefinitionGuid {
+				quota = q.Name
+			}
+		}
+	}
+
+	var settings SpaceSettings
+	var err error
+	if settings.Managers, err = usernames(client.ListSpaceManagers(space.Guid)); err != nil {
+		log.Warnf("Could not list managers of space %s/%s: %v", org.Name, space.Name, err)
+	}
+	if settings.Developers, err = usernames(client.ListSpaceDevelopers(space.Guid)); err != nil {
+		log.Warnf("Could not list developers of space %s/%s: %v", org.Name, space.Name, err)
+	}
+	if settings.Auditors, err = usernames(client.ListSpaceAuditors(space.Guid)); err != nil {
+		log.Warnf("Could not list auditors of space %s/%s: %v", org.Name, space.Name, err)
+	}
+	if space.IsolationSegmentGuid != "" {
+		segment, err := client.GetIsolationSegmentByGUID(space.IsolationSegmentGuid)
+		if err != nil {
+			log.Warnf("Could not get the isolation segment of space %s/%s: %v", org.Name, space.Name, err)
+		} else {
+			settings.IsolationSegment = segment.Name
+		}
+	}
+
+	orgSpacesMutex.Lock()
+	defer orgSpacesMutex.Unlock()
+
+	orgs, err := readOrgSpaces(ctx, dir)
+	if err != nil {
+		return err
+	}
+	o := orgs[org.Name]
+	o.Quota = quota
+	if o.Spaces == nil {
+		o.Spaces = make(map[string]SpaceSettings)
+	}
+	o.Spaces[space.Name] = settings
+	orgs[org.Name] = o
+
+	return writeOrgSpaces(ctx, dir, orgs)
+}
+
+func usernames(users []cfclient.User, err error) ([]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, u := range users {
+		// users of uaa clients have no username and can't be assigned roles by name
+		if u.Username != "" {
+			names = append(names, u.Username)
+		}
+	}
+	return names, nil
+}
+
+// readOrgSpaces returns the org and space settings recorded in dir, keyed by org name. Exports made before the
+// settings were recorded have none.
+func readOrgSpaces(ctx context.Context, dir string) (map[string]OrgSettings, error) {
+	orgs := make(map[string]OrgSettings)
+	b, err := encryption.FromContext(ctx).ReadFile(filepath.Join(dir, OrgSpacesFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return orgs, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", OrgSpacesFile, err)
+	}
+	if err = json.Unmarshal(b, &orgs); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", OrgSpacesFile, err)
+	}
+	return orgs, nil
+}
+
+func writeOrgSpaces(ctx context.Context, dir string, orgs map[string]OrgSettings) error {
+	b, err := json.MarshalIndent(orgs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", OrgSpacesFile, err)
+	}
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+	return encryption.FromContext(ctx).WriteFile(filepath.Join(dir, OrgSpacesFile), b, 0644)
+}
+
+// createMissingOrgSpace creates the org and the space on the target when they don't exist. Only orgs and spaces
+// created here get the settings recorded during export, existing ones are left as they are.
+func createMissingOrgSpace(ctx context.Context, client cf.Client, orgName, spaceName string, orgs map[string]OrgSettings) error {
+	dryRun := false
+	if cfg, ok := config.FromContext(ctx); ok {
+		dryRun = cfg.DryRun
+	}
+
+	settings := orgs[orgName]
+
+	org, err := client.GetOrgByName(orgName)
+	if err != nil {
+		if !cfclient.IsOrganizationNotFoundError(err) {
+			return fmt.Errorf("could not find org %q: %w", orgName, err)
+		}
+		if dryRun {
+			log.Infof("Would create org %q and space %q", orgName, spaceName)
+			return nil
+		}
+		if org, err = createOrg(client, orgName, settings.Quota); err != nil {
+			return err
+		}
+	}
+
+	_, err = client.GetSpaceByName(spaceName, org.Guid)
+	if err == nil {
+		return nil
+	}
+	if !cfclient.IsSpaceNotFoundError(err) {
+		return fmt.Errorf("could not find space %q in org %q: %w", spaceName, orgName, err)
+	}
+	if dryRun {
+		log.Infof("Would create space %q in org %q", spaceName, orgName)
+		return nil
+	}
+
+	return createSpace(client, org, spaceName, settings.Spaces[spaceName])
+}
+
+func createOrg(client cf.Client, name, quota string) (cfclient.Org, error) {
+	req := cfclient.OrgRequest{Name: name}
+	if quota != "" {
+		q, err := client.GetOrgQuotaByName(quota)
+		if err != nil {
+			log.Warnf("Org quota %q not found on the target, creating org %q with the default quota", quota, name)
+		} else {
+			req.QuotaDefinitionGuid = q.Guid
+		}
+	}
+
+	log.Infof("Creating org %q", name)
+	org, err := client.CreateOrg(req)
+	if err != nil {
+		return cfclient.Org{}, fmt.Errorf("failed to create org %q: %w", name, err)
+	}
+	return org, nil
+}
+
+func createSpace(client cf.Client, org cfclient.Org, name string, settings SpaceSettings) error {
+	log.Infof("Creating space %q in org %q", name, org.Name)
+	space, err := client.CreateSpace(cfclient.SpaceRequest{Name: name, OrganizationGuid: org.Guid})
+	if err != nil {
+		return fmt.Errorf("failed to create space %q in org %q: %w", name, org.Name, err)
+	}
+
+	roles := []struct {
+		usernames []string
+		role      string
+		assign    func(spaceGUID, name string) (cfclient.Space, error)
+	}{
+		{settings.Managers, "manager", client.AssociateSpaceManagerByUsername},
+		{settings.Developers, "developer", client.AssociateSpaceDeveloperByUsername},
+		{settings.Auditors, "auditor", client.AssociateSpaceAuditorByUsername},
+	}
+	for _, r := range roles {
+		for _, username := range r.usernames {
+			if _, err = client.AssociateOrgUserByUsername(org.Guid, username); err == nil {
+				_, err = r.assign(space.Guid, username)
+			}
+			if err != nil {
+				log.Warnf("Could not make %q a space %s of %s/%s: %v", username, r.role, org.Name, name, err)
+			}
+		}
+	}
+
+	if settings.IsolationSegment == "" {
+		return nil
+	}
+	segments, err := client.ListIsolationSegmentsByQuery(url.Values{"names": []string{settings.IsolationSegment}})
+	if err != nil || len(segments) == 0 {
+		log.Warnf("Isolation segment %q not found on the target, space %s/%s uses the default segment", settings.IsolationSegment, org.Name, name)
+		return nil
+	}
+	if err = client.AddIsolationSegmentToOrg(segments[0].GUID, org.Guid); err != nil {
+		return fmt.Errorf("failed to entitle org %q to isolation segment %q: %w", org.Name, settings.IsolationSegment, err)
+	}
+	if err = client.IsolationSegmentForSpace(space.Guid, segments[0].GUID); err != nil {
+		return fmt.Errorf("failed to assign isolation segment %q to space %s/%s: %w", settings.IsolationSegment, org.Name, name, err)
+	}
+
+	return nil
+}
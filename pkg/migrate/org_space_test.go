@@ -0,0 +1,229 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package migrate_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry-community/go-cfclient"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	cffakes "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf/fakes"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/encryption"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/fakes"
+)
+
+func exportTestOrgSpace(t *testing.T, dir string) {
+	source := new(cffakes.FakeClient)
+	source.ListOrgQuotasReturns([]cfclient.OrgQuota{{Guid: "default-guid", Name: "default"}, {Guid: "large-guid", Name: "large"}}, nil)
+	source.ListSpaceManagersReturns([]cfclient.User{{Username: "alice"}}, nil)
+	source.ListSpaceDevelopersReturns([]cfclient.User{{Username: "bob"}, {Guid: "some-client"}}, nil)
+	source.GetIsolationSegmentByGUIDReturns(&cfclient.IsolationSegment{GUID: "segment-guid", Name: "dmz"}, nil)
+
+	e := migrate.NewServiceInstanceExporter(&config.Config{}, &fakes.FakeClientHolder{
+		SourceCFClientStub: func() cf.Client {
+			return source
+		},
+	}, new(fakes.FakeMigratorRegistry), new(fakes.FakeServiceInstanceParser))
+
+	org := cfclient.Org{Guid: "org-guid", Name: "cloudfoundry", QuotaDefinitionGuid: "large-guid"}
+	require.NoError(t, e.ExportOrgSpace(context.TODO(), org, cfclient.Space{Guid: "space-1", Name: "test-app", IsolationSegmentGuid: "segment-guid"}, dir))
+	require.NoError(t, e.ExportOrgSpace(context.TODO(), org, cfclient.Space{Guid: "space-2", Name: "other-space"}, dir))
+}
+
+func TestDefaultServiceInstanceExporter_ExportOrgSpace(t *testing.T) {
+	dir := t.TempDir()
+	exportTestOrgSpace(t, dir)
+
+	b, err := os.ReadFile(filepath.Join(dir, migrate.OrgSpacesFile))
+	require.NoError(t, err)
+
+	var orgs map[string]migrate.OrgSettings
+	require.NoError(t, json.Unmarshal(b, &orgs))
+	require.Equal(t, map[string]migrate.OrgSettings{
+		"cloudfoundry": {
+			Quota: "large",
+			Spaces: map[string]migrate.SpaceSettings{
+				"test-app": {
+					IsolationSegment: "dmz",
+					Managers:         []string{"alice"},
+					Developers:       []string{"bob"},
+				},
+				"other-space": {
+					Managers:   []string{"alice"},
+					Developers: []string{"bob"},
+				},
+			},
+		},
+	}, orgs)
+}
+
+func TestDefaultServiceInstanceExporter_ExportOrgSpaceWithoutPermissions(t *testing.T) {
+	dir := t.TempDir()
+	forbidden := cfclient.CloudFoundryHTTPError{StatusCode: 403, Status: "403 Forbidden"}
+	source := new(cffakes.FakeClient)
+	source.ListOrgQuotasReturns(nil, forbidden)
+	source.ListSpaceManagersReturns(nil, forbidden)
+	source.ListSpaceDevelopersReturns([]cfclient.User{{Username: "bob"}}, nil)
+	source.ListSpaceAuditorsReturns(nil, forbidden)
+	source.GetIsolationSegmentByGUIDReturns(nil, forbidden)
+
+	e := migrate.NewServiceInstanceExporter(&config.Config{}, &fakes.FakeClientHolder{
+		SourceCFClientStub: func() cf.Client {
+			return source
+		},
+	}, new(fakes.FakeMigratorRegistry), new(fakes.FakeServiceInstanceParser))
+
+	org := cfclient.Org{Guid: "org-guid", Name: "cloudfoundry", QuotaDefinitionGuid: "large-guid"}
+	require.NoError(t, e.ExportOrgSpace(context.TODO(), org, cfclient.Space{Guid: "space-1", Name: "test-app", IsolationSegmentGuid: "segment-guid"}, dir))
+
+	b, err := os.ReadFile(filepath.Join(dir, migrate.OrgSpacesFile))
+	require.NoError(t, err)
+	var orgs map[string]migrate.OrgSettings
+	require.NoError(t, json.Unmarshal(b, &orgs))
+	require.Equal(t, map[string]migrate.OrgSettings{
+		"cloudfoundry": {
+			Spaces: map[string]migrate.SpaceSettings{
+				"test-app": {Developers: []string{"bob"}},
+			},
+		},
+	}, orgs)
+}
+
+func TestDefaultServiceInstanceExporter_ExportOrgSpaceEncrypted(t *testing.T) {
+	t.Setenv(encryption.PassphraseEnvVar, "correct horse")
+	dir := t.TempDir()
+	ctx := config.ContextWithConfig(context.TODO(), &config.Config{Encrypt: true})
+	source := new(cffakes.FakeClient)
+	source.ListSpaceManagersReturns([]cfclient.User{{Username: "alice"}}, nil)
+
+	e := migrate.NewServiceInstanceExporter(&config.Config{}, &fakes.FakeClientHolder{
+		SourceCFClientStub: func() cf.Client {
+			return source
+		},
+	}, new(fakes.FakeMigratorRegistry), new(fakes.FakeServiceInstanceParser))
+
+	require.NoError(t, e.ExportOrgSpace(ctx, cfclient.Org{Name: "cloudfoundry"}, cfclient.Space{Guid: "space-1", Name: "test-app"}, dir))
+
+	b, err := os.ReadFile(filepath.Join(dir, migrate.OrgSpacesFile))
+	require.NoError(t, err)
+	require.True(t, encryption.IsEncrypted(b))
+	require.NotContains(t, string(b), "alice")
+
+	b, err = encryption.FromContext(ctx).ReadFile(filepath.Join(dir, migrate.OrgSpacesFile))
+	require.NoError(t, err)
+	require.Contains(t, string(b), "alice")
+}
+
+func TestSpaceImporter_ImportCreatesMissingOrgSpace(t *testing.T) {
+	dir := t.TempDir()
+	exportTestOrgSpace(t, dir)
+
+	pwd, _ := os.Getwd()
+	instance, err := os.ReadFile(filepath.Join(pwd, "testdata", "cloudfoundry", "test-app", "sql-test.yml"))
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "cloudfoundry", "test-app"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cloudfoundry", "test-app", "sql-test.yml"), instance, 0644))
+
+	tests := []struct {
+		name       string
+		beforeFunc func(target *cffakes.FakeClient)
+		afterFunc  func(t *testing.T, target *cffakes.FakeClient, importer *fakes.FakeServiceInstanceImporter)
+		wantErr    bool
+	}{
+		{
+			name: "creates the org and space with the exported settings",
+			beforeFunc: func(target *cffakes.FakeClient) {
+				target.GetOrgByNameReturns(cfclient.Org{}, cfclient.NewOrganizationNotFoundError())
+				target.GetOrgQuotaByNameReturns(cfclient.OrgQuota{Guid: "target-large-guid", Name: "large"}, nil)
+				target.CreateOrgReturns(cfclient.Org{Guid: "target-org-guid", Name: "cloudfoundry"}, nil)
+				target.GetSpaceByNameReturns(cfclient.Space{}, cfclient.NewSpaceNotFoundError())
+				target.CreateSpaceReturns(cfclient.Space{Guid: "target-space-guid", Name: "test-app"}, nil)
+				target.ListIsolationSegmentsByQueryReturns([]cfclient.IsolationSegment{{GUID: "target-segment-guid", Name: "dmz"}}, nil)
+			},
+			afterFunc: func(t *testing.T, target *cffakes.FakeClient, importer *fakes.FakeServiceInstanceImporter) {
+				require.Equal(t, cfclient.OrgRequest{Name: "cloudfoundry", QuotaDefinitionGuid: "target-large-guid"}, target.CreateOrgArgsForCall(0))
+				require.Equal(t, cfclient.SpaceRequest{Name: "test-app", OrganizationGuid: "target-org-guid"}, target.CreateSpaceArgsForCall(0))
+
+				require.Equal(t, 2, target.AssociateOrgUserByUsernameCallCount())
+				spaceGUID, username := target.AssociateSpaceManagerByUsernameArgsForCall(0)
+				require.Equal(t, "target-space-guid", spaceGUID)
+				require.Equal(t, "alice", username)
+				_, username = target.AssociateSpaceDeveloperByUsernameArgsForCall(0)
+				require.Equal(t, "bob", username)
+
+				segmentGUID, orgGUID := target.AddIsolationSegmentToOrgArgsForCall(0)
+				require.Equal(t, "target-segment-guid", segmentGUID)
+				require.Equal(t, "target-org-guid", orgGUID)
+				spaceGUID, segmentGUID = target.IsolationSegmentForSpaceArgsForCall(0)
+				require.Equal(t, "target-space-guid", spaceGUID)
+				require.Equal(t, "target-segment-guid", segmentGUID)
+
+				require.Equal(t, 1, importer.ImportManagedServiceCallCount())
+			},
+		},
+		{
+			name: "leaves existing orgs and spaces alone",
+			beforeFunc: func(target *cffakes.FakeClient) {
+				target.GetOrgByNameReturns(cfclient.Org{Guid: "target-org-guid", Name: "cloudfoundry"}, nil)
+				target.GetSpaceByNameReturns(cfclient.Space{Guid: "target-space-guid", Name: "test-app"}, nil)
+			},
+			afterFunc: func(t *testing.T, target *cffakes.FakeClient, importer *fakes.FakeServiceInstanceImporter) {
+				require.Equal(t, 0, target.CreateOrgCallCount())
+				require.Equal(t, 0, target.CreateSpaceCallCount())
+				require.Equal(t, 0, target.AssociateOrgUserByUsernameCallCount())
+				require.Equal(t, 1, importer.ImportManagedServiceCallCount())
+			},
+		},
+		{
+			name: "doesn't import into a space that could not be created",
+			beforeFunc: func(target *cffakes.FakeClient) {
+				target.GetOrgByNameReturns(cfclient.Org{Guid: "target-org-guid", Name: "cloudfoundry"}, nil)
+				target.GetSpaceByNameReturns(cfclient.Space{}, cfclient.NewSpaceNotFoundError())
+				target.CreateSpaceReturns(cfclient.Space{}, cfclient.NewSpaceNameTakenError())
+			},
+			afterFunc: func(t *testing.T, target *cffakes.FakeClient, importer *fakes.FakeServiceInstanceImporter) {
+				require.Equal(t, 1, target.CreateSpaceCallCount())
+				require.Equal(t, 0, importer.ImportManagedServiceCallCount())
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := new(cffakes.FakeClient)
+			tt.beforeFunc(target)
+			importer := new(fakes.FakeServiceInstanceImporter)
+			i := migrate.NewSpaceImporter(importer, &fakes.FakeClientHolder{
+				TargetCFClientStub: func() cf.Client {
+					return target
+				},
+			})
+
+			ctx := config.ContextWithConfig(context.TODO(), &config.Config{CreateMissingOrgsSpaces: true})
+			err := i.Import(ctx, config.OpsManager{}, dir, "cloudfoundry", "test-app")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Import() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			tt.afterFunc(t, target, importer)
+		})
+	}
+}
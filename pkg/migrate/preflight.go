@@ -0,0 +1,447 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cloudfoundry-community/go-cfclient"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/script"
+)
+
+const (
+	ServiceOfferingCheck = "service offering"
+	ServicePlanCheck     = "plan"
+	OrgCheck             = "org"
+	SpaceCheck           = "space"
+	BoundAppsCheck       = "bound apps"
+	RouteDomainsCheck    = "route domains"
+	MigratorConfigCheck  = "migrator config"
+)
+
+// PreflightCheck is the outcome of a single check made against the target foundation
+type PreflightCheck struct {
+	Name    string
+	Passed  bool
+	Message string
+}
+
+// PreflightResult holds the checks made for a single exported service instance
+type PreflightResult struct {
+	Org     string
+	Space   string
+	Name    string
+	Service string
+	Plan    string
+	Checks  []PreflightCheck
+}
+
+// Failed returns true if any check of the service instance failed
+func (r PreflightResult) Failed() bool {
+	for _, c := range r.Checks {
+		if !c.Passed {
+			return true
+		}
+	}
+	return false
+}
+
+// PreflightReport holds the preflight results of every exported service instance
+type PreflightReport []PreflightResult
+
+// Failed returns true if the import of any service instance would fail
+func (r PreflightReport) Failed() bool {
+	for _, res := range r {
+		if res.Failed() {
+			return true
+		}
+	}
+	return false
+}
+
+// Display writes the report as a table, one row per check
+func (r PreflightReport) Display(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 10, 2, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "Org\tSpace\tName\tService\tCheck\tResult")
+	for _, res := range r {
+		for _, c := range res.Checks {
+			result := "ok"
+			if !c.Passed {
+				result = "failed"
+			}
+			if c.Message != "" {
+				result += ": " + c.Message
+			}
+			row := []string{
+				res.Org,
+				res.Space,
+				res.Name,
+				res.Service,
+				c.Name,
+				result,
+			}
+			_, _ = fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+	}
+	_ = tw.Flush()
+}
+
+// Preflight checks that the service instances in an export directory can be imported into the target foundation
+type Preflight struct {
+	ClientHolder   ClientHolder
+	MigratorHelper *MigratorHelper
+}
+
+func NewPreflight(h ClientHolder, mh *MigratorHelper) *Preflight {
+	return &Preflight{
+		ClientHolder:   h,
+		MigratorHelper: mh,
+	}
+}
+
+// targetFoundation caches the marketplace and domains of the target foundation across service instances
+type targetFoundation struct {
+	client   cf.Client
+	services []cfclient.Service
+	plans    []cfclient.ServicePlan
+	brokers  []cfclient.ServiceBroker
+	domains  []string
+}
+
+func (p *Preflight) Check(ctx context.Context, dir string) (PreflightReport, error) {
+	instances, err := readServiceInstances(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, ok := config.FromContext(ctx)
+	if !ok {
+		cfg = &config.Config{}
+	}
+
+	migration := &config.Migration{}
+	if p.MigratorHelper.GetReader() != nil {
+		if migration, err = p.MigratorHelper.GetReader().GetMigration(); err != nil {
+			return nil, err
+		}
+	}
+
+	target, err := p.loadTargetFoundation()
+	if err != nil {
+		return nil, err
+	}
+
+	var report PreflightReport
+	for org, spaces := range instances {
+		for space, sis := range spaces {
+			for _, si := range sis {
+				if si.Name == "" || si.Type == "" {
+					continue
+				}
+				applyMappings(ctx, org, si)
+				if !selected(cfg, p.MigratorHelper, si) {
+					continue
+				}
+				report = append(report, target.check(org, space, si, cfg.DomainsToReplace, p.migratorConfigCheck(*migration, si)))
+			}
+		}
+	}
+
+	sort.SliceStable(report, func(i, j int) bool {
+		if report[i].Org != report[j].Org {
+			return report[i].Org < report[j].Org
+		}
+		if report[i].Space != report[j].Space {
+			return report[i].Space < report[j].Space
+		}
+		return report[i].Name < report[j].Name
+	})
+
+	return report, nil
+}
+
+func (p *Preflight) loadTargetFoundation() (*targetFoundation, error) {
+	client := p.ClientHolder.TargetCFClient()
+
+	services, err := client.ListServices()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services on the target foundation: %w", err)
+	}
+
+	plans, err := client.ListServicePlans()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service plans on the target foundation: %w", err)
+	}
+
+	brokers, err := client.ListServiceBrokers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service brokers on the target foundation: %w", err)
+	}
+
+	privateDomains, err := client.ListDomains()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list domains on the target foundation: %w", err)
+	}
+
+	sharedDomains, err := client.ListSharedDomains()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shared domains on the target foundation: %w", err)
+	}
+
+	domains := make([]string, 0, len(privateDomains)+len(sharedDomains))
+	for _, d := range privateDomains {
+		domains = append(domains, d.Name)
+	}
+	for _, d := range sharedDomains {
+		domains = append(domains, d.Name)
+	}
+
+	return &targetFoundation{
+		client:   client,
+		services: services,
+		plans:    plans,
+		brokers:  brokers,
+		domains:  domains,
+	}, nil
+}
+
+// selected returns true if the service instance matches the instances and services to migrate
+func selected(cfg *config.Config, mh *MigratorHelper, si *cf.ServiceInstance) bool {
+	if len(cfg.Instances) > 0 && !containsString(cfg.Instances, si.Name) {
+		return false
+	}
+
+	if len(cfg.Services) == 0 {
+		return true
+	}
+
+	for _, s := range cfg.Services {
+		if strings.ToLower(s) == si.Service {
+			return true
+		}
+		if migrator, ok := mh.GetMigratorType(si.Service); ok && migrator.String() == strings.ToLower(s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Preflight) migratorConfigCheck(migration config.Migration, si *cf.ServiceInstance) *PreflightCheck {
+	if ServiceType(si.Type) != ManagedService {
+		return nil
+	}
+
+	migrator, ok := p.MigratorHelper.GetMigratorType(si.Service)
+	if !ok {
+		if migration.UseDefaultMigrator {
+			return &PreflightCheck{Name: MigratorConfigCheck, Passed: true}
+		}
+		return &PreflightCheck{
+			Name:    MigratorConfigCheck,
+			Message: fmt.Sprintf("no migrator supports service %q and use_default_migrator is not enabled", si.Service),
+		}
+	}
+
+	if migrator == Script {
+		cfg, err := script.LookupConfig(migration, si.Service)
+		if err != nil {
+			return &PreflightCheck{Name: MigratorConfigCheck, Message: err.Error()}
+		}
+		if cfg.Import == "" {
+			return &PreflightCheck{
+				Name:    MigratorConfigCheck,
+				Message: fmt.Sprintf("script migrator for service %q has no import command", si.Service),
+			}
+		}
+	}
+
+	return &PreflightCheck{Name: MigratorConfigCheck, Passed: true}
+}
+
+func (t *targetFoundation) check(org, space string, si *cf.ServiceInstance, domainsToReplace map[string]string, migratorCheck *PreflightCheck) PreflightResult {
+	res := PreflightResult{
+		Org:     org,
+		Space:   space,
+		Name:    si.Name,
+		Service: si.ServiceToCreate(),
+		Plan:    si.PlanToCreate(),
+	}
+
+	if ServiceType(si.Type) == ManagedService {
+		service, check := t.checkService(si)
+		res.Checks = append(res.Checks, check, t.checkPlan(si, service))
+	}
+
+	targetOrg, orgCheck := t.checkOrg(org)
+	res.Checks = append(res.Checks, orgCheck)
+
+	targetSpace, spaceCheck := t.checkSpace(space, targetOrg)
+	res.Checks = append(res.Checks, spaceCheck)
+
+	res.Checks = append(res.Checks, t.checkApps(si, targetOrg, targetSpace), t.checkRouteDomains(si, domainsToReplace))
+
+	if migratorCheck != nil {
+		res.Checks = append(res.Checks, *migratorCheck)
+	}
+
+	return res
+}
+
+func (t *targetFoundation) checkService(si *cf.ServiceInstance) (*cfclient.Service, PreflightCheck) {
+	for i, s := range t.services {
+		if s.Label != si.ServiceToCreate() {
+			continue
+		}
+		for _, b := range t.brokers {
+			if b.Guid == s.ServiceBrokerGuid {
+				return &t.services[i], PreflightCheck{Name: ServiceOfferingCheck, Passed: true, Message: fmt.Sprintf("provided by broker %q", b.Name)}
+			}
+		}
+		return &t.services[i], PreflightCheck{Name: ServiceOfferingCheck, Passed: true}
+	}
+
+	return nil, PreflightCheck{Name: ServiceOfferingCheck, Message: fmt.Sprintf("service %q not found", si.ServiceToCreate())}
+}
+
+func (t *targetFoundation) checkPlan(si *cf.ServiceInstance, service *cfclient.Service) PreflightCheck {
+	if service == nil {
+		return PreflightCheck{Name: ServicePlanCheck, Message: fmt.Sprintf("plan %q not found, service %q is missing", si.PlanToCreate(), si.ServiceToCreate())}
+	}
+
+	for _, p := range t.plans {
+		if p.Name == si.PlanToCreate() && p.ServiceGuid == service.Guid {
+			return PreflightCheck{Name: ServicePlanCheck, Passed: true}
+		}
+	}
+
+	return PreflightCheck{Name: ServicePlanCheck, Message: fmt.Sprintf("plan %q not found for service %q", si.PlanToCreate(), si.ServiceToCreate())}
+}
+
+func (t *targetFoundation) checkOrg(org string) (*cfclient.Org, PreflightCheck) {
+	o, err := t.client.GetOrgByName(org)
+	if err != nil || o.Guid == "" {
+		return nil, PreflightCheck{Name: OrgCheck, Message: fmt.Sprintf("org %q not found", org)}
+	}
+	return &o, PreflightCheck{Name: OrgCheck, Passed: true}
+}
+
+func (t *targetFoundation) checkSpace(space string, org *cfclient.Org) (*cfclient.Space, PreflightCheck) {
+	if org == nil {
+		return nil, PreflightCheck{Name: SpaceCheck, Message: fmt.Sprintf("space %q not found, org is missing", space)}
+	}
+
+	s, err := t.client.GetSpaceByName(space, org.Guid)
+	if err != nil || s.Guid == "" {
+		return nil, PreflightCheck{Name: SpaceCheck, Message: fmt.Sprintf("space %q not found in org %q", space, org.Name)}
+	}
+	return &s, PreflightCheck{Name: SpaceCheck, Passed: true}
+}
+
+func (t *targetFoundation) checkApps(si *cf.ServiceInstance, org *cfclient.Org, space *cfclient.Space) PreflightCheck {
+	apps := boundApps(si)
+	if len(apps) == 0 {
+		return PreflightCheck{Name: BoundAppsCheck, Passed: true}
+	}
+
+	if org == nil || space == nil {
+		return PreflightCheck{Name: BoundAppsCheck, Message: fmt.Sprintf("apps %s not found, org or space is missing", strings.Join(apps, ", "))}
+	}
+
+	var missing []string
+	for _, name := range apps {
+		if a, err := t.client.AppByName(name, space.Guid, org.Guid); err != nil || a.Guid == "" {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return PreflightCheck{Name: BoundAppsCheck, Message: fmt.Sprintf("apps %s not found", strings.Join(missing, ", "))}
+	}
+	return PreflightCheck{Name: BoundAppsCheck, Passed: true}
+}
+
+func (t *targetFoundation) checkRouteDomains(si *cf.ServiceInstance, domainsToReplace map[string]string) PreflightCheck {
+	var missing []string
+	for _, app := range si.AppManifest.Applications {
+		for _, r := range app.Routes {
+			if r.Route == "" {
+				continue
+			}
+			route := ReplaceDomain(r.Route, domainsToReplace)
+			if !t.hasDomain(routeHost(route)) && !containsString(missing, route) {
+				missing = append(missing, route)
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		return PreflightCheck{Name: RouteDomainsCheck, Message: fmt.Sprintf("no domain found for routes %s", strings.Join(missing, ", "))}
+	}
+	return PreflightCheck{Name: RouteDomainsCheck, Passed: true}
+}
+
+func (t *targetFoundation) hasDomain(host string) bool {
+	for _, d := range t.domains {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// boundApps returns the sorted names of the apps bound to the service instance or pushed from its app manifest
+func boundApps(si *cf.ServiceInstance) []string {
+	var apps []string
+	for _, name := range si.Apps {
+		if !containsString(apps, name) {
+			apps = append(apps, name)
+		}
+	}
+	for _, app := range si.AppManifest.Applications {
+		if app.Name != "" && !containsString(apps, app.Name) {
+			apps = append(apps, app.Name)
+		}
+	}
+	sort.Strings(apps)
+	return apps
+}
+
+// routeHost strips the path and port from a manifest route
+func routeHost(route string) string {
+	if i := strings.Index(route, "/"); i >= 0 {
+		route = route[:i]
+	}
+	if i := strings.Index(route, ":"); i >= 0 {
+		route = route[:i]
+	}
+	return route
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
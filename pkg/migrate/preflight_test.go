@@ -0,0 +1,301 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package migrate_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry-community/go-cfclient"
+	"github.com/stretchr/testify/require"
+	cffakes "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf/fakes"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	configfakes "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config/fakes"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/fakes"
+)
+
+const preflightInstance = `name: %s
+type: managed_service_instance
+service: %s
+plan: %s
+apps:
+  binding-guid: my-app
+app_manifest:
+  applications:
+  - name: my-app
+    routes:
+    - route: my-app.apps.cf1.example.com
+`
+
+func writeInstance(t *testing.T, dir, org, space, file, content string) {
+	t.Helper()
+	path := filepath.Join(dir, org, space)
+	require.NoError(t, os.MkdirAll(path, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(path, file), []byte(content), 0644))
+}
+
+func newTargetCFClient() *cffakes.FakeClient {
+	client := new(cffakes.FakeClient)
+	client.ListServicesReturns([]cfclient.Service{{Guid: "mysql-guid", Label: "p.mysql", ServiceBrokerGuid: "broker-guid"}}, nil)
+	client.ListServicePlansReturns([]cfclient.ServicePlan{{Name: "db-small", ServiceGuid: "mysql-guid"}}, nil)
+	client.ListServiceBrokersReturns([]cfclient.ServiceBroker{{Guid: "broker-guid", Name: "dedicated-mysql-broker"}}, nil)
+	client.ListSharedDomainsReturns([]cfclient.SharedDomain{{Name: "apps.cf2.example.com"}}, nil)
+	client.GetOrgByNameReturns(cfclient.Org{Guid: "org-guid", Name: "org1"}, nil)
+	client.GetSpaceByNameReturns(cfclient.Space{Guid: "space-guid", Name: "space1"}, nil)
+	client.AppByNameReturns(cfclient.App{Guid: "app-guid", Name: "my-app"}, nil)
+	return client
+}
+
+func checkResults(res migrate.PreflightResult) map[string]bool {
+	checks := make(map[string]bool)
+	for _, c := range res.Checks {
+		checks[c.Name] = c.Passed
+	}
+	return checks
+}
+
+func TestPreflight_Check(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(t *testing.T, dir string, client *cffakes.FakeClient)
+		cfg        *config.Config
+		migration  *config.Migration
+		wantErr    bool
+		wantFailed bool
+		afterFunc  func(t *testing.T, report migrate.PreflightReport)
+	}{
+		{
+			name: "passes when everything exists on the target",
+			setup: func(t *testing.T, dir string, client *cffakes.FakeClient) {
+				writeInstance(t, dir, "org1", "space1", "db.yml", fmt.Sprintf(preflightInstance, "db", "p.mysql", "db-small"))
+			},
+			cfg: &config.Config{DomainsToReplace: map[string]string{"apps.cf1.example.com": "apps.cf2.example.com"}},
+			afterFunc: func(t *testing.T, report migrate.PreflightReport) {
+				require.Len(t, report, 1)
+				require.Equal(t, "org1", report[0].Org)
+				require.Equal(t, "space1", report[0].Space)
+				require.Equal(t, "db", report[0].Name)
+				require.Equal(t, map[string]bool{
+					migrate.ServiceOfferingCheck: true,
+					migrate.ServicePlanCheck:     true,
+					migrate.OrgCheck:             true,
+					migrate.SpaceCheck:           true,
+					migrate.BoundAppsCheck:       true,
+					migrate.RouteDomainsCheck:    true,
+					migrate.MigratorConfigCheck:  true,
+				}, checkResults(report[0]))
+			},
+		},
+		{
+			name: "checks the service and plan the instance is mapped to",
+			setup: func(t *testing.T, dir string, client *cffakes.FakeClient) {
+				writeInstance(t, dir, "org1", "space1", "db.yml", fmt.Sprintf(preflightInstance, "db", "p-mysql", "100mb"))
+			},
+			cfg: &config.Config{
+				DomainsToReplace: map[string]string{"apps.cf1.example.com": "apps.cf2.example.com"},
+				Mappings:         []config.Mapping{{Service: "p-mysql", TargetService: "p.mysql", TargetPlan: "db-small"}},
+				Services:         []string{"p-mysql"},
+			},
+			migration: &config.Migration{UseDefaultMigrator: true},
+			afterFunc: func(t *testing.T, report migrate.PreflightReport) {
+				require.Len(t, report, 1)
+				require.Equal(t, "p.mysql", report[0].Service)
+				require.Equal(t, "db-small", report[0].Plan)
+				checks := checkResults(report[0])
+				require.True(t, checks[migrate.ServiceOfferingCheck])
+				require.True(t, checks[migrate.ServicePlanCheck])
+			},
+		},
+		{
+			name: "fails when the plan is missing",
+			setup: func(t *testing.T, dir string, client *cffakes.FakeClient) {
+				writeInstance(t, dir, "org1", "space1", "db.yml", fmt.Sprintf(preflightInstance, "db", "p.mysql", "db-large"))
+			},
+			cfg:        &config.Config{DomainsToReplace: map[string]string{"apps.cf1.example.com": "apps.cf2.example.com"}},
+			wantFailed: true,
+			afterFunc: func(t *testing.T, report migrate.PreflightReport) {
+				checks := checkResults(report[0])
+				require.True(t, checks[migrate.ServiceOfferingCheck])
+				require.False(t, checks[migrate.ServicePlanCheck])
+			},
+		},
+		{
+			name: "fails when the service offering is missing",
+			setup: func(t *testing.T, dir string, client *cffakes.FakeClient) {
+				writeInstance(t, dir, "org1", "space1", "db.yml", fmt.Sprintf(preflightInstance, "db", "p.redis", "cache-small"))
+			},
+			cfg:        &config.Config{DomainsToReplace: map[string]string{"apps.cf1.example.com": "apps.cf2.example.com"}},
+			wantFailed: true,
+			afterFunc: func(t *testing.T, report migrate.PreflightReport) {
+				checks := checkResults(report[0])
+				require.False(t, checks[migrate.ServiceOfferingCheck])
+				require.False(t, checks[migrate.ServicePlanCheck])
+			},
+		},
+		{
+			name: "fails when the org, space and apps are missing",
+			setup: func(t *testing.T, dir string, client *cffakes.FakeClient) {
+				writeInstance(t, dir, "org1", "space1", "db.yml", fmt.Sprintf(preflightInstance, "db", "p.mysql", "db-small"))
+				client.GetOrgByNameReturns(cfclient.Org{}, errors.New("org not found"))
+			},
+			cfg:        &config.Config{DomainsToReplace: map[string]string{"apps.cf1.example.com": "apps.cf2.example.com"}},
+			wantFailed: true,
+			afterFunc: func(t *testing.T, report migrate.PreflightReport) {
+				checks := checkResults(report[0])
+				require.False(t, checks[migrate.OrgCheck])
+				require.False(t, checks[migrate.SpaceCheck])
+				require.False(t, checks[migrate.BoundAppsCheck])
+			},
+		},
+		{
+			name: "fails when the route domains are not replaced",
+			setup: func(t *testing.T, dir string, client *cffakes.FakeClient) {
+				writeInstance(t, dir, "org1", "space1", "db.yml", fmt.Sprintf(preflightInstance, "db", "p.mysql", "db-small"))
+			},
+			cfg:        &config.Config{},
+			wantFailed: true,
+			afterFunc: func(t *testing.T, report migrate.PreflightReport) {
+				checks := checkResults(report[0])
+				require.False(t, checks[migrate.RouteDomainsCheck])
+			},
+		},
+		{
+			name: "fails when no migrator supports the service",
+			setup: func(t *testing.T, dir string, client *cffakes.FakeClient) {
+				writeInstance(t, dir, "org1", "space1", "db.yml", fmt.Sprintf(preflightInstance, "db", "custom-mysql", "db-small"))
+				client.ListServicesReturns([]cfclient.Service{{Guid: "mysql-guid", Label: "custom-mysql", ServiceBrokerGuid: "broker-guid"}}, nil)
+			},
+			cfg:        &config.Config{DomainsToReplace: map[string]string{"apps.cf1.example.com": "apps.cf2.example.com"}},
+			wantFailed: true,
+			afterFunc: func(t *testing.T, report migrate.PreflightReport) {
+				checks := checkResults(report[0])
+				require.True(t, checks[migrate.ServiceOfferingCheck])
+				require.False(t, checks[migrate.MigratorConfigCheck])
+			},
+		},
+		{
+			name: "passes when the default migrator is enabled",
+			setup: func(t *testing.T, dir string, client *cffakes.FakeClient) {
+				writeInstance(t, dir, "org1", "space1", "db.yml", fmt.Sprintf(preflightInstance, "db", "custom-mysql", "db-small"))
+				client.ListServicesReturns([]cfclient.Service{{Guid: "mysql-guid", Label: "custom-mysql", ServiceBrokerGuid: "broker-guid"}}, nil)
+			},
+			cfg:       &config.Config{DomainsToReplace: map[string]string{"apps.cf1.example.com": "apps.cf2.example.com"}},
+			migration: &config.Migration{UseDefaultMigrator: true},
+		},
+		{
+			name: "fails when the script migrator has no import command",
+			setup: func(t *testing.T, dir string, client *cffakes.FakeClient) {
+				writeInstance(t, dir, "org1", "space1", "db.yml", fmt.Sprintf(preflightInstance, "db", "custom-mysql", "db-small"))
+				client.ListServicesReturns([]cfclient.Service{{Guid: "mysql-guid", Label: "custom-mysql", ServiceBrokerGuid: "broker-guid"}}, nil)
+			},
+			cfg: &config.Config{DomainsToReplace: map[string]string{"apps.cf1.example.com": "apps.cf2.example.com"}},
+			migration: &config.Migration{
+				Migrators: []config.Migrator{{
+					Name:          "script",
+					ServiceLabels: []string{"custom-mysql"},
+					Value:         map[string]interface{}{"export": "echo export"},
+				}},
+			},
+			wantFailed: true,
+			afterFunc: func(t *testing.T, report migrate.PreflightReport) {
+				checks := checkResults(report[0])
+				require.True(t, checks[migrate.ServiceOfferingCheck])
+				require.False(t, checks[migrate.MigratorConfigCheck])
+			},
+		},
+		{
+			name: "only checks the selected instances",
+			setup: func(t *testing.T, dir string, client *cffakes.FakeClient) {
+				writeInstance(t, dir, "org1", "space1", "db.yml", fmt.Sprintf(preflightInstance, "db", "p.mysql", "db-small"))
+				writeInstance(t, dir, "org1", "space1", "cache.yml", fmt.Sprintf(preflightInstance, "cache", "p.redis", "cache-small"))
+			},
+			cfg: &config.Config{
+				DomainsToReplace: map[string]string{"apps.cf1.example.com": "apps.cf2.example.com"},
+				Instances:        []string{"db"},
+			},
+			afterFunc: func(t *testing.T, report migrate.PreflightReport) {
+				require.Len(t, report, 1)
+				require.Equal(t, "db", report[0].Name)
+			},
+		},
+		{
+			name: "fails when the target services cannot be listed",
+			setup: func(t *testing.T, dir string, client *cffakes.FakeClient) {
+				client.ListServicesReturns(nil, errors.New("unauthorized"))
+			},
+			cfg:     &config.Config{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			client := newTargetCFClient()
+			tt.setup(t, dir, client)
+
+			holder := new(fakes.FakeClientHolder)
+			holder.TargetCFClientReturns(client)
+
+			migration := tt.migration
+			if migration == nil {
+				migration = &config.Migration{}
+			}
+			mr := new(configfakes.FakeMigrationReader)
+			mr.GetMigrationReturns(migration, nil)
+
+			p := migrate.NewPreflight(holder, migrate.NewMigratorHelper(mr))
+			report, err := p.Check(config.ContextWithConfig(context.TODO(), tt.cfg), dir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			require.Equal(t, tt.wantFailed, report.Failed())
+			if tt.afterFunc != nil {
+				tt.afterFunc(t, report)
+			}
+		})
+	}
+}
+
+func TestPreflightReport_Display(t *testing.T) {
+	report := migrate.PreflightReport{
+		{
+			Org:     "org1",
+			Space:   "space1",
+			Name:    "db",
+			Service: "p.mysql",
+			Checks: []migrate.PreflightCheck{
+				{Name: migrate.ServiceOfferingCheck, Passed: true},
+				{Name: migrate.ServicePlanCheck, Message: `plan "db-large" not found for service "p.mysql"`},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	report.Display(&buf)
+
+	out := buf.String()
+	require.Contains(t, out, "Check")
+	require.Regexp(t, `service offering\s+ok\n`, out)
+	require.Regexp(t, `plan\s+failed: plan "db-large" not found for service "p.mysql"\n`, out)
+}
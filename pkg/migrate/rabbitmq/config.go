@@ -0,0 +1,32 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package rabbitmq
+
+import (
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+)
+
+type Config struct {
+	SkipSSLValidation bool `yaml:"skip_ssl_validation"`
+}
+
+func decodeConfig(c interface{}) Config {
+	var conf Config
+	m, ok := c.(*config.Migration)
+	if !ok || m == nil {
+		return conf
+	}
+	return config.NewMapDecoder(conf).Decode(*m, "rabbitmq").(Config)
+}
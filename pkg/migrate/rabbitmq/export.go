@@ -0,0 +1,141 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/encryption"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/exec"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/flow"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
+)
+
+const (
+	serviceKeyName  = "si-migrator"
+	definitionsFile = "definitions.json"
+)
+
+type CredentialsExtractor func(s string) (Credentials, error)
+
+func NewExportSequence(api, org, space string, instance *cf.ServiceInstance, om config.OpsManager, executor exec.Executor, exportDir string) flow.Flow {
+	cfHome, err := os.MkdirTemp("", instance.GUID)
+	if err != nil {
+		panic("failed to create CF_HOME")
+	}
+
+	return flow.ProgressBarSequence(
+		fmt.Sprintf("Exporting %s", instance.Name),
+		flow.StepWithProgressBar(
+			cf.LoginSourceFoundation(executor, om, api, org, space, cfHome),
+			flow.WithDisplay("Logging into source foundation"),
+			flow.WithAlwaysRun(),
+		),
+		flow.StepWithProgressBar(
+			CreateServiceKey(executor, cfHome, *instance),
+			flow.WithDisplay("Creating service key"),
+			flow.WithAlwaysRun(),
+		),
+		flow.StepWithProgressBar(
+			ExportDefinitions(executor, cfHome, instance, credentialsExtractor, exportDir),
+			flow.WithDisplay("Exporting definitions"),
+			flow.WithAlwaysRun(),
+		),
+		flow.StepWithProgressBar(
+			DeleteServiceKey(executor, cfHome, *instance),
+			flow.WithDisplay("Deleting service key"),
+		),
+	)
+}
+
+func CreateServiceKey(e exec.Executor, cfHome string, instance cf.ServiceInstance) flow.StepFunc {
+	return func(ctx context.Context, c interface{}, dryRun bool) (flow.Result, error) {
+		log.Infof("Creating service key %q for %q", serviceKeyName, instance.Name)
+		res, err := e.Execute(ctx, strings.NewReader(fmt.Sprintf("CF_HOME='%s' cf create-service-key '%s' '%s'", cfHome, instance.Name, serviceKeyName)))
+		if err != nil {
+			return exec.Result{}, errors.Wrap(err, fmt.Sprintf("failed to create service key for %q", instance.Name))
+		}
+
+		return res, nil
+	}
+}
+
+func DeleteServiceKey(e exec.Executor, cfHome string, instance cf.ServiceInstance) flow.StepFunc {
+	return func(ctx context.Context, c interface{}, dryRun bool) (flow.Result, error) {
+		log.Infof("Deleting service key %q for %q", serviceKeyName, instance.Name)
+		res, err := e.Execute(ctx, strings.NewReader(fmt.Sprintf("CF_HOME='%s' cf delete-service-key '%s' '%s' -f", cfHome, instance.Name, serviceKeyName)))
+		if err != nil {
+			return exec.Result{}, errors.Wrap(err, fmt.Sprintf("failed to delete service key for %q", instance.Name))
+		}
+
+		return res, nil
+	}
+}
+
+func ExportDefinitions(e exec.Executor, cfHome string, instance *cf.ServiceInstance, credsExtractor CredentialsExtractor, exportDir string) flow.StepFunc {
+	return func(ctx context.Context, c interface{}, dryRun bool) (flow.Result, error) {
+		backupFile := filepath.Join(exportDir, instance.GUID, definitionsFile)
+		log.Infof("Exporting definitions to %q", backupFile)
+
+		res, err := getServiceKey(ctx, e, cfHome, *instance)
+		if err != nil {
+			return res, err
+		}
+		if dryRun || res.DryRun {
+			instance.BackupFile = backupFile
+			return res, nil
+		}
+
+		creds, err := credsExtractor(res.Output)
+		if err != nil {
+			return res, err
+		}
+
+		definitions, err := NewManagementClient(creds, decodeConfig(c).SkipSSLValidation).GetDefinitions(ctx)
+		if err != nil {
+			return res, err
+		}
+		definitions, err = removeUsers(definitions, serviceKeyName, creds.Username)
+		if err != nil {
+			return res, err
+		}
+
+		if err = os.MkdirAll(filepath.Dir(backupFile), 0700); err != nil {
+			return res, fmt.Errorf("failed to create directory for definitions: %w", err)
+		}
+		if err = encryption.FromContext(ctx).WriteFile(backupFile, definitions, 0600); err != nil {
+			return res, fmt.Errorf("failed to write definitions: %w", err)
+		}
+		instance.BackupFile = backupFile
+
+		return res, nil
+	}
+}
+
+func getServiceKey(ctx context.Context, e exec.Executor, cfHome string, instance cf.ServiceInstance) (exec.Result, error) {
+	res, err := e.Execute(ctx, strings.NewReader(fmt.Sprintf("CF_HOME='%s' cf service-key '%s' '%s'", cfHome, instance.Name, serviceKeyName)))
+	if err != nil {
+		return exec.Result{}, errors.Wrap(err, fmt.Sprintf("failed to get service key for %q", instance.Name))
+	}
+
+	return res, nil
+}
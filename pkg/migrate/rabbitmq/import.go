@@ -0,0 +1,77 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/encryption"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/exec"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/flow"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
+)
+
+func NewImportSequence(api, org, space string, instance *cf.ServiceInstance, om config.OpsManager, executor exec.Executor) flow.Flow {
+	cfHome, err := os.MkdirTemp("", instance.GUID)
+	if err != nil {
+		panic("failed to create CF_HOME")
+	}
+
+	log.Debugf("Creating import with CF_HOME='%s' for %s %s service running in %s/%s", cfHome, instance.Name, instance.Service, org, space)
+
+	return flow.ProgressBarSequence(
+		fmt.Sprintf("Importing %s", instance.Name),
+		flow.StepWithProgressBar(cf.LoginTargetFoundation(executor, om, api, org, space, cfHome), flow.WithDisplay("Logging into target foundation"), flow.WithAlwaysRun()),
+		flow.StepWithProgressBar(cf.CreateServiceInstance(executor, cfHome, *instance), flow.WithDisplay("Creating service instance")),
+		flow.StepWithProgressBar(cf.GetServiceInstance(executor, cfHome, instance, 15*time.Minute, 10*time.Second), flow.WithDisplay("Waiting for service instance to create")),
+		flow.StepWithProgressBar(CreateServiceKey(executor, cfHome, *instance), flow.WithDisplay("Creating service key"), flow.WithAlwaysRun()),
+		flow.StepWithProgressBar(ImportDefinitions(executor, cfHome, instance, credentialsExtractor), flow.WithDisplay("Importing definitions")),
+		flow.StepWithProgressBar(DeleteServiceKey(executor, cfHome, *instance), flow.WithDisplay("Deleting service key")),
+	)
+}
+
+func ImportDefinitions(e exec.Executor, cfHome string, instance *cf.ServiceInstance, credsExtractor CredentialsExtractor) flow.StepFunc {
+	return func(ctx context.Context, c interface{}, dryRun bool) (flow.Result, error) {
+		log.Infof("Importing definitions from %q", instance.BackupFile)
+		if _, err := os.Stat(instance.BackupFile); os.IsNotExist(err) {
+			return exec.Result{}, fmt.Errorf("failed to import definitions: %q, file does not exist", instance.BackupFile)
+		}
+
+		res, err := getServiceKey(ctx, e, cfHome, *instance)
+		if err != nil {
+			return res, err
+		}
+		if dryRun || res.DryRun {
+			return res, nil
+		}
+
+		creds, err := credsExtractor(res.Output)
+		if err != nil {
+			return res, err
+		}
+
+		definitions, err := encryption.FromContext(ctx).ReadFile(instance.BackupFile)
+		if err != nil {
+			return res, fmt.Errorf("failed to read definitions: %w", err)
+		}
+
+		return res, NewManagementClient(creds, decodeConfig(c).SkipSSLValidation).PostDefinitions(ctx, definitions)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package rabbitmq
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/exec"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/exec/fakes"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/flow"
+)
+
+func TestImportDefinitions(t *testing.T) {
+	var imported []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/api/definitions/target-vhost", r.URL.Path)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var err error
+		imported, err = io.ReadAll(r.Body)
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	backupFile := filepath.Join(t.TempDir(), "definitions.json")
+	require.NoError(t, os.WriteFile(backupFile, []byte(definitions), 0600))
+
+	serviceKey := func(ctx context.Context, r io.Reader) (exec.Result, error) {
+		return exec.Result{Output: `{"http_api_uri":"` + server.URL + `/api/","username":"some-user","password":"some-password","vhost":"target-vhost"}`}, nil
+	}
+
+	tests := []struct {
+		name       string
+		backupFile string
+		dryRun     bool
+		wantPost   bool
+		wantErr    bool
+	}{
+		{
+			name:       "imports definitions into the target instance",
+			backupFile: backupFile,
+			wantPost:   true,
+		},
+		{
+			name:       "does not call the management api on dry run",
+			backupFile: backupFile,
+			dryRun:     true,
+		},
+		{
+			name:       "fails when the definitions were not exported",
+			backupFile: filepath.Join(t.TempDir(), "definitions.json"),
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imported = nil
+			instance := &cf.ServiceInstance{Name: "my-rabbit", GUID: "some-guid", BackupFile: tt.backupFile}
+			step := ImportDefinitions(&fakes.FakeExecutor{ExecuteStub: serviceKey}, "/tmp/cf-home", instance, credentialsExtractor)
+
+			_, err := flow.Sequence(step).Run(context.TODO(), &config.Migration{}, tt.dryRun)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantPost {
+				require.JSONEq(t, definitions, string(imported))
+			} else {
+				require.Nil(t, imported)
+			}
+		})
+	}
+}
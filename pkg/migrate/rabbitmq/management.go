@@ -0,0 +1,207 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package rabbitmq
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Credentials are the management API credentials and the vhost read from a service key
+type Credentials struct {
+	URL      string
+	Username string
+	Password string
+	VHost    string
+}
+
+// ManagementClient exports and imports definitions using the RabbitMQ management HTTP API
+type ManagementClient struct {
+	credentials Credentials
+	client      *http.Client
+}
+
+func NewManagementClient(creds Credentials, skipSSLValidation bool) *ManagementClient {
+	return &ManagementClient{
+		credentials: creds,
+		client: &http.Client{
+			Timeout: 5 * time.Minute,
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: skipSSLValidation,
+				},
+			},
+		},
+	}
+}
+
+// GetDefinitions returns the policies, exchanges, queues and bindings of the vhost of the service instance
+func (c *ManagementClient) GetDefinitions(ctx context.Context) ([]byte, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get definitions: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read definitions: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get definitions, status: %q, body: %q", res.Status, string(b))
+	}
+
+	return b, nil
+}
+
+// PostDefinitions merges the given definitions into the vhost of the service instance
+func (c *ManagementClient) PostDefinitions(ctx context.Context, definitions []byte) error {
+	req, err := c.newRequest(ctx, http.MethodPost, bytes.NewReader(definitions))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to import definitions: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		b, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("failed to import definitions, status: %q, body: %q", res.Status, string(b))
+	}
+
+	return nil
+}
+
+func (c *ManagementClient) newRequest(ctx context.Context, method string, body io.Reader) (*http.Request, error) {
+	u := strings.TrimSuffix(c.credentials.URL, "/") + "/definitions/" + url.PathEscape(c.credentials.VHost)
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create management api request: %w", err)
+	}
+	req.SetBasicAuth(c.credentials.Username, c.credentials.Password)
+	req.Header.Set("Accept", "application/json")
+
+	return req, nil
+}
+
+// credentialsExtractor parses the output of 'cf service-key', which prints the credentials
+// as a json document after a header line. Newer cf cli versions nest them under 'credentials'.
+func credentialsExtractor(output string) (Credentials, error) {
+	i := strings.Index(output, "{")
+	if i < 0 {
+		return Credentials{}, fmt.Errorf("couldn't extract service key credentials, output: %q", output)
+	}
+
+	var key map[string]interface{}
+	if err := json.NewDecoder(strings.NewReader(output[i:])).Decode(&key); err != nil {
+		return Credentials{}, fmt.Errorf("couldn't parse service key credentials: %w", err)
+	}
+	if creds, ok := key["credentials"].(map[string]interface{}); ok {
+		key = creds
+	}
+
+	uri, _ := key["http_api_uri"].(string)
+	if uri == "" {
+		if protocols, ok := key["protocols"].(map[string]interface{}); ok {
+			if management, ok := protocols["management"].(map[string]interface{}); ok {
+				uri, _ = management["uri"].(string)
+			}
+		}
+	}
+	if uri == "" {
+		return Credentials{}, fmt.Errorf("service key does not contain a management api uri")
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to parse management api uri: %w", err)
+	}
+
+	creds := Credentials{}
+	creds.Username, _ = key["username"].(string)
+	creds.Password, _ = key["password"].(string)
+	creds.VHost, _ = key["vhost"].(string)
+	if creds.VHost == "" {
+		if protocols, ok := key["protocols"].(map[string]interface{}); ok {
+			if amqp, ok := protocols["amqp"].(map[string]interface{}); ok {
+				creds.VHost, _ = amqp["vhost"].(string)
+			}
+		}
+	}
+	if creds.VHost == "" {
+		return Credentials{}, fmt.Errorf("service key does not contain a vhost")
+	}
+	if u.User != nil {
+		creds.Username = u.User.Username()
+		if p, ok := u.User.Password(); ok {
+			creds.Password = p
+		}
+		u.User = nil
+	}
+	creds.URL = u.String()
+
+	return creds, nil
+}
+
+// removeUsers drops the given users and their permissions from the definitions, so the credentials of
+// the service keys are not carried over to the target instance
+func removeUsers(definitions []byte, users ...string) ([]byte, error) {
+	var defs map[string]interface{}
+	if err := json.Unmarshal(definitions, &defs); err != nil {
+		return nil, fmt.Errorf("failed to parse definitions: %w", err)
+	}
+
+	remove := make(map[string]bool, len(users))
+	for _, u := range users {
+		remove[u] = true
+	}
+	for field, key := range map[string]string{"users": "name", "permissions": "user", "topic_permissions": "user"} {
+		entries, ok := defs[field].([]interface{})
+		if !ok {
+			continue
+		}
+		kept := make([]interface{}, 0, len(entries))
+		for _, e := range entries {
+			if entry, ok := e.(map[string]interface{}); ok {
+				if name, _ := entry[key].(string); remove[name] {
+					continue
+				}
+			}
+			kept = append(kept, e)
+		}
+		defs[field] = kept
+	}
+
+	return json.Marshal(defs)
+}
@@ -0,0 +1,86 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package rabbitmq_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	configfakes "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config/fakes"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/flow"
+	flowfakes "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/flow/fakes"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/rabbitmq"
+)
+
+func TestMigrator_Migrate(t *testing.T) {
+	type fields struct {
+		flow            *flowfakes.FakeFlow
+		migrationReader *configfakes.FakeMigrationReader
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		ctx     context.Context
+		wantErr bool
+	}{
+		{
+			name: "runs migration flow",
+			fields: fields{
+				flow: &flowfakes.FakeFlow{
+					RunStub: func(ctx context.Context, i interface{}, b bool) (flow.Result, error) {
+						return &cf.ServiceInstance{Name: "some-instance"}, nil
+					},
+				},
+				migrationReader: &configfakes.FakeMigrationReader{
+					GetMigrationStub: func() (*config.Migration, error) {
+						return &config.Migration{}, nil
+					},
+				},
+			},
+			ctx: context.TODO(),
+		},
+		{
+			name: "returns flow errors",
+			fields: fields{
+				flow: &flowfakes.FakeFlow{
+					RunStub: func(ctx context.Context, i interface{}, b bool) (flow.Result, error) {
+						return nil, errors.New("failed to export definitions")
+					},
+				},
+				migrationReader: &configfakes.FakeMigrationReader{
+					GetMigrationStub: func() (*config.Migration, error) {
+						return &config.Migration{}, nil
+					},
+				},
+			},
+			ctx:     context.TODO(),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := rabbitmq.NewMigrator(tt.fields.flow, tt.fields.migrationReader)
+			if _, err := m.Migrate(tt.ctx); (err != nil) != tt.wantErr {
+				t.Errorf("Migrate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			require.Equal(t, 1, tt.fields.flow.RunCallCount())
+		})
+	}
+}
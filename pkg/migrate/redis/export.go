@@ -0,0 +1,134 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/bosh"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/encryption"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/exec"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/flow"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
+)
+
+const (
+	remoteRDBFile = "/tmp/si-migrator-dump.rdb"
+	rdbFile       = "dump.rdb"
+)
+
+func NewExportSequence(api, org, space string, instance *cf.ServiceInstance, om config.OpsManager, client bosh.Client, executor exec.Executor, exportDir string) flow.Flow {
+	cfHome, err := os.MkdirTemp("", instance.GUID)
+	if err != nil {
+		panic("failed to create CF_HOME")
+	}
+
+	return flow.ProgressBarSequence(
+		fmt.Sprintf("Exporting %s", instance.Name),
+		flow.StepWithProgressBar(
+			cf.LoginSourceFoundation(executor, om, api, org, space, cfHome),
+			flow.WithDisplay("Logging into source foundation"),
+			flow.WithAlwaysRun(),
+		),
+		flow.StepWithProgressBar(
+			CreateSnapshot(client, instance),
+			flow.WithDisplay("Creating snapshot"),
+		),
+		flow.StepWithProgressBar(
+			DownloadSnapshot(client, instance, exportDir),
+			flow.WithDisplay("Downloading snapshot"),
+			flow.WithAlwaysRun(),
+		),
+	)
+}
+
+func CreateSnapshot(client bosh.Client, instance *cf.ServiceInstance) flow.StepFunc {
+	return func(ctx context.Context, c interface{}, dryRun bool) (flow.Result, error) {
+		log.Infof("Creating snapshot of %q", instance.Name)
+		if dryRun {
+			return exec.Result{DryRun: true}, nil
+		}
+
+		l := layoutFor(instance.Service)
+		deployment, err := l.deployment(client, instance)
+		if err != nil {
+			return exec.Result{}, errors.Wrap(err, fmt.Sprintf("failed to create snapshot of %q", instance.Name))
+		}
+
+		out, err := client.RunCommand(deployment, l.instanceGroup, remoteScript(l, l.dataDir(instance), snapshotScript, remoteRDBFile))
+		if err != nil {
+			return exec.Result{Output: out}, errors.Wrap(err, fmt.Sprintf("failed to create snapshot of %q", instance.Name))
+		}
+
+		return exec.Result{Output: out}, nil
+	}
+}
+
+func DownloadSnapshot(client bosh.Client, instance *cf.ServiceInstance, exportDir string) flow.StepFunc {
+	return func(ctx context.Context, c interface{}, dryRun bool) (flow.Result, error) {
+		backupFile := filepath.Join(exportDir, instance.GUID, rdbFile)
+		log.Infof("Downloading snapshot to %q", backupFile)
+		if dryRun {
+			instance.BackupFile = backupFile
+			return exec.Result{DryRun: true}, nil
+		}
+
+		// the snapshot is removed from the vm once downloaded, so a resumed export keeps the one it downloaded before
+		if cfg, ok := config.FromContext(ctx); ok && cfg.Resume {
+			if _, err := os.Stat(backupFile); err == nil {
+				log.Debugf("Snapshot %q was downloaded by a previous run", backupFile)
+				instance.BackupFile = backupFile
+				return exec.Result{}, nil
+			}
+		}
+
+		l := layoutFor(instance.Service)
+		deployment, err := l.deployment(client, instance)
+		if err != nil {
+			return exec.Result{}, errors.Wrap(err, fmt.Sprintf("failed to download snapshot of %q", instance.Name))
+		}
+
+		if err := os.MkdirAll(filepath.Dir(backupFile), 0700); err != nil {
+			return exec.Result{}, fmt.Errorf("failed to create directory for snapshot: %w", err)
+		}
+
+		// download next to the snapshot and rename it when complete, so a partial download is never reused
+		partFile := backupFile + ".part"
+		defer func() { _ = os.Remove(partFile) }()
+		if err := client.DownloadFile(deployment, l.instanceGroup, remoteRDBFile, partFile); err != nil {
+			return exec.Result{}, errors.Wrap(err, fmt.Sprintf("failed to download snapshot of %q", instance.Name))
+		}
+		if err := encryption.FromContext(ctx).EncryptFile(partFile); err != nil {
+			return exec.Result{}, errors.Wrap(err, fmt.Sprintf("failed to encrypt snapshot of %q", instance.Name))
+		}
+		if err := os.Rename(partFile, backupFile); err != nil {
+			return exec.Result{}, fmt.Errorf("failed to write snapshot of %q: %w", instance.Name, err)
+		}
+		instance.BackupFile = backupFile
+
+		out, err := client.RunCommand(deployment, l.instanceGroup, fmt.Sprintf("sudo rm -f %s", remoteRDBFile))
+		if err != nil {
+			log.Warnf("Failed to remove %q from %q: %v", remoteRDBFile, deployment, err)
+		}
+
+		return exec.Result{Output: out}, nil
+	}
+}
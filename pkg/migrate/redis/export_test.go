@@ -0,0 +1,185 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package redis
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/cloudfoundry/bosh-cli/director"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/bosh/fakes"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/flow"
+)
+
+func decodeScript(t *testing.T, command string) string {
+	m := regexp.MustCompile(`^echo (\S+) \| base64 -d \| sudo bash$`).FindStringSubmatch(command)
+	require.Len(t, m, 2)
+	script, err := base64.StdEncoding.DecodeString(m[1])
+	require.NoError(t, err)
+	return string(script)
+}
+
+func TestCreateSnapshot(t *testing.T) {
+	tests := []struct {
+		name           string
+		service        string
+		wantDeployment string
+		wantVM         string
+		wantScript     []string
+	}{
+		{
+			name:           "snapshots the vm of an on-demand instance",
+			service:        "p.redis",
+			wantDeployment: "service-instance_some-guid",
+			wantVM:         "redis-instance/0",
+			wantScript: []string{
+				"data=/var/vcap/store/redis\n",
+				"conf=/var/vcap/jobs/redis/config/redis.conf\n",
+				`$cli "$(redis_cmd BGSAVE)"`,
+				`cp "$data/dump.rdb" /tmp/si-migrator-dump.rdb`,
+			},
+		},
+		{
+			name:           "snapshots the redis server of a shared-vm instance",
+			service:        "p-redis",
+			wantDeployment: "p-redis-0123456789",
+			wantVM:         "cf-redis-broker/0",
+			wantScript: []string{
+				"data=/var/vcap/store/cf-redis-broker/redis-data/some-guid\n",
+				`conf="$data/redis.conf"`,
+				`$cli "$(redis_cmd BGSAVE)"`,
+				`cp "$data/dump.rdb" /tmp/si-migrator-dump.rdb`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakes.FakeClient{}
+			client.FindDeploymentReturns(director.DeploymentResp{Name: "p-redis-0123456789"}, true, nil)
+			instance := &cf.ServiceInstance{Name: "my-redis", GUID: "some-guid", Service: tt.service}
+
+			_, err := flow.Sequence(CreateSnapshot(client, instance)).Run(context.TODO(), &config.Migration{}, false)
+			require.NoError(t, err)
+
+			require.Equal(t, 1, client.RunCommandCallCount())
+			deployment, vm, command := client.RunCommandArgsForCall(0)
+			require.Equal(t, tt.wantDeployment, deployment)
+			require.Equal(t, tt.wantVM, vm)
+			script := decodeScript(t, command)
+			for _, want := range tt.wantScript {
+				require.Contains(t, script, want)
+			}
+		})
+	}
+}
+
+func TestCreateSnapshot_FailsWithoutLegacyDeployment(t *testing.T) {
+	client := &fakes.FakeClient{}
+	instance := &cf.ServiceInstance{Name: "my-redis", GUID: "some-guid", Service: "p-redis"}
+
+	_, err := flow.Sequence(CreateSnapshot(client, instance)).Run(context.TODO(), &config.Migration{}, false)
+	require.Error(t, err)
+	require.Equal(t, 0, client.RunCommandCallCount())
+}
+
+func TestDownloadSnapshot(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		resume       bool
+		downloaded   bool
+		wantDownload bool
+		wantErr      bool
+	}{
+		{
+			name:         "downloads the snapshot into the export directory",
+			wantDownload: true,
+		},
+		{
+			name:         "fails when the snapshot can't be downloaded",
+			err:          io.ErrUnexpectedEOF,
+			wantDownload: true,
+			wantErr:      true,
+		},
+		{
+			name:       "keeps the snapshot downloaded before on resume",
+			resume:     true,
+			downloaded: true,
+		},
+		{
+			name:         "downloads the snapshot again when not resuming",
+			downloaded:   true,
+			wantDownload: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exportDir := t.TempDir()
+			backupFile := filepath.Join(exportDir, "some-guid", "dump.rdb")
+			if tt.downloaded {
+				require.NoError(t, os.MkdirAll(filepath.Dir(backupFile), 0700))
+				require.NoError(t, os.WriteFile(backupFile, []byte("REDIS0009 old"), 0600))
+			}
+			client := &fakes.FakeClient{}
+			client.DownloadFileStub = func(deployment, vm, src, dst string) error {
+				if tt.err != nil {
+					return tt.err
+				}
+				return os.WriteFile(dst, []byte("REDIS0009 new"), 0600)
+			}
+			instance := &cf.ServiceInstance{Name: "my-redis", GUID: "some-guid"}
+			ctx := config.ContextWithConfig(context.TODO(), &config.Config{Resume: tt.resume})
+
+			_, err := flow.Sequence(DownloadSnapshot(client, instance, exportDir)).Run(ctx, &config.Migration{}, false)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			require.NoFileExists(t, backupFile+".part")
+			if tt.wantErr {
+				require.Empty(t, instance.BackupFile)
+				require.NoFileExists(t, backupFile)
+				require.Equal(t, 0, client.RunCommandCallCount())
+				return
+			}
+
+			require.Equal(t, backupFile, instance.BackupFile)
+			if !tt.wantDownload {
+				require.Equal(t, 0, client.DownloadFileCallCount())
+				return
+			}
+
+			b, err := os.ReadFile(backupFile)
+			require.NoError(t, err)
+			require.Equal(t, "REDIS0009 new", string(b))
+
+			require.Equal(t, 1, client.DownloadFileCallCount())
+			deployment, vm, src, dst := client.DownloadFileArgsForCall(0)
+			require.Equal(t, []string{"service-instance_some-guid", "redis-instance/0", "/tmp/si-migrator-dump.rdb", backupFile + ".part"}, []string{deployment, vm, src, dst})
+
+			require.Equal(t, 1, client.RunCommandCallCount())
+			_, _, command := client.RunCommandArgsForCall(0)
+			require.Equal(t, "sudo rm -f /tmp/si-migrator-dump.rdb", command)
+		})
+	}
+}
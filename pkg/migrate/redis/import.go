@@ -0,0 +1,103 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/bosh"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/encryption"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/exec"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/flow"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
+)
+
+func NewImportSequence(api, org, space string, instance *cf.ServiceInstance, om config.OpsManager, client bosh.Client, executor exec.Executor) flow.Flow {
+	cfHome, err := os.MkdirTemp("", instance.GUID)
+	if err != nil {
+		panic("failed to create CF_HOME")
+	}
+
+	log.Debugf("Creating import with CF_HOME='%s' for %s %s service running in %s/%s", cfHome, instance.Name, instance.Service, org, space)
+
+	return flow.ProgressBarSequence(
+		fmt.Sprintf("Importing %s", instance.Name),
+		flow.StepWithProgressBar(cf.LoginTargetFoundation(executor, om, api, org, space, cfHome), flow.WithDisplay("Logging into target foundation"), flow.WithAlwaysRun()),
+		flow.StepWithProgressBar(cf.CreateServiceInstance(executor, cfHome, *instance), flow.WithDisplay("Creating service instance")),
+		// always run so a resumed import looks up the guid of the target instance again
+		flow.StepWithProgressBar(cf.GetServiceInstance(executor, cfHome, instance, 15*time.Minute, 10*time.Second), flow.WithDisplay("Waiting for service instance to create"), flow.WithAlwaysRun()),
+		flow.StepWithProgressBar(TransferSnapshot(client, instance), flow.WithDisplay("Transferring snapshot")),
+		flow.StepWithProgressBar(RestoreSnapshot(client, instance), flow.WithDisplay("Restoring from snapshot")),
+	)
+}
+
+func TransferSnapshot(client bosh.Client, instance *cf.ServiceInstance) flow.StepFunc {
+	return func(ctx context.Context, c interface{}, dryRun bool) (flow.Result, error) {
+		log.Infof("Transferring snapshot")
+		if _, err := os.Stat(instance.BackupFile); os.IsNotExist(err) {
+			return exec.Result{}, fmt.Errorf("failed to transfer snapshot: %q, file does not exist", instance.BackupFile)
+		}
+		if dryRun {
+			return exec.Result{DryRun: true}, nil
+		}
+
+		l := layoutFor(instance.ServiceToCreate())
+		deployment, err := l.deployment(client, instance)
+		if err != nil {
+			return exec.Result{}, errors.Wrap(err, "failed to transfer snapshot")
+		}
+		log.Debugf("Transferring %q to %q", instance.BackupFile, deployment)
+
+		backupFile, cleanup, err := encryption.FromContext(ctx).DecryptFile(instance.BackupFile)
+		if err != nil {
+			return exec.Result{}, err
+		}
+		defer cleanup()
+
+		if err := client.UploadFile(deployment, l.instanceGroup, backupFile, remoteRDBFile); err != nil {
+			return exec.Result{}, errors.Wrap(err, fmt.Sprintf("failed to transfer snapshot to %q", deployment))
+		}
+
+		return exec.Result{}, nil
+	}
+}
+
+func RestoreSnapshot(client bosh.Client, instance *cf.ServiceInstance) flow.StepFunc {
+	return func(ctx context.Context, c interface{}, dryRun bool) (flow.Result, error) {
+		log.Infof("Restoring snapshot")
+		if dryRun {
+			return exec.Result{DryRun: true}, nil
+		}
+
+		l := layoutFor(instance.ServiceToCreate())
+		deployment, err := l.deployment(client, instance)
+		if err != nil {
+			return exec.Result{}, errors.Wrap(err, "failed to restore snapshot")
+		}
+
+		out, err := client.RunCommand(deployment, l.instanceGroup, remoteScript(l, l.dataDir(instance), restoreScript, remoteRDBFile))
+		if err != nil {
+			return exec.Result{Output: out}, errors.Wrap(err, fmt.Sprintf("failed to restore snapshot on %q", deployment))
+		}
+
+		return exec.Result{Output: out}, nil
+	}
+}
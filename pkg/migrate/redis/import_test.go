@@ -0,0 +1,135 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package redis
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/bosh-cli/director"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/bosh/fakes"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/flow"
+)
+
+func TestTransferSnapshot(t *testing.T) {
+	backupFile := filepath.Join(t.TempDir(), "dump.rdb")
+	require.NoError(t, os.WriteFile(backupFile, []byte("REDIS0009"), 0600))
+
+	tests := []struct {
+		name       string
+		backupFile string
+		want       [][]string
+		wantErr    bool
+	}{
+		{
+			name:       "copies the snapshot to the target instance",
+			backupFile: backupFile,
+			want:       [][]string{{"service-instance_target-guid", "redis-instance/0", backupFile, "/tmp/si-migrator-dump.rdb"}},
+		},
+		{
+			name:       "fails when the snapshot was not exported",
+			backupFile: filepath.Join(t.TempDir(), "dump.rdb"),
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakes.FakeClient{}
+			instance := &cf.ServiceInstance{Name: "my-redis", GUID: "target-guid", BackupFile: tt.backupFile}
+
+			_, err := flow.Sequence(TransferSnapshot(client, instance)).Run(context.TODO(), &config.Migration{}, false)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var uploads [][]string
+			for i := 0; i < client.UploadFileCallCount(); i++ {
+				deployment, vm, src, dst := client.UploadFileArgsForCall(i)
+				uploads = append(uploads, []string{deployment, vm, src, dst})
+			}
+			require.Equal(t, tt.want, uploads)
+		})
+	}
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	tests := []struct {
+		name           string
+		instance       *cf.ServiceInstance
+		wantDeployment string
+		wantVM         string
+		wantScript     []string
+	}{
+		{
+			name:           "restores the vm of an on-demand instance",
+			instance:       &cf.ServiceInstance{Name: "my-redis", GUID: "target-guid", Service: "p.redis"},
+			wantDeployment: "service-instance_target-guid",
+			wantVM:         "redis-instance/0",
+			wantScript: []string{
+				"data=/var/vcap/store/redis\n",
+				`cp /tmp/si-migrator-dump.rdb "$data/dump.rdb"`,
+				"$monit start redis",
+				"rm -f /tmp/si-migrator-dump.rdb",
+			},
+		},
+		{
+			name:           "restores the redis server of a shared-vm instance",
+			instance:       &cf.ServiceInstance{Name: "my-redis", GUID: "target-guid", Service: "p-redis"},
+			wantDeployment: "p-redis-0123456789",
+			wantVM:         "cf-redis-broker/0",
+			wantScript: []string{
+				"data=/var/vcap/store/cf-redis-broker/redis-data/target-guid\n",
+				`cp /tmp/si-migrator-dump.rdb "$data/dump.rdb"`,
+				"$monit stop process-watcher",
+				"$monit start process-watcher",
+				"rm -f /tmp/si-migrator-dump.rdb",
+			},
+		},
+		{
+			name:           "restores on the layout of the mapped service",
+			instance:       &cf.ServiceInstance{Name: "my-redis", GUID: "target-guid", Service: "p-redis", TargetService: "p.redis"},
+			wantDeployment: "service-instance_target-guid",
+			wantVM:         "redis-instance/0",
+			wantScript: []string{
+				"data=/var/vcap/store/redis\n",
+				"$monit start redis",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakes.FakeClient{}
+			client.FindDeploymentReturns(director.DeploymentResp{Name: "p-redis-0123456789"}, true, nil)
+
+			_, err := flow.Sequence(RestoreSnapshot(client, tt.instance)).Run(context.TODO(), &config.Migration{}, false)
+			require.NoError(t, err)
+
+			require.Equal(t, 1, client.RunCommandCallCount())
+			deployment, vm, command := client.RunCommandArgsForCall(0)
+			require.Equal(t, tt.wantDeployment, deployment)
+			require.Equal(t, tt.wantVM, vm)
+			script := decodeScript(t, command)
+			for _, want := range tt.wantScript {
+				require.Contains(t, script, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package redis
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/bosh"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+)
+
+const legacyService = "p-redis"
+
+// layout describes where a redis broker runs the redis server of a service instance
+type layout struct {
+	instanceGroup string
+	// preamble sets conf and defines how the scripts stop and start the redis server
+	preamble string
+	// deployment returns the name of the deployment running the redis server of the instance
+	deployment func(client bosh.Client, instance *cf.ServiceInstance) (string, error)
+	// dataDir returns the directory holding the dump.rdb of the instance
+	dataDir func(instance *cf.ServiceInstance) string
+}
+
+// onDemandLayout is the layout of the p.redis broker, which deploys a vm for each service instance
+var onDemandLayout = layout{
+	instanceGroup: "redis-instance/0",
+	preamble: `conf=/var/vcap/jobs/redis/config/redis.conf
+stop_redis() {
+  $monit stop redis
+  while $monit summary | grep -w redis | grep -qv 'not monitored'; do
+    sleep 1
+  done
+}
+start_redis() {
+  $monit start redis
+}
+`,
+	deployment: func(client bosh.Client, instance *cf.ServiceInstance) (string, error) {
+		return fmt.Sprintf("service-instance_%s", instance.GUID), nil
+	},
+	dataDir: func(*cf.ServiceInstance) string {
+		return "/var/vcap/store/redis"
+	},
+}
+
+// sharedLayout is the layout of the legacy p-redis broker, which runs a redis server for each service instance on the
+// cf-redis-broker vm of its tile deployment. The process-watcher restarts the redis servers that are not running.
+var sharedLayout = layout{
+	instanceGroup: "cf-redis-broker/0",
+	preamble: `conf="$data/redis.conf"
+stop_redis() {
+  $monit stop process-watcher
+  while $monit summary | grep -w process-watcher | grep -qv 'not monitored'; do
+    sleep 1
+  done
+  $cli "$(redis_cmd SHUTDOWN)" NOSAVE || true
+  while $cli PING 2>/dev/null | grep -q PONG; do
+    sleep 1
+  done
+}
+start_redis() {
+  $monit start process-watcher
+}
+`,
+	deployment: func(client bosh.Client, instance *cf.ServiceInstance) (string, error) {
+		d, found, err := client.FindDeployment("^p-redis-")
+		if err != nil {
+			return "", err
+		}
+		if !found {
+			return "", fmt.Errorf("failed to find the %s deployment of %q", legacyService, instance.Name)
+		}
+		return d.Name, nil
+	},
+	dataDir: func(instance *cf.ServiceInstance) string {
+		return path.Join("/var/vcap/store/cf-redis-broker/redis-data", instance.GUID)
+	},
+}
+
+func layoutFor(service string) layout {
+	if service == legacyService {
+		return sharedLayout
+	}
+	return onDemandLayout
+}
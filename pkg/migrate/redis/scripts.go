@@ -0,0 +1,85 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package redis
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// header reads the password and port of the redis server from its config. The shared-vm plans of the p-redis broker
+// rename the admin commands, so redis_cmd looks up the name a command has been given.
+const header = `export REDISCLI_AUTH="$(awk '$1 == "requirepass" {print $2}' "$conf")"
+port="$(awk '$1 == "port" {print $2}' "$conf")"
+cli="/var/vcap/packages/redis/bin/redis-cli -p ${port:-6379}"
+monit=/var/vcap/bosh/bin/monit
+redis_cmd() {
+  local name
+  name="$(awk -v cmd="$1" 'toupper($1) == "RENAME-COMMAND" && toupper($2) == cmd {print $3}' "$conf" | tr -d '"')"
+  echo "${name:-$1}"
+}
+wait_for_redis() {
+  until $cli PING 2>/dev/null | grep -q PONG; do
+    sleep 1
+  done
+}
+`
+
+// snapshotScript runs a BGSAVE, waits for it to finish and copies the RDB file to where the ssh user can read it
+const snapshotScript = `last_save="$($cli LASTSAVE)"
+$cli "$(redis_cmd BGSAVE)"
+until [ "$($cli LASTSAVE)" != "$last_save" ] && $cli INFO persistence | grep -q 'rdb_bgsave_in_progress:0'; do
+  sleep 1
+done
+if ! $cli INFO persistence | grep -q 'rdb_last_bgsave_status:ok'; then
+  echo "BGSAVE failed" >&2
+  exit 1
+fi
+cp "$data/dump.rdb" %[1]s
+chmod 0644 %[1]s
+`
+
+// restoreScript replaces the dataset with the transferred RDB file. When append only persistence is enabled redis
+// ignores the RDB file on startup, so the AOF is rebuilt from the restored dataset before it is turned back on.
+const restoreScript = `stop_redis
+cp %[1]s "$data/dump.rdb"
+chown vcap:vcap "$data/dump.rdb"
+aof=false
+if grep -q '^appendonly yes' "$conf"; then
+  aof=true
+  rm -rf "$data/appendonly.aof" "$data/appendonlydir"
+  sed -i 's/^appendonly yes/appendonly no/' "$conf"
+fi
+start_redis
+wait_for_redis
+if [ "$aof" = true ]; then
+  $cli "$(redis_cmd BGREWRITEAOF)"
+  until $cli INFO persistence | grep -q 'aof_rewrite_in_progress:0'; do
+    sleep 1
+  done
+  sed -i 's/^appendonly no/appendonly yes/' "$conf"
+  stop_redis
+  start_redis
+  wait_for_redis
+fi
+rm -f %[1]s
+`
+
+// remoteScript returns a command that runs the script for the redis server of the layout as root without having to
+// quote its contents
+func remoteScript(l layout, dataDir, script string, args ...interface{}) string {
+	s := fmt.Sprintf("set -euo pipefail\ndata=%s\n", dataDir) + l.preamble + header + fmt.Sprintf(script, args...)
+	return fmt.Sprintf("echo %s | base64 -d | sudo bash", base64.StdEncoding.EncodeToString([]byte(s)))
+}
@@ -0,0 +1,110 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package migrate
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/exec"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/cc"
+)
+
+// MigratorOptions holds everything a MigratorBuilder may need to build the migrator of a single service instance
+type MigratorOptions struct {
+	Name                          string
+	Org                           string
+	Space                         string
+	Instance                      *cf.ServiceInstance
+	OpsManager                    config.OpsManager
+	Dir                           string
+	IsExport                      bool
+	ConfigLoader                  config.Loader
+	ClientHolder                  ClientHolder
+	MigrationReader               config.MigrationReader
+	MigratorHelper                *MigratorHelper
+	Executor                      exec.Executor
+	CloudControllerServiceFactory cc.CloudControllerServiceFactory
+}
+
+// MigratorBuilder creates the migrator used to export or import a service instance
+type MigratorBuilder func(opts MigratorOptions) (ServiceInstanceMigrator, error)
+
+var (
+	registryMu    sync.RWMutex
+	builders      = make(map[string]MigratorBuilder)
+	serviceLabels = make(map[string]string)
+)
+
+// Register makes a migrator available by name for the given service labels. The name is also the key
+// of the migrator's config in si-migrator.yml. Register is meant to be called from an init function and
+// panics if the name or any of the service labels is already registered.
+func Register(name string, labels []string, builder MigratorBuilder) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if builder == nil {
+		panic(fmt.Sprintf("migrate: Register builder for %q is nil", name))
+	}
+	if _, dup := builders[name]; dup {
+		panic(fmt.Sprintf("migrate: Register called twice for migrator %q", name))
+	}
+	for _, label := range labels {
+		if m, dup := serviceLabels[label]; dup {
+			panic(fmt.Sprintf("migrate: service label %q is already registered to migrator %q", label, m))
+		}
+	}
+
+	builders[name] = builder
+	for _, label := range labels {
+		serviceLabels[label] = name
+	}
+}
+
+// Migrators returns the sorted names of all registered migrators
+func Migrators() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(builders))
+	for name := range builders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func lookupBuilder(name string) (MigratorBuilder, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	b, ok := builders[name]
+	return b, ok
+}
+
+func registeredServiceLabels() map[string]string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	labels := make(map[string]string, len(serviceLabels))
+	for label, name := range serviceLabels {
+		labels[label] = name
+	}
+	return labels
+}
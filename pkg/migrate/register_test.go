@@ -0,0 +1,120 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package migrate_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	configfakes "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config/fakes"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/fakes"
+)
+
+func TestRegister(t *testing.T) {
+	var got migrate.MigratorOptions
+	want := new(fakes.FakeServiceInstanceMigrator)
+	migrate.Register("test-migrator", []string{"p.test"}, func(o migrate.MigratorOptions) (migrate.ServiceInstanceMigrator, error) {
+		got = o
+		return want, nil
+	})
+
+	require.Contains(t, migrate.Migrators(), "test-migrator")
+	require.Contains(t, migrate.Migrators(), "mysql")
+
+	require.Panics(t, func() {
+		migrate.Register("test-migrator", nil, func(o migrate.MigratorOptions) (migrate.ServiceInstanceMigrator, error) { return nil, nil })
+	}, "registering a name twice panics")
+	require.Panics(t, func() {
+		migrate.Register("another-test-migrator", []string{"p.mysql"}, func(o migrate.MigratorOptions) (migrate.ServiceInstanceMigrator, error) { return nil, nil })
+	}, "registering a label twice panics")
+
+	tests := []struct {
+		name         string
+		migration    *config.Migration
+		service      string
+		wantMigrator string
+		wantOK       bool
+	}{
+		{
+			name:         "looks up registered service labels",
+			migration:    &config.Migration{},
+			service:      "p.test",
+			wantMigrator: "test-migrator",
+			wantOK:       true,
+		},
+		{
+			name:         "looks up built-in service labels",
+			migration:    &config.Migration{},
+			service:      "MSSQL-Broker",
+			wantMigrator: "sqlserver",
+			wantOK:       true,
+		},
+		{
+			name: "maps service labels from the config to a registered migrator",
+			migration: &config.Migration{
+				Migrators: []config.Migrator{
+					{Name: "test-migrator", ServiceLabels: []string{"custom-test-broker"}},
+				},
+			},
+			service:      "custom-test-broker",
+			wantMigrator: "test-migrator",
+			wantOK:       true,
+		},
+		{
+			name: "ignores service labels of unknown migrators",
+			migration: &config.Migration{
+				Migrators: []config.Migrator{
+					{Name: "unknown", ServiceLabels: []string{"custom-test-broker"}},
+				},
+			},
+			service: "custom-test-broker",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &configfakes.FakeMigrationReader{
+				GetMigrationStub: func() (*config.Migration, error) {
+					return tt.migration, nil
+				},
+			}
+			mh := migrate.NewMigratorHelper(reader)
+
+			m, ok := mh.GetMigratorType(tt.service)
+			require.Equal(t, tt.wantOK, ok)
+			require.Equal(t, tt.wantMigrator, m.String())
+
+			if tt.wantMigrator != "test-migrator" {
+				return
+			}
+
+			got = migrate.MigratorOptions{}
+			f := migrate.NewMigratorFactory(nil, nil, mh, nil, nil)
+			si := &cf.ServiceInstance{Name: "some-instance", Service: tt.service, Type: migrate.ManagedService.String()}
+			migrator, err := f.New("some-org", "some-space", si, config.OpsManager{}, nil, nil, "/tmp/export", true)
+			require.NoError(t, err)
+			require.Same(t, want, migrator)
+			require.Equal(t, "test-migrator", got.Name)
+			require.Equal(t, "some-org", got.Org)
+			require.Equal(t, "some-space", got.Space)
+			require.Same(t, si, got.Instance)
+			require.Equal(t, "/tmp/export", got.Dir)
+			require.True(t, got.IsExport)
+		})
+	}
+}
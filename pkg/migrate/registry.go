@@ -59,6 +59,20 @@ func (r *DefaultMigratorRegistry) Lookup(org, space string, si *cf.ServiceInstan
 	return migrator, true, nil
 }
 
+// MigratorName returns the name of the migrator that Lookup picks for the service instance
+func (r *DefaultMigratorRegistry) MigratorName(si *cf.ServiceInstance) string {
+	switch ServiceType(si.Type) {
+	case ManagedService:
+		if m, ok := r.helper.GetMigratorType(si.Service); ok {
+			return m.String()
+		}
+		return "default"
+	case UserProvidedService:
+		return "user-provided"
+	}
+	return ""
+}
+
 func (r *DefaultMigratorRegistry) shouldMigrate(cfg *config.Config, service string) bool {
 	if len(cfg.Services) == 0 {
 		return true
@@ -151,3 +151,33 @@ func TestDefaultMigratorRegistry(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultMigratorRegistry_MigratorName(t *testing.T) {
+	tests := []struct {
+		name string
+		si   *cf.ServiceInstance
+		want string
+	}{
+		{
+			name: "names the registered migrator of a managed service",
+			si:   &cf.ServiceInstance{Type: "managed_service_instance", Service: "p.mysql"},
+			want: "mysql",
+		},
+		{
+			name: "names the default migrator for an unsupported managed service",
+			si:   &cf.ServiceInstance{Type: "managed_service_instance", Service: "custom-broker"},
+			want: "default",
+		},
+		{
+			name: "names the user provided service migrator",
+			si:   &cf.ServiceInstance{Type: "user_provided_service_instance", Service: "my-cups"},
+			want: "user-provided",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := migrate.NewMigratorRegistry(new(fakes.FakeFactory), &migrate.MigratorHelper{}, &config.Config{}, new(configfakes.FakeLoader), new(fakes.FakeClientHolder))
+			require.Equal(t, tt.want, r.MigratorName(tt.si))
+		})
+	}
+}
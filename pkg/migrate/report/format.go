@@ -0,0 +1,222 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Format is the output format of the migration report
+type Format string
+
+const (
+	TableFormat Format = "table"
+	JSONFormat  Format = "json"
+	JUnitFormat Format = "junit"
+)
+
+// Formats are the supported report formats
+var Formats = []Format{TableFormat, JSONFormat, JUnitFormat}
+
+// ParseFormat returns the report format with the given name, the table format is the default
+func ParseFormat(name string) (Format, error) {
+	if name == "" {
+		return TableFormat, nil
+	}
+
+	for _, f := range Formats {
+		if string(f) == strings.ToLower(name) {
+			return f, nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported report format %q, must be one of %s", name, joinFormats())
+}
+
+func joinFormats() string {
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Write writes every result of the summary to w in the given format
+func (s *Summary) Write(w io.Writer, f Format) error {
+	switch f {
+	case TableFormat:
+		s.writeTable(w)
+		return nil
+	case JSONFormat:
+		return s.writeJSON(w)
+	case JUnitFormat:
+		return s.writeJUnit(w)
+	}
+	return fmt.Errorf("unsupported report format %q, must be one of %s", f, joinFormats())
+}
+
+type jsonReport struct {
+	Successful int          `json:"successful"`
+	Skipped    int          `json:"skipped"`
+	Failed     int          `json:"failed"`
+	Results    []jsonResult `json:"results"`
+}
+
+type jsonResult struct {
+	Org             string   `json:"org"`
+	Space           string   `json:"space"`
+	Instance        string   `json:"instance"`
+	Service         string   `json:"service"`
+	Migrator        string   `json:"migrator,omitempty"`
+	Status          Status   `json:"status"`
+	Error           string   `json:"error,omitempty"`
+	Warnings        []string `json:"warnings,omitempty"`
+	DurationSeconds float64  `json:"duration_seconds"`
+}
+
+func (s *Summary) writeJSON(w io.Writer) error {
+	r := jsonReport{
+		Successful: s.ServiceSuccessCount(),
+		Skipped:    s.ServiceSkippedCount(),
+		Failed:     s.ServiceFailureCount(),
+		Results:    []jsonResult{},
+	}
+	for _, res := range s.Results() {
+		r.Results = append(r.Results, jsonResult{
+			Org:             res.OrgName,
+			Space:           res.SpaceName,
+			Instance:        res.ServiceName,
+			Service:         res.Service,
+			Migrator:        res.Migrator,
+			Status:          res.Status,
+			Error:           res.Error,
+			Warnings:        res.Warnings,
+			DurationSeconds: res.Duration.Seconds(),
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+	duration time.Duration
+}
+
+type junitTestCase struct {
+	Name       string           `xml:"name,attr"`
+	Classname  string           `xml:"classname,attr"`
+	Time       string           `xml:"time,attr"`
+	Properties *junitProperties `xml:"properties,omitempty"`
+	Failure    *junitMessage    `xml:"failure,omitempty"`
+	Skipped    *junitMessage    `xml:"skipped,omitempty"`
+	SystemOut  string           `xml:"system-out,omitempty"`
+}
+
+type junitProperties struct {
+	Properties []junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes one test suite per org and space, with a test case per service instance
+func (s *Summary) writeJUnit(w io.Writer) error {
+	report := junitTestSuites{Name: "si-migrator"}
+
+	var total time.Duration
+	var suite *junitTestSuite
+	for _, res := range s.Results() {
+		name := res.OrgName + "/" + res.SpaceName
+		if suite == nil || suite.Name != name {
+			report.Suites = append(report.Suites, junitTestSuite{Name: name})
+			suite = &report.Suites[len(report.Suites)-1]
+		}
+
+		tc := junitTestCase{
+			Name:      res.ServiceName,
+			Classname: res.Service,
+			Time:      seconds(res.Duration),
+		}
+		if res.Migrator != "" {
+			tc.Properties = &junitProperties{Properties: []junitProperty{{Name: "migrator", Value: res.Migrator}}}
+		}
+		if len(res.Warnings) > 0 {
+			tc.SystemOut = strings.Join(res.Warnings, "\n")
+		}
+		switch res.Status {
+		case Failed:
+			tc.Failure = &junitMessage{Message: strings.Split(res.Error, ":")[0], Text: res.Error}
+			suite.Failures++
+			report.Failures++
+		case Skipped:
+			tc.Skipped = &junitMessage{Message: res.Error}
+			suite.Skipped++
+			report.Skipped++
+		}
+
+		suite.Cases = append(suite.Cases, tc)
+		suite.Tests++
+		suite.duration += res.Duration
+		suite.Time = seconds(suite.duration)
+		report.Tests++
+		total += res.Duration
+	}
+	report.Time = seconds(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,158 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package report
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		want    Format
+		wantErr bool
+	}{
+		{name: "defaults to table", format: "", want: TableFormat},
+		{name: "parses json", format: "json", want: JSONFormat},
+		{name: "parses junit ignoring case", format: "JUnit", want: JUnitFormat},
+		{name: "fails on unknown format", format: "csv", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFormat(tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func newTestSummary() *Summary {
+	s := NewSummary(&bytes.Buffer{})
+	s.AddSuccessfulService("blue", "dev", "my-good-service", "p.mysql", WithMigrator("mysql"), WithDuration(1500*time.Millisecond), WithWarnings("not shared into blue/prod: space not found"))
+	s.AddFailedService("red", "dev", "my-bad-service", "ecs", errors.New("failed to migrate: ccdb: connection refused"), WithMigrator("ecs"), WithDuration(2*time.Second))
+	s.AddSkippedService("red", "dev", "my-skipped-service", "p.redis", nil)
+	return s
+}
+
+func TestSummary_Write(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  Format
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "writes every result as json",
+			format: JSONFormat,
+			want: `{
+  "successful": 1,
+  "skipped": 1,
+  "failed": 1,
+  "results": [
+    {
+      "org": "blue",
+      "space": "dev",
+      "instance": "my-good-service",
+      "service": "p.mysql",
+      "migrator": "mysql",
+      "status": "successful",
+      "warnings": [
+        "not shared into blue/prod: space not found"
+      ],
+      "duration_seconds": 1.5
+    },
+    {
+      "org": "red",
+      "space": "dev",
+      "instance": "my-bad-service",
+      "service": "ecs",
+      "migrator": "ecs",
+      "status": "failed",
+      "error": "failed to migrate: ccdb: connection refused",
+      "duration_seconds": 2
+    },
+    {
+      "org": "red",
+      "space": "dev",
+      "instance": "my-skipped-service",
+      "service": "p.redis",
+      "status": "skipped",
+      "duration_seconds": 0
+    }
+  ]
+}
+`,
+		},
+		{
+			name:   "writes a junit test suite per org and space",
+			format: JUnitFormat,
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="si-migrator" tests="3" failures="1" skipped="1" time="3.500">
+  <testsuite name="blue/dev" tests="1" failures="0" skipped="0" time="1.500">
+    <testcase name="my-good-service" classname="p.mysql" time="1.500">
+      <properties>
+        <property name="migrator" value="mysql"></property>
+      </properties>
+      <system-out>not shared into blue/prod: space not found</system-out>
+    </testcase>
+  </testsuite>
+  <testsuite name="red/dev" tests="2" failures="1" skipped="1" time="2.000">
+    <testcase name="my-bad-service" classname="ecs" time="2.000">
+      <properties>
+        <property name="migrator" value="ecs"></property>
+      </properties>
+      <failure message="failed to migrate">failed to migrate: ccdb: connection refused</failure>
+    </testcase>
+    <testcase name="my-skipped-service" classname="p.redis" time="0.000">
+      <skipped message=""></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+		{
+			name:   "writes the table with errors truncated",
+			format: TableFormat,
+			want: `Org       Space     Name                Service   Result
+blue      dev       my-good-service     p.mysql   successful
+red       dev       my-bad-service      ecs       failed to migrate
+red       dev       my-skipped-service  p.redis   skipped
+`,
+		},
+		{
+			name:    "fails on unknown format",
+			format:  Format("csv"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := newTestSummary().Write(&buf, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
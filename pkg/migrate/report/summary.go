@@ -21,6 +21,7 @@ import (
 	"strings"
 	"sync"
 	"text/tabwriter"
+	"time"
 
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
 )
@@ -30,6 +31,15 @@ const (
 	keyFormat string = "%s%%%s%%%s%%%s"
 )
 
+// Status is the outcome of a service migration
+type Status string
+
+const (
+	Successful Status = "successful"
+	Failed     Status = "failed"
+	Skipped    Status = "skipped"
+)
+
 // Result is a specific service migration result: success or failure
 type Result struct {
 	OrgName     string
@@ -37,11 +47,42 @@ type Result struct {
 	ServiceName string
 	Service     string
 	Message     string
+	Status      Status
+	Error       string
+	Duration    time.Duration
+	Migrator    string
+	Warnings    []string
+}
+
+// ResultOption adds details about how a service was migrated to its result
+type ResultOption func(r *Result)
+
+// WithDuration records how long the service migration took
+func WithDuration(d time.Duration) ResultOption {
+	return func(r *Result) {
+		r.Duration = d
+	}
+}
+
+// WithMigrator records the name of the migrator that ran the service migration
+func WithMigrator(name string) ResultOption {
+	return func(r *Result) {
+		r.Migrator = name
+	}
+}
+
+// WithWarnings records problems that did not stop the service migration from succeeding
+func WithWarnings(warnings ...string) ResultOption {
+	return func(r *Result) {
+		for _, w := range warnings {
+			r.Warnings = append(r.Warnings, log.Redact(w))
+		}
+	}
 }
 
 // Summary is a thread safe sink of execution results for service migrations
 type Summary struct {
-	results      map[string]string
+	results      map[string]Result
 	successCount int
 	failureCount int
 	skippedCount int
@@ -55,7 +96,7 @@ type Summary struct {
 // NewSummary creates a new initialized summary instance
 func NewSummary(w io.Writer) *Summary {
 	return &Summary{
-		results:     make(map[string]string),
+		results:     make(map[string]Result),
 		TableWriter: w,
 	}
 }
@@ -87,15 +128,8 @@ func (s *Summary) Results() []Result {
 	defer s.resMutex.Unlock()
 
 	var r []Result
-	for k, m := range s.results {
-		value := strings.Split(k, separator)
-		r = append(r, Result{
-			OrgName:     value[0],
-			SpaceName:   value[1],
-			ServiceName: value[2],
-			Service:     value[3],
-			Message:     m,
-		})
+	for _, res := range s.results {
+		r = append(r, res)
 	}
 	sort.Slice(r, func(i, j int) bool {
 		var sortedByOrgName, sortedBySpaceName bool
@@ -116,7 +150,7 @@ func (s *Summary) Results() []Result {
 }
 
 // AddFailedService adds a failed service along with its error to the summary result
-func (s *Summary) AddFailedService(org, space, serviceName, serviceType string, err error) {
+func (s *Summary) AddFailedService(org, space, serviceName, serviceType string, err error, opts ...ResultOption) {
 	if len(serviceName) == 0 {
 		return
 	}
@@ -127,11 +161,11 @@ func (s *Summary) AddFailedService(org, space, serviceName, serviceType string,
 	s.resMutex.Lock()
 	defer s.resMutex.Unlock()
 
-	s.results[fmt.Sprintf(keyFormat, org, space, serviceName, serviceType)] = err.Error()
+	s.addResult(org, space, serviceName, serviceType, Failed, err.Error(), err, opts)
 }
 
 // AddSkippedService adds a skipped service along with its error to the summary result
-func (s *Summary) AddSkippedService(org, space, serviceName, serviceType string, err error) {
+func (s *Summary) AddSkippedService(org, space, serviceName, serviceType string, err error, opts ...ResultOption) {
 	if len(serviceName) == 0 {
 		return
 	}
@@ -142,11 +176,11 @@ func (s *Summary) AddSkippedService(org, space, serviceName, serviceType string,
 	s.resMutex.Lock()
 	defer s.resMutex.Unlock()
 
-	s.results[fmt.Sprintf(keyFormat, org, space, serviceName, serviceType)] = fmt.Sprintf("skipped: %v", err)
+	s.addResult(org, space, serviceName, serviceType, Skipped, fmt.Sprintf("skipped: %v", err), err, opts)
 }
 
 // AddSuccessfulService adds a successful service and increments the count of successful services
-func (s *Summary) AddSuccessfulService(org, space, serviceName, serviceType string) {
+func (s *Summary) AddSuccessfulService(org, space, serviceName, serviceType string, opts ...ResultOption) {
 	s.sucMutex.Lock()
 	defer s.sucMutex.Unlock()
 	s.successCount++
@@ -154,7 +188,27 @@ func (s *Summary) AddSuccessfulService(org, space, serviceName, serviceType stri
 	s.resMutex.Lock()
 	defer s.resMutex.Unlock()
 
-	s.results[fmt.Sprintf(keyFormat, org, space, serviceName, serviceType)] = "successful"
+	s.addResult(org, space, serviceName, serviceType, Successful, "successful", nil, opts)
+}
+
+// addResult must be called with resMutex held
+func (s *Summary) addResult(org, space, serviceName, serviceType string, status Status, message string, err error, opts []ResultOption) {
+	r := Result{
+		OrgName:     org,
+		SpaceName:   space,
+		ServiceName: serviceName,
+		Service:     serviceType,
+		Message:     log.Redact(message),
+		Status:      status,
+	}
+	if err != nil {
+		r.Error = log.Redact(err.Error())
+	}
+	for _, opt := range opts {
+		opt(&r)
+	}
+
+	s.results[fmt.Sprintf(keyFormat, org, space, serviceName, serviceType)] = r
 }
 
 func (s *Summary) Display() {
@@ -163,12 +217,18 @@ func (s *Summary) Display() {
 		return
 	}
 
-	tw := tabwriter.NewWriter(s.TableWriter, 10, 2, 2, ' ', 0)
-	// Header
-	_, _ = fmt.Fprintln(tw, "Org\tSpace\tName\tService\tResult")
 	log.Infof("Migration summary: %d successes, %d skipped, %d errors.", s.ServiceSuccessCount(), s.ServiceSkippedCount(), s.ServiceFailureCount())
 	fmt.Println()
 
+	s.writeTable(s.TableWriter)
+	fmt.Println()
+}
+
+func (s *Summary) writeTable(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 10, 2, 2, ' ', 0)
+	// Header
+	_, _ = fmt.Fprintln(tw, "Org\tSpace\tName\tService\tResult")
+
 	for _, f := range s.Results() {
 		row := []string{
 			f.OrgName,
@@ -180,5 +240,4 @@ func (s *Summary) Display() {
 		_, _ = fmt.Fprintln(tw, strings.Join(row, "\t"))
 	}
 	_ = tw.Flush()
-	fmt.Println()
 }
@@ -0,0 +1,149 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/cc"
+)
+
+// CloudControllerRollback replays the compensating actions recorded by the cc migrators in an export directory
+type CloudControllerRollback struct {
+	SourceLoader         config.Loader
+	TargetLoader         config.Loader
+	SourceServiceFactory cc.CloudControllerServiceFactory
+	TargetServiceFactory cc.CloudControllerServiceFactory
+	services             map[string]cc.Service
+}
+
+func NewCloudControllerRollback(sourceLoader, targetLoader config.Loader, sourceFactory, targetFactory cc.CloudControllerServiceFactory) *CloudControllerRollback {
+	return &CloudControllerRollback{
+		SourceLoader:         sourceLoader,
+		TargetLoader:         targetLoader,
+		SourceServiceFactory: sourceFactory,
+		TargetServiceFactory: targetFactory,
+		services:             make(map[string]cc.Service),
+	}
+}
+
+func (r *CloudControllerRollback) Rollback(ctx context.Context, dir string) error {
+	logs, err := cc.LoadRollbackLogs(dir)
+	if err != nil {
+		return err
+	}
+
+	if len(logs) == 0 {
+		log.Infof("Nothing to roll back in %q", dir)
+		return nil
+	}
+
+	dryRun := false
+	if cfg, ok := config.FromContext(ctx); ok {
+		dryRun = cfg.DryRun
+	}
+
+	// undo the imports on the target foundation before restoring the source foundation
+	sort.SliceStable(logs, func(i, j int) bool {
+		return !logs[i].Compensations[0].Export && logs[j].Compensations[0].Export
+	})
+
+	var failed int
+	for _, l := range logs {
+		if err := r.replay(ctx, l, dryRun); err != nil {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to roll back %d service instance(s)", failed)
+	}
+
+	return nil
+}
+
+func (r *CloudControllerRollback) replay(ctx context.Context, l cc.CompensationLog, dryRun bool) error {
+	summary, hasSummary := config.SummaryFromContext(ctx)
+	last := l.Compensations[len(l.Compensations)-1]
+
+	if dryRun {
+		log.Infof("Would roll back %q in %s/%s", last.Instance, last.Org, last.Space)
+		if hasSummary {
+			summary.AddSkippedService(last.Org, last.Space, last.Instance, last.Migrator, nil)
+		}
+		return nil
+	}
+
+	// compensations are applied in the reverse order they were registered
+	for i := len(l.Compensations) - 1; i >= 0; i-- {
+		c := l.Compensations[i]
+		svc, err := r.service(c.Migrator, c.Export)
+		if err == nil {
+			log.Infof("Rolling back %q in %s/%s", c.Instance, c.Org, c.Space)
+			err = c.Apply(svc)
+		}
+		if err != nil {
+			log.Errorf("failed to roll back service instance %q: %v", c.Instance, err)
+			if hasSummary {
+				summary.AddFailedService(c.Org, c.Space, c.Instance, c.Migrator, err)
+			}
+			return err
+		}
+	}
+
+	if err := os.Remove(l.Path); err != nil {
+		log.Warnf("failed to remove rollback log %q: %v", l.Path, err)
+	}
+
+	if hasSummary {
+		summary.AddSuccessfulService(last.Org, last.Space, last.Instance, last.Migrator)
+	}
+
+	return nil
+}
+
+func (r *CloudControllerRollback) service(migrator string, isExport bool) (cc.Service, error) {
+	key := fmt.Sprintf("%s-%t", migrator, isExport)
+	if svc, ok := r.services[key]; ok {
+		return svc, nil
+	}
+
+	l, sf := r.TargetLoader, r.TargetServiceFactory
+	if isExport {
+		l, sf = r.SourceLoader, r.SourceServiceFactory
+	}
+
+	ccConfig, ok := l.CCDBConfig(migrator, isExport).(*cc.Config)
+	if !ok || ccConfig == nil {
+		return nil, fmt.Errorf("failed to find ccdb config for %s", migrator)
+	}
+
+	if err := ccConfig.Validate(isExport); err != nil {
+		return nil, fmt.Errorf("migration config validation failed for %s, %w", migrator, err)
+	}
+
+	svc, err := sf.NewCloudControllerService(ccConfig, isExport)
+	if err != nil {
+		return nil, err
+	}
+	r.services[key] = svc
+
+	return svc, nil
+}
@@ -0,0 +1,83 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
+)
+
+// bindRoutes binds the imported route service instance to the routes it was bound to on the source foundation,
+// with the domains_to_replace applied to their domains. Routes that don't exist on the target are not bound and
+// are returned as warnings instead.
+func bindRoutes(ctx context.Context, client cf.Client, orgName, spaceName string, instance *cf.ServiceInstance) ([]string, error) {
+	_, _, guid, err := findTargetServiceInstance(client, orgName, spaceName, instance)
+	if err != nil {
+		return nil, err
+	}
+
+	var domainsToReplace map[string]string
+	if cfg, ok := config.FromContext(ctx); ok {
+		domainsToReplace = cfg.DomainsToReplace
+	}
+
+	bound, err := client.ListRouteBindings(guid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up route bindings of %q: %w", instance.Name, err)
+	}
+	alreadyBound := make(map[cf.RouteBinding]bool, len(bound))
+	for _, r := range bound {
+		alreadyBound[r] = true
+	}
+
+	var warnings []string
+	ready := false
+	for _, r := range instance.RouteBindings {
+		r.Domain = ReplaceDomain(r.Domain, domainsToReplace)
+		if alreadyBound[r] {
+			log.Debugf("Route %s is already bound to %q", r.URL(), instance.Name)
+			continue
+		}
+
+		routeGUID, err := client.FindRoute(r.Host, r.Domain, r.Path)
+		if err != nil {
+			return warnings, fmt.Errorf("failed to look up route %s: %w", r.URL(), err)
+		}
+		if routeGUID == "" {
+			warning := fmt.Sprintf("not bound to route %s: route not found", r.URL())
+			log.Warnf("Service instance %q %s", instance.Name, warning)
+			warnings = append(warnings, warning)
+			continue
+		}
+
+		if !ready {
+			if err = waitForServiceInstance(ctx, client, instance, guid); err != nil {
+				return warnings, err
+			}
+			ready = true
+		}
+
+		log.Infof("Binding route %s to %q", r.URL(), instance.Name)
+		if err = client.CreateRouteBinding(routeGUID, guid); err != nil {
+			return warnings, fmt.Errorf("failed to bind route %s to %q: %w", r.URL(), instance.Name, err)
+		}
+	}
+
+	return warnings, nil
+}
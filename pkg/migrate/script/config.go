@@ -0,0 +1,66 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package script
+
+import (
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+)
+
+// Name is the name of the script migrator in si-migrator.yml
+const Name = "script"
+
+// Config holds the command templates run to export and import a service instance
+type Config struct {
+	Export string `yaml:"export"`
+	Import string `yaml:"import"`
+}
+
+// LookupConfig returns the config of the script migrator whose service labels contain the given service.
+// Several script migrators can be configured, one per set of service labels.
+func LookupConfig(migration config.Migration, service string) (Config, error) {
+	for _, m := range migration.Migrators {
+		if m.Name != Name || !contains(m.ServiceLabels, service) {
+			continue
+		}
+
+		var cfg Config
+		decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			Result:  &cfg,
+			TagName: "yaml",
+		})
+		if err != nil {
+			return Config{}, err
+		}
+		if err = decoder.Decode(m.Value); err != nil {
+			return Config{}, fmt.Errorf("failed to decode script migrator config for %q: %w", service, err)
+		}
+
+		return cfg, nil
+	}
+
+	return Config{}, fmt.Errorf("no script migrator configured for service %q", service)
+}
+
+func contains(labels []string, service string) bool {
+	for _, l := range labels {
+		if l == service {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package script
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/exec"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/flow"
+)
+
+func NewExportSequence(api, org, space string, instance *cf.ServiceInstance, om config.OpsManager, executor exec.Executor, cfg Config) flow.Flow {
+	cfHome, err := os.MkdirTemp("", instance.GUID)
+	if err != nil {
+		panic("failed to create CF_HOME")
+	}
+
+	return flow.ProgressBarSequence(
+		fmt.Sprintf("Exporting %s", instance.Name),
+		flow.StepWithProgressBar(
+			cf.LoginSourceFoundation(executor, om, api, org, space, cfHome),
+			flow.WithDisplay("Logging into source foundation"),
+			flow.WithAlwaysRun(),
+		),
+		flow.StepWithProgressBar(
+			RunExportScript(executor, cfHome, org, space, instance, cfg.Export),
+			flow.WithDisplay("Running export script"),
+			flow.WithAlwaysRun(),
+		),
+	)
+}
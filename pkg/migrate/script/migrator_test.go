@@ -0,0 +1,113 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package script_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	flowfakes "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/flow/fakes"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/script"
+)
+
+func TestLookupConfig(t *testing.T) {
+	migration := config.Migration{
+		Migrators: []config.Migrator{
+			{
+				Name:          "script",
+				ServiceLabels: []string{"internal-cache"},
+				Value: map[string]interface{}{
+					"export": "./export-cache.sh",
+					"import": "./import-cache.sh",
+				},
+			},
+			{
+				Name:          "script",
+				ServiceLabels: []string{"internal-queue", "legacy-queue"},
+				Value: map[string]interface{}{
+					"export": "./export-queue.sh",
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		service string
+		want    script.Config
+		wantErr bool
+	}{
+		{
+			name:    "returns the config of the script migrator for the service",
+			service: "legacy-queue",
+			want:    script.Config{Export: "./export-queue.sh"},
+		},
+		{
+			name:    "returns the first script migrator",
+			service: "internal-cache",
+			want:    script.Config{Export: "./export-cache.sh", Import: "./import-cache.sh"},
+		},
+		{
+			name:    "fails when no script migrator handles the service",
+			service: "p.mysql",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := script.LookupConfig(migration, tt.service)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LookupConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMigrator_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  script.Config
+		export  bool
+		wantErr bool
+	}{
+		{
+			name:   "validates the export script",
+			config: script.Config{Export: "./export.sh {{ quote .GUID }}"},
+			export: true,
+		},
+		{
+			name:    "fails when no import script is configured",
+			config:  script.Config{Export: "./export.sh"},
+			wantErr: true,
+		},
+		{
+			name:    "fails when the script is not a valid template",
+			config:  script.Config{Export: "./export.sh {{ .GUID"},
+			export:  true,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := script.NewMigrator(new(flowfakes.FakeFlow), nil, tt.config)
+			if err := m.Validate(&cf.ServiceInstance{Service: "internal-cache"}, tt.export); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
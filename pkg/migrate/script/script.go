@@ -0,0 +1,121 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package script
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/exec"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/flow"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
+)
+
+// TemplateData is passed to the export and import command templates. The fields of the service instance
+// are promoted, so templates can refer to them directly, e.g. {{ quote .Name }} or {{ quote (index .Outputs "url") }}.
+type TemplateData struct {
+	*cf.ServiceInstance
+	Org    string
+	Space  string
+	CFHome string
+}
+
+// RunExportScript runs the export script and merges the json object it prints on stdout into the outputs of the instance
+func RunExportScript(e exec.Executor, cfHome, org, space string, instance *cf.ServiceInstance, script string) flow.StepFunc {
+	return func(ctx context.Context, c interface{}, dryRun bool) (flow.Result, error) {
+		log.Infof("Running export script for %q", instance.Name)
+		res, err := run(ctx, e, "export", script, TemplateData{ServiceInstance: instance, Org: org, Space: space, CFHome: cfHome})
+		if err != nil {
+			return res, err
+		}
+		if res.DryRun {
+			return res, nil
+		}
+
+		outputs, err := parseOutputs(res.Output)
+		if err != nil {
+			return res, err
+		}
+		if len(outputs) > 0 && instance.Outputs == nil {
+			instance.Outputs = make(map[string]interface{}, len(outputs))
+		}
+		for k, v := range outputs {
+			instance.Outputs[k] = v
+		}
+
+		return res, nil
+	}
+}
+
+// RunImportScript runs the import script against the service instance created on the target foundation
+func RunImportScript(e exec.Executor, cfHome, org, space string, instance *cf.ServiceInstance, script string) flow.StepFunc {
+	return func(ctx context.Context, c interface{}, dryRun bool) (flow.Result, error) {
+		log.Infof("Running import script for %q", instance.Name)
+		return run(ctx, e, "import", script, TemplateData{ServiceInstance: instance, Org: org, Space: space, CFHome: cfHome})
+	}
+}
+
+func run(ctx context.Context, e exec.Executor, name, script string, data TemplateData) (exec.Result, error) {
+	tmpl, err := parse(name, script)
+	if err != nil {
+		return exec.Result{}, err
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "export CF_HOME=%s\n", quote(data.CFHome))
+	if err = tmpl.Execute(&b, data); err != nil {
+		return exec.Result{}, fmt.Errorf("failed to render %s script for %q: %w", name, data.Name, err)
+	}
+
+	res, err := e.Execute(ctx, &b)
+	if err != nil {
+		return res, errors.Wrap(err, fmt.Sprintf("%s script failed for %q", name, data.Name))
+	}
+
+	return res, nil
+}
+
+func parse(name, script string) (*template.Template, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(template.FuncMap{"quote": quote}).Parse(script)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s script: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+func parseOutputs(output string) (map[string]interface{}, error) {
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return nil, nil
+	}
+
+	var outputs map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &outputs); err != nil {
+		return nil, fmt.Errorf("export script must print a json object on stdout: %w", err)
+	}
+
+	return outputs, nil
+}
+
+// quote single quotes a value so the shell passes it to the command as one argument, whatever it contains
+func quote(v interface{}) string {
+	return "'" + strings.ReplaceAll(fmt.Sprint(v), "'", `'"'"'`) + "'"
+}
@@ -0,0 +1,124 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package script
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/exec"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/exec/fakes"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/flow"
+)
+
+func TestRunExportScript(t *testing.T) {
+	tests := []struct {
+		name        string
+		script      string
+		result      exec.Result
+		outputs     map[string]interface{}
+		params      map[string]interface{}
+		wantCommand string
+		wantOutputs map[string]interface{}
+		wantErr     bool
+	}{
+		{
+			name:        "renders the script and merges its json output",
+			script:      "./export.sh {{ quote .Org }} {{ quote .Space }} {{ quote .Name }} {{ quote .GUID }} {{ quote .Plan }}",
+			result:      exec.Result{Output: "{\"url\": \"https://cache.example.com\", \"shards\": 3}\n"},
+			outputs:     map[string]interface{}{"region": "us-east-1"},
+			wantCommand: "export CF_HOME='/tmp/cf-home'\n./export.sh 'some-org' 'some-space' 'my-cache' 'some-guid' 'small'",
+			wantOutputs: map[string]interface{}{"region": "us-east-1", "url": "https://cache.example.com", "shards": float64(3)},
+		},
+		{
+			name:        "quotes values the shell would otherwise interpret",
+			script:      "./export.sh {{ quote .Params.name }}",
+			params:      map[string]interface{}{"name": "it's $(rm -rf /)"},
+			wantCommand: "export CF_HOME='/tmp/cf-home'\n./export.sh 'it'\"'\"'s $(rm -rf /)'",
+		},
+		{
+			name:        "leaves outputs alone when the script prints nothing",
+			script:      "./export.sh",
+			wantCommand: "export CF_HOME='/tmp/cf-home'\n./export.sh",
+		},
+		{
+			name:        "does not parse the output on dry run",
+			script:      "./export.sh",
+			result:      exec.Result{DryRun: true},
+			wantCommand: "export CF_HOME='/tmp/cf-home'\n./export.sh",
+		},
+		{
+			name:        "fails when the output is not a json object",
+			script:      "./export.sh",
+			result:      exec.Result{Output: "exported my-cache"},
+			wantCommand: "export CF_HOME='/tmp/cf-home'\n./export.sh",
+			wantErr:     true,
+		},
+		{
+			name:    "fails when the template refers to an unknown field",
+			script:  "./export.sh {{ .Unknown }}",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &fakes.FakeExecutor{}
+			e.ExecuteReturns(tt.result, nil)
+			instance := &cf.ServiceInstance{Name: "my-cache", GUID: "some-guid", Plan: "small", Params: tt.params, Outputs: tt.outputs}
+
+			_, err := flow.Sequence(RunExportScript(e, "/tmp/cf-home", "some-org", "some-space", instance, tt.script)).Run(context.TODO(), &config.Migration{}, false)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantCommand != "" {
+				require.Equal(t, 1, e.ExecuteCallCount())
+				_, r := e.ExecuteArgsForCall(0)
+				cmd := &bytes.Buffer{}
+				_, err = io.Copy(cmd, r)
+				require.NoError(t, err)
+				require.Equal(t, tt.wantCommand, cmd.String())
+			}
+			if !tt.wantErr {
+				require.Equal(t, tt.wantOutputs, instance.Outputs)
+			}
+		})
+	}
+}
+
+func TestRunImportScript(t *testing.T) {
+	e := &fakes.FakeExecutor{}
+	instance := &cf.ServiceInstance{
+		Name:    "my-cache",
+		GUID:    "target-guid",
+		Outputs: map[string]interface{}{"url": "https://cache.example.com"},
+	}
+
+	_, err := flow.Sequence(RunImportScript(e, "/tmp/cf-home", "some-org", "some-space", instance, `./import.sh {{ quote .GUID }} {{ quote (index .Outputs "url") }}`)).Run(context.TODO(), &config.Migration{}, false)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, e.ExecuteCallCount())
+	_, r := e.ExecuteArgsForCall(0)
+	cmd := &bytes.Buffer{}
+	_, err = io.Copy(cmd, r)
+	require.NoError(t, err)
+	require.Equal(t, "export CF_HOME='/tmp/cf-home'\n./import.sh 'target-guid' 'https://cache.example.com'", cmd.String())
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
@@ -29,7 +30,6 @@ import (
 
 	"github.com/cloudfoundry-community/go-cfclient"
 	"github.com/pkg/errors"
-	"golang.org/x/sync/errgroup"
 )
 
 type DefaultServiceInstanceExporter struct {
@@ -49,12 +49,9 @@ func NewServiceInstanceExporter(cfg *config.Config, h ClientHolder, registry Mig
 }
 
 func (e *DefaultServiceInstanceExporter) ExportManagedServices(ctx context.Context, org cfclient.Org, space cfclient.Space, om config.OpsManager, dir string) error {
-	g, gctx := errgroup.WithContext(ctx)
+	g, gctx := newGroup(ctx)
 	client := e.ClientHolder.SourceCFClient()
 
-	// variable behavior using the service instances api -- does not always return the correct service instances
-	// similar issue: https://github.com/cloudfoundry/cloud_controller_ng/issues/2474
-	// changed to using the spaces api to fetch the service instances by space
 	instances, err := client.ListSpaceServiceInstances(space.Guid)
 	if err != nil {
 		return fmt.Errorf("error getting managed service instances for '%s/%s': %w", org.Name, space.Name, err)
@@ -72,48 +69,79 @@ func (e *DefaultServiceInstanceExporter) ExportManagedServices(ctx context.Conte
 					return nil
 				}
 
-				bindings, err := client.ListServiceBindingsByQuery(url.Values{"q": []string{fmt.Sprintf("service_instance_guid:%s", instance.Guid)}})
+				tctx, tracker := trackInstance(gctx, org.Name, space.Name, instance.Name)
+				if tracker != nil && tracker.Completed() {
+					log.Infof("Skipping %q, already exported in a previous run", instance.Name)
+					if summary, ok := config.SummaryFromContext(gctx); ok {
+						summary.AddSkippedService(org.Name, space.Name, instance.Name, instance.Type, errAlreadyMigrated)
+					}
+					return nil
+				}
+
+				start := time.Now()
+				si := &cf.ServiceInstance{
+					Name:        instance.Name,
+					GUID:        instance.Guid,
+					Type:        instance.Type,
+					Tags:        strings.Join(instance.Tags, ","),
+					Credentials: instance.Credentials,
+				}
+				failed := func(err error) error {
+					recordFailure(gctx, org.Name, space.Name, si, err, resultDetails(start, e.Registry.MigratorName(si))...)
+					return err
+				}
+
+				servicePlan, err := client.GetServicePlanByGUID(instance.ServicePlanGuid)
+				if err != nil {
+					return failed(fmt.Errorf("error retrieving service plan for instance %s: %w", instance.Name, err))
+				}
+				si.Plan = servicePlan.Name
+
+				svc, err := client.GetServiceByGuid(servicePlan.ServiceGuid)
 				if err != nil {
-					return fmt.Errorf("could not fetch service bindings for instance %s: %w", instance.Guid, err)
+					return failed(fmt.Errorf("error retrieving service for plan %s: %w", servicePlan.Name, err))
 				}
+				si.Service = svc.Label
 
-				serviceKeys, err := client.ListServiceKeysByQuery(url.Values{"q": []string{fmt.Sprintf("service_instance_guid:%s", instance.Guid)}})
+				bindings, err := client.ListServiceBindingsByQuery(url.Values{"service_instance_guids": []string{instance.Guid}})
 				if err != nil {
-					return fmt.Errorf("could not fetch service keys for instance %s: %w", instance.Guid, err)
+					return failed(fmt.Errorf("could not fetch service bindings for instance %s: %w", instance.Guid, err))
 				}
 
-				params, err := client.GetServiceInstanceParams(instance.Guid)
+				serviceKeys, err := client.ListServiceKeysByQuery(url.Values{"service_instance_guids": []string{instance.Guid}})
+				if err != nil {
+					return failed(fmt.Errorf("could not fetch service keys for instance %s: %w", instance.Guid, err))
+				}
+				si.ServiceKeys = convertServiceKeys(serviceKeys)
+
+				si.Params, err = client.GetServiceInstanceParams(instance.Guid)
 				if err != nil && !cfclient.IsServiceFetchInstanceParametersNotSupportedError(err) {
-					return fmt.Errorf("could not fetch service instance params for instance %s: %w", instance.Name, err)
+					return failed(fmt.Errorf("could not fetch service instance params for instance %s: %w", instance.Name, err))
 				}
 
-				servicePlan, err := client.GetServicePlanByGUID(instance.ServicePlanGuid)
+				si.ServiceBindings, si.Apps, err = exportBindings(client, bindings, true)
 				if err != nil {
-					return fmt.Errorf("error retrieving service plan for instance %s: %w", instance.Name, err)
+					return failed(fmt.Errorf("could not export service bindings for instance %s: %w", instance.Name, err))
 				}
 
-				svc, err := client.GetServiceByGuid(servicePlan.ServiceGuid)
+				si.SharedSpaces, err = client.ListSharedSpaces(instance.Guid)
 				if err != nil {
-					return fmt.Errorf("error retrieving service for plan %s: %w", servicePlan.Name, err)
+					return failed(fmt.Errorf("could not export shared spaces for instance %s: %w", instance.Name, err))
 				}
 
-				si := &cf.ServiceInstance{
-					Name:            instance.Name,
-					GUID:            instance.Guid,
-					Type:            instance.Type,
-					Tags:            strings.Join(instance.Tags, ","),
-					Params:          params,
-					Plan:            servicePlan.Name,
-					Credentials:     instance.Credentials,
-					ServiceBindings: convertBindings(bindings),
-					ServiceKeys:     convertServiceKeys(serviceKeys),
-					Service:         svc.Label,
+				si.RouteBindings, err = client.ListRouteBindings(instance.Guid)
+				if err != nil {
+					return failed(fmt.Errorf("could not export route bindings for instance %s: %w", instance.Name, err))
+				}
+
+				if err = exportMetadata(client, si); err != nil {
+					return failed(fmt.Errorf("could not export labels and annotations for instance %s: %w", instance.Name, err))
 				}
 
 				migrator, migrate, err := e.Registry.Lookup(org.Name, space.Name, si, om, dir, true)
 
 				if err != nil {
-					return fmt.Errorf("failed to find a valid migrator for instance %s: %w", si.Name, err)
+					return failed(fmt.Errorf("failed to find a valid migrator for instance %s: %w", si.Name, err))
 				}
 
 				dryRun := false
@@ -123,30 +151,36 @@ func (e *DefaultServiceInstanceExporter) ExportManagedServices(ctx context.Conte
 
 				if !migrate || dryRun {
 					if summary, ok := config.SummaryFromContext(gctx); ok {
-						summary.AddSkippedService(org.Name, space.Name, si.Name, si.Service, nil)
+						summary.AddSkippedService(org.Name, space.Name, si.Name, si.Service, nil, resultDetails(start, e.Registry.MigratorName(si))...)
 					}
 					return nil
 				}
 
 				if migrator == nil {
 					if summary, ok := config.SummaryFromContext(gctx); ok {
-						summary.AddSkippedService(org.Name, space.Name, si.Name, si.Service, nil)
+						summary.AddSkippedService(org.Name, space.Name, si.Name, si.Service, nil, resultDetails(start, e.Registry.MigratorName(si))...)
 					}
 					return nil
 				}
 
 				err = migrator.Validate(si, true)
+				if err != nil {
+					return failed(err)
+				}
+
+				release, err := acquireSlot(gctx, e.Registry.MigratorName(si))
 				if err != nil {
 					return err
 				}
+				defer release()
 
 				log.Infof("Exporting service %s from %s/%s", si.Service, org.Name, space.Name)
 
-				migrated, err := migrator.Migrate(gctx)
+				migrated, err := migrator.Migrate(tctx)
 				if errors.Is(err, db.ErrUnsupportedOperation) {
 					log.Warnf("unsupported db error %v, skipped exporting service instance %s", err, si.Name)
 					if summary, ok := config.SummaryFromContext(gctx); ok {
-						summary.AddSkippedService(org.Name, space.Name, si.Name, si.Service, err)
+						summary.AddSkippedService(org.Name, space.Name, si.Name, si.Service, err, resultDetails(start, e.Registry.MigratorName(si))...)
 					}
 					return nil
 				}
@@ -155,7 +189,7 @@ func (e *DefaultServiceInstanceExporter) ExportManagedServices(ctx context.Conte
 				if errors.As(err, &validationErr) && len(si.ServiceBindings) == 0 {
 					log.Warnf("validation error %s, skipped exporting service instance %s", validationErr.Error(), si.Name)
 					if summary, ok := config.SummaryFromContext(gctx); ok {
-						summary.AddSkippedService(org.Name, space.Name, si.Name, si.Service, validationErr)
+						summary.AddSkippedService(org.Name, space.Name, si.Name, si.Service, validationErr, resultDetails(start, e.Registry.MigratorName(si))...)
 					}
 					return nil
 				}
@@ -163,7 +197,7 @@ func (e *DefaultServiceInstanceExporter) ExportManagedServices(ctx context.Conte
 				if migrated != nil {
 					for _, app := range migrated.AppManifest.Applications {
 						filename := strings.ReplaceAll(app.Name, "/", "-") + "_manifest"
-						err = marshalAppManifest(e.Parser, filename, &migrated.AppManifest, org, space, dir)
+						err = marshalAppManifest(e.Parser, filename, &migrated.AppManifest, org, space, dir, e.extension())
 						if err != nil {
 							log.Errorf("Failed to save manifest: %s, error: %v", filename, err)
 						}
@@ -172,19 +206,21 @@ func (e *DefaultServiceInstanceExporter) ExportManagedServices(ctx context.Conte
 
 				if err != nil {
 					if summary, ok := config.SummaryFromContext(gctx); ok {
-						summary.AddFailedService(org.Name, space.Name, si.Name, si.Service, err)
+						summary.AddFailedService(org.Name, space.Name, si.Name, si.Service, err, resultDetails(start, e.Registry.MigratorName(si))...)
 					}
 					return fmt.Errorf("failed to migrate %s: %w", si.Name, err)
 				}
 
-				err = marshalServiceInstance(e.Parser, si, org, space, dir)
+				err = marshalServiceInstance(e.Parser, si, org, space, dir, e.extension())
 				if err != nil {
 					log.Fatalf("cannot save instance %v, error %v", si, err)
 				}
 
+				completeInstance(tracker, si.Name)
+
 				log.Debugf("Finished exporting %q", si.Name)
 				if summary, ok := config.SummaryFromContext(gctx); ok {
-					summary.AddSuccessfulService(org.Name, space.Name, si.Name, si.Service)
+					summary.AddSuccessfulService(org.Name, space.Name, si.Name, si.Service, resultDetails(start, e.Registry.MigratorName(si))...)
 				}
 
 				return nil
@@ -200,46 +236,85 @@ func (e *DefaultServiceInstanceExporter) ExportManagedServices(ctx context.Conte
 }
 
 func (e *DefaultServiceInstanceExporter) ExportUserProvidedServices(ctx context.Context, org cfclient.Org, space cfclient.Space, dir string) error {
+	g, gctx := newGroup(ctx)
 	client := e.ClientHolder.SourceCFClient()
 
-	spaceGuidParams := url.Values{"q": []string{fmt.Sprintf("space_guid:%s", space.Guid)}}
-	upsInstances, err := client.ListUserProvidedServiceInstancesByQuery(spaceGuidParams)
+	upsInstances, err := client.ListUserProvidedServiceInstancesByQuery(url.Values{"space_guids": []string{space.Guid}})
 	if err != nil {
 		return fmt.Errorf("error getting user provided service instances for space %s instance org %s: %w", space.Name, org.Name, err)
 	}
 
 	for _, ups := range upsInstances {
-		si := &cf.ServiceInstance{
-			Name:            ups.Name,
-			GUID:            ups.Guid,
-			Type:            ups.Type,
-			Tags:            strings.Join(ups.Tags, ","),
-			SyslogDrainUrl:  ups.SyslogDrainUrl,
-			RouteServiceUrl: ups.RouteServiceUrl,
-			Credentials:     ups.Credentials,
-			Service:         ups.Name,
-		}
-		fd := io.FileDescriptor{
-			BaseDir:   dir,
-			Name:      ups.Name,
-			Org:       org.Name,
-			Space:     space.Name,
-			Extension: "yml",
-		}
-		err = e.Parser.Marshal(si, fd)
-		if err != nil {
-			if summary, ok := config.SummaryFromContext(ctx); ok {
-				summary.AddFailedService(org.Name, space.Name, si.Name, si.Service, err)
-			}
-			log.Warnf("cannot save instance %q, error %s", si.Name, err)
-			return err
-		}
+		func(ups cfclient.UserProvidedServiceInstance) {
+			g.Go(func() error {
+				start := time.Now()
+				si := &cf.ServiceInstance{
+					Name:            ups.Name,
+					GUID:            ups.Guid,
+					Type:            ups.Type,
+					Tags:            strings.Join(ups.Tags, ","),
+					SyslogDrainUrl:  ups.SyslogDrainUrl,
+					RouteServiceUrl: ups.RouteServiceUrl,
+					Credentials:     ups.Credentials,
+					Service:         ups.Name,
+				}
+				failed := func(err error) error {
+					recordFailure(gctx, org.Name, space.Name, si, err, resultDetails(start, e.Registry.MigratorName(si))...)
+					return err
+				}
+
+				bindings, err := client.ListServiceBindingsByQuery(url.Values{"service_instance_guids": []string{ups.Guid}})
+				if err != nil {
+					return failed(fmt.Errorf("could not fetch service bindings for instance %s: %w", ups.Guid, err))
+				}
+
+				si.ServiceBindings, si.Apps, err = exportBindings(client, bindings, false)
+				if err != nil {
+					return failed(fmt.Errorf("could not export service bindings for instance %s: %w", ups.Name, err))
+				}
+
+				si.RouteBindings, err = client.ListRouteBindings(ups.Guid)
+				if err != nil {
+					return failed(fmt.Errorf("could not export route bindings for instance %s: %w", ups.Name, err))
+				}
 
-		if summary, ok := config.SummaryFromContext(ctx); ok {
-			summary.AddSuccessfulService(org.Name, space.Name, si.Name, si.Service)
+				if err = exportMetadata(client, si); err != nil {
+					return failed(fmt.Errorf("could not export labels and annotations for instance %s: %w", ups.Name, err))
+				}
+
+				fd := io.FileDescriptor{
+					BaseDir:   dir,
+					Name:      ups.Name,
+					Org:       org.Name,
+					Space:     space.Name,
+					Extension: e.extension(),
+				}
+				if err = e.Parser.Marshal(si, fd); err != nil {
+					log.Warnf("cannot save instance %q, error %s", si.Name, err)
+					return failed(err)
+				}
+
+				if summary, ok := config.SummaryFromContext(gctx); ok {
+					summary.AddSuccessfulService(org.Name, space.Name, si.Name, si.Service, resultDetails(start, e.Registry.MigratorName(si))...)
+				}
+
+				return nil
+			})
+		}(ups)
+	}
+
+	return g.Wait()
+}
+
+// extension is the file extension of the configured export format, the commands reject unknown formats before
+// anything is exported
+func (e *DefaultServiceInstanceExporter) extension() string {
+	if e.cfg != nil {
+		if ext, err := io.FormatExtension(e.cfg.ExportFormat); err == nil {
+			return ext
 		}
 	}
-	return nil
+	return "yml"
 }
 
 func (e *DefaultServiceInstanceExporter) shouldMigrate(instance cfclient.ServiceInstance) bool {
@@ -297,26 +372,26 @@ func convertServiceKey(key cfclient.ServiceKey) cf.ServiceKey {
 	}
 }
 
-func marshalServiceInstance(parser ServiceInstanceParser, si *cf.ServiceInstance, org cfclient.Org, space cfclient.Space, dir string) error {
+func marshalServiceInstance(parser ServiceInstanceParser, si *cf.ServiceInstance, org cfclient.Org, space cfclient.Space, dir string, ext string) error {
 	fd := io.FileDescriptor{
 		BaseDir:   dir,
 		Name:      si.Name,
 		Org:       org.Name,
 		Space:     space.Name,
-		Extension: "yml",
+		Extension: ext,
 	}
 
 	return parser.Marshal(si, fd)
 }
 
-func marshalAppManifest(parser ServiceInstanceParser, filename string, m *cf.Manifest, org cfclient.Org, space cfclient.Space, dir string) error {
+func marshalAppManifest(parser ServiceInstanceParser, filename string, m *cf.Manifest, org cfclient.Org, space cfclient.Space, dir string, ext string) error {
 	fd := io.FileDescriptor{
 		BaseDir:   dir,
 		Name:      filename,
 		Org:       org.Name,
 		Space:     space.Name,
-		Extension: "yml",
+		Extension: ext,
 	}
 
-	return parser.Marshal(m, fd)
+	return parser.MarshalUnversioned(m, fd)
 }
@@ -17,8 +17,10 @@ package migrate_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -30,6 +32,7 @@ import (
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/fakes"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/journal"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/report"
 )
 
@@ -71,6 +74,9 @@ func TestNewServiceInstanceExporter(t *testing.T) {
 func TestServiceInstanceExporter_ExportManagedServices(t *testing.T) {
 	pwd, err := os.Getwd()
 	require.NoError(t, err)
+	j, err := journal.Open(filepath.Join(t.TempDir(), journal.ExportFile), false)
+	require.NoError(t, err)
+	require.NoError(t, j.Track("some-org", "some-space", "already-exported").Complete())
 	type fields struct {
 		holder   *fakes.FakeClientHolder
 		registry *fakes.FakeMigratorRegistry
@@ -132,6 +138,24 @@ func TestServiceInstanceExporter_ExportManagedServices(t *testing.T) {
 						Label: "some-service",
 					}, nil
 				},
+				GetServiceBindingParamsStub: func(string) (map[string]interface{}, error) {
+					return map[string]interface{}{"role": "admin"}, nil
+				},
+				GetAppByGuidNoInlineCallStub: func(guid string) (cfclient.App, error) {
+					return cfclient.App{Guid: guid, Name: "some-app"}, nil
+				},
+				ListSharedSpacesStub: func(string) ([]cf.SharedSpace, error) {
+					return []cf.SharedSpace{{Org: "other-org", Space: "other-space"}}, nil
+				},
+				ListRouteBindingsStub: func(string) ([]cf.RouteBinding, error) {
+					return []cf.RouteBinding{{Host: "auth", Domain: "apps.cf1.example.com", Path: "/admin"}}, nil
+				},
+				GetServiceInstanceMetadataStub: func(string) (cf.Metadata, error) {
+					return cf.Metadata{Labels: map[string]string{"team": "payments"}, Annotations: map[string]string{"owner": "payments@example.com"}}, nil
+				},
+				GetServiceCredentialBindingMetadataStub: func(guid string) (cf.Metadata, error) {
+					return cf.Metadata{Labels: map[string]string{"binding": guid}}, nil
+				},
 			},
 			fields: fields{
 				cfg:    &config.Config{},
@@ -175,8 +199,21 @@ func TestServiceInstanceExporter_ExportManagedServices(t *testing.T) {
 				require.Equal(t, 1, cfClient.GetServiceInstanceParamsCallCount())
 				require.Equal(t, 1, cfClient.GetServicePlanByGUIDCallCount())
 				require.Equal(t, 1, cfClient.GetServiceByGuidCallCount())
+				require.Equal(t, 1, cfClient.GetServiceBindingParamsCallCount())
+				require.Equal(t, "some-app-guid", cfClient.GetAppByGuidNoInlineCallArgsForCall(0))
 				require.Equal(t, 1, r.LookupCallCount())
-				require.Equal(t, 2, p.MarshalCallCount())
+				require.Equal(t, 1, p.MarshalCallCount())
+				require.Equal(t, 1, p.MarshalUnversionedCallCount())
+				m, _ := p.MarshalUnversionedArgsForCall(0)
+				require.IsType(t, &cf.Manifest{}, m)
+				si, _ := p.MarshalArgsForCall(0)
+				require.Equal(t, map[string]string{"some-guid": "some-app"}, si.(*cf.ServiceInstance).Apps)
+				require.Equal(t, map[string]interface{}{"role": "admin"}, si.(*cf.ServiceInstance).ServiceBindings[0].Parameters)
+				require.Equal(t, []cf.SharedSpace{{Org: "other-org", Space: "other-space"}}, si.(*cf.ServiceInstance).SharedSpaces)
+				require.Equal(t, []cf.RouteBinding{{Host: "auth", Domain: "apps.cf1.example.com", Path: "/admin"}}, si.(*cf.ServiceInstance).RouteBindings)
+				require.Equal(t, map[string]string{"team": "payments"}, si.(*cf.ServiceInstance).Labels)
+				require.Equal(t, map[string]string{"owner": "payments@example.com"}, si.(*cf.ServiceInstance).Annotations)
+				require.Equal(t, map[string]string{"binding": "some-guid"}, si.(*cf.ServiceInstance).ServiceBindings[0].Labels)
 				s, ok := config.SummaryFromContext(ctx)
 				require.True(t, ok)
 				require.Equal(t, 0, s.ServiceSkippedCount())
@@ -420,6 +457,48 @@ func TestServiceInstanceExporter_ExportManagedServices(t *testing.T) {
 				require.Equal(t, 1, s.ServiceSuccessCount())
 			},
 		},
+		{
+			name: "skips an instance exported in a previous run before fetching its details",
+			cfClient: &cffakes.FakeClient{
+				ListSpaceServiceInstancesStub: func(spaceGUID string) ([]cfclient.ServiceInstance, error) {
+					return []cfclient.ServiceInstance{{
+						Name: "already-exported",
+						Type: "managed_service_instance",
+						Guid: "some-guid",
+					}}, nil
+				},
+			},
+			fields: fields{
+				cfg:      &config.Config{},
+				holder:   new(fakes.FakeClientHolder),
+				registry: new(fakes.FakeMigratorRegistry),
+				parser:   new(fakes.FakeServiceInstanceParser),
+			},
+			args: args{
+				ctx: config.ContextWithJournal(context.TODO(), j),
+				org: cfclient.Org{
+					Name: "some-org",
+				},
+				space: cfclient.Space{
+					Name: "some-space",
+				},
+				dir: pwd + "/testdata",
+			},
+			wantErr: false,
+			beforeFunc: func(ctx context.Context) context.Context {
+				return config.ContextWithSummary(ctx, report.NewSummary(&bytes.Buffer{}))
+			},
+			afterFunc: func(ctx context.Context, fakeHolder *fakes.FakeClientHolder, r *fakes.FakeMigratorRegistry, p *fakes.FakeServiceInstanceParser) {
+				cfClient := fakeHolder.SourceCFClient().(*cffakes.FakeClient)
+				require.Equal(t, 0, cfClient.ListServiceBindingsByQueryCallCount())
+				require.Equal(t, 0, cfClient.GetServicePlanByGUIDCallCount())
+				require.Equal(t, 0, r.LookupCallCount())
+				require.Equal(t, 0, p.MarshalCallCount())
+				s, ok := config.SummaryFromContext(ctx)
+				require.True(t, ok)
+				require.Equal(t, 1, s.ServiceSkippedCount())
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -447,6 +526,7 @@ func TestServiceInstanceExporter_ExportUserProvidedServices(t *testing.T) {
 	pwd, err := os.Getwd()
 	require.NoError(t, err)
 	type fields struct {
+		cfg      *config.Config
 		holder   *fakes.FakeClientHolder
 		registry *fakes.FakeMigratorRegistry
 		parser   *fakes.FakeServiceInstanceParser
@@ -458,11 +538,13 @@ func TestServiceInstanceExporter_ExportUserProvidedServices(t *testing.T) {
 		ctx   context.Context
 	}
 	tests := []struct {
-		name     string
-		cfClient *cffakes.FakeClient
-		fields   fields
-		args     args
-		wantErr  bool
+		name              string
+		cfClient          *cffakes.FakeClient
+		fields            fields
+		args              args
+		wantExt           string
+		wantRouteBindings []cf.RouteBinding
+		wantErr           bool
 	}{
 		{
 			name: "export a user provided service",
@@ -488,23 +570,99 @@ func TestServiceInstanceExporter_ExportUserProvidedServices(t *testing.T) {
 				},
 				dir: pwd + "/testdata",
 			},
+			wantExt: "yml",
 			wantErr: false,
 		},
+		{
+			name: "export a user provided route service as json",
+			cfClient: &cffakes.FakeClient{
+				ListUserProvidedServiceInstancesByQueryStub: func(url.Values) ([]cfclient.UserProvidedServiceInstance, error) {
+					return []cfclient.UserProvidedServiceInstance{{
+						Name: "service-provided-instance",
+					}}, nil
+				},
+				ListRouteBindingsStub: func(string) ([]cf.RouteBinding, error) {
+					return []cf.RouteBinding{{Host: "auth", Domain: "apps.cf1.example.com"}}, nil
+				},
+			},
+			fields: fields{
+				cfg:      &config.Config{ExportFormat: "json"},
+				registry: new(fakes.FakeMigratorRegistry),
+				parser:   new(fakes.FakeServiceInstanceParser),
+				holder:   new(fakes.FakeClientHolder),
+			},
+			args: args{
+				ctx:   context.TODO(),
+				org:   cfclient.Org{Name: "some-org"},
+				space: cfclient.Space{Name: "some-space"},
+				dir:   pwd + "/testdata",
+			},
+			wantExt:           "json",
+			wantRouteBindings: []cf.RouteBinding{{Host: "auth", Domain: "apps.cf1.example.com"}},
+			wantErr:           false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.fields.holder.SourceCFClientStub = func() cf.Client {
 				return tt.cfClient
 			}
-			e := migrate.DefaultServiceInstanceExporter{
-				ClientHolder: tt.fields.holder,
-				Registry:     tt.fields.registry,
-				Parser:       tt.fields.parser,
-			}
+			e := migrate.NewServiceInstanceExporter(tt.fields.cfg, tt.fields.holder, tt.fields.registry, tt.fields.parser)
 			if err := e.ExportUserProvidedServices(tt.args.ctx, tt.args.org, tt.args.space, tt.args.dir); (err != nil) != tt.wantErr {
 				t.Errorf("ExportUserProvidedServices() error = %v, wantErr %v", err, tt.wantErr)
 			}
 			require.Equal(t, 1, tt.cfClient.ListUserProvidedServiceInstancesByQueryCallCount())
+			require.Equal(t, 1, tt.fields.parser.MarshalCallCount())
+			si, fd := tt.fields.parser.MarshalArgsForCall(0)
+			require.Equal(t, tt.wantExt, fd.Extension)
+			require.Equal(t, 1, tt.cfClient.ListRouteBindingsCallCount())
+			require.Equal(t, tt.wantRouteBindings, si.(*cf.ServiceInstance).RouteBindings)
 		})
 	}
 }
+
+func TestServiceInstanceExporter_RecordsFailures(t *testing.T) {
+	cfClient := &cffakes.FakeClient{
+		ListSpaceServiceInstancesStub: func(string) ([]cfclient.ServiceInstance, error) {
+			return []cfclient.ServiceInstance{{Name: "some-instance", Type: "managed_service_instance"}}, nil
+		},
+		ListUserProvidedServiceInstancesByQueryStub: func(url.Values) ([]cfclient.UserProvidedServiceInstance, error) {
+			return []cfclient.UserProvidedServiceInstance{{Name: "some-ups", Type: "user_provided_service_instance"}}, nil
+		},
+		GetServicePlanByGUIDStub: func(string) (*cfclient.ServicePlan, error) {
+			return &cfclient.ServicePlan{Name: "some-plan"}, nil
+		},
+		GetServiceByGuidStub: func(string) (cfclient.Service, error) {
+			return cfclient.Service{Label: "p.mysql"}, nil
+		},
+		ListRouteBindingsStub: func(string) ([]cf.RouteBinding, error) {
+			return nil, errors.New("route bindings unavailable")
+		},
+	}
+	holder := &fakes.FakeClientHolder{
+		SourceCFClientStub: func() cf.Client {
+			return cfClient
+		},
+	}
+	registry := &fakes.FakeMigratorRegistry{
+		MigratorNameStub: func(si *cf.ServiceInstance) string {
+			if si.Type == "user_provided_service_instance" {
+				return "user-provided"
+			}
+			return "mysql"
+		},
+	}
+	summary := report.NewSummary(&bytes.Buffer{})
+	ctx := config.ContextWithSummary(context.TODO(), summary)
+	e := migrate.NewServiceInstanceExporter(&config.Config{}, holder, registry, new(fakes.FakeServiceInstanceParser))
+
+	require.ErrorContains(t, e.ExportManagedServices(ctx, cfclient.Org{Name: "some-org"}, cfclient.Space{Name: "some-space"}, config.OpsManager{}, t.TempDir()), "route bindings unavailable")
+	require.ErrorContains(t, e.ExportUserProvidedServices(ctx, cfclient.Org{Name: "some-org"}, cfclient.Space{Name: "some-space"}, t.TempDir()), "route bindings unavailable")
+
+	require.Equal(t, 2, summary.ServiceFailureCount())
+	results := summary.Results()
+	require.Equal(t, "p.mysql", results[0].Service)
+	require.Equal(t, "mysql", results[0].Migrator)
+	require.Equal(t, "some-ups", results[1].ServiceName)
+	require.Equal(t, "user-provided", results[1].Migrator)
+}
@@ -17,29 +17,47 @@ package migrate
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/report"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/validation"
 )
 
 type ManagedServiceInstanceImporter struct {
-	Registry MigratorRegistry
+	Registry     MigratorRegistry
+	ClientHolder ClientHolder
 }
 
-func NewServiceInstanceImporter(registry MigratorRegistry) ManagedServiceInstanceImporter {
+func NewServiceInstanceImporter(registry MigratorRegistry, h ClientHolder) ManagedServiceInstanceImporter {
 	return ManagedServiceInstanceImporter{
-		Registry: registry,
+		Registry:     registry,
+		ClientHolder: h,
 	}
 }
 
 func (i ManagedServiceInstanceImporter) ImportManagedService(ctx context.Context, org, space string, si *cf.ServiceInstance, om config.OpsManager, dir string) error {
+	start := time.Now()
+	ctx, tracker := trackInstance(ctx, org, space, si.Name)
+	if tracker != nil && tracker.Completed() {
+		log.Infof("Skipping %q, already imported in a previous run", si.Name)
+		if summary, ok := config.SummaryFromContext(ctx); ok {
+			summary.AddSkippedService(org, space, si.Name, si.Service, errAlreadyMigrated)
+		}
+		return nil
+	}
+
+	applyMappings(ctx, org, si)
+
 	migrator, migrate, err := i.Registry.Lookup(org, space, si, om, dir, false)
 	if err != nil {
-		return fmt.Errorf("failed to find a valid migrator for instance %s: %w", si.Name, err)
+		err = fmt.Errorf("failed to find a valid migrator for instance %s: %w", si.Name, err)
+		recordFailure(ctx, org, space, si, err, resultDetails(start, i.Registry.MigratorName(si))...)
+		return err
 	}
 
 	dryRun := false
@@ -49,23 +67,30 @@ func (i ManagedServiceInstanceImporter) ImportManagedService(ctx context.Context
 
 	if !migrate || dryRun {
 		if summary, ok := config.SummaryFromContext(ctx); ok {
-			summary.AddSkippedService(org, space, si.Name, si.Service, nil)
+			summary.AddSkippedService(org, space, si.Name, si.Service, nil, resultDetails(start, i.Registry.MigratorName(si))...)
 		}
 		return nil
 	}
 
 	if migrator == nil {
 		if summary, ok := config.SummaryFromContext(ctx); ok {
-			summary.AddSkippedService(org, space, si.Name, si.Service, nil)
+			summary.AddSkippedService(org, space, si.Name, si.Service, nil, resultDetails(start, i.Registry.MigratorName(si))...)
 		}
 		return nil
 	}
 
 	err = migrator.Validate(si, false)
 	if err != nil {
+		recordFailure(ctx, org, space, si, err, resultDetails(start, i.Registry.MigratorName(si))...)
 		return err
 	}
 
+	release, err := acquireSlot(ctx, i.Registry.MigratorName(si))
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	log.Infof("Importing %q to %s/%s", si.Name, org, space)
 	_, err = migrator.Migrate(ctx)
 	if err != nil {
@@ -73,22 +98,65 @@ func (i ManagedServiceInstanceImporter) ImportManagedService(ctx context.Context
 		if errors.As(err, &validationErr) && len(si.ServiceBindings) == 0 {
 			log.Warnf("validation error %s, skipped migrating service instance %s", validationErr.Error(), si.Name)
 			if summary, ok := config.SummaryFromContext(ctx); ok {
-				summary.AddSkippedService(org, space, si.Name, si.Service, validationErr)
+				summary.AddSkippedService(org, space, si.Name, si.Service, validationErr, resultDetails(start, i.Registry.MigratorName(si))...)
 			}
 			return nil
 		}
 		log.Errorf("error migrating service instance %s", si.Name)
 		if summary, ok := config.SummaryFromContext(ctx); ok {
-			summary.AddFailedService(org, space, si.Name, si.Service, err)
+			summary.AddFailedService(org, space, si.Name, si.Service, err, resultDetails(start, i.Registry.MigratorName(si))...)
 		}
 		return errors.Wrap(err, fmt.Sprintf("failed to migrate %s", si.Name))
 	}
 
+	var warnings []string
+	if len(si.SharedSpaces) > 0 && i.ClientHolder != nil {
+		warnings, err = shareInstance(i.ClientHolder.TargetCFClient(), org, space, si)
+		if err != nil {
+			recordFailure(ctx, org, space, si, err, resultDetails(start, i.Registry.MigratorName(si))...)
+			return err
+		}
+	}
+
+	if len(si.RouteBindings) > 0 && i.ClientHolder != nil {
+		routeWarnings, err := bindRoutes(ctx, i.ClientHolder.TargetCFClient(), org, space, si)
+		warnings = append(warnings, routeWarnings...)
+		if err != nil {
+			recordFailure(ctx, org, space, si, err, resultDetails(start, i.Registry.MigratorName(si))...)
+			return err
+		}
+	}
+
+	if hasMetadata(si) && i.ClientHolder != nil {
+		if err = applyMetadata(i.ClientHolder.TargetCFClient(), org, space, si); err != nil {
+			recordFailure(ctx, org, space, si, err, resultDetails(start, i.Registry.MigratorName(si))...)
+			return err
+		}
+	}
+
+	completeInstance(tracker, si.Name)
+
 	log.Debugf("Finished importing %q", si.Name)
 
 	if summary, ok := config.SummaryFromContext(ctx); ok {
-		summary.AddSuccessfulService(org, space, si.Name, si.Service)
+		opts := append(resultDetails(start, i.Registry.MigratorName(si)), report.WithWarnings(warnings...))
+		summary.AddSuccessfulService(org, space, si.Name, si.Service, opts...)
 	}
 
 	return nil
 }
+
+// applyMappings sets the service label and plan the config maps a managed service instance to as the ones to create
+// on the target. The exported label is kept, so the migrator and the --services filter still use it.
+func applyMappings(ctx context.Context, org string, si *cf.ServiceInstance) {
+	cfg, ok := config.FromContext(ctx)
+	if !ok || ServiceType(si.Type) != ManagedService {
+		return
+	}
+
+	service, plan := cfg.MapService(org, si.Service, si.Plan)
+	if service != si.Service || plan != si.Plan {
+		log.Debugf("Mapping %q from service %q plan %q to service %q plan %q", si.Name, si.Service, si.Plan, service, plan)
+		si.TargetService, si.TargetPlan = service, plan
+	}
+}
@@ -15,19 +15,82 @@
 package migrate_test
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/journal"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/limit"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/report"
+	"net/url"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/cloudfoundry-community/go-cfclient"
 	"github.com/stretchr/testify/require"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	cffakes "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf/fakes"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/fakes"
 )
 
 func TestManagedServiceInstanceImporter_ImportManagedService(t *testing.T) {
+	j, err := journal.Open(filepath.Join(t.TempDir(), journal.ImportFile), false)
+	require.NoError(t, err)
+	require.NoError(t, j.Track("some-org", "some-space", "already-imported").Complete())
+
+	busy := limit.New(0, map[string]int{"mysql": 1})
+	_, err = busy.Acquire(context.TODO(), "mysql")
+	require.NoError(t, err)
+	canceled, cancel := context.WithCancel(config.ContextWithLimiter(context.TODO(), busy))
+	cancel()
+	waitingMigrator := &fakes.FakeServiceInstanceMigrator{}
+
+	summary := report.NewSummary(&bytes.Buffer{})
+	targetClient := new(cffakes.FakeClient)
+	targetClient.GetOrgByNameStub = func(name string) (cfclient.Org, error) {
+		if name == "missing-org" {
+			return cfclient.Org{}, cfclient.NewOrganizationNotFoundError()
+		}
+		if name == "broken-org" {
+			return cfclient.Org{}, errors.New("connection refused")
+		}
+		return cfclient.Org{Guid: name + "-guid", Name: name}, nil
+	}
+	targetClient.GetSpaceByNameStub = func(name string, orgGUID string) (cfclient.Space, error) {
+		return cfclient.Space{Guid: name + "-guid", Name: name, OrganizationGuid: orgGUID}, nil
+	}
+	targetClient.ListServiceInstancesByQueryReturns([]cfclient.ServiceInstance{{Guid: "target-si-guid", Name: "mysqldb"}}, nil)
+	targetClient.ListSharedSpacesReturns([]cf.SharedSpace{{Org: "some-org", Space: "already-shared"}}, nil)
+	targetClient.ListServiceBindingsByQueryReturns([]cfclient.ServiceBinding{
+		{Guid: "target-binding-1", AppGuid: "app-1-guid"},
+		{Guid: "target-binding-2", AppGuid: "app-2-guid"},
+	}, nil)
+	targetClient.GetAppByGuidNoInlineCallStub = func(guid string) (cfclient.App, error) {
+		return cfclient.App{Guid: guid, Name: strings.TrimSuffix(guid, "-guid")}, nil
+	}
+	targetClient.ListRouteBindingsReturns([]cf.RouteBinding{{Host: "auth", Domain: "apps.cf2.example.com"}}, nil)
+	targetClient.FindRouteStub = func(host, domain, path string) (string, error) {
+		if host == "missing" {
+			return "", nil
+		}
+		return host + "." + domain + path, nil
+	}
+	routeSummary := report.NewSummary(&bytes.Buffer{})
+	routeCtx := config.ContextWithSummary(config.ContextWithConfig(context.TODO(), &config.Config{
+		DomainsToReplace: map[string]string{"apps.cf1.example.com": "apps.cf2.example.com"},
+	}), routeSummary)
+	targetClient.ListServiceKeysByQueryStub = func(query url.Values) ([]cfclient.ServiceKey, error) {
+		if query.Get("names") == "some-key" {
+			return []cfclient.ServiceKey{{Guid: "target-key", Name: "some-key"}}, nil
+		}
+		return nil, nil
+	}
+
 	type fields struct {
-		Registry *fakes.FakeMigratorRegistry
+		Registry     *fakes.FakeMigratorRegistry
+		ClientHolder *fakes.FakeClientHolder
 	}
 	type args struct {
 		ctx       context.Context
@@ -85,12 +148,312 @@ func TestManagedServiceInstanceImporter_ImportManagedService(t *testing.T) {
 				}, si)
 			},
 		},
+		{
+			name: "skips a service instance imported in a previous run",
+			fields: fields{
+				Registry: &fakes.FakeMigratorRegistry{},
+			},
+			args: args{
+				ctx:   config.ContextWithJournal(context.TODO(), j),
+				org:   "some-org",
+				space: "some-space",
+				instance: &cf.ServiceInstance{
+					Name:    "already-imported",
+					GUID:    "some-guid",
+					Type:    "managed_service_instance",
+					Service: "p.mysql",
+				},
+				importDir: "/path/to/import-dir",
+			},
+			wantErr: false,
+			afterFunc: func(t *testing.T, fields fields) {
+				require.Equal(t, 0, fields.Registry.LookupCallCount())
+			},
+		},
+		{
+			name: "records a successful import in the journal",
+			fields: fields{
+				Registry: &fakes.FakeMigratorRegistry{
+					LookupStub: func(org string, space string, instance *cf.ServiceInstance, om config.OpsManager, dir string, isExport bool) (migrate.ServiceInstanceMigrator, bool, error) {
+						return &fakes.FakeServiceInstanceMigrator{}, true, nil
+					},
+				},
+			},
+			args: args{
+				ctx:   config.ContextWithJournal(context.TODO(), j),
+				org:   "some-org",
+				space: "some-space",
+				instance: &cf.ServiceInstance{
+					Name:    "newly-imported",
+					GUID:    "some-guid",
+					Type:    "managed_service_instance",
+					Service: "p.mysql",
+				},
+				importDir: "/path/to/import-dir",
+			},
+			wantErr: false,
+			afterFunc: func(t *testing.T, fields fields) {
+				require.Equal(t, 1, fields.Registry.LookupCallCount())
+				require.True(t, j.Track("some-org", "some-space", "newly-imported").Completed())
+			},
+		},
+		{
+			name: "waits for a free slot of the migrator",
+			fields: fields{
+				Registry: &fakes.FakeMigratorRegistry{
+					LookupStub: func(org string, space string, instance *cf.ServiceInstance, om config.OpsManager, dir string, isExport bool) (migrate.ServiceInstanceMigrator, bool, error) {
+						return waitingMigrator, true, nil
+					},
+					MigratorNameStub: func(instance *cf.ServiceInstance) string {
+						return "mysql"
+					},
+				},
+			},
+			args: args{
+				ctx:   canceled,
+				org:   "some-org",
+				space: "some-space",
+				instance: &cf.ServiceInstance{
+					Name:    "mysqldb",
+					GUID:    "some-guid",
+					Type:    "managed_service_instance",
+					Service: "p.mysql",
+				},
+				importDir: "/path/to/import-dir",
+			},
+			wantErr: true,
+			afterFunc: func(t *testing.T, fields fields) {
+				require.Equal(t, 0, waitingMigrator.MigrateCallCount())
+			},
+		},
+		{
+			name: "looks up the migrator by the exported service and creates the mapped service and plan",
+			fields: fields{
+				Registry: &fakes.FakeMigratorRegistry{
+					LookupStub: func(org string, space string, instance *cf.ServiceInstance, om config.OpsManager, dir string, isExport bool) (migrate.ServiceInstanceMigrator, bool, error) {
+						return &fakes.FakeServiceInstanceMigrator{}, true, nil
+					},
+				},
+			},
+			args: args{
+				ctx: config.ContextWithConfig(context.TODO(), &config.Config{
+					Mappings: []config.Mapping{
+						{Service: "p-mysql", TargetService: "p.mysql"},
+						{Org: "some-org", Service: "p-mysql", Plan: "100mb", TargetPlan: "db-small"},
+					},
+				}),
+				org:   "some-org",
+				space: "some-space",
+				instance: &cf.ServiceInstance{
+					Name:    "mysqldb",
+					GUID:    "some-guid",
+					Type:    "managed_service_instance",
+					Service: "p-mysql",
+					Plan:    "100mb",
+				},
+				importDir: "/path/to/import-dir",
+			},
+			wantErr: false,
+			afterFunc: func(t *testing.T, fields fields) {
+				_, _, si, _, _, _ := fields.Registry.LookupArgsForCall(0)
+				require.Equal(t, "p-mysql", si.Service)
+				require.Equal(t, "100mb", si.Plan)
+				require.Equal(t, "p.mysql", si.ServiceToCreate())
+				require.Equal(t, "db-small", si.PlanToCreate())
+			},
+		},
+		{
+			name: "shares the imported instance into the spaces found on the target",
+			fields: fields{
+				Registry: &fakes.FakeMigratorRegistry{
+					LookupStub: func(org string, space string, instance *cf.ServiceInstance, om config.OpsManager, dir string, isExport bool) (migrate.ServiceInstanceMigrator, bool, error) {
+						return &fakes.FakeServiceInstanceMigrator{}, true, nil
+					},
+				},
+				ClientHolder: &fakes.FakeClientHolder{
+					TargetCFClientStub: func() cf.Client {
+						return targetClient
+					},
+				},
+			},
+			args: args{
+				ctx:   config.ContextWithSummary(context.TODO(), summary),
+				org:   "some-org",
+				space: "some-space",
+				instance: &cf.ServiceInstance{
+					Name:    "mysqldb",
+					GUID:    "some-guid",
+					Type:    "managed_service_instance",
+					Service: "p.mysql",
+					SharedSpaces: []cf.SharedSpace{
+						{Org: "some-org", Space: "already-shared"},
+						{Org: "some-org", Space: "other-space"},
+						{Org: "missing-org", Space: "some-space"},
+					},
+				},
+				importDir: "/path/to/import-dir",
+			},
+			wantErr: false,
+			afterFunc: func(t *testing.T, fields fields) {
+				require.Equal(t, 1, targetClient.ShareServiceInstanceCallCount())
+				guid, spaceGUIDs := targetClient.ShareServiceInstanceArgsForCall(0)
+				require.Equal(t, "target-si-guid", guid)
+				require.Equal(t, []string{"other-space-guid"}, spaceGUIDs)
+
+				results := summary.Results()
+				require.Len(t, results, 1)
+				require.Equal(t, report.Successful, results[0].Status)
+				require.Equal(t, []string{`not shared into missing-org/some-space: org "missing-org" not found`}, results[0].Warnings)
+			},
+		},
+		{
+			name: "fails when a shared org can't be looked up",
+			fields: fields{
+				Registry: &fakes.FakeMigratorRegistry{
+					LookupStub: func(org string, space string, instance *cf.ServiceInstance, om config.OpsManager, dir string, isExport bool) (migrate.ServiceInstanceMigrator, bool, error) {
+						return &fakes.FakeServiceInstanceMigrator{}, true, nil
+					},
+				},
+				ClientHolder: &fakes.FakeClientHolder{
+					TargetCFClientStub: func() cf.Client {
+						return targetClient
+					},
+				},
+			},
+			args: args{
+				ctx:   config.ContextWithSummary(context.TODO(), report.NewSummary(&bytes.Buffer{})),
+				org:   "some-org",
+				space: "some-space",
+				instance: &cf.ServiceInstance{
+					Name:         "mysqldb",
+					GUID:         "some-guid",
+					Type:         "managed_service_instance",
+					Service:      "p.mysql",
+					SharedSpaces: []cf.SharedSpace{{Org: "broken-org", Space: "some-space"}},
+				},
+				importDir: "/path/to/import-dir",
+			},
+			wantErr: true,
+			afterFunc: func(t *testing.T, fields fields) {
+				require.Equal(t, 1, targetClient.ShareServiceInstanceCallCount())
+			},
+		},
+		{
+			name: "binds the imported route service to the routes found on the target",
+			fields: fields{
+				Registry: &fakes.FakeMigratorRegistry{
+					LookupStub: func(org string, space string, instance *cf.ServiceInstance, om config.OpsManager, dir string, isExport bool) (migrate.ServiceInstanceMigrator, bool, error) {
+						return &fakes.FakeServiceInstanceMigrator{}, true, nil
+					},
+				},
+				ClientHolder: &fakes.FakeClientHolder{
+					TargetCFClientStub: func() cf.Client {
+						return targetClient
+					},
+				},
+			},
+			args: args{
+				ctx:   routeCtx,
+				org:   "some-org",
+				space: "some-space",
+				instance: &cf.ServiceInstance{
+					Name:            "mysqldb",
+					GUID:            "some-guid",
+					Type:            "managed_service_instance",
+					Service:         "p.mysql",
+					RouteServiceUrl: "https://proxy.apps.cf2.example.com",
+					RouteBindings: []cf.RouteBinding{
+						{Host: "auth", Domain: "apps.cf1.example.com"},
+						{Host: "admin", Domain: "apps.cf1.example.com", Path: "/console"},
+						{Host: "missing", Domain: "apps.cf1.example.com"},
+					},
+				},
+				importDir: "/path/to/import-dir",
+			},
+			wantErr: false,
+			afterFunc: func(t *testing.T, fields fields) {
+				require.Equal(t, "target-si-guid", targetClient.ListRouteBindingsArgsForCall(0))
+				require.Equal(t, 2, targetClient.FindRouteCallCount())
+				host, domain, path := targetClient.FindRouteArgsForCall(0)
+				require.Equal(t, []string{"admin", "apps.cf2.example.com", "/console"}, []string{host, domain, path})
+
+				require.Equal(t, 1, targetClient.CreateRouteBindingCallCount())
+				routeGUID, guid := targetClient.CreateRouteBindingArgsForCall(0)
+				require.Equal(t, "admin.apps.cf2.example.com/console", routeGUID)
+				require.Equal(t, "target-si-guid", guid)
+
+				results := routeSummary.Results()
+				require.Len(t, results, 1)
+				require.Equal(t, report.Successful, results[0].Status)
+				require.Equal(t, []string{"not bound to route missing.apps.cf2.example.com: route not found"}, results[0].Warnings)
+			},
+		},
+		{
+			name: "adds the exported labels and annotations to the imported instance, its bindings and its keys",
+			fields: fields{
+				Registry: &fakes.FakeMigratorRegistry{
+					LookupStub: func(org string, space string, instance *cf.ServiceInstance, om config.OpsManager, dir string, isExport bool) (migrate.ServiceInstanceMigrator, bool, error) {
+						return &fakes.FakeServiceInstanceMigrator{}, true, nil
+					},
+				},
+				ClientHolder: &fakes.FakeClientHolder{
+					TargetCFClientStub: func() cf.Client {
+						return targetClient
+					},
+				},
+			},
+			args: args{
+				ctx:   context.TODO(),
+				org:   "some-org",
+				space: "some-space",
+				instance: &cf.ServiceInstance{
+					Name:        "mysqldb",
+					GUID:        "some-guid",
+					Type:        "managed_service_instance",
+					Service:     "p.mysql",
+					Labels:      map[string]string{"team": "payments"},
+					Annotations: map[string]string{"owner": "payments@example.com"},
+					ServiceBindings: []cf.ServiceBinding{
+						{Guid: "binding-1", Labels: map[string]string{"tier": "web"}},
+						{Guid: "binding-2"},
+						{Guid: "binding-3", Labels: map[string]string{"tier": "worker"}},
+					},
+					ServiceKeys: []cf.ServiceKey{
+						{Name: "some-key", Annotations: map[string]string{"rotated": "2022-10-01"}},
+						{Name: "ignored-key", Annotations: map[string]string{"rotated": "2022-09-01"}},
+					},
+					Apps: map[string]string{"binding-1": "app-1", "binding-2": "app-2", "binding-3": "app-3"},
+				},
+				importDir: "/path/to/import-dir",
+			},
+			wantErr: false,
+			afterFunc: func(t *testing.T, fields fields) {
+				require.Equal(t, 1, targetClient.UpdateServiceInstanceMetadataCallCount())
+				guid, m := targetClient.UpdateServiceInstanceMetadataArgsForCall(0)
+				require.Equal(t, "target-si-guid", guid)
+				require.Equal(t, cf.Metadata{
+					Labels:      map[string]string{"team": "payments"},
+					Annotations: map[string]string{"owner": "payments@example.com"},
+				}, m)
+
+				require.Equal(t, 2, targetClient.UpdateServiceCredentialBindingMetadataCallCount())
+				guid, m = targetClient.UpdateServiceCredentialBindingMetadataArgsForCall(0)
+				require.Equal(t, "target-binding-1", guid)
+				require.Equal(t, cf.Metadata{Labels: map[string]string{"tier": "web"}}, m)
+				guid, m = targetClient.UpdateServiceCredentialBindingMetadataArgsForCall(1)
+				require.Equal(t, "target-key", guid)
+				require.Equal(t, cf.Metadata{Annotations: map[string]string{"rotated": "2022-10-01"}}, m)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			i := migrate.ManagedServiceInstanceImporter{
 				Registry: tt.fields.Registry,
 			}
+			if tt.fields.ClientHolder != nil {
+				i.ClientHolder = tt.fields.ClientHolder
+			}
 			if err := i.ImportManagedService(tt.args.ctx, tt.args.org, tt.args.space, tt.args.instance, tt.args.om, tt.args.importDir); (err != nil) != tt.wantErr {
 				t.Errorf("ImportManagedService() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -0,0 +1,85 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package migrate
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-community/go-cfclient"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
+)
+
+// shareInstance shares the imported service instance into the same org and space names it was shared
+// into on the source foundation. Shares into orgs or spaces that don't exist on the target are not
+// recreated and are returned as warnings instead.
+func shareInstance(client cf.Client, orgName, spaceName string, instance *cf.ServiceInstance) ([]string, error) {
+	_, _, guid, err := findTargetServiceInstance(client, orgName, spaceName, instance)
+	if err != nil {
+		return nil, err
+	}
+
+	shared, err := client.ListSharedSpaces(guid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up shared spaces of %q: %w", instance.Name, err)
+	}
+	alreadyShared := make(map[cf.SharedSpace]bool, len(shared))
+	for _, s := range shared {
+		alreadyShared[s] = true
+	}
+
+	var warnings []string
+	var spaceGUIDs []string
+	for _, s := range instance.SharedSpaces {
+		if alreadyShared[s] {
+			log.Debugf("Service instance %q is already shared into %s/%s", instance.Name, s.Org, s.Space)
+			continue
+		}
+
+		org, err := client.GetOrgByName(s.Org)
+		if err != nil && !cfclient.IsOrganizationNotFoundError(err) {
+			return nil, fmt.Errorf("failed to look up org %q: %w", s.Org, err)
+		}
+		if err != nil || org.Guid == "" {
+			warnings = append(warnings, fmt.Sprintf("not shared into %s/%s: org %q not found", s.Org, s.Space, s.Org))
+			continue
+		}
+
+		space, err := client.GetSpaceByName(s.Space, org.Guid)
+		if err != nil && !cfclient.IsSpaceNotFoundError(err) {
+			return nil, fmt.Errorf("failed to look up space %q in org %q: %w", s.Space, s.Org, err)
+		}
+		if err != nil || space.Guid == "" {
+			warnings = append(warnings, fmt.Sprintf("not shared into %s/%s: space %q not found", s.Org, s.Space, s.Space))
+			continue
+		}
+
+		spaceGUIDs = append(spaceGUIDs, space.Guid)
+	}
+
+	for _, w := range warnings {
+		log.Warnf("Service instance %q %s", instance.Name, w)
+	}
+
+	if len(spaceGUIDs) == 0 {
+		return warnings, nil
+	}
+
+	if err = client.ShareServiceInstance(guid, spaceGUIDs); err != nil {
+		return warnings, fmt.Errorf("failed to share %q: %w", instance.Name, err)
+	}
+	log.Infof("Shared %q into %d space(s)", instance.Name, len(spaceGUIDs))
+
+	return warnings, nil
+}
@@ -21,7 +21,6 @@ import (
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
 
 	"github.com/cloudfoundry-community/go-cfclient"
-	"golang.org/x/sync/errgroup"
 
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
 )
@@ -39,7 +38,7 @@ func NewSpaceExporter(e ServiceInstanceExporter, h ClientHolder) *SpaceExporter
 }
 
 func (e SpaceExporter) Export(ctx context.Context, om config.OpsManager, dir string, orgName, spaceName string) error {
-	g, gctx := errgroup.WithContext(ctx)
+	g, gctx := newGroup(ctx)
 	client := e.ClientHolder.SourceCFClient()
 
 	org, err := client.GetOrgByName(orgName)
@@ -66,6 +65,12 @@ func (e SpaceExporter) Export(ctx context.Context, om config.OpsManager, dir str
 		})
 	}(org, space)
 
+	func(o cfclient.Org, s cfclient.Space) {
+		g.Go(func() error {
+			return e.ExportOrgSpace(gctx, o, s, dir)
+		})
+	}(org, space)
+
 	log.Debugln("Waiting for export to finish...")
 	err = g.Wait()
 	if err != nil {
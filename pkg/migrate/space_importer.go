@@ -19,30 +19,34 @@ import (
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/encryption"
 	sio "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/io"
-	"golang.org/x/sync/errgroup"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
 )
 
 type SpaceImporter struct {
 	ServiceInstanceImporter
+	ClientHolder ClientHolder
 }
 
-func NewSpaceImporter(serviceInstanceImporter ServiceInstanceImporter) *SpaceImporter {
+func NewSpaceImporter(serviceInstanceImporter ServiceInstanceImporter, h ClientHolder) *SpaceImporter {
 	return &SpaceImporter{
 		ServiceInstanceImporter: serviceInstanceImporter,
+		ClientHolder:            h,
 	}
 }
 
 func (i SpaceImporter) Import(ctx context.Context, om config.OpsManager, dir string, orgName, spaceName string) error {
-	serviceInstanceMap, err := i.createServiceInstances(dir)
+	serviceInstanceMap, err := i.createServiceInstances(ctx, dir)
 	if err != nil {
 		return err
 	}
 
-	g, gctx := errgroup.WithContext(ctx)
+	g, gctx := newGroup(ctx)
 	for org, spaces := range serviceInstanceMap {
 		for space, instances := range spaces {
 			importInstances := func(ctx context.Context, org, space string, instances []*cf.ServiceInstance, dir string) {
@@ -64,6 +68,10 @@ func (i SpaceImporter) Import(ctx context.Context, om config.OpsManager, dir str
 				}
 			}
 			if org == orgName && space == spaceName {
+				if err := i.createOrgSpace(gctx, org, space, instances, dir); err != nil {
+					g.Go(func() error { return err })
+					continue
+				}
 				importInstances(gctx, org, space, instances, dir)
 			}
 		}
@@ -71,13 +79,52 @@ func (i SpaceImporter) Import(ctx context.Context, om config.OpsManager, dir str
 	return g.Wait()
 }
 
-func (i SpaceImporter) createServiceInstances(dir string, orgNames ...string) (map[string]map[string][]*cf.ServiceInstance, error) {
+// createOrgSpace creates the org and space on the target when create_missing_orgs_spaces is set, recording a
+// failure for each of the instances to import into them if that fails
+func (i SpaceImporter) createOrgSpace(ctx context.Context, org, space string, instances []*cf.ServiceInstance, dir string) error {
+	cfg, ok := config.FromContext(ctx)
+	if !ok || !cfg.CreateMissingOrgsSpaces {
+		return nil
+	}
+
+	orgs, err := readOrgSpaces(ctx, dir)
+	if err == nil {
+		err = createMissingOrgSpace(ctx, i.ClientHolder.TargetCFClient(), org, space, orgs)
+	}
+	if err != nil {
+		for _, si := range instances {
+			if i.shouldMigrate(ctx, si) {
+				recordFailure(ctx, org, space, si, err)
+			}
+		}
+	}
+
+	return err
+}
+
+func (i SpaceImporter) createServiceInstances(ctx context.Context, dir string, orgNames ...string) (map[string]map[string][]*cf.ServiceInstance, error) {
+	return readServiceInstances(ctx, dir, orgNames...)
+}
+
+// readServiceInstances parses the service instances exported to dir, keyed by org and space. Encrypted files are
+// decrypted with the keys in the config stored in ctx.
+func readServiceInstances(ctx context.Context, dir string, orgNames ...string) (map[string]map[string][]*cf.ServiceInstance, error) {
+	parser := sio.NewParser(sio.WithCipher(encryption.FromContext(ctx)))
 	orgs := make(map[string]map[string][]*cf.ServiceInstance)
 	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 		if !info.IsDir() {
+			// service instances are only exported to <dir>/<org>/<space>, skip the journals, manifests and
+			// backups kept elsewhere in the export directory
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			if len(strings.Split(rel, string(os.PathSeparator))) != 3 {
+				return nil
+			}
 			fd, err := sio.NewFileDescriptor(path)
 			var invalidExtErr *sio.InvalidFileExtensionError
 			if err != nil {
@@ -87,10 +134,11 @@ func (i SpaceImporter) createServiceInstances(dir string, orgNames ...string) (m
 				return err
 			}
 			instance := &cf.ServiceInstance{}
-			err = sio.NewParser().Unmarshal(instance, fd)
+			err = parser.Unmarshal(instance, fd)
 			if err != nil {
 				return err
 			}
+			log.RegisterSecrets(instance.BackupEncryptionKey)
 
 			addInstance := func(org, space string, instance *cf.ServiceInstance) {
 				if vo, ok := orgs[org]; ok {
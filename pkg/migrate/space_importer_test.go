@@ -16,6 +16,7 @@ package migrate_test
 
 import (
 	"context"
+	"errors"
 	"github.com/stretchr/testify/require"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
@@ -23,6 +24,7 @@ import (
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/fakes"
 	"io"
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 )
@@ -33,6 +35,7 @@ func TestNewImporter(t *testing.T) {
 		skipUpdate bool
 		writer     io.Writer
 		sii        *fakes.FakeServiceInstanceImporter
+		holder     *fakes.FakeClientHolder
 	}
 	tests := []struct {
 		name string
@@ -46,15 +49,17 @@ func TestNewImporter(t *testing.T) {
 				skipUpdate: false,
 				writer:     nil,
 				sii:        new(fakes.FakeServiceInstanceImporter),
+				holder:     new(fakes.FakeClientHolder),
 			},
 			want: &migrate.SpaceImporter{
 				ServiceInstanceImporter: new(fakes.FakeServiceInstanceImporter),
+				ClientHolder:            new(fakes.FakeClientHolder),
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := migrate.NewSpaceImporter(tt.args.sii); !reflect.DeepEqual(got, tt.want) {
+			if got := migrate.NewSpaceImporter(tt.args.sii, tt.args.holder); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("NewSpaceImporter() = %v, want %v", got, tt.want)
 			}
 		})
@@ -63,6 +68,16 @@ func TestNewImporter(t *testing.T) {
 
 func TestSpaceImporter_Import(t *testing.T) {
 	pwd, _ := os.Getwd()
+	jsonDir := t.TempDir()
+	for file, content := range map[string]string{
+		filepath.Join("cloudfoundry", "test-app", "sql-test.json"): `{"schema_version": 1, "name": "sql-test", "type": "managed_service_instance", "service": "SQLServer", "plan": "sharedVM"}`,
+		migrate.OrgSpacesFile: `[]`,
+		filepath.Join("8d756e69-d9a9-4d6a-ad97-0984381a68da", "definitions.json"):                   `{"queues": []}`,
+		filepath.Join(".rollback", "import", "cloudfoundry", "test-app", "8d756e69-d9a9-4d6a.json"): `{}`,
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(jsonDir, file)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(jsonDir, file), []byte(content), 0644))
+	}
 	type fields struct {
 		skipUpdate              bool
 		serviceInstanceImporter *fakes.FakeServiceInstanceImporter
@@ -128,6 +143,59 @@ func TestSpaceImporter_Import(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "import service instances exported as json",
+			fields: fields{
+				skipUpdate:              false,
+				serviceInstanceImporter: new(fakes.FakeServiceInstanceImporter),
+			},
+			args: args{
+				dir:   jsonDir,
+				org:   "cloudfoundry",
+				space: "test-app",
+				om: config.OpsManager{
+					Hostname: "opsman.url.com",
+				},
+				cfg: &config.Config{},
+			},
+			afterFunc: func(i *fakes.FakeServiceInstanceImporter) {
+				require.Equal(t, 1, i.ImportManagedServiceCallCount())
+				_, _, _, si, _, _ := i.ImportManagedServiceArgsForCall(0)
+				require.Equal(t, "sql-test", si.Name)
+				require.Equal(t, "sharedVM", si.Plan)
+			},
+			wantErr: false,
+		},
+		{
+			name: "keeps importing after a failed instance when continuing on error",
+			fields: fields{
+				skipUpdate: false,
+				serviceInstanceImporter: &fakes.FakeServiceInstanceImporter{
+					ImportManagedServiceStub: func(ctx context.Context, org string, space string, si *cf.ServiceInstance, om config.OpsManager, dir string) error {
+						if si.Name == "sql-test" {
+							return errors.New("restore failed")
+						}
+						return ctx.Err()
+					},
+				},
+			},
+			args: args{
+				dir:   pwd + "/testdata",
+				org:   "cloudfoundry",
+				space: "test-app",
+				om: config.OpsManager{
+					Hostname: "opsman.url.com",
+				},
+				cfg: &config.Config{
+					ContinueOnError: true,
+					MaxParallel:     1,
+				},
+			},
+			afterFunc: func(i *fakes.FakeServiceInstanceImporter) {
+				require.Equal(t, 2, i.ImportManagedServiceCallCount())
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,63 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package migrate
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/log"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/journal"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/report"
+)
+
+var errAlreadyMigrated = errors.New("already migrated in a previous run")
+
+// trackInstance returns a context carrying a journal tracker for the instance, if a journal is being kept
+func trackInstance(ctx context.Context, org, space, name string) (context.Context, *journal.Tracker) {
+	j, ok := config.JournalFromContext(ctx)
+	if !ok {
+		return ctx, nil
+	}
+	t := j.Track(org, space, name)
+	return config.ContextWithTracker(ctx, t), t
+}
+
+func completeInstance(t *journal.Tracker, name string) {
+	if t == nil {
+		return
+	}
+	if err := t.Complete(); err != nil {
+		log.Warnf("failed to record %q as completed in journal: %v", name, err)
+	}
+}
+
+// resultDetails records how long a service instance has been migrating and which migrator ran it
+func resultDetails(start time.Time, migrator string) []report.ResultOption {
+	return []report.ResultOption{
+		report.WithDuration(time.Since(start)),
+		report.WithMigrator(migrator),
+	}
+}
+
+// recordFailure adds a service instance that failed before its migrator ran to the summary
+func recordFailure(ctx context.Context, org, space string, si *cf.ServiceInstance, err error, opts ...report.ResultOption) {
+	if summary, ok := config.SummaryFromContext(ctx); ok {
+		summary.AddFailedService(org, space, si.Name, si.Service, err, opts...)
+	}
+}
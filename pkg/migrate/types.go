@@ -20,6 +20,7 @@ import (
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/bosh"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/credhub"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/io"
 	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/om"
 )
@@ -31,6 +32,7 @@ import (
 type ServiceInstanceExporter interface {
 	ExportManagedServices(ctx context.Context, org cfclient.Org, space cfclient.Space, om config.OpsManager, dir string) error
 	ExportUserProvidedServices(ctx context.Context, org cfclient.Org, space cfclient.Space, dir string) error
+	ExportOrgSpace(ctx context.Context, org cfclient.Org, space cfclient.Space, dir string) error
 }
 
 //counterfeiter:generate -o fakes . ServiceInstanceImporter
@@ -44,12 +46,14 @@ type ServiceInstanceImporter interface {
 type ServiceInstanceParser interface {
 	Unmarshal(out interface{}, fd io.FileDescriptor) error
 	Marshal(in interface{}, fd io.FileDescriptor) error
+	MarshalUnversioned(in interface{}, fd io.FileDescriptor) error
 }
 
 //counterfeiter:generate -o fakes . MigratorRegistry
 
 type MigratorRegistry interface {
 	Lookup(org, space string, si *cf.ServiceInstance, om config.OpsManager, dir string, isExport bool) (ServiceInstanceMigrator, bool, error)
+	MigratorName(si *cf.ServiceInstance) string
 }
 
 //counterfeiter:generate -o fakes . Validator
@@ -75,6 +79,9 @@ type ClientHolder interface {
 	SourceCFClient() cf.Client
 	TargetCFClient() cf.Client
 	CFClient(toSource bool) cf.Client
+	BoshClient(toSource bool) bosh.Client
+	CredhubClient(toSource bool) credhub.Client
+	RuntimeCredhubClient(toSource bool) (credhub.Client, error)
 }
 
 //counterfeiter:generate -o fakes . Factory
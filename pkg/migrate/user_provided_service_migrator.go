@@ -66,6 +66,10 @@ func NewUserProvidedServiceFlow(org, space string, h ClientHolder, instance *cf.
 			CreateUserProvidedService(org, space, instance, h, isExport),
 			flow.WithDisplay("Creating user provided service"),
 		),
+		flow.StepWithProgressBar(
+			BindApps(org, space, instance, h, isExport),
+			flow.WithDisplay("Binding apps"),
+		),
 	)
 }
 
@@ -106,6 +110,7 @@ func CreateUserProvidedService(orgName, spaceName string, instance *cf.ServiceIn
 
 		creds := map[string]interface{}{}
 		if len(instance.Credentials) > 0 {
+			log.RegisterCredentials(instance.Credentials)
 			for k, v := range instance.Credentials {
 				creds[k] = v
 				if val, ok := v.(string); ok {
@@ -113,14 +118,16 @@ func CreateUserProvidedService(orgName, spaceName string, instance *cf.ServiceIn
 				}
 			}
 			instance.Credentials = creds
+			// the replaced domains may have turned a registered secret into a new value
+			log.RegisterCredentials(instance.Credentials)
 			log.Debugf("Set creds to %v", instance.Credentials)
 		}
 
-		ups, err := client.ListUserProvidedServiceInstancesByQuery(url.Values{"q": []string{
-			fmt.Sprintf("organization_guid:%s", org.Guid),
-			fmt.Sprintf("space_guid:%s", space.Guid),
-			fmt.Sprintf("name:%s", instance.Name),
-		}})
+		ups, err := client.ListUserProvidedServiceInstancesByQuery(url.Values{
+			"organization_guids": []string{org.Guid},
+			"space_guids":        []string{space.Guid},
+			"names":              []string{instance.Name},
+		})
 		if err != nil {
 			log.Errorf("Error looking up user provided services by name: %q in %s/%s, %v", instance.Name, orgName, spaceName, err)
 			ups = []cfclient.UserProvidedServiceInstance{}
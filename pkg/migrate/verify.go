@@ -0,0 +1,460 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package migrate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cloudfoundry-community/go-cfclient"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+)
+
+const (
+	InstanceCheck    = "instance"
+	TagsCheck        = "tags"
+	ParametersCheck  = "parameters"
+	BindingsCheck    = "bindings"
+	ServiceKeysCheck = "service keys"
+	URLsCheck        = "urls"
+)
+
+// VerifyCheck is the outcome of comparing one property of an exported service instance with the target foundation
+type VerifyCheck struct {
+	Name    string
+	Passed  bool
+	Message string
+}
+
+// VerifyResult holds the comparisons made for a single exported service instance
+type VerifyResult struct {
+	Org     string
+	Space   string
+	Name    string
+	Service string
+	Plan    string
+	Checks  []VerifyCheck
+}
+
+// Failed returns true if the service instance on the target foundation differs from the export
+func (r VerifyResult) Failed() bool {
+	for _, c := range r.Checks {
+		if !c.Passed {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifyReport holds the verification results of every exported service instance
+type VerifyReport []VerifyResult
+
+// Failed returns true if any service instance was not fully migrated
+func (r VerifyReport) Failed() bool {
+	for _, res := range r {
+		if res.Failed() {
+			return true
+		}
+	}
+	return false
+}
+
+// Display writes the report as a table, one row per service instance listing the checks that failed
+func (r VerifyReport) Display(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 10, 2, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "Org\tSpace\tName\tService\tResult")
+	for _, res := range r {
+		result := "passed"
+		var failures []string
+		for _, c := range res.Checks {
+			if !c.Passed {
+				failures = append(failures, c.Name+": "+c.Message)
+			}
+		}
+		if len(failures) > 0 {
+			result = "failed: " + strings.Join(failures, "; ")
+		}
+		row := []string{
+			res.Org,
+			res.Space,
+			res.Name,
+			res.Service,
+			result,
+		}
+		_, _ = fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	_ = tw.Flush()
+}
+
+// Verifier compares the service instances in an export directory with the target foundation after an import
+type Verifier struct {
+	ClientHolder   ClientHolder
+	MigratorHelper *MigratorHelper
+}
+
+func NewVerifier(h ClientHolder, mh *MigratorHelper) *Verifier {
+	return &Verifier{
+		ClientHolder:   h,
+		MigratorHelper: mh,
+	}
+}
+
+func (v *Verifier) Verify(ctx context.Context, dir string) (VerifyReport, error) {
+	instances, err := readServiceInstances(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, ok := config.FromContext(ctx)
+	if !ok {
+		cfg = &config.Config{}
+	}
+
+	client := v.ClientHolder.TargetCFClient()
+
+	var report VerifyReport
+	for org, spaces := range instances {
+		for space, sis := range spaces {
+			for _, si := range sis {
+				if si.Name == "" || si.Type == "" {
+					continue
+				}
+				applyMappings(ctx, org, si)
+				if !selected(cfg, v.MigratorHelper, si) {
+					continue
+				}
+				report = append(report, verifyInstance(client, cfg, org, space, si))
+			}
+		}
+	}
+
+	sort.SliceStable(report, func(i, j int) bool {
+		if report[i].Org != report[j].Org {
+			return report[i].Org < report[j].Org
+		}
+		if report[i].Space != report[j].Space {
+			return report[i].Space < report[j].Space
+		}
+		return report[i].Name < report[j].Name
+	})
+
+	return report, nil
+}
+
+// targetInstance is the state of a service instance on the target foundation that verify compares against
+type targetInstance struct {
+	guid            string
+	servicePlanGuid string
+	tags            []string
+	routeServiceUrl string
+	syslogDrainUrl  string
+}
+
+func verifyInstance(client cf.Client, cfg *config.Config, org, space string, si *cf.ServiceInstance) VerifyResult {
+	res := VerifyResult{
+		Org:     org,
+		Space:   space,
+		Name:    si.Name,
+		Service: si.ServiceToCreate(),
+		Plan:    si.PlanToCreate(),
+	}
+
+	target, check := findInstance(client, org, space, si)
+	res.Checks = append(res.Checks, check)
+	if target == nil {
+		return res
+	}
+
+	managed := ServiceType(si.Type) == ManagedService
+	if managed {
+		res.Checks = append(res.Checks, verifyServicePlan(client, si, target)...)
+	}
+
+	res.Checks = append(res.Checks, verifyTags(si, target))
+
+	if managed {
+		res.Checks = append(res.Checks, verifyParameters(client, si, target))
+	}
+
+	res.Checks = append(res.Checks, verifyBindings(client, si, target))
+
+	if managed && !cfg.IgnoreServiceKeys {
+		res.Checks = append(res.Checks, verifyServiceKeys(client, si, target))
+	}
+
+	if !managed {
+		res.Checks = append(res.Checks, verifyURLs(si, target, cfg.DomainsToReplace))
+	}
+
+	return res
+}
+
+func findInstance(client cf.Client, org, space string, si *cf.ServiceInstance) (*targetInstance, VerifyCheck) {
+	o, err := client.GetOrgByName(org)
+	if err != nil || o.Guid == "" {
+		return nil, VerifyCheck{Name: InstanceCheck, Message: fmt.Sprintf("org %q not found", org)}
+	}
+
+	s, err := client.GetSpaceByName(space, o.Guid)
+	if err != nil || s.Guid == "" {
+		return nil, VerifyCheck{Name: InstanceCheck, Message: fmt.Sprintf("space %q not found in org %q", space, org)}
+	}
+
+	query := url.Values{
+		"space_guids": []string{s.Guid},
+		"names":       []string{si.Name},
+	}
+
+	var target *targetInstance
+	if ServiceType(si.Type) == UserProvidedService {
+		ups, err := client.ListUserProvidedServiceInstancesByQuery(query)
+		if err != nil {
+			return nil, VerifyCheck{Name: InstanceCheck, Message: fmt.Sprintf("failed to look up user-provided-service: %v", err)}
+		}
+		if len(ups) > 0 {
+			target = &targetInstance{
+				guid:            ups[0].Guid,
+				tags:            ups[0].Tags,
+				routeServiceUrl: ups[0].RouteServiceUrl,
+				syslogDrainUrl:  ups[0].SyslogDrainUrl,
+			}
+		}
+	} else {
+		sis, err := client.ListServiceInstancesByQuery(query)
+		if err != nil {
+			return nil, VerifyCheck{Name: InstanceCheck, Message: fmt.Sprintf("failed to look up service instance: %v", err)}
+		}
+		if len(sis) > 0 {
+			target = &targetInstance{
+				guid:            sis[0].Guid,
+				servicePlanGuid: sis[0].ServicePlanGuid,
+				tags:            sis[0].Tags,
+			}
+		}
+	}
+
+	if target == nil {
+		return nil, VerifyCheck{Name: InstanceCheck, Message: fmt.Sprintf("not found in %s/%s", org, space)}
+	}
+	return target, VerifyCheck{Name: InstanceCheck, Passed: true}
+}
+
+func verifyServicePlan(client cf.Client, si *cf.ServiceInstance, target *targetInstance) []VerifyCheck {
+	plan, err := client.GetServicePlanByGUID(target.servicePlanGuid)
+	if err != nil || plan == nil {
+		msg := fmt.Sprintf("failed to look up the plan of the target instance: %v", err)
+		return []VerifyCheck{
+			{Name: ServiceOfferingCheck, Message: msg},
+			{Name: ServicePlanCheck, Message: msg},
+		}
+	}
+
+	serviceCheck := VerifyCheck{Name: ServiceOfferingCheck, Passed: true}
+	svc, err := client.GetServiceByGuid(plan.ServiceGuid)
+	if err != nil {
+		serviceCheck = VerifyCheck{Name: ServiceOfferingCheck, Message: fmt.Sprintf("failed to look up the service of the target instance: %v", err)}
+	} else if svc.Label != si.ServiceToCreate() {
+		serviceCheck = VerifyCheck{Name: ServiceOfferingCheck, Message: fmt.Sprintf("expected service %q, found %q", si.ServiceToCreate(), svc.Label)}
+	}
+
+	planCheck := VerifyCheck{Name: ServicePlanCheck, Passed: true}
+	if plan.Name != si.PlanToCreate() {
+		planCheck = VerifyCheck{Name: ServicePlanCheck, Message: fmt.Sprintf("expected plan %q, found %q", si.PlanToCreate(), plan.Name)}
+	}
+
+	return []VerifyCheck{serviceCheck, planCheck}
+}
+
+func verifyTags(si *cf.ServiceInstance, target *targetInstance) VerifyCheck {
+	expected := sortedValues(strings.Split(si.Tags, ","))
+	actual := sortedValues(target.tags)
+	if !reflect.DeepEqual(expected, actual) {
+		return VerifyCheck{Name: TagsCheck, Message: fmt.Sprintf("expected [%s], found [%s]", strings.Join(expected, ", "), strings.Join(actual, ", "))}
+	}
+	return VerifyCheck{Name: TagsCheck, Passed: true}
+}
+
+func verifyParameters(client cf.Client, si *cf.ServiceInstance, target *targetInstance) VerifyCheck {
+	params, err := client.GetServiceInstanceParams(target.guid)
+	if cfclient.IsServiceFetchInstanceParametersNotSupportedError(err) {
+		return VerifyCheck{Name: ParametersCheck, Passed: true, Message: "not retrievable from the broker"}
+	}
+	if err != nil {
+		return VerifyCheck{Name: ParametersCheck, Message: fmt.Sprintf("failed to fetch parameters: %v", err)}
+	}
+
+	expected, err := normalizeParams(si.Params)
+	if err != nil {
+		return VerifyCheck{Name: ParametersCheck, Message: fmt.Sprintf("failed to read exported parameters: %v", err)}
+	}
+	actual, err := normalizeParams(params)
+	if err != nil {
+		return VerifyCheck{Name: ParametersCheck, Message: fmt.Sprintf("failed to read target parameters: %v", err)}
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		return VerifyCheck{Name: ParametersCheck, Message: "parameters differ from the export"}
+	}
+	return VerifyCheck{Name: ParametersCheck, Passed: true}
+}
+
+func verifyBindings(client cf.Client, si *cf.ServiceInstance, target *targetInstance) VerifyCheck {
+	var expected []string
+	for _, app := range si.Apps {
+		expected = append(expected, app)
+	}
+	expected = sortedValues(expected)
+	if len(expected) == 0 {
+		return VerifyCheck{Name: BindingsCheck, Passed: true}
+	}
+
+	bindings, err := client.ListServiceBindingsByQuery(url.Values{"service_instance_guids": []string{target.guid}})
+	if err != nil {
+		return VerifyCheck{Name: BindingsCheck, Message: fmt.Sprintf("failed to list bindings: %v", err)}
+	}
+
+	var bound []string
+	for _, b := range bindings {
+		if b.AppGuid == "" {
+			continue
+		}
+		app, err := client.GetAppByGuidNoInlineCall(b.AppGuid)
+		if err != nil {
+			return VerifyCheck{Name: BindingsCheck, Message: fmt.Sprintf("failed to look up app %s: %v", b.AppGuid, err)}
+		}
+		bound = append(bound, app.Name)
+	}
+
+	if missing := missingValues(expected, bound); len(missing) > 0 {
+		return VerifyCheck{Name: BindingsCheck, Message: fmt.Sprintf("apps %s are not bound", strings.Join(missing, ", "))}
+	}
+	return VerifyCheck{Name: BindingsCheck, Passed: true}
+}
+
+func verifyServiceKeys(client cf.Client, si *cf.ServiceInstance, target *targetInstance) VerifyCheck {
+	var expected []string
+	for _, k := range si.ServiceKeys {
+		expected = append(expected, k.Name)
+	}
+	expected = sortedValues(expected)
+	if len(expected) == 0 {
+		return VerifyCheck{Name: ServiceKeysCheck, Passed: true}
+	}
+
+	keys, err := client.ListServiceKeysByQuery(url.Values{"service_instance_guids": []string{target.guid}})
+	if err != nil {
+		return VerifyCheck{Name: ServiceKeysCheck, Message: fmt.Sprintf("failed to list service keys: %v", err)}
+	}
+
+	var actual []string
+	for _, k := range keys {
+		actual = append(actual, k.Name)
+	}
+
+	if missing := missingValues(expected, actual); len(missing) > 0 {
+		return VerifyCheck{Name: ServiceKeysCheck, Message: fmt.Sprintf("service keys %s not found", strings.Join(missing, ", "))}
+	}
+	return VerifyCheck{Name: ServiceKeysCheck, Passed: true}
+}
+
+func verifyURLs(si *cf.ServiceInstance, target *targetInstance, domainsToReplace map[string]string) VerifyCheck {
+	var diffs []string
+	if expected := ReplaceDomain(si.RouteServiceUrl, domainsToReplace); expected != target.routeServiceUrl {
+		diffs = append(diffs, fmt.Sprintf("expected route service url %q, found %q", expected, target.routeServiceUrl))
+	}
+	if expected := ReplaceDomain(si.SyslogDrainUrl, domainsToReplace); expected != target.syslogDrainUrl {
+		diffs = append(diffs, fmt.Sprintf("expected syslog drain url %q, found %q", expected, target.syslogDrainUrl))
+	}
+
+	if len(diffs) > 0 {
+		return VerifyCheck{Name: URLsCheck, Message: strings.Join(diffs, ", ")}
+	}
+	return VerifyCheck{Name: URLsCheck, Passed: true}
+}
+
+// normalizeParams converts parameters read from yaml or json to the same types, so they can be compared
+func normalizeParams(params map[string]interface{}) (map[string]interface{}, error) {
+	if len(params) == 0 {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(stringKeys(params))
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized map[string]interface{}
+	if err = json.Unmarshal(b, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// stringKeys converts the nested maps decoded by yaml into maps that can be marshalled as json
+func stringKeys(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, e := range val {
+			m[fmt.Sprint(k)] = stringKeys(e)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, e := range val {
+			m[k] = stringKeys(e)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(val))
+		for i, e := range val {
+			s[i] = stringKeys(e)
+		}
+		return s
+	default:
+		return v
+	}
+}
+
+// sortedValues returns the unique, non-empty values sorted
+func sortedValues(values []string) []string {
+	var sorted []string
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v != "" && !containsString(sorted, v) {
+			sorted = append(sorted, v)
+		}
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// missingValues returns the expected values that are not in actual
+func missingValues(expected, actual []string) []string {
+	var missing []string
+	for _, v := range expected {
+		if !containsString(actual, v) {
+			missing = append(missing, v)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,330 @@
+/*
+ *  Copyright 2022 VMware, Inc.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package migrate_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/cloudfoundry-community/go-cfclient"
+	"github.com/stretchr/testify/require"
+	cffakes "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/cf/fakes"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config"
+	configfakes "github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/config/fakes"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate"
+	"github.com/vmware-tanzu/service-instance-migrator-for-cloud-foundry/pkg/migrate/fakes"
+)
+
+const verifyManagedInstance = `name: db
+type: managed_service_instance
+service: p-mysql
+plan: 100mb
+tags: mysql,primary
+params:
+  backups:
+    enabled: true
+    retention: 7
+apps:
+  binding-guid: my-app
+service_keys:
+- name: db-key
+`
+
+const verifyUserProvidedInstance = `name: logs
+type: user_provided_service_instance
+service: logs
+route_service_url: https://proxy.apps.cf1.example.com
+syslog_drain_url: syslog-tls://logs.cf1.example.com:6514
+`
+
+func newVerifyCFClient() *cffakes.FakeClient {
+	client := new(cffakes.FakeClient)
+	client.GetOrgByNameReturns(cfclient.Org{Guid: "org-guid", Name: "org1"}, nil)
+	client.GetSpaceByNameReturns(cfclient.Space{Guid: "space-guid", Name: "space1"}, nil)
+	client.ListServiceInstancesByQueryReturns([]cfclient.ServiceInstance{{
+		Guid:            "si-guid",
+		Name:            "db",
+		ServicePlanGuid: "plan-guid",
+		Tags:            []string{"primary", "mysql"},
+	}}, nil)
+	client.GetServicePlanByGUIDReturns(&cfclient.ServicePlan{Guid: "plan-guid", Name: "100mb", ServiceGuid: "service-guid"}, nil)
+	client.GetServiceByGuidReturns(cfclient.Service{Guid: "service-guid", Label: "p-mysql"}, nil)
+	client.GetServiceInstanceParamsReturns(map[string]interface{}{
+		"backups": map[string]interface{}{"enabled": true, "retention": float64(7)},
+	}, nil)
+	client.ListServiceBindingsByQueryReturns([]cfclient.ServiceBinding{{Guid: "new-binding-guid", AppGuid: "app-guid"}}, nil)
+	client.GetAppByGuidNoInlineCallReturns(cfclient.App{Guid: "app-guid", Name: "my-app"}, nil)
+	client.ListServiceKeysByQueryReturns([]cfclient.ServiceKey{{Name: "db-key"}}, nil)
+	client.ListUserProvidedServiceInstancesByQueryReturns([]cfclient.UserProvidedServiceInstance{{
+		Guid:            "ups-guid",
+		Name:            "logs",
+		RouteServiceUrl: "https://proxy.apps.cf2.example.com",
+		SyslogDrainUrl:  "syslog-tls://logs.cf2.example.com:6514",
+	}}, nil)
+	return client
+}
+
+func verifyResults(res migrate.VerifyResult) map[string]bool {
+	checks := make(map[string]bool)
+	for _, c := range res.Checks {
+		checks[c.Name] = c.Passed
+	}
+	return checks
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	domains := map[string]string{"cf1.example.com": "cf2.example.com"}
+	tests := []struct {
+		name       string
+		setup      func(t *testing.T, dir string, client *cffakes.FakeClient)
+		cfg        *config.Config
+		wantErr    bool
+		wantFailed bool
+		afterFunc  func(t *testing.T, report migrate.VerifyReport, client *cffakes.FakeClient)
+	}{
+		{
+			name: "passes when the target matches the export",
+			setup: func(t *testing.T, dir string, client *cffakes.FakeClient) {
+				writeInstance(t, dir, "org1", "space1", "db.yml", verifyManagedInstance)
+			},
+			cfg: &config.Config{},
+			afterFunc: func(t *testing.T, report migrate.VerifyReport, client *cffakes.FakeClient) {
+				require.Len(t, report, 1)
+				require.Equal(t, "db", report[0].Name)
+				require.Equal(t, map[string]bool{
+					migrate.InstanceCheck:        true,
+					migrate.ServiceOfferingCheck: true,
+					migrate.ServicePlanCheck:     true,
+					migrate.TagsCheck:            true,
+					migrate.ParametersCheck:      true,
+					migrate.BindingsCheck:        true,
+					migrate.ServiceKeysCheck:     true,
+				}, verifyResults(report[0]))
+				require.Equal(t, url.Values{
+					"space_guids": []string{"space-guid"},
+					"names":       []string{"db"},
+				}, client.ListServiceInstancesByQueryArgsForCall(0))
+			},
+		},
+		{
+			name: "fails when the instance does not exist",
+			setup: func(t *testing.T, dir string, client *cffakes.FakeClient) {
+				writeInstance(t, dir, "org1", "space1", "db.yml", verifyManagedInstance)
+				client.ListServiceInstancesByQueryReturns(nil, nil)
+			},
+			cfg:        &config.Config{},
+			wantFailed: true,
+			afterFunc: func(t *testing.T, report migrate.VerifyReport, client *cffakes.FakeClient) {
+				require.Equal(t, map[string]bool{migrate.InstanceCheck: false}, verifyResults(report[0]))
+			},
+		},
+		{
+			name: "fails when the space does not exist",
+			setup: func(t *testing.T, dir string, client *cffakes.FakeClient) {
+				writeInstance(t, dir, "org1", "space1", "db.yml", verifyManagedInstance)
+				client.GetSpaceByNameReturns(cfclient.Space{}, errors.New("space not found"))
+			},
+			cfg:        &config.Config{},
+			wantFailed: true,
+			afterFunc: func(t *testing.T, report migrate.VerifyReport, client *cffakes.FakeClient) {
+				require.Equal(t, map[string]bool{migrate.InstanceCheck: false}, verifyResults(report[0]))
+			},
+		},
+		{
+			name: "compares with the service and plan the instance is mapped to",
+			setup: func(t *testing.T, dir string, client *cffakes.FakeClient) {
+				writeInstance(t, dir, "org1", "space1", "db.yml", verifyManagedInstance)
+				client.GetServicePlanByGUIDReturns(&cfclient.ServicePlan{Name: "db-small", ServiceGuid: "service-guid"}, nil)
+				client.GetServiceByGuidReturns(cfclient.Service{Label: "p.mysql"}, nil)
+			},
+			cfg: &config.Config{
+				Mappings: []config.Mapping{{Service: "p-mysql", TargetService: "p.mysql", TargetPlan: "db-small"}},
+			},
+			afterFunc: func(t *testing.T, report migrate.VerifyReport, client *cffakes.FakeClient) {
+				require.Equal(t, "p.mysql", report[0].Service)
+				require.Equal(t, "db-small", report[0].Plan)
+			},
+		},
+		{
+			name: "fails when the plan differs",
+			setup: func(t *testing.T, dir string, client *cffakes.FakeClient) {
+				writeInstance(t, dir, "org1", "space1", "db.yml", verifyManagedInstance)
+				client.GetServicePlanByGUIDReturns(&cfclient.ServicePlan{Name: "1gb", ServiceGuid: "service-guid"}, nil)
+			},
+			cfg:        &config.Config{},
+			wantFailed: true,
+			afterFunc: func(t *testing.T, report migrate.VerifyReport, client *cffakes.FakeClient) {
+				checks := verifyResults(report[0])
+				require.True(t, checks[migrate.ServiceOfferingCheck])
+				require.False(t, checks[migrate.ServicePlanCheck])
+			},
+		},
+		{
+			name: "fails when the tags and parameters differ",
+			setup: func(t *testing.T, dir string, client *cffakes.FakeClient) {
+				writeInstance(t, dir, "org1", "space1", "db.yml", verifyManagedInstance)
+				client.ListServiceInstancesByQueryReturns([]cfclient.ServiceInstance{{Guid: "si-guid", ServicePlanGuid: "plan-guid", Tags: []string{"mysql"}}}, nil)
+				client.GetServiceInstanceParamsReturns(map[string]interface{}{"backups": map[string]interface{}{"enabled": false}}, nil)
+			},
+			cfg:        &config.Config{},
+			wantFailed: true,
+			afterFunc: func(t *testing.T, report migrate.VerifyReport, client *cffakes.FakeClient) {
+				checks := verifyResults(report[0])
+				require.False(t, checks[migrate.TagsCheck])
+				require.False(t, checks[migrate.ParametersCheck])
+			},
+		},
+		{
+			name: "passes when the broker does not return parameters",
+			setup: func(t *testing.T, dir string, client *cffakes.FakeClient) {
+				writeInstance(t, dir, "org1", "space1", "db.yml", verifyManagedInstance)
+				client.GetServiceInstanceParamsReturns(nil, cfclient.NewServiceFetchInstanceParametersNotSupportedError())
+			},
+			cfg: &config.Config{},
+		},
+		{
+			name: "fails when an app is not bound or a service key is missing",
+			setup: func(t *testing.T, dir string, client *cffakes.FakeClient) {
+				writeInstance(t, dir, "org1", "space1", "db.yml", verifyManagedInstance)
+				client.ListServiceBindingsByQueryReturns(nil, nil)
+				client.ListServiceKeysByQueryReturns(nil, nil)
+			},
+			cfg:        &config.Config{},
+			wantFailed: true,
+			afterFunc: func(t *testing.T, report migrate.VerifyReport, client *cffakes.FakeClient) {
+				checks := verifyResults(report[0])
+				require.False(t, checks[migrate.BindingsCheck])
+				require.False(t, checks[migrate.ServiceKeysCheck])
+			},
+		},
+		{
+			name: "does not check service keys when they are ignored",
+			setup: func(t *testing.T, dir string, client *cffakes.FakeClient) {
+				writeInstance(t, dir, "org1", "space1", "db.yml", verifyManagedInstance)
+				client.ListServiceKeysByQueryReturns(nil, nil)
+			},
+			cfg: &config.Config{IgnoreServiceKeys: true},
+			afterFunc: func(t *testing.T, report migrate.VerifyReport, client *cffakes.FakeClient) {
+				require.NotContains(t, verifyResults(report[0]), migrate.ServiceKeysCheck)
+				require.Equal(t, 0, client.ListServiceKeysByQueryCallCount())
+			},
+		},
+		{
+			name: "compares user-provided service urls after replacing domains",
+			setup: func(t *testing.T, dir string, client *cffakes.FakeClient) {
+				writeInstance(t, dir, "org1", "space1", "logs.yml", verifyUserProvidedInstance)
+			},
+			cfg: &config.Config{DomainsToReplace: domains},
+			afterFunc: func(t *testing.T, report migrate.VerifyReport, client *cffakes.FakeClient) {
+				require.Equal(t, map[string]bool{
+					migrate.InstanceCheck: true,
+					migrate.TagsCheck:     true,
+					migrate.BindingsCheck: true,
+					migrate.URLsCheck:     true,
+				}, verifyResults(report[0]))
+			},
+		},
+		{
+			name: "fails when user-provided service urls differ",
+			setup: func(t *testing.T, dir string, client *cffakes.FakeClient) {
+				writeInstance(t, dir, "org1", "space1", "logs.yml", verifyUserProvidedInstance)
+			},
+			cfg:        &config.Config{},
+			wantFailed: true,
+			afterFunc: func(t *testing.T, report migrate.VerifyReport, client *cffakes.FakeClient) {
+				require.False(t, verifyResults(report[0])[migrate.URLsCheck])
+			},
+		},
+		{
+			name: "only verifies the selected instances",
+			setup: func(t *testing.T, dir string, client *cffakes.FakeClient) {
+				writeInstance(t, dir, "org1", "space1", "db.yml", verifyManagedInstance)
+				writeInstance(t, dir, "org1", "space1", "logs.yml", verifyUserProvidedInstance)
+			},
+			cfg: &config.Config{Instances: []string{"db"}},
+			afterFunc: func(t *testing.T, report migrate.VerifyReport, client *cffakes.FakeClient) {
+				require.Len(t, report, 1)
+				require.Equal(t, "db", report[0].Name)
+			},
+		},
+		{
+			name: "fails when the export cannot be read",
+			setup: func(t *testing.T, dir string, client *cffakes.FakeClient) {
+				writeInstance(t, dir, "org1", "space1", "db.yml", "name: [")
+			},
+			cfg:     &config.Config{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			client := newVerifyCFClient()
+			tt.setup(t, dir, client)
+
+			holder := new(fakes.FakeClientHolder)
+			holder.TargetCFClientReturns(client)
+
+			mr := new(configfakes.FakeMigrationReader)
+			mr.GetMigrationReturns(&config.Migration{}, nil)
+
+			v := migrate.NewVerifier(holder, migrate.NewMigratorHelper(mr))
+			report, err := v.Verify(config.ContextWithConfig(context.TODO(), tt.cfg), dir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			require.Equal(t, tt.wantFailed, report.Failed())
+			if tt.afterFunc != nil {
+				tt.afterFunc(t, report, client)
+			}
+		})
+	}
+}
+
+func TestVerifyReport_Display(t *testing.T) {
+	report := migrate.VerifyReport{
+		{
+			Org:     "org1",
+			Space:   "space1",
+			Name:    "cache",
+			Service: "p.redis",
+			Checks:  []migrate.VerifyCheck{{Name: migrate.InstanceCheck, Passed: true}},
+		},
+		{
+			Org:     "org1",
+			Space:   "space1",
+			Name:    "db",
+			Service: "p.mysql",
+			Checks: []migrate.VerifyCheck{
+				{Name: migrate.InstanceCheck, Passed: true},
+				{Name: migrate.TagsCheck, Message: "expected [mysql], found []"},
+				{Name: migrate.BindingsCheck, Message: "apps my-app are not bound"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	report.Display(&buf)
+
+	out := buf.String()
+	require.Contains(t, out, "Result")
+	require.Regexp(t, `cache\s+p.redis\s+passed\n`, out)
+	require.Regexp(t, `db\s+p.mysql\s+failed: tags: expected \[mysql\], found \[\]; bindings: apps my-app are not bound\n`, out)
+}
@@ -100,6 +100,7 @@ func (b *boshPropertiesBuilder) Build() *config.BoshProperties {
 	b.Properties.AllProxy = AllProxyURL(b.OpsManager)
 	b.Properties.ClientID = clientID
 	b.Properties.ClientSecret = clientSecret
+	log.RegisterSecrets(clientSecret)
 	b.Properties.RootCA = authorities
 	b.Properties.Deployment = deployedProductGUID
 
@@ -128,6 +129,7 @@ func (b *cfPropertiesBuilder) Build() *config.CFProperties {
 	b.Properties.URL = "https://api." + props[".cloud_controller.system_domain"].Value.(string)
 	b.Properties.Username = uaaAdminCreds.Credential.Value["identity"]
 	b.Properties.Password = uaaAdminCreds.Credential.Value["password"]
+	log.RegisterSecrets(b.Properties.Password)
 	return b.Properties
 }
 
@@ -140,6 +142,7 @@ func (b *ccdbPropertiesBuilder) Build() *config.CCDBProperties {
 		log.Fatalln(err)
 	}
 	b.CCDBProperties.EncryptionKey = ccdbEncCreds.Credential.Value["password"]
+	log.RegisterSecrets(b.CCDBProperties.EncryptionKey)
 
 	b.CCDBProperties.SSHHost = b.OpsManager.Hostname
 	b.CCDBProperties.SSHUsername = b.OpsManager.SshUser
@@ -174,6 +177,7 @@ func setCCDBCredentials(boshProperties config.BoshProperties, ccdbProperties *co
 	value := data[0]["value"].(map[string]interface{})
 	ccdbProperties.Username = value["username"].(string)
 	ccdbProperties.Password = value["password"].(string)
+	log.RegisterSecrets(ccdbProperties.Password)
 }
 
 func newCredhubClient(c credhub.ClientFactory, properties config.BoshProperties) credhub.Client {
@@ -187,11 +191,11 @@ func newCredhubClient(c credhub.ClientFactory, properties config.BoshProperties)
 	}
 
 	return c.New(
-		fmt.Sprintf("%s://%s", scheme, host),
-		"8844",
-		"8443",
+		fmt.Sprintf("%s://%s:8844", scheme, host),
+		fmt.Sprintf("%s://%s:8443", scheme, host),
 		properties.AllProxy,
 		[]byte(trustedCert),
+		false,
 		properties.ClientID,
 		properties.ClientSecret)
 }